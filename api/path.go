@@ -65,6 +65,9 @@ const (
 	// URIForCRLByID provides URI for CRL by issuer
 	PathForCRLByID = "/v1/crl/:issuer_id"
 
+	// PathForCRLShardByID provides URI for CRL shard by issuer
+	PathForCRLShardByID = "/v1/crl/:issuer_id/:shard"
+
 	// URIForAIACerts provides base URI for AIA certs
 	PathForAIACerts = "/v1/cert"
 
@@ -89,9 +92,148 @@ const (
 	// Response: IssuersInfoResponse
 	PathForCAIssuers = "/v1/ca/issuers"
 
+	// PathForCAIssuer provides Issuer by Label or IKID
+	//
+	// Verbs: GET
+	// Response: IssuerInfo
+	PathForCAIssuer = "/v1/ca/issuer"
+
 	// PathForCAProfileInfo provides profile information
 	//
 	// Verbs: GET
-	// Response: CertProfileInfo
+	// Response: CertProfile
 	PathForCAProfileInfo = "/v1/ca/csr/profile_info"
+
+	// PathForCASign signs the certificate
+	//
+	// Verbs: POST
+	// Request: SignCertificateRequest
+	// Response: CertificateResponse
+	PathForCASign = "/v1/ca/sign"
+
+	// PathForCASignBatch signs the list of certificates
+	//
+	// Verbs: POST
+	// Request: SignCertificatesRequest
+	// Response: SignCertificatesResponse
+	PathForCASignBatch = "/v1/ca/sign/batch"
+
+	// PathForCARenew renews the certificate with the same key
+	//
+	// Verbs: POST
+	// Request: RenewCertificateRequest
+	// Response: CertificateResponse
+	PathForCARenew = "/v1/ca/renew"
+
+	// PathForCARekey renews the certificate with a new key
+	//
+	// Verbs: POST
+	// Request: RekeyCertificateRequest
+	// Response: CertificateResponse
+	PathForCARekey = "/v1/ca/rekey"
+
+	// PathForCAGenerate generates the key, and signs the certificate
+	//
+	// Verbs: POST
+	// Request: GenerateCertificateRequest
+	// Response: GenerateCertificateResponse
+	PathForCAGenerate = "/v1/ca/generate"
+
+	// PathForCACert provides the certificate by ID, SKID or Issuer and Serial
+	//
+	// Verbs: GET
+	// Response: CertificateResponse
+	PathForCACert = "/v1/ca/cert"
+
+	// PathForCACerts provides the certificates by issuer
+	//
+	// Verbs: GET
+	// Response: CertificatesResponse
+	PathForCACerts = "/v1/ca/certs"
+
+	// PathForCAOrgCerts provides the certificates by Org
+	//
+	// Verbs: GET
+	// Response: CertificatesResponse
+	PathForCAOrgCerts = "/v1/ca/orgs/:OrgID/certs"
+
+	// PathForCARevoked provides the revoked certificates by issuer
+	//
+	// Verbs: GET
+	// Response: RevokedCertificatesResponse
+	PathForCARevoked = "/v1/ca/revoked"
+
+	// PathForCARevoke revokes the certificate
+	//
+	// Verbs: POST
+	// Request: RevokeCertificateRequest
+	// Response: RevokedCertificateResponse
+	PathForCARevoke = "/v1/ca/revoke"
+
+	// PathForCALabel updates the certificate label
+	//
+	// Verbs: POST
+	// Request: UpdateCertificateLabelRequest
+	// Response: CertificateResponse
+	PathForCALabel = "/v1/ca/label"
+
+	// PathForCAImport registers the certificate issued by an external CA
+	//
+	// Verbs: POST
+	// Request: ImportCertificateRequest
+	// Response: CertificateResponse
+	PathForCAImport = "/v1/ca/import"
+
+	// PathForCACRL provides the CRL
+	//
+	// Verbs: GET
+	// Response: CrlResponse
+	PathForCACRL = "/v1/ca/crl"
+
+	// PathForCAOCSP signs OCSP response
+	//
+	// Verbs: POST
+	// Request: OCSPRequest
+	// Response: OCSPResponse
+	PathForCAOCSP = "/v1/ca/ocsp"
+
+	// PathForCAPublishCrls publishes CRLs
+	//
+	// Verbs: POST
+	// Request: PublishCrlsRequest
+	// Response: CrlsResponse
+	PathForCAPublishCrls = "/v1/ca/publish/crls"
+
+	// PathForCAPublishStatus provides the status of the publish outbox
+	//
+	// Verbs: GET
+	// Response: PublishStatusResponse
+	PathForCAPublishStatus = "/v1/ca/publish/status"
+
+	// PathForCAProfiles registers the certificate profile
+	//
+	// Verbs: POST
+	// Request: RegisterProfileRequest
+	// Response: CertProfile
+	PathForCAProfiles = "/v1/ca/profiles"
+
+	// PathForCADelegatedIssuers provides the delegated issuers
+	//
+	// Verbs: GET
+	// Response: IssuersInfoResponse
+	PathForCADelegatedIssuers = "/v1/ca/delegated_issuers"
+
+	// PathForCARegisterDelegatedIssuer creates new delegated issuer
+	//
+	// Verbs: POST
+	// Request: SignCertificateRequest
+	// Response: IssuerInfo
+	PathForCARegisterDelegatedIssuer = "/v1/ca/delegated_issuers/register"
+
+	// PathForCAArchiveDelegatedIssuer archives the delegated issuer
+	//
+	// Verbs: POST
+	// Request: IssuerInfoRequest
+	// Response: IssuerInfo
+	PathForCAArchiveDelegatedIssuer = "/v1/ca/delegated_issuers/archive"
 )
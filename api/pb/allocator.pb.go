@@ -113,6 +113,30 @@ var methods = map[string]*MethodInfo{
 		Allocator: func() any { return new(RegisterProfileRequest) },
 	},
 
+	CA_ImportCertificate_FullMethodName: {
+		Allocator: func() any { return new(ImportCertificateRequest) },
+	},
+
+	CA_GetPublishStatus_FullMethodName: {
+		Allocator: func() any { return new(PublishStatusRequest) },
+	},
+
+	CA_SignCertificates_FullMethodName: {
+		Allocator: func() any { return new(SignCertificatesRequest) },
+	},
+
+	CA_RenewCertificate_FullMethodName: {
+		Allocator: func() any { return new(RenewCertificateRequest) },
+	},
+
+	CA_RekeyCertificate_FullMethodName: {
+		Allocator: func() any { return new(RekeyCertificateRequest) },
+	},
+
+	CA_GenerateAndSignCertificate_FullMethodName: {
+		Allocator: func() any { return new(GenerateCertificateRequest) },
+	},
+
 	CIS_GetRoots_FullMethodName: {
 		Allocator: func() any { return new(emptypb.Empty) },
 	},
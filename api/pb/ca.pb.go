@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 	sync "sync"
 
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 )
@@ -67,6 +68,53 @@ func (IssuerStatus) EnumDescriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{0}
 }
 
+// KeyBundleFormat specifies the format of the generated key bundle
+type KeyBundleFormat int32
+
+const (
+	KeyBundleFormat_PKCS12 KeyBundleFormat = 0 // default, password protected PKCS#12
+	KeyBundleFormat_PKCS8  KeyBundleFormat = 1 // PEM with encrypted PKCS#8 key and the certificate chain
+)
+
+// Enum value maps for KeyBundleFormat.
+var (
+	KeyBundleFormat_name = map[int32]string{
+		0: "PKCS12",
+		1: "PKCS8",
+	}
+	KeyBundleFormat_value = map[string]int32{
+		"PKCS12": 0,
+		"PKCS8":  1,
+	}
+)
+
+func (x KeyBundleFormat) Enum() *KeyBundleFormat {
+	p := new(KeyBundleFormat)
+	*p = x
+	return p
+}
+
+func (x KeyBundleFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyBundleFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_ca_proto_enumTypes[1].Descriptor()
+}
+
+func (KeyBundleFormat) Type() protoreflect.EnumType {
+	return &file_ca_proto_enumTypes[1]
+}
+
+func (x KeyBundleFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyBundleFormat.Descriptor instead.
+func (KeyBundleFormat) EnumDescriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{1}
+}
+
 type CertProfileInfoRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -403,7 +451,8 @@ type SignCertificateRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// RequestFormat provides the certificate request format: CSR, CMS
+	// RequestFormat provides the certificate request format: PEM, DER, SPKI, SPKAC, CRMF.
+	// For SPKI and SPKAC the subject is provided by Subject and SAN.
 	RequestFormat EncodingFormat `protobuf:"varint,1,opt,name=RequestFormat,proto3,enum=pb.EncodingFormat" json:"RequestFormat,omitempty"`
 	// Request provides the certificate request
 	Request []byte `protobuf:"bytes,2,opt,name=Request,proto3" json:"Request,omitempty"`
@@ -429,6 +478,10 @@ type SignCertificateRequest struct {
 	Label string `protobuf:"bytes,12,opt,name=Label,proto3" json:"Label,omitempty"`
 	// Metadata is provided by a client
 	Metadata map[string]string `protobuf:"bytes,13,rep,name=Metadata,proto3" json:"Metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// IdempotencyKey optionally specifies a unique key of SignCertificate request
+	// within the OrgID, the retry of the request with the same key returns the issued certificate.
+	// The key can not be reused with a different request.
+	IdempotencyKey string `protobuf:"bytes,14,opt,name=IdempotencyKey,proto3" json:"IdempotencyKey,omitempty"`
 }
 
 func (x *SignCertificateRequest) Reset() {
@@ -554,20 +607,24 @@ func (x *SignCertificateRequest) GetMetadata() map[string]string {
 	return nil
 }
 
-// UpdateCertificateLabelRequest specifies certificate label update request
-type UpdateCertificateLabelRequest struct {
+func (x *SignCertificateRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+// SignCertificatesRequest specifies a batch of certificate sign requests
+type SignCertificatesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Id specifies certificate ID.
-	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	// Label is provided by a client
-	Label string `protobuf:"bytes,2,opt,name=Label,proto3" json:"Label,omitempty"`
+	Requests []*SignCertificateRequest `protobuf:"bytes,1,rep,name=Requests,proto3" json:"Requests,omitempty"`
 }
 
-func (x *UpdateCertificateLabelRequest) Reset() {
-	*x = UpdateCertificateLabelRequest{}
+func (x *SignCertificatesRequest) Reset() {
+	*x = SignCertificatesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -575,13 +632,13 @@ func (x *UpdateCertificateLabelRequest) Reset() {
 	}
 }
 
-func (x *UpdateCertificateLabelRequest) String() string {
+func (x *SignCertificatesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateCertificateLabelRequest) ProtoMessage() {}
+func (*SignCertificatesRequest) ProtoMessage() {}
 
-func (x *UpdateCertificateLabelRequest) ProtoReflect() protoreflect.Message {
+func (x *SignCertificatesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -593,42 +650,39 @@ func (x *UpdateCertificateLabelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateCertificateLabelRequest.ProtoReflect.Descriptor instead.
-func (*UpdateCertificateLabelRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SignCertificatesRequest.ProtoReflect.Descriptor instead.
+func (*SignCertificatesRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UpdateCertificateLabelRequest) GetID() uint64 {
-	if x != nil {
-		return x.ID
-	}
-	return 0
-}
-
-func (x *UpdateCertificateLabelRequest) GetLabel() string {
+func (x *SignCertificatesRequest) GetRequests() []*SignCertificateRequest {
 	if x != nil {
-		return x.Label
+		return x.Requests
 	}
-	return ""
+	return nil
 }
 
-// GetCertificateRequest specifies certificate request by ID or issuer key identifier
-type GetCertificateRequest struct {
+// SignCertificateResult provides the result of the request in a batch or stream
+type SignCertificateResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// ID specifies certificate ID.
-	// If it's not set, then SKID must be provided
-	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	// SKID specifies Subject Key ID to search
-	SKID string `protobuf:"bytes,2,opt,name=SKID,proto3" json:"SKID,omitempty"`
-	// IssuerSerial specifies Issuer Key ID and certificate serial number to search
-	IssuerSerial *IssuerSerial `protobuf:"bytes,3,opt,name=IssuerSerial,proto3" json:"IssuerSerial,omitempty"`
-}
-
-func (x *GetCertificateRequest) Reset() {
-	*x = GetCertificateRequest{}
+	// Index specifies the index of the request in the batch or stream
+	Index uint32 `protobuf:"varint,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	// Certificate is set if the request succeeded
+	Certificate *Certificate `protobuf:"bytes,2,opt,name=Certificate,proto3" json:"Certificate,omitempty"`
+	// Code specifies gRPC status code if the request failed
+	Code uint32 `protobuf:"varint,3,opt,name=Code,proto3" json:"Code,omitempty"`
+	// Error specifies the error message if the request failed
+	Error string `protobuf:"bytes,4,opt,name=Error,proto3" json:"Error,omitempty"`
+	// Warnings provides the results of pre-issuance linting,
+	// that did not block the issuance
+	Warnings []*LintResult `protobuf:"bytes,5,rep,name=Warnings,proto3" json:"Warnings,omitempty"`
+}
+
+func (x *SignCertificateResult) Reset() {
+	*x = SignCertificateResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -636,13 +690,13 @@ func (x *GetCertificateRequest) Reset() {
 	}
 }
 
-func (x *GetCertificateRequest) String() string {
+func (x *SignCertificateResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCertificateRequest) ProtoMessage() {}
+func (*SignCertificateResult) ProtoMessage() {}
 
-func (x *GetCertificateRequest) ProtoReflect() protoreflect.Message {
+func (x *SignCertificateResult) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -654,44 +708,57 @@ func (x *GetCertificateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCertificateRequest.ProtoReflect.Descriptor instead.
-func (*GetCertificateRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SignCertificateResult.ProtoReflect.Descriptor instead.
+func (*SignCertificateResult) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetCertificateRequest) GetID() uint64 {
+func (x *SignCertificateResult) GetIndex() uint32 {
 	if x != nil {
-		return x.ID
+		return x.Index
 	}
 	return 0
 }
 
-func (x *GetCertificateRequest) GetSKID() string {
+func (x *SignCertificateResult) GetCertificate() *Certificate {
 	if x != nil {
-		return x.SKID
+		return x.Certificate
+	}
+	return nil
+}
+
+func (x *SignCertificateResult) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SignCertificateResult) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-func (x *GetCertificateRequest) GetIssuerSerial() *IssuerSerial {
+func (x *SignCertificateResult) GetWarnings() []*LintResult {
 	if x != nil {
-		return x.IssuerSerial
+		return x.Warnings
 	}
 	return nil
 }
 
-// GetCrlRequest specifies CRL request by IKID
-type GetCrlRequest struct {
+// SignCertificatesResponse returns the results of the batch
+type SignCertificatesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// IKID specifies Issuer
-	IKID string `protobuf:"bytes,1,opt,name=IKID,proto3" json:"IKID,omitempty"`
+	Results []*SignCertificateResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
 }
 
-func (x *GetCrlRequest) Reset() {
-	*x = GetCrlRequest{}
+func (x *SignCertificatesResponse) Reset() {
+	*x = SignCertificatesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -699,13 +766,13 @@ func (x *GetCrlRequest) Reset() {
 	}
 }
 
-func (x *GetCrlRequest) String() string {
+func (x *SignCertificatesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCrlRequest) ProtoMessage() {}
+func (*SignCertificatesResponse) ProtoMessage() {}
 
-func (x *GetCrlRequest) ProtoReflect() protoreflect.Message {
+func (x *SignCertificatesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -717,33 +784,54 @@ func (x *GetCrlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCrlRequest.ProtoReflect.Descriptor instead.
-func (*GetCrlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SignCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*SignCertificatesResponse) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetCrlRequest) GetIKID() string {
+func (x *SignCertificatesResponse) GetResults() []*SignCertificateResult {
 	if x != nil {
-		return x.IKID
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-type ListByIssuerRequest struct {
+// GenerateCertificateRequest specifies the request to generate the key,
+// and sign the certificate
+type GenerateCertificateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Limit specifies the limit to return
-	Limit int64 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
-	// After specifies certificate ID to start after
-	After uint64 `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
-	// IKID specifies Issuer Key ID to search
-	IKID string `protobuf:"bytes,3,opt,name=IKID,proto3" json:"IKID,omitempty"`
-}
-
-func (x *ListByIssuerRequest) Reset() {
-	*x = ListByIssuerRequest{}
+	// Profile specifies the certificate profile, that allows key generation
+	Profile string `protobuf:"bytes,1,opt,name=Profile,proto3" json:"Profile,omitempty"`
+	// IssuerLabel specifies which Issuer to be appointed to sign the request
+	IssuerLabel string `protobuf:"bytes,2,opt,name=IssuerLabel,proto3" json:"IssuerLabel,omitempty"`
+	// San specifies Subject Alternative Names
+	SAN []string `protobuf:"bytes,3,rep,name=SAN,proto3" json:"SAN,omitempty"`
+	// Subject specifies name
+	Subject *X509Subject `protobuf:"bytes,4,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	// OrgID provides the ID of Organization that certificate belongs to
+	OrgID uint64 `protobuf:"varint,5,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	// NotBefore is the time when the validity period starts
+	NotBefore string `protobuf:"bytes,6,opt,name=NotBefore,proto3" json:"NotBefore,omitempty"`
+	// NotAfter is the time when the validity period ends
+	NotAfter string `protobuf:"bytes,7,opt,name=NotAfter,proto3" json:"NotAfter,omitempty"`
+	// Label is provided by a client
+	Label string `protobuf:"bytes,8,opt,name=Label,proto3" json:"Label,omitempty"`
+	// Metadata is provided by a client
+	Metadata map[string]string `protobuf:"bytes,9,rep,name=Metadata,proto3" json:"Metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Format specifies the format of the key bundle
+	Format KeyBundleFormat `protobuf:"varint,10,opt,name=Format,proto3,enum=pb.KeyBundleFormat" json:"Format,omitempty"`
+	// Password specifies the password to protect the key bundle
+	Password string `protobuf:"bytes,11,opt,name=Password,proto3" json:"Password,omitempty"`
+	// Recipient optionally specifies age X25519 public key,
+	// to encrypt the key bundle to
+	Recipient string `protobuf:"bytes,12,opt,name=Recipient,proto3" json:"Recipient,omitempty"`
+}
+
+func (x *GenerateCertificateRequest) Reset() {
+	*x = GenerateCertificateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -751,13 +839,13 @@ func (x *ListByIssuerRequest) Reset() {
 	}
 }
 
-func (x *ListByIssuerRequest) String() string {
+func (x *GenerateCertificateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListByIssuerRequest) ProtoMessage() {}
+func (*GenerateCertificateRequest) ProtoMessage() {}
 
-func (x *ListByIssuerRequest) ProtoReflect() protoreflect.Message {
+func (x *GenerateCertificateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -769,51 +857,114 @@ func (x *ListByIssuerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListByIssuerRequest.ProtoReflect.Descriptor instead.
-func (*ListByIssuerRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GenerateCertificateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateCertificateRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ListByIssuerRequest) GetLimit() int64 {
+func (x *GenerateCertificateRequest) GetProfile() string {
 	if x != nil {
-		return x.Limit
+		return x.Profile
 	}
-	return 0
+	return ""
 }
 
-func (x *ListByIssuerRequest) GetAfter() uint64 {
+func (x *GenerateCertificateRequest) GetIssuerLabel() string {
 	if x != nil {
-		return x.After
+		return x.IssuerLabel
+	}
+	return ""
+}
+
+func (x *GenerateCertificateRequest) GetSAN() []string {
+	if x != nil {
+		return x.SAN
+	}
+	return nil
+}
+
+func (x *GenerateCertificateRequest) GetSubject() *X509Subject {
+	if x != nil {
+		return x.Subject
+	}
+	return nil
+}
+
+func (x *GenerateCertificateRequest) GetOrgID() uint64 {
+	if x != nil {
+		return x.OrgID
 	}
 	return 0
 }
 
-func (x *ListByIssuerRequest) GetIKID() string {
+func (x *GenerateCertificateRequest) GetNotBefore() string {
 	if x != nil {
-		return x.IKID
+		return x.NotBefore
 	}
 	return ""
 }
 
-// RevokeCertificateRequest specifies revocation request
-type RevokeCertificateRequest struct {
+func (x *GenerateCertificateRequest) GetNotAfter() string {
+	if x != nil {
+		return x.NotAfter
+	}
+	return ""
+}
+
+func (x *GenerateCertificateRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *GenerateCertificateRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *GenerateCertificateRequest) GetFormat() KeyBundleFormat {
+	if x != nil {
+		return x.Format
+	}
+	return KeyBundleFormat_PKCS12
+}
+
+func (x *GenerateCertificateRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *GenerateCertificateRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+// GenerateCertificateResponse returns the issued certificate and the key bundle
+type GenerateCertificateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Id specifies certificate ID.
-	// If it's not set, then SKID must be provided
-	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	// SKID specifies Subject Key ID to search
-	SKID string `protobuf:"bytes,2,opt,name=SKID,proto3" json:"SKID,omitempty"`
-	// IssuerSerial specifies Issuer Key ID and certificate serial number to search
-	IssuerSerial *IssuerSerial `protobuf:"bytes,3,opt,name=IssuerSerial,proto3" json:"IssuerSerial,omitempty"`
-	// Reason for revocation
-	Reason Reason `protobuf:"varint,4,opt,name=Reason,proto3,enum=pb.Reason" json:"Reason,omitempty"`
-}
-
-func (x *RevokeCertificateRequest) Reset() {
-	*x = RevokeCertificateRequest{}
+	Certificate *Certificate `protobuf:"bytes,1,opt,name=Certificate,proto3" json:"Certificate,omitempty"`
+	// Format specifies the format of the key bundle
+	Format KeyBundleFormat `protobuf:"varint,2,opt,name=Format,proto3,enum=pb.KeyBundleFormat" json:"Format,omitempty"`
+	// Bundle provides the key and certificate chain in the requested format,
+	// encrypted with age if the Recipient was specified
+	Bundle []byte `protobuf:"bytes,3,opt,name=Bundle,proto3" json:"Bundle,omitempty"`
+	// Warnings provides the results of pre-issuance linting,
+	// that did not block the issuance
+	Warnings []*LintResult `protobuf:"bytes,4,rep,name=Warnings,proto3" json:"Warnings,omitempty"`
+}
+
+func (x *GenerateCertificateResponse) Reset() {
+	*x = GenerateCertificateResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -821,13 +972,13 @@ func (x *RevokeCertificateRequest) Reset() {
 	}
 }
 
-func (x *RevokeCertificateRequest) String() string {
+func (x *GenerateCertificateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokeCertificateRequest) ProtoMessage() {}
+func (*GenerateCertificateResponse) ProtoMessage() {}
 
-func (x *RevokeCertificateRequest) ProtoReflect() protoreflect.Message {
+func (x *GenerateCertificateResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -839,50 +990,62 @@ func (x *RevokeCertificateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokeCertificateRequest.ProtoReflect.Descriptor instead.
-func (*RevokeCertificateRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GenerateCertificateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateCertificateResponse) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *RevokeCertificateRequest) GetID() uint64 {
+func (x *GenerateCertificateResponse) GetCertificate() *Certificate {
 	if x != nil {
-		return x.ID
+		return x.Certificate
 	}
-	return 0
+	return nil
 }
 
-func (x *RevokeCertificateRequest) GetSKID() string {
+func (x *GenerateCertificateResponse) GetFormat() KeyBundleFormat {
 	if x != nil {
-		return x.SKID
+		return x.Format
 	}
-	return ""
+	return KeyBundleFormat_PKCS12
 }
 
-func (x *RevokeCertificateRequest) GetIssuerSerial() *IssuerSerial {
+func (x *GenerateCertificateResponse) GetBundle() []byte {
 	if x != nil {
-		return x.IssuerSerial
+		return x.Bundle
 	}
 	return nil
 }
 
-func (x *RevokeCertificateRequest) GetReason() Reason {
+func (x *GenerateCertificateResponse) GetWarnings() []*LintResult {
 	if x != nil {
-		return x.Reason
+		return x.Warnings
 	}
-	return Reason_UNSPECIFIED
+	return nil
 }
 
-// CertificateResponse returns Certificate
-type CertificateResponse struct {
+// RenewCertificateRequest specifies the request to renew the certificate
+type RenewCertificateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Certificate *Certificate `protobuf:"bytes,1,opt,name=Certificate,proto3" json:"Certificate,omitempty"`
-}
-
-func (x *CertificateResponse) Reset() {
-	*x = CertificateResponse{}
+	// ID specifies the certificate ID to renew
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// NotBefore is the time when the validity period starts
+	NotBefore string `protobuf:"bytes,2,opt,name=NotBefore,proto3" json:"NotBefore,omitempty"`
+	// NotAfter is the time when the validity period ends
+	NotAfter string `protobuf:"bytes,3,opt,name=NotAfter,proto3" json:"NotAfter,omitempty"`
+	// RevokePredecessor specifies to revoke the renewed certificate
+	// with superseded reason
+	RevokePredecessor bool `protobuf:"varint,4,opt,name=RevokePredecessor,proto3" json:"RevokePredecessor,omitempty"`
+	// GracePeriod specifies the duration, for example 24h,
+	// after which the renewed certificate is revoked.
+	// If not set, the certificate is revoked immediately.
+	GracePeriod string `protobuf:"bytes,5,opt,name=GracePeriod,proto3" json:"GracePeriod,omitempty"`
+}
+
+func (x *RenewCertificateRequest) Reset() {
+	*x = RenewCertificateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -890,13 +1053,13 @@ func (x *CertificateResponse) Reset() {
 	}
 }
 
-func (x *CertificateResponse) String() string {
+func (x *RenewCertificateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CertificateResponse) ProtoMessage() {}
+func (*RenewCertificateRequest) ProtoMessage() {}
 
-func (x *CertificateResponse) ProtoReflect() protoreflect.Message {
+func (x *RenewCertificateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -908,29 +1071,73 @@ func (x *CertificateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CertificateResponse.ProtoReflect.Descriptor instead.
-func (*CertificateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RenewCertificateRequest.ProtoReflect.Descriptor instead.
+func (*RenewCertificateRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CertificateResponse) GetCertificate() *Certificate {
+func (x *RenewCertificateRequest) GetID() uint64 {
 	if x != nil {
-		return x.Certificate
+		return x.ID
 	}
-	return nil
+	return 0
 }
 
-// CertificatesResponse returns Certificates list
-type CertificatesResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *RenewCertificateRequest) GetNotBefore() string {
+	if x != nil {
+		return x.NotBefore
+	}
+	return ""
+}
 
-	Certificates []*Certificate `protobuf:"bytes,1,rep,name=Certificates,proto3" json:"Certificates,omitempty"`
+func (x *RenewCertificateRequest) GetNotAfter() string {
+	if x != nil {
+		return x.NotAfter
+	}
+	return ""
 }
 
-func (x *CertificatesResponse) Reset() {
-	*x = CertificatesResponse{}
+func (x *RenewCertificateRequest) GetRevokePredecessor() bool {
+	if x != nil {
+		return x.RevokePredecessor
+	}
+	return false
+}
+
+func (x *RenewCertificateRequest) GetGracePeriod() string {
+	if x != nil {
+		return x.GracePeriod
+	}
+	return ""
+}
+
+// RekeyCertificateRequest specifies the request to rekey the certificate
+type RekeyCertificateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID specifies the certificate ID to rekey
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// RequestFormat provides the certificate request format: PEM, DER
+	RequestFormat EncodingFormat `protobuf:"varint,2,opt,name=RequestFormat,proto3,enum=pb.EncodingFormat" json:"RequestFormat,omitempty"`
+	// Request provides the certificate request with a new key
+	Request []byte `protobuf:"bytes,3,opt,name=Request,proto3" json:"Request,omitempty"`
+	// NotBefore is the time when the validity period starts
+	NotBefore string `protobuf:"bytes,4,opt,name=NotBefore,proto3" json:"NotBefore,omitempty"`
+	// NotAfter is the time when the validity period ends
+	NotAfter string `protobuf:"bytes,5,opt,name=NotAfter,proto3" json:"NotAfter,omitempty"`
+	// RevokePredecessor specifies to revoke the rekeyed certificate
+	// with superseded reason
+	RevokePredecessor bool `protobuf:"varint,6,opt,name=RevokePredecessor,proto3" json:"RevokePredecessor,omitempty"`
+	// GracePeriod specifies the duration, for example 24h,
+	// after which the rekeyed certificate is revoked.
+	// If not set, the certificate is revoked immediately.
+	GracePeriod string `protobuf:"bytes,7,opt,name=GracePeriod,proto3" json:"GracePeriod,omitempty"`
+}
+
+func (x *RekeyCertificateRequest) Reset() {
+	*x = RekeyCertificateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -938,13 +1145,13 @@ func (x *CertificatesResponse) Reset() {
 	}
 }
 
-func (x *CertificatesResponse) String() string {
+func (x *RekeyCertificateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CertificatesResponse) ProtoMessage() {}
+func (*RekeyCertificateRequest) ProtoMessage() {}
 
-func (x *CertificatesResponse) ProtoReflect() protoreflect.Message {
+func (x *RekeyCertificateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -956,29 +1163,74 @@ func (x *CertificatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CertificatesResponse.ProtoReflect.Descriptor instead.
-func (*CertificatesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RekeyCertificateRequest.ProtoReflect.Descriptor instead.
+func (*RekeyCertificateRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *CertificatesResponse) GetCertificates() []*Certificate {
+func (x *RekeyCertificateRequest) GetID() uint64 {
 	if x != nil {
-		return x.Certificates
+		return x.ID
+	}
+	return 0
+}
+
+func (x *RekeyCertificateRequest) GetRequestFormat() EncodingFormat {
+	if x != nil {
+		return x.RequestFormat
+	}
+	return EncodingFormat_PEM
+}
+
+func (x *RekeyCertificateRequest) GetRequest() []byte {
+	if x != nil {
+		return x.Request
 	}
 	return nil
 }
 
-// RevokedCertificateResponse returns Revoked Certificate
-type RevokedCertificateResponse struct {
+func (x *RekeyCertificateRequest) GetNotBefore() string {
+	if x != nil {
+		return x.NotBefore
+	}
+	return ""
+}
+
+func (x *RekeyCertificateRequest) GetNotAfter() string {
+	if x != nil {
+		return x.NotAfter
+	}
+	return ""
+}
+
+func (x *RekeyCertificateRequest) GetRevokePredecessor() bool {
+	if x != nil {
+		return x.RevokePredecessor
+	}
+	return false
+}
+
+func (x *RekeyCertificateRequest) GetGracePeriod() string {
+	if x != nil {
+		return x.GracePeriod
+	}
+	return ""
+}
+
+// UpdateCertificateLabelRequest specifies certificate label update request
+type UpdateCertificateLabelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Revoked *RevokedCertificate `protobuf:"bytes,1,opt,name=Revoked,proto3" json:"Revoked,omitempty"`
+	// Id specifies certificate ID.
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Label is provided by a client
+	Label string `protobuf:"bytes,2,opt,name=Label,proto3" json:"Label,omitempty"`
 }
 
-func (x *RevokedCertificateResponse) Reset() {
-	*x = RevokedCertificateResponse{}
+func (x *UpdateCertificateLabelRequest) Reset() {
+	*x = UpdateCertificateLabelRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -986,13 +1238,13 @@ func (x *RevokedCertificateResponse) Reset() {
 	}
 }
 
-func (x *RevokedCertificateResponse) String() string {
+func (x *UpdateCertificateLabelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokedCertificateResponse) ProtoMessage() {}
+func (*UpdateCertificateLabelRequest) ProtoMessage() {}
 
-func (x *RevokedCertificateResponse) ProtoReflect() protoreflect.Message {
+func (x *UpdateCertificateLabelRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1004,29 +1256,42 @@ func (x *RevokedCertificateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokedCertificateResponse.ProtoReflect.Descriptor instead.
-func (*RevokedCertificateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateCertificateLabelRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCertificateLabelRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *RevokedCertificateResponse) GetRevoked() *RevokedCertificate {
+func (x *UpdateCertificateLabelRequest) GetID() uint64 {
 	if x != nil {
-		return x.Revoked
+		return x.ID
 	}
-	return nil
+	return 0
 }
 
-// RevokedCertificatesResponse returns Revoked Certificates list
-type RevokedCertificatesResponse struct {
+func (x *UpdateCertificateLabelRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+// GetCertificateRequest specifies certificate request by ID or issuer key identifier
+type GetCertificateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RevokedCertificates []*RevokedCertificate `protobuf:"bytes,1,rep,name=RevokedCertificates,proto3" json:"RevokedCertificates,omitempty"`
+	// ID specifies certificate ID.
+	// If it's not set, then SKID must be provided
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// SKID specifies Subject Key ID to search
+	SKID string `protobuf:"bytes,2,opt,name=SKID,proto3" json:"SKID,omitempty"`
+	// IssuerSerial specifies Issuer Key ID and certificate serial number to search
+	IssuerSerial *IssuerSerial `protobuf:"bytes,3,opt,name=IssuerSerial,proto3" json:"IssuerSerial,omitempty"`
 }
 
-func (x *RevokedCertificatesResponse) Reset() {
-	*x = RevokedCertificatesResponse{}
+func (x *GetCertificateRequest) Reset() {
+	*x = GetCertificateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1034,13 +1299,13 @@ func (x *RevokedCertificatesResponse) Reset() {
 	}
 }
 
-func (x *RevokedCertificatesResponse) String() string {
+func (x *GetCertificateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RevokedCertificatesResponse) ProtoMessage() {}
+func (*GetCertificateRequest) ProtoMessage() {}
 
-func (x *RevokedCertificatesResponse) ProtoReflect() protoreflect.Message {
+func (x *GetCertificateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1052,30 +1317,48 @@ func (x *RevokedCertificatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RevokedCertificatesResponse.ProtoReflect.Descriptor instead.
-func (*RevokedCertificatesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetCertificateRequest.ProtoReflect.Descriptor instead.
+func (*GetCertificateRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *RevokedCertificatesResponse) GetRevokedCertificates() []*RevokedCertificate {
+func (x *GetCertificateRequest) GetID() uint64 {
 	if x != nil {
-		return x.RevokedCertificates
+		return x.ID
+	}
+	return 0
+}
+
+func (x *GetCertificateRequest) GetSKID() string {
+	if x != nil {
+		return x.SKID
+	}
+	return ""
+}
+
+func (x *GetCertificateRequest) GetIssuerSerial() *IssuerSerial {
+	if x != nil {
+		return x.IssuerSerial
 	}
 	return nil
 }
 
-// PublishCrlsRequest allows to publish CRLs on demand
-type PublishCrlsRequest struct {
+// GetCrlRequest specifies CRL request by IKID
+type GetCrlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// IKID specifies Issuer, or empty to publish for all issuers
+	// IKID specifies Issuer
 	IKID string `protobuf:"bytes,1,opt,name=IKID,proto3" json:"IKID,omitempty"`
+	// Delta specifies to return delta CRL
+	Delta bool `protobuf:"varint,2,opt,name=Delta,proto3" json:"Delta,omitempty"`
+	// Shard specifies to return CRL of the shard
+	Shard uint32 `protobuf:"varint,3,opt,name=Shard,proto3" json:"Shard,omitempty"`
 }
 
-func (x *PublishCrlsRequest) Reset() {
-	*x = PublishCrlsRequest{}
+func (x *GetCrlRequest) Reset() {
+	*x = GetCrlRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1083,13 +1366,13 @@ func (x *PublishCrlsRequest) Reset() {
 	}
 }
 
-func (x *PublishCrlsRequest) String() string {
+func (x *GetCrlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublishCrlsRequest) ProtoMessage() {}
+func (*GetCrlRequest) ProtoMessage() {}
 
-func (x *PublishCrlsRequest) ProtoReflect() protoreflect.Message {
+func (x *GetCrlRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1101,29 +1384,47 @@ func (x *PublishCrlsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublishCrlsRequest.ProtoReflect.Descriptor instead.
-func (*PublishCrlsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetCrlRequest.ProtoReflect.Descriptor instead.
+func (*GetCrlRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *PublishCrlsRequest) GetIKID() string {
+func (x *GetCrlRequest) GetIKID() string {
 	if x != nil {
 		return x.IKID
 	}
 	return ""
 }
 
-// CrlsResponse returns published CRLs
-type CrlsResponse struct {
+func (x *GetCrlRequest) GetDelta() bool {
+	if x != nil {
+		return x.Delta
+	}
+	return false
+}
+
+func (x *GetCrlRequest) GetShard() uint32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+type ListByIssuerRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Crls []*Crl `protobuf:"bytes,1,rep,name=Crls,proto3" json:"Crls,omitempty"`
+	// Limit specifies the limit to return
+	Limit int64 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	// After specifies certificate ID to start after
+	After uint64 `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
+	// IKID specifies Issuer Key ID to search
+	IKID string `protobuf:"bytes,3,opt,name=IKID,proto3" json:"IKID,omitempty"`
 }
 
-func (x *CrlsResponse) Reset() {
-	*x = CrlsResponse{}
+func (x *ListByIssuerRequest) Reset() {
+	*x = ListByIssuerRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1131,13 +1432,13 @@ func (x *CrlsResponse) Reset() {
 	}
 }
 
-func (x *CrlsResponse) String() string {
+func (x *ListByIssuerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CrlsResponse) ProtoMessage() {}
+func (*ListByIssuerRequest) ProtoMessage() {}
 
-func (x *CrlsResponse) ProtoReflect() protoreflect.Message {
+func (x *ListByIssuerRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1149,29 +1450,51 @@ func (x *CrlsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CrlsResponse.ProtoReflect.Descriptor instead.
-func (*CrlsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListByIssuerRequest.ProtoReflect.Descriptor instead.
+func (*ListByIssuerRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *CrlsResponse) GetCrls() []*Crl {
+func (x *ListByIssuerRequest) GetLimit() int64 {
 	if x != nil {
-		return x.Crls
+		return x.Limit
 	}
-	return nil
+	return 0
 }
 
-// CrlResponse returns CRL
-type CrlResponse struct {
+func (x *ListByIssuerRequest) GetAfter() uint64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *ListByIssuerRequest) GetIKID() string {
+	if x != nil {
+		return x.IKID
+	}
+	return ""
+}
+
+// RevokeCertificateRequest specifies revocation request
+type RevokeCertificateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Crl *Crl `protobuf:"bytes,1,opt,name=Crl,proto3" json:"Crl,omitempty"`
+	// Id specifies certificate ID.
+	// If it's not set, then SKID must be provided
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// SKID specifies Subject Key ID to search
+	SKID string `protobuf:"bytes,2,opt,name=SKID,proto3" json:"SKID,omitempty"`
+	// IssuerSerial specifies Issuer Key ID and certificate serial number to search
+	IssuerSerial *IssuerSerial `protobuf:"bytes,3,opt,name=IssuerSerial,proto3" json:"IssuerSerial,omitempty"`
+	// Reason for revocation
+	Reason Reason `protobuf:"varint,4,opt,name=Reason,proto3,enum=pb.Reason" json:"Reason,omitempty"`
 }
 
-func (x *CrlResponse) Reset() {
-	*x = CrlResponse{}
+func (x *RevokeCertificateRequest) Reset() {
+	*x = RevokeCertificateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1179,13 +1502,13 @@ func (x *CrlResponse) Reset() {
 	}
 }
 
-func (x *CrlResponse) String() string {
+func (x *RevokeCertificateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CrlResponse) ProtoMessage() {}
+func (*RevokeCertificateRequest) ProtoMessage() {}
 
-func (x *CrlResponse) ProtoReflect() protoreflect.Message {
+func (x *RevokeCertificateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1197,29 +1520,53 @@ func (x *CrlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CrlResponse.ProtoReflect.Descriptor instead.
-func (*CrlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RevokeCertificateRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCertificateRequest) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *CrlResponse) GetCrl() *Crl {
+func (x *RevokeCertificateRequest) GetID() uint64 {
 	if x != nil {
-		return x.Crl
+		return x.ID
 	}
-	return nil
+	return 0
 }
 
-type OCSPRequest struct {
+func (x *RevokeCertificateRequest) GetSKID() string {
+	if x != nil {
+		return x.SKID
+	}
+	return ""
+}
+
+func (x *RevokeCertificateRequest) GetIssuerSerial() *IssuerSerial {
+	if x != nil {
+		return x.IssuerSerial
+	}
+	return nil
+}
+
+func (x *RevokeCertificateRequest) GetReason() Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return Reason_UNSPECIFIED
+}
+
+// CertificateResponse returns Certificate
+type CertificateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Der provides DER encoded request
-	Der []byte `protobuf:"bytes,1,opt,name=Der,proto3" json:"Der,omitempty"`
+	Certificate *Certificate `protobuf:"bytes,1,opt,name=Certificate,proto3" json:"Certificate,omitempty"`
+	// Warnings provides the results of pre-issuance linting,
+	// that did not block the issuance
+	Warnings []*LintResult `protobuf:"bytes,2,rep,name=Warnings,proto3" json:"Warnings,omitempty"`
 }
 
-func (x *OCSPRequest) Reset() {
-	*x = OCSPRequest{}
+func (x *CertificateResponse) Reset() {
+	*x = CertificateResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1227,13 +1574,13 @@ func (x *OCSPRequest) Reset() {
 	}
 }
 
-func (x *OCSPRequest) String() string {
+func (x *CertificateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OCSPRequest) ProtoMessage() {}
+func (*CertificateResponse) ProtoMessage() {}
 
-func (x *OCSPRequest) ProtoReflect() protoreflect.Message {
+func (x *CertificateResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1245,30 +1592,43 @@ func (x *OCSPRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OCSPRequest.ProtoReflect.Descriptor instead.
-func (*OCSPRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CertificateResponse.ProtoReflect.Descriptor instead.
+func (*CertificateResponse) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *OCSPRequest) GetDer() []byte {
+func (x *CertificateResponse) GetCertificate() *Certificate {
 	if x != nil {
-		return x.Der
+		return x.Certificate
 	}
 	return nil
 }
 
-// OCSPResponse returns OCSP
-type OCSPResponse struct {
+func (x *CertificateResponse) GetWarnings() []*LintResult {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// LintResult provides the result of pre-issuance linting
+type LintResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Der provides DER encoded response
-	Der []byte `protobuf:"bytes,1,opt,name=Der,proto3" json:"Der,omitempty"`
+	// Name of the lint
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Source of the lint, such as RFC5280 or CABF_BR
+	Source string `protobuf:"bytes,2,opt,name=Source,proto3" json:"Source,omitempty"`
+	// Severity of the result: notice, warn, error, fatal
+	Severity string `protobuf:"bytes,3,opt,name=Severity,proto3" json:"Severity,omitempty"`
+	// Details of the result
+	Details string `protobuf:"bytes,4,opt,name=Details,proto3" json:"Details,omitempty"`
 }
 
-func (x *OCSPResponse) Reset() {
-	*x = OCSPResponse{}
+func (x *LintResult) Reset() {
+	*x = LintResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1276,13 +1636,13 @@ func (x *OCSPResponse) Reset() {
 	}
 }
 
-func (x *OCSPResponse) String() string {
+func (x *LintResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OCSPResponse) ProtoMessage() {}
+func (*LintResult) ProtoMessage() {}
 
-func (x *OCSPResponse) ProtoReflect() protoreflect.Message {
+func (x *LintResult) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1294,34 +1654,50 @@ func (x *OCSPResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OCSPResponse.ProtoReflect.Descriptor instead.
-func (*OCSPResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use LintResult.ProtoReflect.Descriptor instead.
+func (*LintResult) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *OCSPResponse) GetDer() []byte {
+func (x *LintResult) GetName() string {
 	if x != nil {
-		return x.Der
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-// ListOrgsCertificatesRequest specifies a certificates request by Org ID
-type ListOrgCertificatesRequest struct {
+func (x *LintResult) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *LintResult) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LintResult) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+// CertificatesResponse returns Certificates list
+type CertificatesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Limit specifies the limit to return
-	Limit int64 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
-	// After specifies certificate ID to start after
-	After uint64 `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
-	// OrgID specifies the Org ID.
-	OrgID uint64 `protobuf:"varint,3,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	Certificates []*Certificate `protobuf:"bytes,1,rep,name=Certificates,proto3" json:"Certificates,omitempty"`
 }
 
-func (x *ListOrgCertificatesRequest) Reset() {
-	*x = ListOrgCertificatesRequest{}
+func (x *CertificatesResponse) Reset() {
+	*x = CertificatesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_ca_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1329,13 +1705,13 @@ func (x *ListOrgCertificatesRequest) Reset() {
 	}
 }
 
-func (x *ListOrgCertificatesRequest) String() string {
+func (x *CertificatesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrgCertificatesRequest) ProtoMessage() {}
+func (*CertificatesResponse) ProtoMessage() {}
 
-func (x *ListOrgCertificatesRequest) ProtoReflect() protoreflect.Message {
+func (x *CertificatesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_ca_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1347,120 +1723,883 @@ func (x *ListOrgCertificatesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrgCertificatesRequest.ProtoReflect.Descriptor instead.
-func (*ListOrgCertificatesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CertificatesResponse.ProtoReflect.Descriptor instead.
+func (*CertificatesResponse) Descriptor() ([]byte, []int) {
 	return file_ca_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *ListOrgCertificatesRequest) GetLimit() int64 {
+func (x *CertificatesResponse) GetCertificates() []*Certificate {
 	if x != nil {
-		return x.Limit
+		return x.Certificates
 	}
-	return 0
+	return nil
 }
 
-func (x *ListOrgCertificatesRequest) GetAfter() uint64 {
+// RevokedCertificateResponse returns Revoked Certificate
+type RevokedCertificateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Revoked *RevokedCertificate `protobuf:"bytes,1,opt,name=Revoked,proto3" json:"Revoked,omitempty"`
+}
+
+func (x *RevokedCertificateResponse) Reset() {
+	*x = RevokedCertificateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokedCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokedCertificateResponse) ProtoMessage() {}
+
+func (x *RevokedCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokedCertificateResponse.ProtoReflect.Descriptor instead.
+func (*RevokedCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RevokedCertificateResponse) GetRevoked() *RevokedCertificate {
 	if x != nil {
-		return x.After
+		return x.Revoked
 	}
-	return 0
+	return nil
 }
 
-func (x *ListOrgCertificatesRequest) GetOrgID() uint64 {
+// RevokedCertificatesResponse returns Revoked Certificates list
+type RevokedCertificatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RevokedCertificates []*RevokedCertificate `protobuf:"bytes,1,rep,name=RevokedCertificates,proto3" json:"RevokedCertificates,omitempty"`
+}
+
+func (x *RevokedCertificatesResponse) Reset() {
+	*x = RevokedCertificatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokedCertificatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokedCertificatesResponse) ProtoMessage() {}
+
+func (x *RevokedCertificatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokedCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*RevokedCertificatesResponse) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RevokedCertificatesResponse) GetRevokedCertificates() []*RevokedCertificate {
 	if x != nil {
-		return x.OrgID
+		return x.RevokedCertificates
 	}
-	return 0
+	return nil
 }
 
-// RegisterProfileRequest specifies a request to register a persisted profile
-type RegisterProfileRequest struct {
+// PublishCrlsRequest allows to publish CRLs on demand
+type PublishCrlsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Label provides Profile label
-	Label string `protobuf:"bytes,1,opt,name=Label,proto3" json:"Label,omitempty"`
-	// Config is yaml encoded Profile configuration
-	Config []byte `protobuf:"bytes,2,opt,name=Config,proto3" json:"Config,omitempty"`
+	// IKID specifies Issuer, or empty to publish for all issuers
+	IKID string `protobuf:"bytes,1,opt,name=IKID,proto3" json:"IKID,omitempty"`
+}
+
+func (x *PublishCrlsRequest) Reset() {
+	*x = PublishCrlsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishCrlsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishCrlsRequest) ProtoMessage() {}
+
+func (x *PublishCrlsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishCrlsRequest.ProtoReflect.Descriptor instead.
+func (*PublishCrlsRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PublishCrlsRequest) GetIKID() string {
+	if x != nil {
+		return x.IKID
+	}
+	return ""
+}
+
+// CrlsResponse returns published CRLs
+type CrlsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Crls []*Crl `protobuf:"bytes,1,rep,name=Crls,proto3" json:"Crls,omitempty"`
+}
+
+func (x *CrlsResponse) Reset() {
+	*x = CrlsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CrlsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrlsResponse) ProtoMessage() {}
+
+func (x *CrlsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrlsResponse.ProtoReflect.Descriptor instead.
+func (*CrlsResponse) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CrlsResponse) GetCrls() []*Crl {
+	if x != nil {
+		return x.Crls
+	}
+	return nil
+}
+
+// CrlResponse returns CRL
+type CrlResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Crl *Crl `protobuf:"bytes,1,opt,name=Crl,proto3" json:"Crl,omitempty"`
+}
+
+func (x *CrlResponse) Reset() {
+	*x = CrlResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CrlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrlResponse) ProtoMessage() {}
+
+func (x *CrlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrlResponse.ProtoReflect.Descriptor instead.
+func (*CrlResponse) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CrlResponse) GetCrl() *Crl {
+	if x != nil {
+		return x.Crl
+	}
+	return nil
+}
+
+type OCSPRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Der provides DER encoded request
+	Der []byte `protobuf:"bytes,1,opt,name=Der,proto3" json:"Der,omitempty"`
+	// IKID optionally specifies Issuer Key ID of the responder,
+	// the request for certificates of other issuers is rejected
+	IKID string `protobuf:"bytes,2,opt,name=IKID,proto3" json:"IKID,omitempty"`
+}
+
+func (x *OCSPRequest) Reset() {
+	*x = OCSPRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OCSPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OCSPRequest) ProtoMessage() {}
+
+func (x *OCSPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OCSPRequest.ProtoReflect.Descriptor instead.
+func (*OCSPRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *OCSPRequest) GetDer() []byte {
+	if x != nil {
+		return x.Der
+	}
+	return nil
+}
+
+func (x *OCSPRequest) GetIKID() string {
+	if x != nil {
+		return x.IKID
+	}
+	return ""
+}
+
+// OCSPResponse returns OCSP
+type OCSPResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Der provides DER encoded response
+	Der []byte `protobuf:"bytes,1,opt,name=Der,proto3" json:"Der,omitempty"`
+}
+
+func (x *OCSPResponse) Reset() {
+	*x = OCSPResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OCSPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OCSPResponse) ProtoMessage() {}
+
+func (x *OCSPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OCSPResponse.ProtoReflect.Descriptor instead.
+func (*OCSPResponse) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *OCSPResponse) GetDer() []byte {
+	if x != nil {
+		return x.Der
+	}
+	return nil
+}
+
+// ListOrgsCertificatesRequest specifies a certificates request by Org ID
+type ListOrgCertificatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Limit specifies the limit to return
+	Limit int64 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	// After specifies certificate ID to start after
+	After uint64 `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
+	// OrgID specifies the Org ID.
+	OrgID uint64 `protobuf:"varint,3,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+}
+
+func (x *ListOrgCertificatesRequest) Reset() {
+	*x = ListOrgCertificatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOrgCertificatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrgCertificatesRequest) ProtoMessage() {}
+
+func (x *ListOrgCertificatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrgCertificatesRequest.ProtoReflect.Descriptor instead.
+func (*ListOrgCertificatesRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListOrgCertificatesRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrgCertificatesRequest) GetAfter() uint64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *ListOrgCertificatesRequest) GetOrgID() uint64 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+// RegisterProfileRequest specifies a request to register a persisted profile
+type RegisterProfileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Label provides Profile label
+	Label string `protobuf:"bytes,1,opt,name=Label,proto3" json:"Label,omitempty"`
+	// Config is yaml encoded Profile configuration
+	Config []byte `protobuf:"bytes,2,opt,name=Config,proto3" json:"Config,omitempty"`
+}
+
+func (x *RegisterProfileRequest) Reset() {
+	*x = RegisterProfileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterProfileRequest) ProtoMessage() {}
+
+func (x *RegisterProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterProfileRequest.ProtoReflect.Descriptor instead.
+func (*RegisterProfileRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RegisterProfileRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *RegisterProfileRequest) GetConfig() []byte {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type ListIssuersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Limit specifies the limit to return
+	Limit int64 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	// After specifies certificate ID to start after
+	After uint64 `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
+	// Bundle specifies to return entire chain
+	Bundle bool `protobuf:"varint,3,opt,name=Bundle,proto3" json:"Bundle,omitempty"`
+}
+
+func (x *ListIssuersRequest) Reset() {
+	*x = ListIssuersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListIssuersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIssuersRequest) ProtoMessage() {}
+
+func (x *ListIssuersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIssuersRequest.ProtoReflect.Descriptor instead.
+func (*ListIssuersRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListIssuersRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListIssuersRequest) GetAfter() uint64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *ListIssuersRequest) GetBundle() bool {
+	if x != nil {
+		return x.Bundle
+	}
+	return false
+}
+
+// ImportCertificateRequest specifies a request to import externally issued certificate
+type ImportCertificateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Pem provides PEM encoded certificate
+	Pem string `protobuf:"bytes,1,opt,name=Pem,proto3" json:"Pem,omitempty"`
+	// IssuersPem provides optional PEM encoded issuers
+	IssuersPem string `protobuf:"bytes,2,opt,name=IssuersPem,proto3" json:"IssuersPem,omitempty"`
+	// OrgID provides the ID of Organization that certificate belongs to
+	OrgID uint64 `protobuf:"varint,3,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	// Profile specifies an optional profile name, if not provided "external" is used
+	Profile string `protobuf:"bytes,4,opt,name=Profile,proto3" json:"Profile,omitempty"`
+	// Label is provided by a client
+	Label string `protobuf:"bytes,5,opt,name=Label,proto3" json:"Label,omitempty"`
+	// Metadata is provided by a client
+	Metadata map[string]string `protobuf:"bytes,6,rep,name=Metadata,proto3" json:"Metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ImportCertificateRequest) Reset() {
+	*x = ImportCertificateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportCertificateRequest) ProtoMessage() {}
+
+func (x *ImportCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportCertificateRequest.ProtoReflect.Descriptor instead.
+func (*ImportCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ImportCertificateRequest) GetPem() string {
+	if x != nil {
+		return x.Pem
+	}
+	return ""
+}
+
+func (x *ImportCertificateRequest) GetIssuersPem() string {
+	if x != nil {
+		return x.IssuersPem
+	}
+	return ""
+}
+
+func (x *ImportCertificateRequest) GetOrgID() uint64 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *ImportCertificateRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *ImportCertificateRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ImportCertificateRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// PublishStatusRequest specifies a request for the publish outbox status
+type PublishStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Status specifies to return jobs with the status: pending, failed, published.
+	// If empty, only the counts are returned.
+	Status string `protobuf:"bytes,1,opt,name=Status,proto3" json:"Status,omitempty"`
+	// Limit specifies the limit to return
+	Limit int64 `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	// After specifies job ID to start after
+	After uint64 `protobuf:"varint,3,opt,name=After,proto3" json:"After,omitempty"`
+}
+
+func (x *PublishStatusRequest) Reset() {
+	*x = PublishStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishStatusRequest) ProtoMessage() {}
+
+func (x *PublishStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishStatusRequest.ProtoReflect.Descriptor instead.
+func (*PublishStatusRequest) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PublishStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PublishStatusRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PublishStatusRequest) GetAfter() uint64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+// PublishJob provides the publish outbox job
+type PublishJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Kind of the published object: cert, crl, issuer
+	Kind string `protobuf:"bytes,2,opt,name=Kind,proto3" json:"Kind,omitempty"`
+	// IKID provides Issuer Key Identifier
+	IKID string `protobuf:"bytes,3,opt,name=IKID,proto3" json:"IKID,omitempty"`
+	// RefID provides the ID of the published certificate
+	RefID uint64 `protobuf:"varint,4,opt,name=RefID,proto3" json:"RefID,omitempty"`
+	// Status of the job: pending, failed, published
+	Status string `protobuf:"bytes,5,opt,name=Status,proto3" json:"Status,omitempty"`
+	// Attempts provides the number of publish attempts
+	Attempts int32 `protobuf:"varint,6,opt,name=Attempts,proto3" json:"Attempts,omitempty"`
+	// NextAttemptAt is the time of the next attempt
+	NextAttemptAt string `protobuf:"bytes,7,opt,name=NextAttemptAt,proto3" json:"NextAttemptAt,omitempty"`
+	// LastError provides the error of the last attempt
+	LastError string `protobuf:"bytes,8,opt,name=LastError,proto3" json:"LastError,omitempty"`
+	// Location provides the published location
+	Location  string `protobuf:"bytes,9,opt,name=Location,proto3" json:"Location,omitempty"`
+	CreatedAt string `protobuf:"bytes,10,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	UpdatedAt string `protobuf:"bytes,11,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+}
+
+func (x *PublishJob) Reset() {
+	*x = PublishJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ca_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishJob) ProtoMessage() {}
+
+func (x *PublishJob) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishJob.ProtoReflect.Descriptor instead.
+func (*PublishJob) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *PublishJob) GetID() uint64 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *PublishJob) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *PublishJob) GetIKID() string {
+	if x != nil {
+		return x.IKID
+	}
+	return ""
 }
 
-func (x *RegisterProfileRequest) Reset() {
-	*x = RegisterProfileRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_ca_proto_msgTypes[21]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *PublishJob) GetRefID() uint64 {
+	if x != nil {
+		return x.RefID
 	}
+	return 0
 }
 
-func (x *RegisterProfileRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *PublishJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
 }
 
-func (*RegisterProfileRequest) ProtoMessage() {}
+func (x *PublishJob) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
 
-func (x *RegisterProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ca_proto_msgTypes[21]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *PublishJob) GetNextAttemptAt() string {
+	if x != nil {
+		return x.NextAttemptAt
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use RegisterProfileRequest.ProtoReflect.Descriptor instead.
-func (*RegisterProfileRequest) Descriptor() ([]byte, []int) {
-	return file_ca_proto_rawDescGZIP(), []int{21}
+func (x *PublishJob) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
 }
 
-func (x *RegisterProfileRequest) GetLabel() string {
+func (x *PublishJob) GetLocation() string {
 	if x != nil {
-		return x.Label
+		return x.Location
 	}
 	return ""
 }
 
-func (x *RegisterProfileRequest) GetConfig() []byte {
+func (x *PublishJob) GetCreatedAt() string {
 	if x != nil {
-		return x.Config
+		return x.CreatedAt
 	}
-	return nil
+	return ""
 }
 
-type ListIssuersRequest struct {
+func (x *PublishJob) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+// PublishStatusResponse returns the publish outbox status
+type PublishStatusResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Limit specifies the limit to return
-	Limit int64 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
-	// After specifies certificate ID to start after
-	After uint64 `protobuf:"varint,2,opt,name=After,proto3" json:"After,omitempty"`
-	// Bundle specifies to return entire chain
-	Bundle bool `protobuf:"varint,3,opt,name=Bundle,proto3" json:"Bundle,omitempty"`
+	// Counts provides number of jobs by status
+	Counts map[string]int64 `protobuf:"bytes,1,rep,name=Counts,proto3" json:"Counts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Jobs   []*PublishJob    `protobuf:"bytes,2,rep,name=Jobs,proto3" json:"Jobs,omitempty"`
 }
 
-func (x *ListIssuersRequest) Reset() {
-	*x = ListIssuersRequest{}
+func (x *PublishStatusResponse) Reset() {
+	*x = PublishStatusResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_ca_proto_msgTypes[22]
+		mi := &file_ca_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListIssuersRequest) String() string {
+func (x *PublishStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListIssuersRequest) ProtoMessage() {}
+func (*PublishStatusResponse) ProtoMessage() {}
 
-func (x *ListIssuersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_ca_proto_msgTypes[22]
+func (x *PublishStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ca_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1471,262 +2610,496 @@ func (x *ListIssuersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListIssuersRequest.ProtoReflect.Descriptor instead.
-func (*ListIssuersRequest) Descriptor() ([]byte, []int) {
-	return file_ca_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *ListIssuersRequest) GetLimit() int64 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
+// Deprecated: Use PublishStatusResponse.ProtoReflect.Descriptor instead.
+func (*PublishStatusResponse) Descriptor() ([]byte, []int) {
+	return file_ca_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *ListIssuersRequest) GetAfter() uint64 {
+func (x *PublishStatusResponse) GetCounts() map[string]int64 {
 	if x != nil {
-		return x.After
+		return x.Counts
 	}
-	return 0
+	return nil
 }
 
-func (x *ListIssuersRequest) GetBundle() bool {
+func (x *PublishStatusResponse) GetJobs() []*PublishJob {
 	if x != nil {
-		return x.Bundle
+		return x.Jobs
 	}
-	return false
+	return nil
 }
 
 var File_ca_proto protoreflect.FileDescriptor
 
 var file_ca_proto_rawDesc = []byte{
 	0x0a, 0x08, 0x63, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x0a,
-	0x70, 0x6b, 0x69, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2e, 0x0a, 0x16, 0x43, 0x65,
-	0x72, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x3d, 0x0a, 0x11, 0x49, 0x73,
-	0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x22, 0x6f, 0x0a, 0x11, 0x43, 0x65, 0x72,
-	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x20,
-	0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x12, 0x24, 0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65,
-	0x64, 0x69, 0x61, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x22, 0xe8, 0x01, 0x0a, 0x0a, 0x49,
-	0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62,
-	0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12,
-	0x20, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
-	0x65, 0x12, 0x24, 0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74,
-	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d,
-	0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x50,
-	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x50,
-	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x70, 0x62,
-	0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x3f, 0x0a, 0x13, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07,
-	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
-	0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x49,
-	0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x22, 0x97, 0x04, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e, 0x43,
-	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x38, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x46, 0x6f, 0x72, 0x6d,
-	0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x6e,
-	0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x0d, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12,
-	0x20, 0x0a, 0x0b, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65,
-	0x6c, 0x12, 0x10, 0x0a, 0x03, 0x53, 0x41, 0x4e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03,
-	0x53, 0x41, 0x4e, 0x12, 0x29, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x58, 0x35, 0x30, 0x39, 0x53, 0x75,
-	0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x07, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14,
-	0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54,
-	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x18, 0x08, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x6f,
-	0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e,
-	0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x41,
-	0x66, 0x74, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4e, 0x6f, 0x74, 0x41,
-	0x66, 0x74, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x58, 0x35,
-	0x30, 0x39, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x45, 0x78, 0x74,
-	0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x44, 0x0a,
-	0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x28, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61,
-	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
-	0x22, 0x45, 0x0a, 0x1d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
-	0x69, 0x63, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49,
-	0x44, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x71, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x43, 0x65,
-	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44,
-	0x12, 0x12, 0x0a, 0x04, 0x53, 0x4b, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x53, 0x4b, 0x49, 0x44, 0x12, 0x34, 0x0a, 0x0c, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65,
-	0x72, 0x69, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e,
-	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x52, 0x0c, 0x49, 0x73,
-	0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x22, 0x23, 0x0a, 0x0d, 0x47, 0x65,
-	0x74, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x49,
-	0x4b, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x22,
-	0x55, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x79, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05,
-	0x41, 0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74,
-	0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x22, 0x98, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x70, 0x6b, 0x69, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2e, 0x0a, 0x16, 0x43, 0x65, 0x72, 0x74,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x3d, 0x0a, 0x11, 0x49, 0x73, 0x73, 0x75,
+	0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x22, 0x6f, 0x0a, 0x11, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x24,
+	0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x22, 0xe8, 0x01, 0x0a, 0x0a, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x20, 0x0a,
+	0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12,
+	0x24, 0x0a, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x74, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x49,
+	0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x22, 0x3f, 0x0a, 0x13, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x49, 0x73,
+	0x73, 0x75, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x62,
+	0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x72, 0x73, 0x22, 0xbf, 0x04, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x38, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x0d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12,
+	0x10, 0x0a, 0x03, 0x53, 0x41, 0x4e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x53, 0x41,
+	0x4e, 0x12, 0x29, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x58, 0x35, 0x30, 0x39, 0x53, 0x75, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x52, 0x07, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x6f, 0x74, 0x42,
+	0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e, 0x6f, 0x74,
+	0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66, 0x74,
+	0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66, 0x74,
+	0x65, 0x72, 0x12, 0x31, 0x0a, 0x0a, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x58, 0x35, 0x30, 0x39,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x44, 0x0a, 0x08, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x26, 0x0a, 0x0e, 0x49, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x4b, 0x65, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x49, 0x64, 0x65, 0x6d, 0x70,
+	0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x51, 0x0a, 0x17, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x36, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x08, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x15, 0x53, 0x69,
+	0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x31, 0x0a, 0x0b, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52,
+	0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x08, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e,
+	0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x08, 0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e,
+	0x67, 0x73, 0x22, 0x4f, 0x0a, 0x18, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33,
+	0x0a, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x22, 0xe9, 0x03, 0x0a, 0x1a, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x10,
+	0x0a, 0x03, 0x53, 0x41, 0x4e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x53, 0x41, 0x4e,
+	0x12, 0x29, 0x0a, 0x07, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x58, 0x35, 0x30, 0x39, 0x53, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x52, 0x07, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4f,
+	0x72, 0x67, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x4f, 0x72, 0x67, 0x49,
+	0x44, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x12, 0x48, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74,
 	0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2b, 0x0a, 0x06, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x70, 0x62,
+	0x2e, 0x4b, 0x65, 0x79, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x52, 0x06, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e,
+	0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65,
+	0x6e, 0x74, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xc1, 0x01, 0x0a, 0x1b, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x31, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x4b, 0x65, 0x79, 0x42, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x57, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x4c,
+	0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x08, 0x57, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x73, 0x22, 0xb3, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x12,
+	0x1c, 0x0a, 0x09, 0x4e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x4e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x11, 0x52, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x50, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x50, 0x72, 0x65, 0x64,
+	0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x47, 0x72, 0x61, 0x63, 0x65,
+	0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x47, 0x72,
+	0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x22, 0x87, 0x02, 0x0a, 0x17, 0x52, 0x65,
+	0x6b, 0x65, 0x79, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x49, 0x44, 0x12, 0x38, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x70,
+	0x62, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x52, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x6f, 0x74,
+	0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e, 0x6f,
+	0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4e, 0x6f, 0x74, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x11, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x50, 0x72, 0x65,
+	0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x50, 0x72, 0x65, 0x64, 0x65, 0x63, 0x65, 0x73, 0x73, 0x6f,
+	0x72, 0x12, 0x20, 0x0a, 0x0b, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x22, 0x45, 0x0a, 0x1d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x71, 0x0a, 0x15, 0x47, 0x65,
+	0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
 	0x02, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x4b, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x04, 0x53, 0x4b, 0x49, 0x44, 0x12, 0x34, 0x0a, 0x0c, 0x49, 0x73, 0x73, 0x75, 0x65,
 	0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
 	0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x52,
-	0x0c, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12, 0x22, 0x0a,
-	0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e,
-	0x70, 0x62, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f,
-	0x6e, 0x22, 0x48, 0x0a, 0x13, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
-	0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0b,
-	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x22, 0x4b, 0x0a, 0x14, 0x43,
-	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x0c, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43,
-	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x22, 0x4e, 0x0a, 0x1a, 0x52, 0x65, 0x76, 0x6f,
-	0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76,
-	0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52,
-	0x07, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x22, 0x67, 0x0a, 0x1b, 0x52, 0x65, 0x76, 0x6f,
-	0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x13, 0x52, 0x65, 0x76, 0x6f, 0x6b,
-	0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
-	0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x13, 0x52, 0x65,
-	0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x73, 0x22, 0x28, 0x0a, 0x12, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x72, 0x6c, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x22, 0x2b, 0x0a, 0x0c, 0x43,
-	0x72, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x43,
-	0x72, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x70, 0x62, 0x2e, 0x43,
-	0x72, 0x6c, 0x52, 0x04, 0x43, 0x72, 0x6c, 0x73, 0x22, 0x28, 0x0a, 0x0b, 0x43, 0x72, 0x6c, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x03, 0x43, 0x72, 0x6c, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x52, 0x03, 0x43,
-	0x72, 0x6c, 0x22, 0x1f, 0x0a, 0x0b, 0x4f, 0x43, 0x53, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x10, 0x0a, 0x03, 0x44, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03,
-	0x44, 0x65, 0x72, 0x22, 0x20, 0x0a, 0x0c, 0x4f, 0x43, 0x53, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x44, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x03, 0x44, 0x65, 0x72, 0x22, 0x5e, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x67,
-	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x66, 0x74,
-	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12,
-	0x14, 0x0a, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
-	0x4f, 0x72, 0x67, 0x49, 0x44, 0x22, 0x46, 0x0a, 0x16, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
-	0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x58, 0x0a,
-	0x12, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x66, 0x74,
-	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12,
-	0x16, 0x0a, 0x06, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x06, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2a, 0x28, 0x0a, 0x0c, 0x49, 0x73, 0x73, 0x75, 0x65,
-	0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x52, 0x43, 0x48, 0x49,
-	0x56, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10,
-	0x01, 0x32, 0xa1, 0x09, 0x0a, 0x02, 0x43, 0x41, 0x12, 0x3c, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x66,
-	0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72,
-	0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x50, 0x72, 0x6f,
-	0x66, 0x69, 0x6c, 0x65, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x49, 0x73, 0x73,
-	0x75, 0x65, 0x72, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e,
-	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0b,
-	0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x70, 0x62,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48,
-	0x0a, 0x0f, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
-	0x65, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
-	0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x43,
-	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x62, 0x2e,
-	0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x2e, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x43, 0x52, 0x4c, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e,
-	0x47, 0x65, 0x74, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e,
-	0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x2f, 0x0a, 0x08, 0x53, 0x69, 0x67, 0x6e, 0x4f, 0x43, 0x53, 0x50, 0x12, 0x0f, 0x2e, 0x70,
-	0x62, 0x2e, 0x4f, 0x43, 0x53, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e,
-	0x70, 0x62, 0x2e, 0x4f, 0x43, 0x53, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x53, 0x0a, 0x11, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69,
-	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f,
-	0x6b, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
-	0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x0b, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73,
-	0x68, 0x43, 0x72, 0x6c, 0x73, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69,
-	0x73, 0x68, 0x43, 0x72, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e,
-	0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x51, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x67, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
-	0x73, 0x74, 0x4f, 0x72, 0x67, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65,
+	0x0c, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x22, 0x4f, 0x0a,
+	0x0d, 0x47, 0x65, 0x74, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x4b,
+	0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x68, 0x61, 0x72,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x53, 0x68, 0x61, 0x72, 0x64, 0x22, 0x55,
+	0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x79, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41,
+	0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x49, 0x4b, 0x49, 0x44, 0x22, 0x98, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02,
+	0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x4b, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x53, 0x4b, 0x49, 0x44, 0x12, 0x34, 0x0a, 0x0c, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72,
+	0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70,
+	0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x52, 0x0c,
+	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x06,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x22, 0x74, 0x0a, 0x13, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70,
+	0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x57, 0x61,
+	0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70,
+	0x62, 0x2e, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x08, 0x57, 0x61,
+	0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x6e, 0x0a, 0x0a, 0x4c, 0x69, 0x6e, 0x74, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x44,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x4b, 0x0a, 0x14, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33,
+	0x0a, 0x0c, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x73, 0x22, 0x4e, 0x0a, 0x1a, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x30, 0x0a, 0x07, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x07, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x64, 0x22, 0x67, 0x0a, 0x1b, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65,
 	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
-	0x73, 0x74, 0x42, 0x79, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a,
-	0x17, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69,
-	0x73, 0x74, 0x42, 0x79, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65,
-	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x65,
+	0x73, 0x65, 0x12, 0x48, 0x0a, 0x13, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x13, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x22, 0x28, 0x0a, 0x12,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x72, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x22, 0x2b, 0x0a, 0x0c, 0x43, 0x72, 0x6c, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x43, 0x72, 0x6c, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x52, 0x04, 0x43,
+	0x72, 0x6c, 0x73, 0x22, 0x28, 0x0a, 0x0b, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x19, 0x0a, 0x03, 0x43, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x07, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x52, 0x03, 0x43, 0x72, 0x6c, 0x22, 0x33, 0x0a,
+	0x0b, 0x4f, 0x43, 0x53, 0x50, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x44, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x44, 0x65, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49, 0x4b,
+	0x49, 0x44, 0x22, 0x20, 0x0a, 0x0c, 0x4f, 0x43, 0x53, 0x50, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x44, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x03, 0x44, 0x65, 0x72, 0x22, 0x5e, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x67, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x14,
+	0x0a, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x4f,
+	0x72, 0x67, 0x49, 0x44, 0x22, 0x46, 0x0a, 0x16, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x58, 0x0a, 0x12,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x16,
+	0x0a, 0x06, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x22, 0x97, 0x02, 0x0a, 0x18, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x50, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x50, 0x65, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73,
+	0x50, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x49, 0x73, 0x73, 0x75, 0x65,
+	0x72, 0x73, 0x50, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x4f, 0x72, 0x67, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x46, 0x0a, 0x08, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e,
+	0x70, 0x62, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x5a, 0x0a, 0x14, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x66, 0x74, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22, 0xaa, 0x02, 0x0a,
+	0x0a, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x4a, 0x6f, 0x62, 0x12, 0x0e, 0x0a, 0x02, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x4b,
+	0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x49, 0x4b, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x49,
+	0x4b, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x52, 0x65, 0x66, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x52, 0x65, 0x66, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x24, 0x0a,
+	0x0d, 0x4e, 0x65, 0x78, 0x74, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x41, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x4e, 0x65, 0x78, 0x74, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70,
+	0x74, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a,
+	0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xb5, 0x01, 0x0a, 0x15, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x12, 0x22, 0x0a, 0x04, 0x4a, 0x6f, 0x62, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x4a, 0x6f, 0x62,
+	0x52, 0x04, 0x4a, 0x6f, 0x62, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x2a, 0x28, 0x0a, 0x0c, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x52, 0x43, 0x48, 0x49, 0x56, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x2a, 0x28, 0x0a, 0x0f, 0x4b,
+	0x65, 0x79, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x0a,
+	0x0a, 0x06, 0x50, 0x4b, 0x43, 0x53, 0x31, 0x32, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x4b,
+	0x43, 0x53, 0x38, 0x10, 0x01, 0x32, 0xb6, 0x12, 0x0a, 0x02, 0x43, 0x41, 0x12, 0x5b, 0x0a, 0x0b,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x2e, 0x70, 0x62,
+	0x2e, 0x43, 0x65, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72,
+	0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19,
+	0x12, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x63, 0x73, 0x72, 0x2f, 0x70, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x49, 0x0a, 0x09, 0x47, 0x65, 0x74,
+	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75,
+	0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x15, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x12, 0x0d, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x72, 0x12, 0x56, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73, 0x75,
+	0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73,
+	0x75, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62,
+	0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f, 0x76,
+	0x31, 0x2f, 0x63, 0x61, 0x2f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x12, 0x5e, 0x0a, 0x0f,
+	0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12,
+	0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62,
+	0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x3a, 0x01, 0x2a, 0x22,
+	0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x12, 0x59, 0x0a, 0x0e,
+	0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x19,
+	0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x13, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0d, 0x12, 0x0b, 0x2f, 0x76, 0x31, 0x2f,
+	0x63, 0x61, 0x2f, 0x63, 0x65, 0x72, 0x74, 0x12, 0x40, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x43, 0x52,
+	0x4c, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x12, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f,
+	0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x63, 0x72, 0x6c, 0x12, 0x45, 0x0a, 0x08, 0x53, 0x69, 0x67,
+	0x6e, 0x4f, 0x43, 0x53, 0x50, 0x12, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x4f, 0x43, 0x53, 0x50, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x4f, 0x43, 0x53, 0x50,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10,
+	0x3a, 0x01, 0x2a, 0x22, 0x0b, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x6f, 0x63, 0x73, 0x70,
+	0x12, 0x6b, 0x0a, 0x11, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d,
+	0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x12, 0x57, 0x0a,
+	0x0b, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x72, 0x6c, 0x73, 0x12, 0x16, 0x2e, 0x70,
+	0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x43, 0x72, 0x6c, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x72, 0x6c, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x3a, 0x01,
+	0x2a, 0x22, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x2f, 0x63, 0x72, 0x6c, 0x73, 0x12, 0x72, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72,
+	0x67, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1e, 0x2e,
+	0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x72, 0x67, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x12,
+	0x19, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x6f, 0x72, 0x67, 0x73, 0x2f, 0x7b, 0x4f, 0x72,
+	0x67, 0x49, 0x44, 0x7d, 0x2f, 0x63, 0x65, 0x72, 0x74, 0x73, 0x12, 0x5b, 0x0a, 0x10, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12, 0x17,
+	0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x79, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x14, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0e, 0x12, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x63,
+	0x61, 0x2f, 0x63, 0x65, 0x72, 0x74, 0x73, 0x12, 0x6b, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x79, 0x49, 0x73,
+	0x73, 0x75, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x62,
+	0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x16, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x72, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x64, 0x12, 0x6d, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x65,
 	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x21,
 	0x2e, 0x70, 0x62, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
 	0x69, 0x63, 0x61, 0x74, 0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x14,
-	0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x49, 0x73, 0x73,
-	0x75, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x73,
-	0x73, 0x75, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70,
-	0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x17, 0x52, 0x65, 0x67, 0x69, 0x73,
-	0x74, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x49, 0x73, 0x73, 0x75,
-	0x65, 0x72, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e,
-	0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00,
-	0x12, 0x41, 0x0a, 0x16, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x67,
-	0x61, 0x74, 0x65, 0x64, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e,
-	0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66,
-	0x6f, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50,
-	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x67, 0x69,
-	0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x66,
-	0x69, 0x6c, 0x65, 0x22, 0x00, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x2d, 0x73, 0x65,
-	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x2f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x79, 0x2f, 0x61, 0x70,
-	0x69, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x11, 0x3a, 0x01, 0x2a, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x12, 0x69, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x64, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x70, 0x62,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x20, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x1a, 0x12, 0x18, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x64, 0x65, 0x6c,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x12, 0x73,
+	0x0a, 0x17, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x64, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53,
+	0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65,
+	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26, 0x3a, 0x01, 0x2a,
+	0x22, 0x21, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x2f, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x12, 0x6c, 0x0a, 0x16, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x44, 0x65,
+	0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x15, 0x2e,
+	0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65, 0x72,
+	0x49, 0x6e, 0x66, 0x6f, 0x22, 0x2b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x25, 0x3a, 0x01, 0x2a, 0x22,
+	0x20, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x73, 0x2f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x12, 0x5a, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01, 0x2a, 0x22, 0x0f, 0x2f, 0x76,
+	0x31, 0x2f, 0x63, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x64, 0x0a,
+	0x11, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x65,
+	0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x69, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x12, 0x66, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x17, 0x12, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x70, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x6b, 0x0a, 0x10, 0x53,
+	0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12,
+	0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70,
+	0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x73, 0x69,
+	0x67, 0x6e, 0x2f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x12, 0x53, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e,
+	0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x70, 0x62, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x28, 0x01, 0x30, 0x01, 0x12, 0x61, 0x0a,
+	0x10, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x12, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17,
+	0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x3a,
+	0x01, 0x2a, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x72, 0x65, 0x6e, 0x65, 0x77,
+	0x12, 0x61, 0x0a, 0x10, 0x52, 0x65, 0x6b, 0x65, 0x79, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6b, 0x65, 0x79, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x11, 0x3a, 0x01, 0x2a, 0x22, 0x0c, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x72, 0x65,
+	0x6b, 0x65, 0x79, 0x12, 0x79, 0x0a, 0x1a, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x41,
+	0x6e, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x12, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01, 0x2a, 0x22, 0x0f, 0x2f,
+	0x76, 0x31, 0x2f, 0x63, 0x61, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x42, 0x2d,
+	0x5a, 0x2b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x66, 0x66,
+	0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x2d, 0x73, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x2f,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x79, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1741,99 +3114,144 @@ func file_ca_proto_rawDescGZIP() []byte {
 	return file_ca_proto_rawDescData
 }
 
-var file_ca_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_ca_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_ca_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_ca_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
 var file_ca_proto_goTypes = []any{
 	(IssuerStatus)(0),                     // 0: pb.IssuerStatus
-	(*CertProfileInfoRequest)(nil),        // 1: pb.CertProfileInfoRequest
-	(*IssuerInfoRequest)(nil),             // 2: pb.IssuerInfoRequest
-	(*CertificateBundle)(nil),             // 3: pb.CertificateBundle
-	(*IssuerInfo)(nil),                    // 4: pb.IssuerInfo
-	(*IssuersInfoResponse)(nil),           // 5: pb.IssuersInfoResponse
-	(*SignCertificateRequest)(nil),        // 6: pb.SignCertificateRequest
-	(*UpdateCertificateLabelRequest)(nil), // 7: pb.UpdateCertificateLabelRequest
-	(*GetCertificateRequest)(nil),         // 8: pb.GetCertificateRequest
-	(*GetCrlRequest)(nil),                 // 9: pb.GetCrlRequest
-	(*ListByIssuerRequest)(nil),           // 10: pb.ListByIssuerRequest
-	(*RevokeCertificateRequest)(nil),      // 11: pb.RevokeCertificateRequest
-	(*CertificateResponse)(nil),           // 12: pb.CertificateResponse
-	(*CertificatesResponse)(nil),          // 13: pb.CertificatesResponse
-	(*RevokedCertificateResponse)(nil),    // 14: pb.RevokedCertificateResponse
-	(*RevokedCertificatesResponse)(nil),   // 15: pb.RevokedCertificatesResponse
-	(*PublishCrlsRequest)(nil),            // 16: pb.PublishCrlsRequest
-	(*CrlsResponse)(nil),                  // 17: pb.CrlsResponse
-	(*CrlResponse)(nil),                   // 18: pb.CrlResponse
-	(*OCSPRequest)(nil),                   // 19: pb.OCSPRequest
-	(*OCSPResponse)(nil),                  // 20: pb.OCSPResponse
-	(*ListOrgCertificatesRequest)(nil),    // 21: pb.ListOrgCertificatesRequest
-	(*RegisterProfileRequest)(nil),        // 22: pb.RegisterProfileRequest
-	(*ListIssuersRequest)(nil),            // 23: pb.ListIssuersRequest
-	nil,                                   // 24: pb.SignCertificateRequest.MetadataEntry
-	(EncodingFormat)(0),                   // 25: pb.EncodingFormat
-	(*X509Subject)(nil),                   // 26: pb.X509Subject
-	(*X509Extension)(nil),                 // 27: pb.X509Extension
-	(*IssuerSerial)(nil),                  // 28: pb.IssuerSerial
-	(Reason)(0),                           // 29: pb.Reason
-	(*Certificate)(nil),                   // 30: pb.Certificate
-	(*RevokedCertificate)(nil),            // 31: pb.RevokedCertificate
-	(*Crl)(nil),                           // 32: pb.Crl
-	(*CertProfile)(nil),                   // 33: pb.CertProfile
+	(KeyBundleFormat)(0),                  // 1: pb.KeyBundleFormat
+	(*CertProfileInfoRequest)(nil),        // 2: pb.CertProfileInfoRequest
+	(*IssuerInfoRequest)(nil),             // 3: pb.IssuerInfoRequest
+	(*CertificateBundle)(nil),             // 4: pb.CertificateBundle
+	(*IssuerInfo)(nil),                    // 5: pb.IssuerInfo
+	(*IssuersInfoResponse)(nil),           // 6: pb.IssuersInfoResponse
+	(*SignCertificateRequest)(nil),        // 7: pb.SignCertificateRequest
+	(*SignCertificatesRequest)(nil),       // 8: pb.SignCertificatesRequest
+	(*SignCertificateResult)(nil),         // 9: pb.SignCertificateResult
+	(*SignCertificatesResponse)(nil),      // 10: pb.SignCertificatesResponse
+	(*GenerateCertificateRequest)(nil),    // 11: pb.GenerateCertificateRequest
+	(*GenerateCertificateResponse)(nil),   // 12: pb.GenerateCertificateResponse
+	(*RenewCertificateRequest)(nil),       // 13: pb.RenewCertificateRequest
+	(*RekeyCertificateRequest)(nil),       // 14: pb.RekeyCertificateRequest
+	(*UpdateCertificateLabelRequest)(nil), // 15: pb.UpdateCertificateLabelRequest
+	(*GetCertificateRequest)(nil),         // 16: pb.GetCertificateRequest
+	(*GetCrlRequest)(nil),                 // 17: pb.GetCrlRequest
+	(*ListByIssuerRequest)(nil),           // 18: pb.ListByIssuerRequest
+	(*RevokeCertificateRequest)(nil),      // 19: pb.RevokeCertificateRequest
+	(*CertificateResponse)(nil),           // 20: pb.CertificateResponse
+	(*LintResult)(nil),                    // 21: pb.LintResult
+	(*CertificatesResponse)(nil),          // 22: pb.CertificatesResponse
+	(*RevokedCertificateResponse)(nil),    // 23: pb.RevokedCertificateResponse
+	(*RevokedCertificatesResponse)(nil),   // 24: pb.RevokedCertificatesResponse
+	(*PublishCrlsRequest)(nil),            // 25: pb.PublishCrlsRequest
+	(*CrlsResponse)(nil),                  // 26: pb.CrlsResponse
+	(*CrlResponse)(nil),                   // 27: pb.CrlResponse
+	(*OCSPRequest)(nil),                   // 28: pb.OCSPRequest
+	(*OCSPResponse)(nil),                  // 29: pb.OCSPResponse
+	(*ListOrgCertificatesRequest)(nil),    // 30: pb.ListOrgCertificatesRequest
+	(*RegisterProfileRequest)(nil),        // 31: pb.RegisterProfileRequest
+	(*ListIssuersRequest)(nil),            // 32: pb.ListIssuersRequest
+	(*ImportCertificateRequest)(nil),      // 33: pb.ImportCertificateRequest
+	(*PublishStatusRequest)(nil),          // 34: pb.PublishStatusRequest
+	(*PublishJob)(nil),                    // 35: pb.PublishJob
+	(*PublishStatusResponse)(nil),         // 36: pb.PublishStatusResponse
+	nil,                                   // 37: pb.SignCertificateRequest.MetadataEntry
+	nil,                                   // 38: pb.GenerateCertificateRequest.MetadataEntry
+	nil,                                   // 39: pb.ImportCertificateRequest.MetadataEntry
+	nil,                                   // 40: pb.PublishStatusResponse.CountsEntry
+	(EncodingFormat)(0),                   // 41: pb.EncodingFormat
+	(*X509Subject)(nil),                   // 42: pb.X509Subject
+	(*X509Extension)(nil),                 // 43: pb.X509Extension
+	(*Certificate)(nil),                   // 44: pb.Certificate
+	(*IssuerSerial)(nil),                  // 45: pb.IssuerSerial
+	(Reason)(0),                           // 46: pb.Reason
+	(*RevokedCertificate)(nil),            // 47: pb.RevokedCertificate
+	(*Crl)(nil),                           // 48: pb.Crl
+	(*CertProfile)(nil),                   // 49: pb.CertProfile
 }
 var file_ca_proto_depIdxs = []int32{
 	0,  // 0: pb.IssuerInfo.Status:type_name -> pb.IssuerStatus
-	4,  // 1: pb.IssuersInfoResponse.Issuers:type_name -> pb.IssuerInfo
-	25, // 2: pb.SignCertificateRequest.RequestFormat:type_name -> pb.EncodingFormat
-	26, // 3: pb.SignCertificateRequest.Subject:type_name -> pb.X509Subject
-	27, // 4: pb.SignCertificateRequest.Extensions:type_name -> pb.X509Extension
-	24, // 5: pb.SignCertificateRequest.Metadata:type_name -> pb.SignCertificateRequest.MetadataEntry
-	28, // 6: pb.GetCertificateRequest.IssuerSerial:type_name -> pb.IssuerSerial
-	28, // 7: pb.RevokeCertificateRequest.IssuerSerial:type_name -> pb.IssuerSerial
-	29, // 8: pb.RevokeCertificateRequest.Reason:type_name -> pb.Reason
-	30, // 9: pb.CertificateResponse.Certificate:type_name -> pb.Certificate
-	30, // 10: pb.CertificatesResponse.Certificates:type_name -> pb.Certificate
-	31, // 11: pb.RevokedCertificateResponse.Revoked:type_name -> pb.RevokedCertificate
-	31, // 12: pb.RevokedCertificatesResponse.RevokedCertificates:type_name -> pb.RevokedCertificate
-	32, // 13: pb.CrlsResponse.Crls:type_name -> pb.Crl
-	32, // 14: pb.CrlResponse.Crl:type_name -> pb.Crl
-	1,  // 15: pb.CA.ProfileInfo:input_type -> pb.CertProfileInfoRequest
-	2,  // 16: pb.CA.GetIssuer:input_type -> pb.IssuerInfoRequest
-	23, // 17: pb.CA.ListIssuers:input_type -> pb.ListIssuersRequest
-	6,  // 18: pb.CA.SignCertificate:input_type -> pb.SignCertificateRequest
-	8,  // 19: pb.CA.GetCertificate:input_type -> pb.GetCertificateRequest
-	9,  // 20: pb.CA.GetCRL:input_type -> pb.GetCrlRequest
-	19, // 21: pb.CA.SignOCSP:input_type -> pb.OCSPRequest
-	11, // 22: pb.CA.RevokeCertificate:input_type -> pb.RevokeCertificateRequest
-	16, // 23: pb.CA.PublishCrls:input_type -> pb.PublishCrlsRequest
-	21, // 24: pb.CA.ListOrgCertificates:input_type -> pb.ListOrgCertificatesRequest
-	10, // 25: pb.CA.ListCertificates:input_type -> pb.ListByIssuerRequest
-	10, // 26: pb.CA.ListRevokedCertificates:input_type -> pb.ListByIssuerRequest
-	7,  // 27: pb.CA.UpdateCertificateLabel:input_type -> pb.UpdateCertificateLabelRequest
-	23, // 28: pb.CA.ListDelegatedIssuers:input_type -> pb.ListIssuersRequest
-	6,  // 29: pb.CA.RegisterDelegatedIssuer:input_type -> pb.SignCertificateRequest
-	2,  // 30: pb.CA.ArchiveDelegatedIssuer:input_type -> pb.IssuerInfoRequest
-	22, // 31: pb.CA.RegisterProfile:input_type -> pb.RegisterProfileRequest
-	33, // 32: pb.CA.ProfileInfo:output_type -> pb.CertProfile
-	4,  // 33: pb.CA.GetIssuer:output_type -> pb.IssuerInfo
-	5,  // 34: pb.CA.ListIssuers:output_type -> pb.IssuersInfoResponse
-	12, // 35: pb.CA.SignCertificate:output_type -> pb.CertificateResponse
-	12, // 36: pb.CA.GetCertificate:output_type -> pb.CertificateResponse
-	18, // 37: pb.CA.GetCRL:output_type -> pb.CrlResponse
-	20, // 38: pb.CA.SignOCSP:output_type -> pb.OCSPResponse
-	14, // 39: pb.CA.RevokeCertificate:output_type -> pb.RevokedCertificateResponse
-	17, // 40: pb.CA.PublishCrls:output_type -> pb.CrlsResponse
-	13, // 41: pb.CA.ListOrgCertificates:output_type -> pb.CertificatesResponse
-	13, // 42: pb.CA.ListCertificates:output_type -> pb.CertificatesResponse
-	15, // 43: pb.CA.ListRevokedCertificates:output_type -> pb.RevokedCertificatesResponse
-	12, // 44: pb.CA.UpdateCertificateLabel:output_type -> pb.CertificateResponse
-	5,  // 45: pb.CA.ListDelegatedIssuers:output_type -> pb.IssuersInfoResponse
-	4,  // 46: pb.CA.RegisterDelegatedIssuer:output_type -> pb.IssuerInfo
-	4,  // 47: pb.CA.ArchiveDelegatedIssuer:output_type -> pb.IssuerInfo
-	33, // 48: pb.CA.RegisterProfile:output_type -> pb.CertProfile
-	32, // [32:49] is the sub-list for method output_type
-	15, // [15:32] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	5,  // 1: pb.IssuersInfoResponse.Issuers:type_name -> pb.IssuerInfo
+	41, // 2: pb.SignCertificateRequest.RequestFormat:type_name -> pb.EncodingFormat
+	42, // 3: pb.SignCertificateRequest.Subject:type_name -> pb.X509Subject
+	43, // 4: pb.SignCertificateRequest.Extensions:type_name -> pb.X509Extension
+	37, // 5: pb.SignCertificateRequest.Metadata:type_name -> pb.SignCertificateRequest.MetadataEntry
+	7,  // 6: pb.SignCertificatesRequest.Requests:type_name -> pb.SignCertificateRequest
+	44, // 7: pb.SignCertificateResult.Certificate:type_name -> pb.Certificate
+	21, // 8: pb.SignCertificateResult.Warnings:type_name -> pb.LintResult
+	9,  // 9: pb.SignCertificatesResponse.Results:type_name -> pb.SignCertificateResult
+	42, // 10: pb.GenerateCertificateRequest.Subject:type_name -> pb.X509Subject
+	38, // 11: pb.GenerateCertificateRequest.Metadata:type_name -> pb.GenerateCertificateRequest.MetadataEntry
+	1,  // 12: pb.GenerateCertificateRequest.Format:type_name -> pb.KeyBundleFormat
+	44, // 13: pb.GenerateCertificateResponse.Certificate:type_name -> pb.Certificate
+	1,  // 14: pb.GenerateCertificateResponse.Format:type_name -> pb.KeyBundleFormat
+	21, // 15: pb.GenerateCertificateResponse.Warnings:type_name -> pb.LintResult
+	41, // 16: pb.RekeyCertificateRequest.RequestFormat:type_name -> pb.EncodingFormat
+	45, // 17: pb.GetCertificateRequest.IssuerSerial:type_name -> pb.IssuerSerial
+	45, // 18: pb.RevokeCertificateRequest.IssuerSerial:type_name -> pb.IssuerSerial
+	46, // 19: pb.RevokeCertificateRequest.Reason:type_name -> pb.Reason
+	44, // 20: pb.CertificateResponse.Certificate:type_name -> pb.Certificate
+	21, // 21: pb.CertificateResponse.Warnings:type_name -> pb.LintResult
+	44, // 22: pb.CertificatesResponse.Certificates:type_name -> pb.Certificate
+	47, // 23: pb.RevokedCertificateResponse.Revoked:type_name -> pb.RevokedCertificate
+	47, // 24: pb.RevokedCertificatesResponse.RevokedCertificates:type_name -> pb.RevokedCertificate
+	48, // 25: pb.CrlsResponse.Crls:type_name -> pb.Crl
+	48, // 26: pb.CrlResponse.Crl:type_name -> pb.Crl
+	39, // 27: pb.ImportCertificateRequest.Metadata:type_name -> pb.ImportCertificateRequest.MetadataEntry
+	40, // 28: pb.PublishStatusResponse.Counts:type_name -> pb.PublishStatusResponse.CountsEntry
+	35, // 29: pb.PublishStatusResponse.Jobs:type_name -> pb.PublishJob
+	2,  // 30: pb.CA.ProfileInfo:input_type -> pb.CertProfileInfoRequest
+	3,  // 31: pb.CA.GetIssuer:input_type -> pb.IssuerInfoRequest
+	32, // 32: pb.CA.ListIssuers:input_type -> pb.ListIssuersRequest
+	7,  // 33: pb.CA.SignCertificate:input_type -> pb.SignCertificateRequest
+	16, // 34: pb.CA.GetCertificate:input_type -> pb.GetCertificateRequest
+	17, // 35: pb.CA.GetCRL:input_type -> pb.GetCrlRequest
+	28, // 36: pb.CA.SignOCSP:input_type -> pb.OCSPRequest
+	19, // 37: pb.CA.RevokeCertificate:input_type -> pb.RevokeCertificateRequest
+	25, // 38: pb.CA.PublishCrls:input_type -> pb.PublishCrlsRequest
+	30, // 39: pb.CA.ListOrgCertificates:input_type -> pb.ListOrgCertificatesRequest
+	18, // 40: pb.CA.ListCertificates:input_type -> pb.ListByIssuerRequest
+	18, // 41: pb.CA.ListRevokedCertificates:input_type -> pb.ListByIssuerRequest
+	15, // 42: pb.CA.UpdateCertificateLabel:input_type -> pb.UpdateCertificateLabelRequest
+	32, // 43: pb.CA.ListDelegatedIssuers:input_type -> pb.ListIssuersRequest
+	7,  // 44: pb.CA.RegisterDelegatedIssuer:input_type -> pb.SignCertificateRequest
+	3,  // 45: pb.CA.ArchiveDelegatedIssuer:input_type -> pb.IssuerInfoRequest
+	31, // 46: pb.CA.RegisterProfile:input_type -> pb.RegisterProfileRequest
+	33, // 47: pb.CA.ImportCertificate:input_type -> pb.ImportCertificateRequest
+	34, // 48: pb.CA.GetPublishStatus:input_type -> pb.PublishStatusRequest
+	8,  // 49: pb.CA.SignCertificates:input_type -> pb.SignCertificatesRequest
+	7,  // 50: pb.CA.SignCertificatesStream:input_type -> pb.SignCertificateRequest
+	13, // 51: pb.CA.RenewCertificate:input_type -> pb.RenewCertificateRequest
+	14, // 52: pb.CA.RekeyCertificate:input_type -> pb.RekeyCertificateRequest
+	11, // 53: pb.CA.GenerateAndSignCertificate:input_type -> pb.GenerateCertificateRequest
+	49, // 54: pb.CA.ProfileInfo:output_type -> pb.CertProfile
+	5,  // 55: pb.CA.GetIssuer:output_type -> pb.IssuerInfo
+	6,  // 56: pb.CA.ListIssuers:output_type -> pb.IssuersInfoResponse
+	20, // 57: pb.CA.SignCertificate:output_type -> pb.CertificateResponse
+	20, // 58: pb.CA.GetCertificate:output_type -> pb.CertificateResponse
+	27, // 59: pb.CA.GetCRL:output_type -> pb.CrlResponse
+	29, // 60: pb.CA.SignOCSP:output_type -> pb.OCSPResponse
+	23, // 61: pb.CA.RevokeCertificate:output_type -> pb.RevokedCertificateResponse
+	26, // 62: pb.CA.PublishCrls:output_type -> pb.CrlsResponse
+	22, // 63: pb.CA.ListOrgCertificates:output_type -> pb.CertificatesResponse
+	22, // 64: pb.CA.ListCertificates:output_type -> pb.CertificatesResponse
+	24, // 65: pb.CA.ListRevokedCertificates:output_type -> pb.RevokedCertificatesResponse
+	20, // 66: pb.CA.UpdateCertificateLabel:output_type -> pb.CertificateResponse
+	6,  // 67: pb.CA.ListDelegatedIssuers:output_type -> pb.IssuersInfoResponse
+	5,  // 68: pb.CA.RegisterDelegatedIssuer:output_type -> pb.IssuerInfo
+	5,  // 69: pb.CA.ArchiveDelegatedIssuer:output_type -> pb.IssuerInfo
+	49, // 70: pb.CA.RegisterProfile:output_type -> pb.CertProfile
+	20, // 71: pb.CA.ImportCertificate:output_type -> pb.CertificateResponse
+	36, // 72: pb.CA.GetPublishStatus:output_type -> pb.PublishStatusResponse
+	10, // 73: pb.CA.SignCertificates:output_type -> pb.SignCertificatesResponse
+	9,  // 74: pb.CA.SignCertificatesStream:output_type -> pb.SignCertificateResult
+	20, // 75: pb.CA.RenewCertificate:output_type -> pb.CertificateResponse
+	20, // 76: pb.CA.RekeyCertificate:output_type -> pb.CertificateResponse
+	12, // 77: pb.CA.GenerateAndSignCertificate:output_type -> pb.GenerateCertificateResponse
+	54, // [54:78] is the sub-list for method output_type
+	30, // [30:54] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_ca_proto_init() }
@@ -1916,7 +3334,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*UpdateCertificateLabelRequest); i {
+			switch v := v.(*SignCertificatesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1928,7 +3346,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*GetCertificateRequest); i {
+			switch v := v.(*SignCertificateResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1940,7 +3358,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*GetCrlRequest); i {
+			switch v := v.(*SignCertificatesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1952,7 +3370,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[9].Exporter = func(v any, i int) any {
-			switch v := v.(*ListByIssuerRequest); i {
+			switch v := v.(*GenerateCertificateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1964,7 +3382,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[10].Exporter = func(v any, i int) any {
-			switch v := v.(*RevokeCertificateRequest); i {
+			switch v := v.(*GenerateCertificateResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1976,7 +3394,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[11].Exporter = func(v any, i int) any {
-			switch v := v.(*CertificateResponse); i {
+			switch v := v.(*RenewCertificateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1988,7 +3406,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[12].Exporter = func(v any, i int) any {
-			switch v := v.(*CertificatesResponse); i {
+			switch v := v.(*RekeyCertificateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2000,7 +3418,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[13].Exporter = func(v any, i int) any {
-			switch v := v.(*RevokedCertificateResponse); i {
+			switch v := v.(*UpdateCertificateLabelRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2012,7 +3430,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[14].Exporter = func(v any, i int) any {
-			switch v := v.(*RevokedCertificatesResponse); i {
+			switch v := v.(*GetCertificateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2024,7 +3442,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[15].Exporter = func(v any, i int) any {
-			switch v := v.(*PublishCrlsRequest); i {
+			switch v := v.(*GetCrlRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2036,7 +3454,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[16].Exporter = func(v any, i int) any {
-			switch v := v.(*CrlsResponse); i {
+			switch v := v.(*ListByIssuerRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2048,7 +3466,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[17].Exporter = func(v any, i int) any {
-			switch v := v.(*CrlResponse); i {
+			switch v := v.(*RevokeCertificateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2060,7 +3478,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[18].Exporter = func(v any, i int) any {
-			switch v := v.(*OCSPRequest); i {
+			switch v := v.(*CertificateResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2072,7 +3490,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[19].Exporter = func(v any, i int) any {
-			switch v := v.(*OCSPResponse); i {
+			switch v := v.(*LintResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2084,7 +3502,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[20].Exporter = func(v any, i int) any {
-			switch v := v.(*ListOrgCertificatesRequest); i {
+			switch v := v.(*CertificatesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2096,7 +3514,7 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[21].Exporter = func(v any, i int) any {
-			switch v := v.(*RegisterProfileRequest); i {
+			switch v := v.(*RevokedCertificateResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2108,6 +3526,102 @@ func file_ca_proto_init() {
 			}
 		}
 		file_ca_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*RevokedCertificatesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*PublishCrlsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*CrlsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*CrlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[26].Exporter = func(v any, i int) any {
+			switch v := v.(*OCSPRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[27].Exporter = func(v any, i int) any {
+			switch v := v.(*OCSPResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[28].Exporter = func(v any, i int) any {
+			switch v := v.(*ListOrgCertificatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*RegisterProfileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[30].Exporter = func(v any, i int) any {
 			switch v := v.(*ListIssuersRequest); i {
 			case 0:
 				return &v.state
@@ -2119,14 +3633,62 @@ func file_ca_proto_init() {
 				return nil
 			}
 		}
+		file_ca_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*ImportCertificateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*PublishStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*PublishJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ca_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*PublishStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_ca_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   24,
+			NumEnums:      2,
+			NumMessages:   39,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
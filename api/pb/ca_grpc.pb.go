@@ -20,23 +20,30 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CA_ProfileInfo_FullMethodName             = "/pb.CA/ProfileInfo"
-	CA_GetIssuer_FullMethodName               = "/pb.CA/GetIssuer"
-	CA_ListIssuers_FullMethodName             = "/pb.CA/ListIssuers"
-	CA_SignCertificate_FullMethodName         = "/pb.CA/SignCertificate"
-	CA_GetCertificate_FullMethodName          = "/pb.CA/GetCertificate"
-	CA_GetCRL_FullMethodName                  = "/pb.CA/GetCRL"
-	CA_SignOCSP_FullMethodName                = "/pb.CA/SignOCSP"
-	CA_RevokeCertificate_FullMethodName       = "/pb.CA/RevokeCertificate"
-	CA_PublishCrls_FullMethodName             = "/pb.CA/PublishCrls"
-	CA_ListOrgCertificates_FullMethodName     = "/pb.CA/ListOrgCertificates"
-	CA_ListCertificates_FullMethodName        = "/pb.CA/ListCertificates"
-	CA_ListRevokedCertificates_FullMethodName = "/pb.CA/ListRevokedCertificates"
-	CA_UpdateCertificateLabel_FullMethodName  = "/pb.CA/UpdateCertificateLabel"
-	CA_ListDelegatedIssuers_FullMethodName    = "/pb.CA/ListDelegatedIssuers"
-	CA_RegisterDelegatedIssuer_FullMethodName = "/pb.CA/RegisterDelegatedIssuer"
-	CA_ArchiveDelegatedIssuer_FullMethodName  = "/pb.CA/ArchiveDelegatedIssuer"
-	CA_RegisterProfile_FullMethodName         = "/pb.CA/RegisterProfile"
+	CA_ProfileInfo_FullMethodName                = "/pb.CA/ProfileInfo"
+	CA_GetIssuer_FullMethodName                  = "/pb.CA/GetIssuer"
+	CA_ListIssuers_FullMethodName                = "/pb.CA/ListIssuers"
+	CA_SignCertificate_FullMethodName            = "/pb.CA/SignCertificate"
+	CA_GetCertificate_FullMethodName             = "/pb.CA/GetCertificate"
+	CA_GetCRL_FullMethodName                     = "/pb.CA/GetCRL"
+	CA_SignOCSP_FullMethodName                   = "/pb.CA/SignOCSP"
+	CA_RevokeCertificate_FullMethodName          = "/pb.CA/RevokeCertificate"
+	CA_PublishCrls_FullMethodName                = "/pb.CA/PublishCrls"
+	CA_ListOrgCertificates_FullMethodName        = "/pb.CA/ListOrgCertificates"
+	CA_ListCertificates_FullMethodName           = "/pb.CA/ListCertificates"
+	CA_ListRevokedCertificates_FullMethodName    = "/pb.CA/ListRevokedCertificates"
+	CA_UpdateCertificateLabel_FullMethodName     = "/pb.CA/UpdateCertificateLabel"
+	CA_ListDelegatedIssuers_FullMethodName       = "/pb.CA/ListDelegatedIssuers"
+	CA_RegisterDelegatedIssuer_FullMethodName    = "/pb.CA/RegisterDelegatedIssuer"
+	CA_ArchiveDelegatedIssuer_FullMethodName     = "/pb.CA/ArchiveDelegatedIssuer"
+	CA_RegisterProfile_FullMethodName            = "/pb.CA/RegisterProfile"
+	CA_ImportCertificate_FullMethodName          = "/pb.CA/ImportCertificate"
+	CA_GetPublishStatus_FullMethodName           = "/pb.CA/GetPublishStatus"
+	CA_SignCertificates_FullMethodName           = "/pb.CA/SignCertificates"
+	CA_SignCertificatesStream_FullMethodName     = "/pb.CA/SignCertificatesStream"
+	CA_RenewCertificate_FullMethodName           = "/pb.CA/RenewCertificate"
+	CA_RekeyCertificate_FullMethodName           = "/pb.CA/RekeyCertificate"
+	CA_GenerateAndSignCertificate_FullMethodName = "/pb.CA/GenerateAndSignCertificate"
 )
 
 // CAClient is the client API for CA service.
@@ -78,6 +85,28 @@ type CAClient interface {
 	ArchiveDelegatedIssuer(ctx context.Context, in *IssuerInfoRequest, opts ...grpc.CallOption) (*IssuerInfo, error)
 	// RegisterProfile registers the certificate profile
 	RegisterProfile(ctx context.Context, in *RegisterProfileRequest, opts ...grpc.CallOption) (*CertProfile, error)
+	// ImportCertificate registers the certificate issued by an external CA
+	ImportCertificate(ctx context.Context, in *ImportCertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error)
+	// GetPublishStatus returns the status of the publish outbox
+	GetPublishStatus(ctx context.Context, in *PublishStatusRequest, opts ...grpc.CallOption) (*PublishStatusResponse, error)
+	// SignCertificates signs a batch of certificates,
+	// and returns the result per request in the order of the requests
+	SignCertificates(ctx context.Context, in *SignCertificatesRequest, opts ...grpc.CallOption) (*SignCertificatesResponse, error)
+	// SignCertificatesStream signs the stream of certificate requests,
+	// and returns the result per request with the index of the request in the stream
+	SignCertificatesStream(ctx context.Context, opts ...grpc.CallOption) (CA_SignCertificatesStreamClient, error)
+	// RenewCertificate re-issues the certificate with the same key,
+	// subject and SANs, and a new validity period.
+	// The extensions are issued by the current profile.
+	RenewCertificate(ctx context.Context, in *RenewCertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error)
+	// RekeyCertificate re-issues the certificate with a new key
+	// from the request, and the subject, SANs, profile, org, label and metadata
+	// of the existing certificate
+	RekeyCertificate(ctx context.Context, in *RekeyCertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error)
+	// GenerateAndSignCertificate generates the key pair for the profile,
+	// signs the certificate, and returns the password protected key bundle.
+	// The profile must allow server-side key generation.
+	GenerateAndSignCertificate(ctx context.Context, in *GenerateCertificateRequest, opts ...grpc.CallOption) (*GenerateCertificateResponse, error)
 }
 
 type cAClient struct {
@@ -241,6 +270,91 @@ func (c *cAClient) RegisterProfile(ctx context.Context, in *RegisterProfileReque
 	return out, nil
 }
 
+func (c *cAClient) ImportCertificate(ctx context.Context, in *ImportCertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error) {
+	out := new(CertificateResponse)
+	err := c.cc.Invoke(ctx, CA_ImportCertificate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) GetPublishStatus(ctx context.Context, in *PublishStatusRequest, opts ...grpc.CallOption) (*PublishStatusResponse, error) {
+	out := new(PublishStatusResponse)
+	err := c.cc.Invoke(ctx, CA_GetPublishStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) SignCertificates(ctx context.Context, in *SignCertificatesRequest, opts ...grpc.CallOption) (*SignCertificatesResponse, error) {
+	out := new(SignCertificatesResponse)
+	err := c.cc.Invoke(ctx, CA_SignCertificates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) SignCertificatesStream(ctx context.Context, opts ...grpc.CallOption) (CA_SignCertificatesStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CA_ServiceDesc.Streams[0], CA_SignCertificatesStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cASignCertificatesStreamClient{stream}
+	return x, nil
+}
+
+type CA_SignCertificatesStreamClient interface {
+	Send(*SignCertificateRequest) error
+	Recv() (*SignCertificateResult, error)
+	grpc.ClientStream
+}
+
+type cASignCertificatesStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *cASignCertificatesStreamClient) Send(m *SignCertificateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cASignCertificatesStreamClient) Recv() (*SignCertificateResult, error) {
+	m := new(SignCertificateResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cAClient) RenewCertificate(ctx context.Context, in *RenewCertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error) {
+	out := new(CertificateResponse)
+	err := c.cc.Invoke(ctx, CA_RenewCertificate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) RekeyCertificate(ctx context.Context, in *RekeyCertificateRequest, opts ...grpc.CallOption) (*CertificateResponse, error) {
+	out := new(CertificateResponse)
+	err := c.cc.Invoke(ctx, CA_RekeyCertificate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAClient) GenerateAndSignCertificate(ctx context.Context, in *GenerateCertificateRequest, opts ...grpc.CallOption) (*GenerateCertificateResponse, error) {
+	out := new(GenerateCertificateResponse)
+	err := c.cc.Invoke(ctx, CA_GenerateAndSignCertificate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CAServer is the server API for CA service.
 // All implementations should embed UnimplementedCAServer
 // for forward compatibility
@@ -280,6 +394,28 @@ type CAServer interface {
 	ArchiveDelegatedIssuer(context.Context, *IssuerInfoRequest) (*IssuerInfo, error)
 	// RegisterProfile registers the certificate profile
 	RegisterProfile(context.Context, *RegisterProfileRequest) (*CertProfile, error)
+	// ImportCertificate registers the certificate issued by an external CA
+	ImportCertificate(context.Context, *ImportCertificateRequest) (*CertificateResponse, error)
+	// GetPublishStatus returns the status of the publish outbox
+	GetPublishStatus(context.Context, *PublishStatusRequest) (*PublishStatusResponse, error)
+	// SignCertificates signs a batch of certificates,
+	// and returns the result per request in the order of the requests
+	SignCertificates(context.Context, *SignCertificatesRequest) (*SignCertificatesResponse, error)
+	// SignCertificatesStream signs the stream of certificate requests,
+	// and returns the result per request with the index of the request in the stream
+	SignCertificatesStream(CA_SignCertificatesStreamServer) error
+	// RenewCertificate re-issues the certificate with the same key,
+	// subject and SANs, and a new validity period.
+	// The extensions are issued by the current profile.
+	RenewCertificate(context.Context, *RenewCertificateRequest) (*CertificateResponse, error)
+	// RekeyCertificate re-issues the certificate with a new key
+	// from the request, and the subject, SANs, profile, org, label and metadata
+	// of the existing certificate
+	RekeyCertificate(context.Context, *RekeyCertificateRequest) (*CertificateResponse, error)
+	// GenerateAndSignCertificate generates the key pair for the profile,
+	// signs the certificate, and returns the password protected key bundle.
+	// The profile must allow server-side key generation.
+	GenerateAndSignCertificate(context.Context, *GenerateCertificateRequest) (*GenerateCertificateResponse, error)
 }
 
 // UnimplementedCAServer should be embedded to have forward compatible implementations.
@@ -337,6 +473,27 @@ func (UnimplementedCAServer) ArchiveDelegatedIssuer(context.Context, *IssuerInfo
 func (UnimplementedCAServer) RegisterProfile(context.Context, *RegisterProfileRequest) (*CertProfile, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterProfile not implemented")
 }
+func (UnimplementedCAServer) ImportCertificate(context.Context, *ImportCertificateRequest) (*CertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportCertificate not implemented")
+}
+func (UnimplementedCAServer) GetPublishStatus(context.Context, *PublishStatusRequest) (*PublishStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublishStatus not implemented")
+}
+func (UnimplementedCAServer) SignCertificates(context.Context, *SignCertificatesRequest) (*SignCertificatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignCertificates not implemented")
+}
+func (UnimplementedCAServer) SignCertificatesStream(CA_SignCertificatesStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SignCertificatesStream not implemented")
+}
+func (UnimplementedCAServer) RenewCertificate(context.Context, *RenewCertificateRequest) (*CertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewCertificate not implemented")
+}
+func (UnimplementedCAServer) RekeyCertificate(context.Context, *RekeyCertificateRequest) (*CertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RekeyCertificate not implemented")
+}
+func (UnimplementedCAServer) GenerateAndSignCertificate(context.Context, *GenerateCertificateRequest) (*GenerateCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateAndSignCertificate not implemented")
+}
 
 // UnsafeCAServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to CAServer will
@@ -655,6 +812,140 @@ func _CA_RegisterProfile_Handler(srv any, ctx context.Context, dec func(any) err
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CA_ImportCertificate_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ImportCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).ImportCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_ImportCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(CAServer).ImportCertificate(ctx, req.(*ImportCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_GetPublishStatus_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(PublishStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).GetPublishStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_GetPublishStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(CAServer).GetPublishStatus(ctx, req.(*PublishStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_SignCertificates_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(SignCertificatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).SignCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_SignCertificates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(CAServer).SignCertificates(ctx, req.(*SignCertificatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_SignCertificatesStream_Handler(srv any, stream grpc.ServerStream) error {
+	return srv.(CAServer).SignCertificatesStream(&cASignCertificatesStreamServer{stream})
+}
+
+type CA_SignCertificatesStreamServer interface {
+	Send(*SignCertificateResult) error
+	Recv() (*SignCertificateRequest, error)
+	grpc.ServerStream
+}
+
+type cASignCertificatesStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *cASignCertificatesStreamServer) Send(m *SignCertificateResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cASignCertificatesStreamServer) Recv() (*SignCertificateRequest, error) {
+	m := new(SignCertificateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CA_RenewCertificate_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(RenewCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).RenewCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_RenewCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(CAServer).RenewCertificate(ctx, req.(*RenewCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_RekeyCertificate_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(RekeyCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).RekeyCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_RekeyCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(CAServer).RekeyCertificate(ctx, req.(*RekeyCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CA_GenerateAndSignCertificate_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(GenerateCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CAServer).GenerateAndSignCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CA_GenerateAndSignCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(CAServer).GenerateAndSignCertificate(ctx, req.(*GenerateCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CA_ServiceDesc is the grpc.ServiceDesc for CA service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -730,7 +1021,38 @@ var CA_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RegisterProfile",
 			Handler:    _CA_RegisterProfile_Handler,
 		},
+		{
+			MethodName: "ImportCertificate",
+			Handler:    _CA_ImportCertificate_Handler,
+		},
+		{
+			MethodName: "GetPublishStatus",
+			Handler:    _CA_GetPublishStatus_Handler,
+		},
+		{
+			MethodName: "SignCertificates",
+			Handler:    _CA_SignCertificates_Handler,
+		},
+		{
+			MethodName: "RenewCertificate",
+			Handler:    _CA_RenewCertificate_Handler,
+		},
+		{
+			MethodName: "RekeyCertificate",
+			Handler:    _CA_RekeyCertificate_Handler,
+		},
+		{
+			MethodName: "GenerateAndSignCertificate",
+			Handler:    _CA_GenerateAndSignCertificate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SignCertificatesStream",
+			Handler:       _CA_SignCertificatesStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "ca.proto",
 }
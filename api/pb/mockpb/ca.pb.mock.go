@@ -5,6 +5,7 @@ package mockpb
 
 import (
 	"context"
+	"io"
 
 	"github.com/effective-security/trusty/api/pb"
 	"google.golang.org/protobuf/proto"
@@ -177,3 +178,78 @@ func (m *MockCAServer) RegisterProfile(ctx context.Context, req *pb.RegisterProf
 	}
 	return m.next().(*pb.CertProfile), nil
 }
+
+// ImportCertificate registers the certificate issued by an external CA
+func (m *MockCAServer) ImportCertificate(ctx context.Context, req *pb.ImportCertificateRequest) (*pb.CertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.next().(*pb.CertificateResponse), nil
+}
+
+// GetPublishStatus returns the status of the publish outbox
+func (m *MockCAServer) GetPublishStatus(ctx context.Context, req *pb.PublishStatusRequest) (*pb.PublishStatusResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.next().(*pb.PublishStatusResponse), nil
+}
+
+// SignCertificates signs a batch of certificates,
+// and returns the result per request in the order of the requests
+func (m *MockCAServer) SignCertificates(ctx context.Context, req *pb.SignCertificatesRequest) (*pb.SignCertificatesResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.next().(*pb.SignCertificatesResponse), nil
+}
+
+// SignCertificatesStream signs the stream of certificate requests,
+// and returns the result per request with the index of the request in the stream
+func (m *MockCAServer) SignCertificatesStream(stream pb.CA_SignCertificatesStreamServer) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for {
+		_, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(m.next().(*pb.SignCertificateResult)); err != nil {
+			return err
+		}
+	}
+}
+
+// RenewCertificate re-issues the certificate with the same key,
+// subject and SANs, and a new validity period.
+// The extensions are issued by the current profile.
+func (m *MockCAServer) RenewCertificate(ctx context.Context, req *pb.RenewCertificateRequest) (*pb.CertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.next().(*pb.CertificateResponse), nil
+}
+
+// RekeyCertificate re-issues the certificate with a new key
+// from the request, and the subject, SANs, profile, org, label and metadata
+// of the existing certificate
+func (m *MockCAServer) RekeyCertificate(ctx context.Context, req *pb.RekeyCertificateRequest) (*pb.CertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.next().(*pb.CertificateResponse), nil
+}
+
+// GenerateAndSignCertificate generates the key pair for the profile,
+// signs the certificate, and returns the password protected key bundle.
+// The profile must allow server-side key generation.
+func (m *MockCAServer) GenerateAndSignCertificate(ctx context.Context, req *pb.GenerateCertificateRequest) (*pb.GenerateCertificateResponse, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.next().(*pb.GenerateCertificateResponse), nil
+}
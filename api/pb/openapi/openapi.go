@@ -0,0 +1,10 @@
+// Package openapi provides OpenAPI specs of the services,
+// generated from the proto files
+package openapi
+
+import "embed"
+
+// Specs provides OpenAPI specs by file name, like `ca.openapi.yaml`
+//
+//go:embed *.openapi.yaml
+var Specs embed.FS
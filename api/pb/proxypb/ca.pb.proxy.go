@@ -5,12 +5,14 @@ package proxypb
 
 import (
 	"context"
+	"io"
 
 	"github.com/effective-security/porto/pkg/retriable"
 	"github.com/effective-security/porto/xhttp/correlation"
 	"github.com/effective-security/porto/xhttp/httperror"
 	"github.com/effective-security/trusty/api/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 type proxyCAServer struct {
@@ -616,3 +618,269 @@ func (s *postproxyCAClient) RegisterProfile(ctx context.Context, req *pb.Registe
 	}
 	return &res, nil
 }
+
+// ImportCertificate registers the certificate issued by an external CA
+func (s *proxyCAServer) ImportCertificate(ctx context.Context, req *pb.ImportCertificateRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.srv.ImportCertificate(ctx, req)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// ImportCertificate registers the certificate issued by an external CA
+func (s *proxyCAClient) ImportCertificate(ctx context.Context, req *pb.ImportCertificateRequest) (*pb.CertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.remote.ImportCertificate(ctx, req, s.callOpts...)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// ImportCertificate registers the certificate issued by an external CA
+func (s *postproxyCAClient) ImportCertificate(ctx context.Context, req *pb.ImportCertificateRequest) (*pb.CertificateResponse, error) {
+	var res pb.CertificateResponse
+	path := "/pb.CA/ImportCertificate"
+	_, _, err := s.client.Post(ctx, path, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetPublishStatus returns the status of the publish outbox
+func (s *proxyCAServer) GetPublishStatus(ctx context.Context, req *pb.PublishStatusRequest, opts ...grpc.CallOption) (*pb.PublishStatusResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.srv.GetPublishStatus(ctx, req)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// GetPublishStatus returns the status of the publish outbox
+func (s *proxyCAClient) GetPublishStatus(ctx context.Context, req *pb.PublishStatusRequest) (*pb.PublishStatusResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.remote.GetPublishStatus(ctx, req, s.callOpts...)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// GetPublishStatus returns the status of the publish outbox
+func (s *postproxyCAClient) GetPublishStatus(ctx context.Context, req *pb.PublishStatusRequest) (*pb.PublishStatusResponse, error) {
+	var res pb.PublishStatusResponse
+	path := "/pb.CA/GetPublishStatus"
+	_, _, err := s.client.Post(ctx, path, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SignCertificates signs a batch of certificates,
+// and returns the result per request in the order of the requests
+func (s *proxyCAServer) SignCertificates(ctx context.Context, req *pb.SignCertificatesRequest, opts ...grpc.CallOption) (*pb.SignCertificatesResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.srv.SignCertificates(ctx, req)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// SignCertificates signs a batch of certificates,
+// and returns the result per request in the order of the requests
+func (s *proxyCAClient) SignCertificates(ctx context.Context, req *pb.SignCertificatesRequest) (*pb.SignCertificatesResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.remote.SignCertificates(ctx, req, s.callOpts...)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// SignCertificates signs a batch of certificates,
+// and returns the result per request in the order of the requests
+func (s *postproxyCAClient) SignCertificates(ctx context.Context, req *pb.SignCertificatesRequest) (*pb.SignCertificatesResponse, error) {
+	var res pb.SignCertificatesResponse
+	path := "/pb.CA/SignCertificates"
+	_, _, err := s.client.Post(ctx, path, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SignCertificatesStream signs the stream of certificate requests,
+// and returns the result per request with the index of the request in the stream
+func (s *proxyCAServer) SignCertificatesStream(ctx context.Context, opts ...grpc.CallOption) (pb.CA_SignCertificatesStreamClient, error) {
+	return nil, httperror.NewGrpc(codes.Unimplemented, "streaming is not supported by in-process client")
+}
+
+// SignCertificatesStream signs the stream of certificate requests,
+// and returns the result per request with the index of the request in the stream
+func (s *proxyCAClient) SignCertificatesStream(srv pb.CA_SignCertificatesStreamServer) error {
+	// add corellation ID to outgoing RPC calls
+	ctx := correlation.WithMetaFromContext(srv.Context())
+	stream, err := s.remote.SignCertificatesStream(ctx, s.callOpts...)
+	if err != nil {
+		return httperror.NewFromPb(err)
+	}
+	go func() {
+		for {
+			req, err := srv.Recv()
+			if err != nil {
+				_ = stream.CloseSend()
+				return
+			}
+			if err = stream.Send(req); err != nil {
+				return
+			}
+		}
+	}()
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return httperror.NewFromPb(err)
+		}
+		if err = srv.Send(res); err != nil {
+			return err
+		}
+	}
+}
+
+// SignCertificatesStream signs the stream of certificate requests,
+// and returns the result per request with the index of the request in the stream
+func (s *postproxyCAClient) SignCertificatesStream(srv pb.CA_SignCertificatesStreamServer) error {
+	return httperror.NewGrpc(codes.Unimplemented, "streaming is not supported over HTTP")
+}
+
+// RenewCertificate re-issues the certificate with the same key,
+// subject and SANs, and a new validity period.
+// The extensions are issued by the current profile.
+func (s *proxyCAServer) RenewCertificate(ctx context.Context, req *pb.RenewCertificateRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.srv.RenewCertificate(ctx, req)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// RenewCertificate re-issues the certificate with the same key,
+// subject and SANs, and a new validity period.
+// The extensions are issued by the current profile.
+func (s *proxyCAClient) RenewCertificate(ctx context.Context, req *pb.RenewCertificateRequest) (*pb.CertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.remote.RenewCertificate(ctx, req, s.callOpts...)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// RenewCertificate re-issues the certificate with the same key,
+// subject and SANs, and a new validity period.
+// The extensions are issued by the current profile.
+func (s *postproxyCAClient) RenewCertificate(ctx context.Context, req *pb.RenewCertificateRequest) (*pb.CertificateResponse, error) {
+	var res pb.CertificateResponse
+	path := "/pb.CA/RenewCertificate"
+	_, _, err := s.client.Post(ctx, path, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RekeyCertificate re-issues the certificate with a new key
+// from the request, and the subject, SANs, profile, org, label and metadata
+// of the existing certificate
+func (s *proxyCAServer) RekeyCertificate(ctx context.Context, req *pb.RekeyCertificateRequest, opts ...grpc.CallOption) (*pb.CertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.srv.RekeyCertificate(ctx, req)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// RekeyCertificate re-issues the certificate with a new key
+// from the request, and the subject, SANs, profile, org, label and metadata
+// of the existing certificate
+func (s *proxyCAClient) RekeyCertificate(ctx context.Context, req *pb.RekeyCertificateRequest) (*pb.CertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.remote.RekeyCertificate(ctx, req, s.callOpts...)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// RekeyCertificate re-issues the certificate with a new key
+// from the request, and the subject, SANs, profile, org, label and metadata
+// of the existing certificate
+func (s *postproxyCAClient) RekeyCertificate(ctx context.Context, req *pb.RekeyCertificateRequest) (*pb.CertificateResponse, error) {
+	var res pb.CertificateResponse
+	path := "/pb.CA/RekeyCertificate"
+	_, _, err := s.client.Post(ctx, path, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GenerateAndSignCertificate generates the key pair for the profile,
+// signs the certificate, and returns the password protected key bundle.
+// The profile must allow server-side key generation.
+func (s *proxyCAServer) GenerateAndSignCertificate(ctx context.Context, req *pb.GenerateCertificateRequest, opts ...grpc.CallOption) (*pb.GenerateCertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.srv.GenerateAndSignCertificate(ctx, req)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// GenerateAndSignCertificate generates the key pair for the profile,
+// signs the certificate, and returns the password protected key bundle.
+// The profile must allow server-side key generation.
+func (s *proxyCAClient) GenerateAndSignCertificate(ctx context.Context, req *pb.GenerateCertificateRequest) (*pb.GenerateCertificateResponse, error) {
+	// add corellation ID to outgoing RPC calls
+	ctx = correlation.WithMetaFromContext(ctx)
+	res, err := s.remote.GenerateAndSignCertificate(ctx, req, s.callOpts...)
+	if err != nil {
+		return nil, httperror.NewFromPb(err)
+	}
+	return res, nil
+}
+
+// GenerateAndSignCertificate generates the key pair for the profile,
+// signs the certificate, and returns the password protected key bundle.
+// The profile must allow server-side key generation.
+func (s *postproxyCAClient) GenerateAndSignCertificate(ctx context.Context, req *pb.GenerateCertificateRequest) (*pb.GenerateCertificateResponse, error) {
+	var res pb.GenerateCertificateResponse
+	path := "/pb.CA/GenerateAndSignCertificate"
+	_, _, err := s.client.Post(ctx, path, req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -340,10 +340,7 @@ func provideClientFactory(cfg *config.Configuration) (client.Factory, error) {
 }
 
 func providePublisher(cfg *config.Configuration) (certpublisher.Publisher, error) {
-	pub, err := certpublisher.NewPublisher(&certpublisher.Config{
-		CertsBucket: cfg.RegistrationAuthority.Publisher.CertsBucket,
-		CRLBucket:   cfg.RegistrationAuthority.Publisher.CRLBucket,
-	})
+	pub, err := certpublisher.NewPublisher(cfg.RegistrationAuthority.Publisher.PublisherConfig())
 	if err != nil {
 		return nil, err
 	}
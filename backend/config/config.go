@@ -1,6 +1,9 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/effective-security/porto/gserver"
 	appinit "github.com/effective-security/porto/pkg/appinit/config"
 	"github.com/effective-security/trusty/api/client"
@@ -56,6 +59,52 @@ type Configuration struct {
 	// RegistrationAuthority contains configuration info for RA
 	RegistrationAuthority *RegistrationAuthority `json:"ra" yaml:"ra"`
 
+	// DeltaCRL specifies configuration for delta CRLs
+	DeltaCRL DeltaCRL `json:"delta_crl" yaml:"delta_crl"`
+
+	// CRLRegeneration specifies configuration for CRL regeneration after revocations
+	CRLRegeneration CRLRegeneration `json:"crl_regeneration" yaml:"crl_regeneration"`
+
+	// OCSPStore specifies configuration for pre-signed OCSP responses
+	OCSPStore OCSPStore `json:"ocsp_store" yaml:"ocsp_store"`
+
+	// OCSPCache specifies configuration for OCSP responses cache of the responder
+	OCSPCache OCSPCache `json:"ocsp_cache" yaml:"ocsp_cache"`
+
+	// OCSPResponder specifies configuration for delegated OCSP responders
+	OCSPResponder OCSPResponder `json:"ocsp_responder" yaml:"ocsp_responder"`
+
+	// CRLShards specifies the number of CRL shards by issuer label,
+	// the certificates of the issuer are assigned to a shard at issuance.
+	// The number of shards must not be decreased,
+	// as the issued certificates refer to the shard CRL DP URL.
+	CRLShards map[string]uint32 `json:"crl_shards,omitempty" yaml:"crl_shards,omitempty"`
+
+	// OCSPNonce specifies the labels of issuers, which OCSP responses
+	// echo the nonce extension of the request, the "*" label matches all issuers.
+	// The responses with nonce are signed per request.
+	OCSPNonce []string `json:"ocsp_nonce,omitempty" yaml:"ocsp_nonce,omitempty"`
+
+	// KeyGeneration specifies server-side key generation by profile name,
+	// the profiles not in the list do not allow GenerateAndSignCertificate.
+	KeyGeneration map[string]*KeyGeneration `json:"key_generation,omitempty" yaml:"key_generation,omitempty"`
+
+	// Linting specifies pre-issuance linting of certificates by profile name,
+	// the "*" entry applies to the profiles not in the list.
+	// The profiles without the policy are not linted.
+	Linting map[string]*LintPolicy `json:"linting,omitempty" yaml:"linting,omitempty"`
+
+	// KeyValidation specifies the policy of the public keys by profile name,
+	// the "*" entry applies to the profiles not in the list.
+	KeyValidation map[string]*KeyPolicy `json:"key_validation,omitempty" yaml:"key_validation,omitempty"`
+
+	// WeakKeys specifies the lists of known weak keys,
+	// that are not allowed to be certified by any profile
+	WeakKeys WeakKeys `json:"weak_keys" yaml:"weak_keys"`
+
+	// Swagger specifies configuration for serving OpenAPI specs of the services
+	Swagger gserver.SwaggerCfg `json:"swagger" yaml:"swagger"`
+
 	// HTTPServers specifies a list of servers that expose HTTP or gRPC services
 	HTTPServers map[string]*gserver.Config `json:"servers" yaml:"servers"`
 
@@ -102,6 +151,269 @@ func (c *DelegatedIssuers) GetDisabled() bool {
 	return c.Disabled != nil && *c.Disabled
 }
 
+// DeltaCRL specifies configuration for delta CRLs
+type DeltaCRL struct {
+	// Renewal specifies value in 1h format for interval of delta CRL issuance,
+	// if not specified, then delta CRLs are not issued
+	Renewal time.Duration `json:"renewal,omitempty" yaml:"renewal,omitempty"`
+	// Expiry specifies value in 2h format for duration of delta CRL next update time,
+	// if not specified, then twice of Renewal is used
+	Expiry time.Duration `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+}
+
+// Enabled returns true if delta CRLs are issued
+func (c *DeltaCRL) Enabled() bool {
+	return c.Renewal > 0
+}
+
+// GetExpiry returns duration of delta CRL next update time
+func (c *DeltaCRL) GetExpiry() time.Duration {
+	if c.Expiry > 0 {
+		return c.Expiry
+	}
+	return 2 * c.Renewal
+}
+
+// CRLRegeneration specifies configuration for CRL regeneration after revocations.
+// The revocations of an issuer are coalesced,
+// and its CRL is regenerated once the revocations stop for the Delay period,
+// but not later than MaxDelay after the first revocation.
+type CRLRegeneration struct {
+	// Delay specifies value in 5s format for the quiet period after a revocation,
+	// if not specified, then 5s is used
+	Delay time.Duration `json:"delay,omitempty" yaml:"delay,omitempty"`
+	// MaxDelay specifies value in 1m format for the max delay after the first revocation,
+	// if not specified, then 1m is used
+	MaxDelay time.Duration `json:"max_delay,omitempty" yaml:"max_delay,omitempty"`
+}
+
+// GetDelay returns the quiet period after a revocation
+func (c *CRLRegeneration) GetDelay() time.Duration {
+	if c.Delay > 0 {
+		return c.Delay
+	}
+	return 5 * time.Second
+}
+
+// GetMaxDelay returns the max delay after the first revocation
+func (c *CRLRegeneration) GetMaxDelay() time.Duration {
+	if c.MaxDelay > 0 {
+		return c.MaxDelay
+	}
+	return time.Minute
+}
+
+// OCSPStore specifies configuration for pre-signed OCSP responses.
+// The responses of unexpired certificates are signed in the background,
+// and served by the responder without signing per request.
+type OCSPStore struct {
+	// Interval specifies value in 1h format for interval of the background updater,
+	// if not specified, then OCSP responses are signed per request
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// RefreshBefore specifies value in 30m format for the period before next update time
+	// to sign a new response, if not specified, then half of the OCSP expiry is used.
+	// The period is not less than Interval, so the responses do not expire between the runs.
+	RefreshBefore time.Duration `json:"refresh_before,omitempty" yaml:"refresh_before,omitempty"`
+}
+
+// Enabled returns true if OCSP responses are pre-signed
+func (c *OCSPStore) Enabled() bool {
+	return c.Interval > 0
+}
+
+// GetRefreshBefore returns the period before next update time
+// to sign a new response, for the specified OCSP expiry
+func (c *OCSPStore) GetRefreshBefore(expiry time.Duration) time.Duration {
+	d := expiry / 2
+	if c.RefreshBefore > 0 && c.RefreshBefore < expiry {
+		d = c.RefreshBefore
+	}
+	return max(d, c.Interval)
+}
+
+// OCSPResponder specifies configuration for delegated OCSP responders.
+// The responder certificate of each issuer is issued with the Profile,
+// its key is created in the crypto provider, and the certificate
+// is renewed before it expires, so the issuer key is not used to sign OCSP.
+type OCSPResponder struct {
+	// Profile specifies the name of the profile for responder certificates,
+	// with OCSP signing usage and OCSP no check extension.
+	// If not specified, then OCSP responses are signed by the issuer.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	// RenewBefore specifies value in 24h format for the period before the responder
+	// certificate expires to issue a new one, if not specified,
+	// then half of the certificate validity is used.
+	// The period is not less than OCSP expiry of the issuer,
+	// so the signed responses do not outlive the responder certificate.
+	RenewBefore time.Duration `json:"renew_before,omitempty" yaml:"renew_before,omitempty"`
+	// CryptoProvider specifies the name of Crypto provider for responder keys,
+	// if not specified, then default will be used
+	CryptoProvider string `json:"crypto_provider,omitempty" yaml:"crypto_provider,omitempty"`
+	// CryptoModel specifies the name of Crypto model to use,
+	// if not specified, then default will be used
+	CryptoModel string `json:"crypto_model,omitempty" yaml:"crypto_model,omitempty"`
+}
+
+// Enabled returns true if OCSP responses are signed by delegated responders
+func (c *OCSPResponder) Enabled() bool {
+	return c.Profile != ""
+}
+
+// GetRenewBefore returns the period before the responder certificate expires
+// to issue a new one, for the specified certificate validity and OCSP expiry
+func (c *OCSPResponder) GetRenewBefore(validity, ocspExpiry time.Duration) time.Duration {
+	d := validity / 2
+	if c.RenewBefore > 0 && c.RenewBefore < validity {
+		d = c.RenewBefore
+	}
+	return max(d, ocspExpiry)
+}
+
+// KeyGeneration specifies configuration for server-side key generation
+// of the profile, for clients that can not create a CSR.
+// The generated key is never retained: it's created in memory of the request,
+// and is only returned in the bundle. The retention in HSM or KMS is not supported,
+// as their keys can not be exported.
+type KeyGeneration struct {
+	// Allowed specifies to allow key generation for the profile
+	Allowed bool `json:"allowed,omitempty" yaml:"allowed,omitempty"`
+	// Algorithm specifies the key algorithm: RSA or ECDSA,
+	// if not specified, then ECDSA is used
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	// Size specifies the key size in bits for RSA, or the curve size for ECDSA,
+	// if not specified, then 2048 for RSA and 256 for ECDSA is used
+	Size int `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+// KeyAlgorithm returns the key algorithm and size
+func (c *KeyGeneration) KeyAlgorithm() (string, int) {
+	algo := strings.ToUpper(c.Algorithm)
+	size := c.Size
+	switch algo {
+	case "RSA":
+		if size == 0 {
+			size = 2048
+		}
+	default:
+		algo = "ECDSA"
+		if size == 0 {
+			size = 256
+		}
+	}
+	return algo, size
+}
+
+// LintPolicy specifies pre-issuance linting of the profile
+type LintPolicy struct {
+	// Disabled specifies to not lint the profile
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// Block specifies the lowest severity of the lint result that blocks the issuance:
+	// notice, warn, error, fatal or never; if not specified, then error is used
+	Block string `json:"block,omitempty" yaml:"block,omitempty"`
+	// Warn specifies the lowest severity of the lint result that is returned as warning,
+	// and stored with the certificate; if not specified, then warn is used
+	Warn string `json:"warn,omitempty" yaml:"warn,omitempty"`
+	// Ignore specifies the names of the lints to ignore
+	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+	// Sources specifies the lint sources, such as RFC5280 and CABF_BR,
+	// if not specified, then RFC5280 and CABF_BR are used
+	Sources []string `json:"sources,omitempty" yaml:"sources,omitempty"`
+}
+
+// KeyPolicy specifies the public keys allowed for the profile
+type KeyPolicy struct {
+	// MinRSASize specifies the minimum size of RSA keys in bits,
+	// if not specified, then 2048 is used
+	MinRSASize int `json:"min_rsa_size,omitempty" yaml:"min_rsa_size,omitempty"`
+	// MinECDSASize specifies the minimum curve size of ECDSA keys,
+	// if not specified, then 256 is used
+	MinECDSASize int `json:"min_ecdsa_size,omitempty" yaml:"min_ecdsa_size,omitempty"`
+	// AllowSPKI specifies to sign a bare SubjectPublicKeyInfo,
+	// without proof of possession of the private key
+	AllowSPKI bool `json:"allow_spki,omitempty" yaml:"allow_spki,omitempty"`
+}
+
+// WeakKeys specifies the lists of known weak keys.
+// RSA keys are always checked for ROCA vulnerability.
+type WeakKeys struct {
+	// Debian specifies extra files of Debian weak keys in openssl-blacklist format,
+	// such as /usr/share/openssl-blacklist/blacklist.RSA-2048.
+	// The lists of openssl-blacklist package are bundled,
+	// and always checked in addition to the files.
+	Debian []string `json:"debian,omitempty" yaml:"debian,omitempty"`
+}
+
+// OCSPCache specifies configuration for the in-process cache of OCSP responses.
+// The cached response is served until its next update time, or MaxAge,
+// and removed when the certificate is revoked by CA in the same process.
+type OCSPCache struct {
+	// Size specifies the maximum number of cached responses,
+	// if not specified, then OCSP responses are not cached
+	Size int `json:"size,omitempty" yaml:"size,omitempty"`
+	// MaxAge specifies value in 1h format for the maximum age of the cached response,
+	// also used as the maximum value of max-age in Cache-Control header.
+	// If not specified, then the response is cached until its next update time.
+	// Set it when CA is remote, to limit the period to serve the status before revocation.
+	MaxAge time.Duration `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+}
+
+// Enabled returns true if OCSP responses are cached
+func (c *OCSPCache) Enabled() bool {
+	return c.Size > 0
+}
+
+// CRLShardsCount returns the number of CRL shards for the issuer,
+// or 0 if the CRL of the issuer is not partitioned
+func (c *Configuration) CRLShardsCount(label string) uint32 {
+	return c.CRLShards[label]
+}
+
+// OCSPNonceEnabled returns true if OCSP responses of the issuer echo the nonce
+func (c *Configuration) OCSPNonceEnabled(label string) bool {
+	for _, l := range c.OCSPNonce {
+		if l == "*" || l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyGenerationProfile returns key generation configuration of the profile,
+// or nil if the profile does not allow key generation
+func (c *Configuration) KeyGenerationProfile(profile string) *KeyGeneration {
+	kg := c.KeyGeneration[profile]
+	if kg == nil || !kg.Allowed {
+		return nil
+	}
+	return kg
+}
+
+// LintPolicy returns the linting policy of the profile,
+// or nil if the profile is not linted
+func (c *Configuration) LintPolicy(profile string) *LintPolicy {
+	lp := c.Linting[profile]
+	if lp == nil {
+		lp = c.Linting["*"]
+	}
+	if lp == nil || lp.Disabled {
+		return nil
+	}
+	return lp
+}
+
+// KeyPolicy returns the key policy of the profile,
+// or the default policy if not specified
+func (c *Configuration) KeyPolicy(profile string) *KeyPolicy {
+	kp := c.KeyValidation[profile]
+	if kp == nil {
+		kp = c.KeyValidation["*"]
+	}
+	if kp == nil {
+		kp = &KeyPolicy{}
+	}
+	return kp
+}
+
 // Task specifies configuration of a single task.
 type Task struct {
 
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/effective-security/x/configloader"
 	"github.com/pkg/errors"
@@ -48,6 +49,48 @@ func TestLoadConfig(t *testing.T) {
 	testDirAbs("Client.ClientTLS.KeyFile", c.Client.ClientTLS.KeyFile)
 	testDirAbs("Authority", c.Authority)
 
+	assert.True(t, c.DeltaCRL.Enabled())
+	assert.Equal(t, time.Hour, c.DeltaCRL.Renewal)
+	assert.Equal(t, 2*time.Hour, c.DeltaCRL.GetExpiry())
+	assert.Equal(t, 5*time.Second, c.CRLRegeneration.GetDelay())
+	assert.Equal(t, time.Minute, c.CRLRegeneration.GetMaxDelay())
+	assert.True(t, c.OCSPStore.Enabled())
+	assert.Equal(t, 10*time.Minute, c.OCSPStore.Interval)
+	assert.Equal(t, 30*time.Minute, c.OCSPStore.GetRefreshBefore(8*time.Hour))
+	assert.Equal(t, 10*time.Minute, c.OCSPStore.GetRefreshBefore(20*time.Minute))
+	assert.True(t, c.OCSPCache.Enabled())
+	assert.Equal(t, 10000, c.OCSPCache.Size)
+	assert.Equal(t, time.Hour, c.OCSPCache.MaxAge)
+	assert.True(t, c.OCSPNonceEnabled("trusty.svc"))
+	kg := c.KeyGenerationProfile("client")
+	require.NotNil(t, kg)
+	algo, size := kg.KeyAlgorithm()
+	assert.Equal(t, "RSA", algo)
+	assert.Equal(t, 2048, size)
+	assert.Nil(t, c.KeyGenerationProfile("server"))
+
+	lp := c.LintPolicy("server")
+	require.NotNil(t, lp)
+	assert.Equal(t, "error", lp.Block)
+	lp = c.LintPolicy("client")
+	require.NotNil(t, lp)
+	assert.Equal(t, "never", lp.Block)
+	c.Linting["client"] = &LintPolicy{Disabled: true}
+	assert.Nil(t, c.LintPolicy("client"))
+	assert.Equal(t, 2048, c.KeyPolicy("server").MinRSASize)
+	assert.Equal(t, 256, c.KeyPolicy("server").MinECDSASize)
+	assert.False(t, c.KeyPolicy("server").AllowSPKI)
+	assert.Empty(t, c.WeakKeys.Debian)
+	assert.Equal(t, &KeyPolicy{}, (&Configuration{}).KeyPolicy("server"))
+	algo, size = (&KeyGeneration{}).KeyAlgorithm()
+	assert.Equal(t, "ECDSA", algo)
+	assert.Equal(t, 256, size)
+	assert.True(t, c.OCSPResponder.Enabled())
+	assert.Equal(t, "ocsp", c.OCSPResponder.Profile)
+	assert.Equal(t, 48*time.Hour, c.OCSPResponder.GetRenewBefore(168*time.Hour, 8*time.Hour))
+	assert.Equal(t, 84*time.Hour, c.OCSPResponder.GetRenewBefore(72*time.Hour, 84*time.Hour))
+	assert.True(t, c.Swagger.Enabled)
+
 	cis := c.HTTPServers["cis"]
 	require.NotNil(t, cis)
 	require.NotNil(t, cis.CORS)
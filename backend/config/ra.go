@@ -1,5 +1,10 @@
 package config
 
+import (
+	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/pkg/storage"
+)
+
 // RegistrationAuthority contains configuration info for RA
 type RegistrationAuthority struct {
 	// PrivateRoots specifies the list of private Root Certs files.
@@ -19,6 +24,39 @@ type Publisher struct {
 	BaseURL     string `json:"base_url" yaml:"base_url"`
 	CertsBucket string `json:"cert_bucket" yaml:"cert_bucket"`
 	CRLBucket   string `json:"crl_bucket" yaml:"crl_bucket"`
+	// IssuersBucket specifies location for issuer certificates and PKCS#7 bundles,
+	// it must match the AIA caIssuers URL.
+	// If empty, CertsBucket is used.
+	IssuersBucket string `json:"issuer_bucket,omitempty" yaml:"issuer_bucket,omitempty"`
+	// RootsBucket specifies location for the root store.
+	// If empty, the root store is not published.
+	RootsBucket string `json:"roots_bucket,omitempty" yaml:"roots_bucket,omitempty"`
+
+	// S3 specifies options for s3:// buckets
+	S3 storage.S3Options `json:"s3,omitempty" yaml:"s3,omitempty"`
+	// Filesystem specifies options for local folders
+	Filesystem storage.FilesystemOptions `json:"filesystem,omitempty" yaml:"filesystem,omitempty"`
+
+	// Policies specifies Content-Type and Cache-Control by object kind:
+	// cert, crl, issuer, p7c, roots
+	Policies map[string]*certpublisher.Policy `json:"policies,omitempty" yaml:"policies,omitempty"`
+	// Destinations specifies additional locations,
+	// the objects are published to all of them
+	Destinations []*certpublisher.Destination `json:"destinations,omitempty" yaml:"destinations,omitempty"`
+}
+
+// PublisherConfig returns the configuration for certpublisher
+func (p *Publisher) PublisherConfig() *certpublisher.Config {
+	return &certpublisher.Config{
+		CertsBucket:   p.CertsBucket,
+		CRLBucket:     p.CRLBucket,
+		IssuersBucket: p.IssuersBucket,
+		RootsBucket:   p.RootsBucket,
+		S3:            p.S3,
+		Filesystem:    p.Filesystem,
+		Policies:      p.Policies,
+		Destinations:  p.Destinations,
+	}
 }
 
 // GenCerts contains configuration info for the auto generated certificates
@@ -0,0 +1,386 @@
+// Package backup provides encrypted backup and restore of CA tables
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/backend/db/cadb", "backup")
+
+// FormatVersion specifies the version of the archive format
+const FormatVersion = 1
+
+// ManifestFileName is the name of the manifest in the archive
+const ManifestFileName = "manifest.json"
+
+// Tables specifies the tables included in the backup,
+// in the order of restore.
+// The pre-signed OCSP responses are not included,
+// as they are signed again by the OCSP updater,
+// as well as the delegated OCSP responders, which are issued again on demand,
+// and the idempotency keys of sign requests.
+var Tables = []string{
+	cadb.TableNameForIssuers,
+	cadb.TableNameForCertProfiles,
+	cadb.TableNameForRoots,
+	cadb.TableNameForCertificates,
+	cadb.TableNameForRevoked,
+	cadb.TableNameForRevokedTombstones,
+	cadb.TableNameForCrls,
+	cadb.TableNameForDeltaCrls,
+	cadb.TableNameForCrlNumbers,
+	cadb.TableNameForShardCrls,
+	cadb.TableNameForShardCrlNumbers,
+	cadb.TableNameForBlockedKeys,
+	cadb.TableNameForPendingRevocations,
+}
+
+// Manifest describes the backup archive
+type Manifest struct {
+	FormatVersion int          `json:"format_version"`
+	SchemaVersion uint         `json:"schema_version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	Tables        []*TableInfo `json:"tables"`
+}
+
+// TableInfo describes the table in the archive
+type TableInfo struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Rows   int    `json:"rows"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Backup writes a consistent snapshot of CA tables to w,
+// as gzip compressed tar archive with the manifest as the first entry
+func Backup(ctx context.Context, db xdb.Provider, w io.Writer) (*Manifest, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// the snapshot is read only
+	defer func() { _ = tx.Rollback() }()
+
+	if db.Name() == "postgres" {
+		_, err = tx.ExecContext(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY;`)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	version, err := SchemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		FormatVersion: FormatVersion,
+		SchemaVersion: version,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	// the first pass computes the size and checksum of each table,
+	// as tar headers and the manifest precede the content
+	for _, table := range Tables {
+		h := sha256.New()
+		cw := &countingWriter{w: h}
+		rows, err := exportTable(ctx, tx, table, cw)
+		if err != nil {
+			return nil, err
+		}
+		m.Tables = append(m.Tables, &TableInfo{
+			Name:   table,
+			File:   table + ".jsonl",
+			Rows:   rows,
+			Size:   cw.n,
+			SHA256: hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = writeTarEntry(tw, ManifestFileName, int64(len(manifest)), m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tw.Write(manifest); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, ti := range m.Tables {
+		err = writeTarEntry(tw, ti.File, ti.Size, m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		h := sha256.New()
+		_, err = exportTable(ctx, tx, ti.Name, io.MultiWriter(tw, h))
+		if err != nil {
+			return nil, err
+		}
+		if hex.EncodeToString(h.Sum(nil)) != ti.SHA256 {
+			return nil, errors.Errorf("table %s changed during backup", ti.Name)
+		}
+
+		logger.ContextKV(ctx, xlog.INFO, "table", ti.Name, "rows", ti.Rows)
+	}
+
+	if err = tw.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = gz.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return m, nil
+}
+
+// Restore loads the archive created by Backup into an empty database.
+// The tables are restored in a single transaction,
+// which is committed only if all checksums match.
+func Restore(ctx context.Context, db xdb.Provider, r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid archive")
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid archive")
+	}
+	if hdr.Name != ManifestFileName {
+		return nil, errors.Errorf("invalid archive: expected %s, found %s", ManifestFileName, hdr.Name)
+	}
+
+	m := new(Manifest)
+	if err = json.NewDecoder(tr).Decode(m); err != nil {
+		return nil, errors.WithMessage(err, "invalid manifest")
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, errors.Errorf("unsupported archive format: %d", m.FormatVersion)
+	}
+
+	version, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if version != m.SchemaVersion {
+		return nil, errors.Errorf("schema version mismatch: archive %d, database %d", m.SchemaVersion, version)
+	}
+
+	for _, ti := range m.Tables {
+		if !isKnownTable(ti.Name) {
+			return nil, errors.Errorf("unsupported table: %s", ti.Name)
+		}
+		var count uint64
+		err = db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s;`, ti.Name)).Scan(&count)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if count > 0 {
+			return nil, errors.Errorf("database is not empty: table %s has %d rows", ti.Name, count)
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = restoreTables(ctx, tx, tr, m)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return m, nil
+}
+
+func restoreTables(ctx context.Context, tx xdb.Provider, tr *tar.Reader, m *Manifest) error {
+	for _, ti := range m.Tables {
+		hdr, err := tr.Next()
+		if err != nil {
+			return errors.WithMessagef(err, "missing table in archive: %s", ti.Name)
+		}
+		if hdr.Name != ti.File {
+			return errors.Errorf("invalid archive: expected %s, found %s", ti.File, hdr.Name)
+		}
+
+		h := sha256.New()
+		rows, err := importTable(ctx, tx, ti.Name, io.TeeReader(tr, h))
+		if err != nil {
+			return err
+		}
+		if rows != ti.Rows || hex.EncodeToString(h.Sum(nil)) != ti.SHA256 {
+			return errors.Errorf("checksum mismatch: table %s", ti.Name)
+		}
+
+		logger.ContextKV(ctx, xlog.INFO, "table", ti.Name, "rows", rows)
+	}
+	return nil
+}
+
+// SchemaVersion returns the version of the applied migrations
+func SchemaVersion(ctx context.Context, db xdb.DB) (uint, error) {
+	var version uint
+	var dirty bool
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1;`).Scan(&version, &dirty)
+	if err != nil {
+		return 0, errors.WithMessage(err, "unable to get schema version")
+	}
+	if dirty {
+		return 0, errors.Errorf("schema version %d is dirty", version)
+	}
+	return version, nil
+}
+
+// exportTable writes table rows as JSON lines, ordered by the primary key,
+// which is the first column of the tables
+func exportTable(ctx context.Context, db xdb.DB, table string, w io.Writer) (int, error) {
+	res, err := db.QueryContext(ctx, fmt.Sprintf(`SELECT * FROM %s ORDER BY 1 ASC;`, table))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	cols, err := res.Columns()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	count := 0
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+
+	enc := json.NewEncoder(w)
+	for res.Next() {
+		if err = res.Scan(ptrs...); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			v := vals[i]
+			switch t := v.(type) {
+			case []byte:
+				v = string(t)
+			case time.Time:
+				v = t.UTC().Format(time.RFC3339Nano)
+			}
+			row[col] = v
+		}
+		if err = enc.Encode(row); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		count++
+	}
+	if err = res.Err(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return count, nil
+}
+
+// importTable inserts rows from JSON lines
+func importTable(ctx context.Context, db xdb.DB, table string, r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	count := 0
+	for {
+		row := map[string]any{}
+		err := dec.Decode(&row)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, errors.WithMessagef(err, "invalid row: table %s", table)
+		}
+
+		cols := make([]string, 0, len(row))
+		for col := range row {
+			cols = append(cols, col)
+		}
+		sort.Strings(cols)
+
+		params := make([]string, len(cols))
+		args := make([]any, len(cols))
+		for i, col := range cols {
+			params[i] = fmt.Sprintf("$%d", i+1)
+			v := row[col]
+			if n, ok := v.(json.Number); ok {
+				v = n.String()
+			}
+			args[i] = v
+		}
+
+		_, err = db.ExecContext(ctx,
+			fmt.Sprintf(`INSERT INTO %s(%s) VALUES(%s);`, table, strings.Join(cols, ","), strings.Join(params, ",")),
+			args...)
+		if err != nil {
+			return 0, errors.WithMessagef(err, "unable to restore table %s", table)
+		}
+		count++
+	}
+	return count, nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, size int64, modTime time.Time) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: modTime,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func isKnownTable(name string) bool {
+	for _, t := range Tables {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
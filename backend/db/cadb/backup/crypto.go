@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/pkg/errors"
+)
+
+// NewEncryptor returns a writer that encrypts the archive
+// with a passphrase, or for the age X25519 recipients.
+// The caller must close the writer to flush the last chunk.
+func NewEncryptor(w io.Writer, passphrase string, recipients []string) (io.WriteCloser, error) {
+	var list []age.Recipient
+	switch {
+	case passphrase != "" && len(recipients) > 0:
+		return nil, errors.New("passphrase and recipients can't be used together")
+	case passphrase != "":
+		r, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, r)
+	case len(recipients) > 0:
+		for _, s := range recipients {
+			r, err := age.ParseRecipients(strings.NewReader(s))
+			if err != nil {
+				return nil, errors.WithMessagef(err, "invalid recipient")
+			}
+			list = append(list, r...)
+		}
+	default:
+		return nil, errors.New("passphrase or recipient must be specified")
+	}
+
+	ew, err := age.Encrypt(w, list...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ew, nil
+}
+
+// NewDecryptor returns a reader that decrypts the archive
+// with a passphrase, or with the age identities
+func NewDecryptor(r io.Reader, passphrase string, identities io.Reader) (io.Reader, error) {
+	var list []age.Identity
+	switch {
+	case passphrase != "":
+		id, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, id)
+	case identities != nil:
+		ids, err := age.ParseIdentities(identities)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid identity")
+		}
+		list = append(list, ids...)
+	default:
+		return nil, errors.New("passphrase or identity must be specified")
+	}
+
+	dr, err := age.Decrypt(r, list...)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to decrypt archive")
+	}
+	return dr, nil
+}
@@ -17,13 +17,24 @@ import (
 
 // Table names
 const (
-	TableNameForCertificates = "certificates"
-	TableNameForRevoked      = "revoked"
-	TableNameForCrls         = "crls"
-	TableNameForIssuers      = "issuers"
-	TableNameForRoots        = "roots"
-	TableNameForCertProfiles = "cert_profiles"
-	TableNameForNonces       = "nonces"
+	TableNameForCertificates       = "certificates"
+	TableNameForRevoked            = "revoked"
+	TableNameForRevokedTombstones  = "revoked_tombstones"
+	TableNameForCrls               = "crls"
+	TableNameForDeltaCrls          = "delta_crls"
+	TableNameForCrlNumbers         = "crl_numbers"
+	TableNameForShardCrls          = "shard_crls"
+	TableNameForShardCrlNumbers    = "shard_crl_numbers"
+	TableNameForIssuers            = "issuers"
+	TableNameForRoots              = "roots"
+	TableNameForCertProfiles       = "cert_profiles"
+	TableNameForNonces             = "nonces"
+	TableNameForPublishJobs        = "publish_jobs"
+	TableNameForOcsp               = "ocsp_responses"
+	TableNameForResponders         = "ocsp_responders"
+	TableNameForSignRequests       = "sign_requests"
+	TableNameForBlockedKeys        = "blocked_keys"
+	TableNameForPendingRevocations = "pending_revocations"
 )
 
 // CaReadonlyDb defines an interface for Read operations on Certs
@@ -36,17 +47,34 @@ type CaReadonlyDb interface {
 	GetCertificatesBySKID(ctx context.Context, skid string) ([]*model.Certificate, error)
 	// GetCertificateByIKIDAndSerial returns registered Certificate
 	GetCertificateByIKIDAndSerial(ctx context.Context, ikid, serial string) (*model.Certificate, error)
+	// GetRevokedCertificate returns revoked certificate by the ID of the certificate
+	GetRevokedCertificate(ctx context.Context, id uint64) (*model.RevokedCertificate, error)
 	// GetRevokedCertificateByIKIDAndSerial returns revoked certificate
 	GetRevokedCertificateByIKIDAndSerial(ctx context.Context, ikid, serial string) (*model.RevokedCertificate, error)
 	// GetCrl returns CRL by a specified issuer
 	GetCrl(ctx context.Context, ikid string) (*model.Crl, error)
+	// ListCrls returns CRLs of all issuers
+	ListCrls(ctx context.Context) ([]*model.Crl, error)
+	// GetDeltaCrl returns delta CRL by a specified issuer
+	GetDeltaCrl(ctx context.Context, ikid string) (*model.Crl, error)
+	// GetShardCrl returns CRL of the shard by a specified issuer
+	GetShardCrl(ctx context.Context, ikid string, shard uint32) (*model.Crl, error)
+	// ListShardCrls returns CRL shards of all issuers
+	ListShardCrls(ctx context.Context) ([]*model.Crl, error)
+	// GetOcspResponse returns pre-signed OCSP response of the certificate
+	GetOcspResponse(ctx context.Context, ikid, serial string) (*model.OcspResponse, error)
+	// ListOcspResponses returns pre-signed OCSP responses of the issuer,
+	// for the certificates with ID in the specified range, inclusive.
+	// The responses are returned without DER.
+	ListOcspResponses(ctx context.Context, ikid string, fromID, toID uint64) (model.OcspResponses, error)
 	// ListOrgRevokedCertificates returns list of Org's revoked certificates
 	ListOrgRevokedCertificates(ctx context.Context, orgID uint64, limit int, afterID uint64) (model.RevokedCertificates, error)
 	// ListRevokedCertificates returns revoked certificates info by a specified issuer
 	ListRevokedCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.RevokedCertificates, error)
 	// ListOrgCertificates returns Certificates for organization
 	ListOrgCertificates(ctx context.Context, orgID uint64, limit int, afterID uint64) (model.Certificates, error)
-	// ListCertificates returns list of Certificate info
+	// ListCertificates returns list of Certificate info,
+	// if ikid is empty, then certificates of all issuers are returned
 	ListCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.Certificates, error)
 	// ListIssuers returns list of Issuer
 	ListIssuers(ctx context.Context, limit int, afterID uint64) ([]*model.Issuer, error)
@@ -71,6 +99,8 @@ type CaDb interface {
 
 	// RegisterCertificate registers Certificate
 	RegisterCertificate(ctx context.Context, crt *model.Certificate) (*model.Certificate, error)
+	// RegisterCertificates registers the list of Certificate in a single statement
+	RegisterCertificates(ctx context.Context, list []*model.Certificate) ([]*model.Certificate, error)
 	// RemoveCertificate removes Certificate
 	RemoveCertificate(ctx context.Context, id uint64) error
 	// UpdateCertificateLabel update Certificate label
@@ -87,6 +117,64 @@ type CaDb interface {
 	RegisterCrl(ctx context.Context, crt *model.Crl) (*model.Crl, error)
 	// RemoveCrl removes CRL
 	RemoveCrl(ctx context.Context, id uint64) error
+	// RegisterDeltaCrl registers delta CRL
+	RegisterDeltaCrl(ctx context.Context, crl *model.Crl) (*model.Crl, error)
+	// RegisterShardCrl registers CRL of the shard
+	RegisterShardCrl(ctx context.Context, crl *model.Crl) (*model.Crl, error)
+	// NextCrlNumber returns the next CRL number for the issuer,
+	// the number is shared by complete and delta CRLs
+	NextCrlNumber(ctx context.Context, ikid string) (uint64, error)
+	// NextShardCrlNumber returns the next CRL number for the shard of the issuer,
+	// each shard has own sequence
+	NextShardCrlNumber(ctx context.Context, ikid string, shard uint32) (uint64, error)
+
+	// RegisterOcspResponse registers pre-signed OCSP response of the certificate,
+	// the revoked response is not replaced by the good one
+	RegisterOcspResponse(ctx context.Context, r *model.OcspResponse) (*model.OcspResponse, error)
+	// MarkOcspResponseRevoked marks pre-signed OCSP response of the certificate
+	// as revoked and expired at the specified time, to be signed again.
+	// The marked response is not served, and is not replaced by the good one.
+	MarkOcspResponseRevoked(ctx context.Context, id uint64, ikid, serial string, at time.Time) error
+	// RemoveOcspResponse removes pre-signed OCSP response of the certificate
+	RemoveOcspResponse(ctx context.Context, id uint64) error
+	// PurgeOcspResponses removes OCSP responses expired before the specified time
+	PurgeOcspResponses(ctx context.Context, before time.Time) (int64, error)
+
+	// RegisterOcspResponder registers delegated OCSP responder of the issuer
+	RegisterOcspResponder(ctx context.Context, r *model.OcspResponder) (*model.OcspResponder, error)
+	// GetOcspResponder returns delegated OCSP responder of the issuer,
+	// with the latest expiration time
+	GetOcspResponder(ctx context.Context, ikid string) (*model.OcspResponder, error)
+	// PurgeOcspResponders removes OCSP responders expired before the specified time
+	PurgeOcspResponders(ctx context.Context, before time.Time) (int64, error)
+
+	// ReserveSignRequest registers the idempotency key of the sign request,
+	// or returns the existing request with the same key
+	ReserveSignRequest(ctx context.Context, r *model.SignRequest) (*model.SignRequest, error)
+	// GetSignRequest returns the sign request by the idempotency key in the org
+	GetSignRequest(ctx context.Context, orgID uint64, key string) (*model.SignRequest, error)
+	// CompleteSignRequest sets the issued certificate of the sign request
+	CompleteSignRequest(ctx context.Context, id, certID uint64) (*model.SignRequest, error)
+	// DeleteSignRequest deletes the sign request
+	DeleteSignRequest(ctx context.Context, id uint64) error
+	// PurgeSignRequests removes sign requests created before the specified time
+	PurgeSignRequests(ctx context.Context, before time.Time) (int64, error)
+
+	// BlockKey registers the blocked key,
+	// or returns the existing record with the same SPKI hash
+	BlockKey(ctx context.Context, r *model.BlockedKey) (*model.BlockedKey, error)
+	// GetBlockedKey returns the blocked key by hex encoded SHA256 of SPKI
+	GetBlockedKey(ctx context.Context, spkiHash string) (*model.BlockedKey, error)
+	// UnblockKey removes the blocked key
+	UnblockKey(ctx context.Context, spkiHash string) error
+
+	// RegisterPendingRevocation schedules the certificate to revoke,
+	// the existing record is returned with the earliest revoke_at
+	RegisterPendingRevocation(ctx context.Context, r *model.PendingRevocation) (*model.PendingRevocation, error)
+	// ListPendingRevocations returns the certificates to revoke before the specified time
+	ListPendingRevocations(ctx context.Context, before time.Time, limit int) (model.PendingRevocations, error)
+	// RemovePendingRevocation removes the pending revocation
+	RemovePendingRevocation(ctx context.Context, id uint64) error
 
 	// CreateNonce returns Nonce
 	CreateNonce(ctx context.Context, nonce *model.Nonce) (*model.Nonce, error)
@@ -94,6 +182,31 @@ type CaDb interface {
 	UseNonce(ctx context.Context, nonce string) (*model.Nonce, error)
 	// DeleteNonce deletes the nonce
 	DeleteNonce(ctx context.Context, id uint64) error
+	// PurgeNonces removes nonces used or expired before the specified time
+	PurgeNonces(ctx context.Context, before time.Time) (int64, error)
+
+	// PurgeExpiredCertificates removes certificates expired before the specified time
+	PurgeExpiredCertificates(ctx context.Context, before time.Time, limit int) (model.Certificates, error)
+	// PurgeExpiredRevokedCertificates removes revoked certificates expired before the specified time,
+	// and keeps their revocation info as tombstones
+	PurgeExpiredRevokedCertificates(ctx context.Context, before time.Time, limit int) (model.RevokedCertificates, error)
+	// GetRevokedTombstone returns the revocation info of the purged revoked certificate
+	GetRevokedTombstone(ctx context.Context, ikid, serial string) (*model.RevokedTombstone, error)
+
+	// EnqueuePublishJob adds the job to the publish outbox
+	EnqueuePublishJob(ctx context.Context, job *model.PublishJob) (*model.PublishJob, error)
+	// EnqueuePublishJobs adds the list of jobs to the publish outbox
+	EnqueuePublishJobs(ctx context.Context, jobs model.PublishJobs) (model.PublishJobs, error)
+	// ClaimPublishJobs returns pending jobs due to publish, and marks them as processing
+	ClaimPublishJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) (model.PublishJobs, error)
+	// UpdatePublishJob updates the job status
+	UpdatePublishJob(ctx context.Context, job *model.PublishJob) (*model.PublishJob, error)
+	// ListPublishJobs returns the jobs with the specified status
+	ListPublishJobs(ctx context.Context, status string, limit int, afterID uint64) (model.PublishJobs, error)
+	// GetPublishJobsCounts returns number of jobs by status
+	GetPublishJobsCounts(ctx context.Context) (map[string]int64, error)
+	// PurgePublishJobs removes published jobs updated before the specified time
+	PurgePublishJobs(ctx context.Context, before time.Time) (int64, error)
 
 	// RegisterIssuer registers Issuer config
 	RegisterIssuer(ctx context.Context, crt *model.Issuer) (*model.Issuer, error)
@@ -0,0 +1,91 @@
+package legacy
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/effective-security/xpki/certutil"
+	"github.com/pkg/errors"
+
+	// register pure Go SQLite driver for CFSSL certdb
+	_ "modernc.org/sqlite"
+)
+
+// CFSSL certdb statuses
+const (
+	cfsslRevoked = "revoked"
+)
+
+// OpenCFSSLCertDB opens CFSSL certdb.
+// The data source can be PostgreSQL URL, or SQLite file name.
+func OpenCFSSLCertDB(dataSource string) (*sql.DB, error) {
+	driver := "sqlite"
+	if strings.HasPrefix(dataSource, "postgres://") ||
+		strings.HasPrefix(dataSource, "postgresql://") {
+		driver = "postgres"
+	}
+
+	db, err := sql.Open(driver, dataSource)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to open CFSSL certdb")
+	}
+	return db, nil
+}
+
+// LoadCFSSLCertDB loads entries from CFSSL certdb,
+// and calls the function for each batch of the entries
+func LoadCFSSLCertDB(ctx context.Context, db *sql.DB, batchSize int, fn BatchFunc) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT
+			serial_number,status,reason,revoked_at,pem
+		FROM
+			certificates
+		ORDER BY
+			expiry ASC
+		;`)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	b := newBatcher(batchSize, fn)
+	for rows.Next() {
+		var serial, status, pem string
+		var reason sql.NullInt64
+		var revokedAt sql.NullTime
+
+		err = rows.Scan(&serial, &status, &reason, &revokedAt, &pem)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		crt, err := certutil.ParseFromPEM([]byte(pem))
+		if err != nil {
+			return errors.WithMessagef(err, "unable to parse certificate: %s", serial)
+		}
+		if crt.SerialNumber.String() != serial {
+			return errors.Errorf("serial mismatch: expected %s", serial)
+		}
+
+		e := &Entry{
+			Certificate: crt,
+		}
+		if status == cfsslRevoked {
+			if !revokedAt.Valid {
+				return errors.Errorf("missing revocation date: %s", serial)
+			}
+			e.Revoked = true
+			e.RevokedAt = revokedAt.Time.UTC()
+			e.Reason = int(reason.Int64)
+		}
+		if err = b.add(e); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return b.flush()
+}
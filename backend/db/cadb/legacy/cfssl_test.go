@@ -0,0 +1,87 @@
+package legacy_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/legacy"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cfsslSchema is CFSSL certdb schema for SQLite
+const cfsslSchema = `
+CREATE TABLE certificates (
+  serial_number            blob NOT NULL,
+  authority_key_identifier blob NOT NULL,
+  ca_label                 blob,
+  status                   blob NOT NULL,
+  reason                   int,
+  expiry                   timestamp,
+  revoked_at               timestamp,
+  pem                      blob NOT NULL,
+  PRIMARY KEY(serial_number, authority_key_identifier)
+);`
+
+func TestLoadCFSSLCertDB(t *testing.T) {
+	ca := newTestCA(t)
+	ctx := context.Background()
+
+	db, err := legacy.OpenCFSSLCertDB(filepath.Join(t.TempDir(), "certdb.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, cfsslSchema)
+	require.NoError(t, err)
+
+	revokedAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, status := range []string{"good", "revoked"} {
+		crt := ca.issue(t, int64(0x1000+i))
+		pem, err := certutil.EncodeToPEMString(false, crt)
+		require.NoError(t, err)
+
+		var at any
+		reason := 0
+		if status == "revoked" {
+			at = revokedAt
+			reason = 4
+		}
+		_, err = db.ExecContext(ctx,
+			`INSERT INTO certificates(serial_number,authority_key_identifier,ca_label,status,reason,expiry,revoked_at,pem)
+				VALUES(?,?,?,?,?,?,?,?)`,
+			crt.SerialNumber.String(), certutil.GetAuthorityKeyID(crt), "", status, reason, crt.NotAfter, at, pem)
+		require.NoError(t, err)
+	}
+
+	var list []*legacy.Entry
+	batches := 0
+	err = legacy.LoadCFSSLCertDB(ctx, db, 1, func(entries []*legacy.Entry) error {
+		batches++
+		list = append(list, entries...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, 2, batches)
+
+	assert.False(t, list[0].Revoked)
+	assert.Equal(t, int64(0x1000), list[0].Certificate.SerialNumber.Int64())
+	assert.True(t, list[1].Revoked)
+	assert.Equal(t, revokedAt, list[1].RevokedAt)
+	assert.Equal(t, 4, list[1].Reason)
+
+	_, err = db.ExecContext(ctx, `UPDATE certificates SET revoked_at=NULL`)
+	require.NoError(t, err)
+	err = legacy.LoadCFSSLCertDB(ctx, db, 0, ignoreBatch)
+	require.Error(t, err)
+	assert.Equal(t, "missing revocation date: 4097", err.Error())
+
+	_, err = db.ExecContext(ctx, `UPDATE certificates SET pem='invalid'`)
+	require.NoError(t, err)
+	err = legacy.LoadCFSSLCertDB(ctx, db, 0, ignoreBatch)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse certificate")
+}
@@ -0,0 +1,189 @@
+// Package legacy provides migration of the certificates issued by legacy CAs,
+// from OpenSSL index.txt or CFSSL certdb
+package legacy
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/pkg/errors"
+)
+
+var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/backend/db/cadb", "legacy")
+
+// Entry provides a certificate record from a legacy CA database
+type Entry struct {
+	// Certificate provides the issued certificate
+	Certificate *x509.Certificate
+	// Revoked is set if the certificate was revoked
+	Revoked bool
+	// RevokedAt is the revocation time
+	RevokedAt time.Time
+	// Reason is RFC 5280 revocation reason code
+	Reason int
+}
+
+// Options specifies migration options
+type Options struct {
+	// Source specifies the name of the legacy CA, stored in certificate metadata
+	Source string
+	// OrgID specifies the Organization for migrated certificates
+	OrgID uint64
+	// Profile specifies the profile name for migrated certificates
+	Profile string
+	// Label specifies the label for migrated certificates
+	Label string
+	// DryRun specifies to validate entries without registering them
+	DryRun bool
+}
+
+// Result provides migration summary
+type Result struct {
+	Issued  int
+	Revoked int
+	Skipped int
+}
+
+// Add adds the counts of the other result
+func (r *Result) Add(other *Result) {
+	r.Issued += other.Issued
+	r.Revoked += other.Revoked
+	r.Skipped += other.Skipped
+}
+
+// BatchSize specifies the default number of entries loaded at a time,
+// so the memory is bounded for large legacy databases
+const BatchSize = 1000
+
+// BatchFunc is called for each batch of the loaded entries.
+// The loading is stopped, if the function returns error.
+type BatchFunc func(entries []*Entry) error
+
+// batcher groups the loaded entries in batches
+type batcher struct {
+	size int
+	fn   BatchFunc
+	list []*Entry
+}
+
+func newBatcher(size int, fn BatchFunc) *batcher {
+	if size <= 0 {
+		size = BatchSize
+	}
+	return &batcher{
+		size: size,
+		fn:   fn,
+		list: make([]*Entry, 0, size),
+	}
+}
+
+func (b *batcher) add(e *Entry) error {
+	b.list = append(b.list, e)
+	if len(b.list) < b.size {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *batcher) flush() error {
+	if len(b.list) == 0 {
+		return nil
+	}
+	list := b.list
+	b.list = make([]*Entry, 0, b.size)
+	return b.fn(list)
+}
+
+// Migrate registers the legacy entries in certificates and revoked tables,
+// under the specified issuer.
+// The entries not signed by the issuer are skipped.
+func Migrate(ctx context.Context, db cadb.CaDb, issuer *authority.Issuer, entries []*Entry, opts Options) (*Result, error) {
+	res := new(Result)
+	issuerCert := issuer.Bundle().Cert
+	ikid := issuer.SubjectKID()
+
+	for _, e := range entries {
+		crt := e.Certificate
+		if err := crt.CheckSignatureFrom(issuerCert); err != nil {
+			logger.ContextKV(ctx, xlog.WARNING,
+				"reason", "not_signed_by_issuer",
+				"issuer", issuer.Label(),
+				"serial", crt.SerialNumber.String(),
+				"subject", crt.Subject.String(),
+			)
+			res.Skipped++
+			continue
+		}
+
+		if opts.DryRun {
+			if e.Revoked {
+				res.Revoked++
+			} else {
+				res.Issued++
+			}
+			continue
+		}
+
+		pem, err := certutil.EncodeToPEMString(false, crt)
+		if err != nil {
+			return res, errors.WithStack(err)
+		}
+
+		meta := map[string]string{}
+		if opts.Source != "" {
+			meta["migrated_from"] = opts.Source
+		}
+
+		mcert := model.NewCertificate(crt, opts.OrgID, opts.Profile, pem, issuer.PEM(), opts.Label, nil, meta)
+		// legacy certificates may not have AKID
+		mcert.IKID = ikid
+
+		if !e.Revoked {
+			_, err = db.RegisterCertificate(ctx, mcert)
+			if err != nil {
+				return res, errors.WithMessagef(err, "unable to register certificate: %s", mcert.SerialNumber)
+			}
+			res.Issued++
+			continue
+		}
+
+		// remove the certificate, if it was migrated before revocation
+		existing, err := db.GetCertificateByIKIDAndSerial(ctx, ikid, mcert.SerialNumber)
+		if err == nil {
+			err = db.RemoveCertificate(ctx, existing.ID)
+			if err != nil {
+				return res, errors.WithMessagef(err, "unable to remove certificate: %s", mcert.SerialNumber)
+			}
+		} else if !xdb.IsNotFoundError(err) {
+			return res, errors.WithMessagef(err, "unable to find certificate: %s", mcert.SerialNumber)
+		}
+
+		_, err = db.RegisterRevokedCertificate(ctx, &model.RevokedCertificate{
+			Certificate: *mcert,
+			RevokedAt:   xdb.Time(e.RevokedAt.UTC()),
+			Reason:      e.Reason,
+		})
+		if err != nil {
+			return res, errors.WithMessagef(err, "unable to register revoked certificate: %s", mcert.SerialNumber)
+		}
+		res.Revoked++
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"issuer", issuer.Label(),
+		"source", opts.Source,
+		"issued", res.Issued,
+		"revoked", res.Revoked,
+		"skipped", res.Skipped,
+		"dry_run", opts.DryRun,
+	)
+
+	return res, nil
+}
@@ -0,0 +1,203 @@
+package legacy_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/legacy"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert    *x509.Certificate
+	signer  crypto.Signer
+	pem     string
+	rootPem string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "[TEST] Legacy Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "[TEST] Legacy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err = x509.CreateCertificate(rand.Reader, template, root, key.Public(), rootKey)
+	require.NoError(t, err)
+	crt, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	ca := &testCA{cert: crt, signer: key}
+	ca.pem, err = certutil.EncodeToPEMString(false, crt)
+	require.NoError(t, err)
+	ca.rootPem, err = certutil.EncodeToPEMString(false, root)
+	require.NoError(t, err)
+	return ca
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "legacy.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.signer)
+	require.NoError(t, err)
+	crt, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return crt
+}
+
+func (ca *testCA) issuer(t *testing.T) *authority.Issuer {
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label: "legacy",
+		AIA:   &authority.AIAConfig{},
+	}, []byte(ca.pem), nil, []byte(ca.rootPem), ca.signer)
+	require.NoError(t, err)
+	return issuer
+}
+
+type mockDB struct {
+	cadb.CaDb
+	certs   map[string]*model.Certificate
+	revoked map[string]*model.RevokedCertificate
+	lastID  uint64
+}
+
+func newMockDB() *mockDB {
+	return &mockDB{
+		certs:   map[string]*model.Certificate{},
+		revoked: map[string]*model.RevokedCertificate{},
+	}
+}
+
+func (m *mockDB) RegisterCertificate(_ context.Context, crt *model.Certificate) (*model.Certificate, error) {
+	m.lastID++
+	crt.ID = m.lastID
+	m.certs[crt.SerialNumber] = crt
+	return crt, nil
+}
+
+func (m *mockDB) GetCertificateByIKIDAndSerial(_ context.Context, ikid, serial string) (*model.Certificate, error) {
+	crt := m.certs[serial]
+	if crt == nil || crt.IKID != ikid {
+		return nil, sql.ErrNoRows
+	}
+	return crt, nil
+}
+
+func (m *mockDB) RemoveCertificate(_ context.Context, id uint64) error {
+	for sn, crt := range m.certs {
+		if crt.ID == id {
+			delete(m.certs, sn)
+		}
+	}
+	return nil
+}
+
+func (m *mockDB) RegisterRevokedCertificate(_ context.Context, r *model.RevokedCertificate) (*model.RevokedCertificate, error) {
+	m.revoked[r.Certificate.SerialNumber] = r
+	return r, nil
+}
+
+func TestMigrate(t *testing.T) {
+	ca := newTestCA(t)
+	issuer := ca.issuer(t)
+	other := newTestCA(t)
+
+	revokedAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []*legacy.Entry{
+		{Certificate: ca.issue(t, 0x1001)},
+		{Certificate: ca.issue(t, 0x1002), Revoked: true, RevokedAt: revokedAt, Reason: 1},
+		{Certificate: other.issue(t, 0x1003)},
+	}
+
+	ctx := context.Background()
+	db := newMockDB()
+
+	res, err := legacy.Migrate(ctx, db, issuer, entries, legacy.Options{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, legacy.Result{Issued: 1, Revoked: 1, Skipped: 1}, *res)
+	assert.Empty(t, db.certs)
+	assert.Empty(t, db.revoked)
+
+	// migrate the revoked certificate as valid first
+	_, err = db.RegisterCertificate(ctx, model.NewCertificate(entries[1].Certificate, 0, "legacy", "pem", "", "", nil, nil))
+	require.NoError(t, err)
+	db.certs["4098"].IKID = issuer.SubjectKID()
+
+	opts := legacy.Options{
+		Source:  "openssl",
+		OrgID:   123,
+		Profile: "legacy",
+		Label:   "migrated",
+	}
+	res, err = legacy.Migrate(ctx, db, issuer, entries, opts)
+	require.NoError(t, err)
+	assert.Equal(t, legacy.Result{Issued: 1, Revoked: 1, Skipped: 1}, *res)
+
+	require.Len(t, db.certs, 1)
+	crt := db.certs["4097"]
+	require.NotNil(t, crt)
+	assert.Equal(t, issuer.SubjectKID(), crt.IKID)
+	assert.Equal(t, uint64(123), crt.OrgID)
+	assert.Equal(t, "legacy", crt.Profile)
+	assert.Equal(t, "migrated", crt.Label)
+	assert.Equal(t, "openssl", crt.Metadata["migrated_from"])
+
+	require.Len(t, db.revoked, 1)
+	r := db.revoked["4098"]
+	require.NotNil(t, r)
+	assert.Equal(t, revokedAt, r.RevokedAt.UTC())
+	assert.Equal(t, 1, r.Reason)
+	assert.Equal(t, issuer.SubjectKID(), r.Certificate.IKID)
+}
+
+func TestResultAdd(t *testing.T) {
+	res := &legacy.Result{Issued: 1, Skipped: 2}
+	res.Add(&legacy.Result{Issued: 3, Revoked: 4, Skipped: 5})
+	assert.Equal(t, legacy.Result{Issued: 4, Revoked: 4, Skipped: 7}, *res)
+}
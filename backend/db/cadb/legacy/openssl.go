@@ -0,0 +1,162 @@
+package legacy
+
+import (
+	"bufio"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/effective-security/x/fileutil"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/pkg/errors"
+)
+
+// OpenSSL index.txt status flags
+const (
+	opensslValid   = "V"
+	opensslRevoked = "R"
+	opensslExpired = "E"
+)
+
+// opensslReasons maps OpenSSL revocation reasons to RFC 5280 reason codes
+var opensslReasons = map[string]int{
+	"unspecified":          0,
+	"keyCompromise":        1,
+	"keyTime":              1,
+	"CACompromise":         2,
+	"CAkeyTime":            2,
+	"affiliationChanged":   3,
+	"superseded":           4,
+	"cessationOfOperation": 5,
+	"certificateHold":      6,
+	"holdInstruction":      6,
+	"removeFromCRL":        8,
+	"privilegeWithdrawn":   9,
+	"aACompromise":         10,
+}
+
+// LoadOpenSSLIndex loads entries from OpenSSL CA database,
+// and calls the function for each batch of the entries.
+// The certificates are loaded from certsDir, by the file name in the index,
+// or by {SERIAL}.pem if the file name is unknown.
+func LoadOpenSSLIndex(indexFile, certsDir string, batchSize int, fn BatchFunc) error {
+	f, err := os.Open(indexFile)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	if certsDir == "" {
+		certsDir = filepath.Join(filepath.Dir(indexFile), "newcerts")
+	}
+
+	b := newBatcher(batchSize, fn)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+
+		e, err := parseOpenSSLLine(text, certsDir)
+		if err != nil {
+			return errors.WithMessagef(err, "line %d", line)
+		}
+		if err = b.add(e); err != nil {
+			return err
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return b.flush()
+}
+
+// parseOpenSSLLine parses the index.txt line in the following format:
+// status \t expiration \t revocation[,reason] \t serial \t filename \t subject
+func parseOpenSSLLine(text, certsDir string) (*Entry, error) {
+	fields := strings.Split(text, "\t")
+	if len(fields) != 6 {
+		return nil, errors.Errorf("invalid format: expected 6 fields, found %d", len(fields))
+	}
+
+	status, revocation, serial, filename := fields[0], fields[2], fields[3], fields[4]
+
+	sn, ok := new(big.Int).SetString(serial, 16)
+	if !ok {
+		return nil, errors.Errorf("invalid serial: %q", serial)
+	}
+
+	e := new(Entry)
+	switch status {
+	case opensslValid, opensslExpired:
+	case opensslRevoked:
+		if revocation == "" {
+			return nil, errors.Errorf("missing revocation date: %s", serial)
+		}
+		parts := strings.Split(revocation, ",")
+		at, err := parseOpenSSLTime(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		e.Revoked = true
+		e.RevokedAt = at
+		if len(parts) > 1 {
+			reason, ok := opensslReasons[parts[1]]
+			if !ok {
+				return nil, errors.Errorf("unsupported revocation reason: %q", parts[1])
+			}
+			e.Reason = reason
+		}
+	default:
+		return nil, errors.Errorf("unsupported status: %q", status)
+	}
+
+	var candidates []string
+	if filename != "" && filename != "unknown" {
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(certsDir, filename)
+		}
+		candidates = append(candidates, filename)
+	}
+	candidates = append(candidates,
+		filepath.Join(certsDir, strings.ToUpper(serial)+".pem"),
+		filepath.Join(certsDir, strings.ToLower(serial)+".pem"),
+	)
+
+	for _, fn := range candidates {
+		if fileutil.FileExists(fn) != nil {
+			continue
+		}
+		crt, err := certutil.LoadFromPEM(fn)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "unable to load certificate: %s", fn)
+		}
+		if crt.SerialNumber.Cmp(sn) != 0 {
+			return nil, errors.Errorf("serial mismatch in %s: expected %s", fn, serial)
+		}
+		e.Certificate = crt
+		return e, nil
+	}
+
+	return nil, errors.Errorf("certificate not found: %s", serial)
+}
+
+// parseOpenSSLTime parses UTCTime or GeneralizedTime
+func parseOpenSSLTime(s string) (time.Time, error) {
+	layout := "060102150405Z"
+	if len(s) == 15 {
+		layout = "20060102150405Z"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return t, errors.Errorf("invalid time: %q", s)
+	}
+	return t.UTC(), nil
+}
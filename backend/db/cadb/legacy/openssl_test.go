@@ -0,0 +1,108 @@
+package legacy_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/legacy"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ignoreBatch([]*legacy.Entry) error {
+	return nil
+}
+
+func TestLoadOpenSSLIndex(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certsDir := filepath.Join(dir, "newcerts")
+	require.NoError(t, os.MkdirAll(certsDir, 0700))
+
+	var index strings.Builder
+	for i, status := range []string{"V", "R", "E", "R"} {
+		crt := ca.issue(t, int64(0x1000+i))
+		pem, err := certutil.EncodeToPEMString(true, crt)
+		require.NoError(t, err)
+
+		serial := fmt.Sprintf("%X", crt.SerialNumber)
+		filename := "unknown"
+		if i == 2 {
+			filename = "expired.pem"
+			require.NoError(t, os.WriteFile(filepath.Join(certsDir, filename), []byte(pem), 0600))
+		} else {
+			require.NoError(t, os.WriteFile(filepath.Join(certsDir, serial+".pem"), []byte(pem), 0600))
+		}
+
+		revocation := ""
+		switch i {
+		case 1:
+			revocation = "230102030405Z,keyCompromise"
+		case 3:
+			revocation = "20230102030405Z"
+		}
+		fmt.Fprintf(&index, "%s\t%s\t%s\t%s\t%s\t/CN=%s\n",
+			status, crt.NotAfter.UTC().Format("060102150405Z"), revocation, serial, filename, crt.Subject.CommonName)
+	}
+
+	indexFile := filepath.Join(dir, "index.txt")
+	require.NoError(t, os.WriteFile(indexFile, []byte(index.String()), 0600))
+
+	var list []*legacy.Entry
+	batches := 0
+	err := legacy.LoadOpenSSLIndex(indexFile, "", 3, func(entries []*legacy.Entry) error {
+		batches++
+		list = append(list, entries...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+	assert.Equal(t, 2, batches)
+
+	revokedAt := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.False(t, list[0].Revoked)
+	assert.Equal(t, int64(0x1000), list[0].Certificate.SerialNumber.Int64())
+	assert.True(t, list[1].Revoked)
+	assert.Equal(t, revokedAt, list[1].RevokedAt)
+	assert.Equal(t, 1, list[1].Reason)
+	assert.False(t, list[2].Revoked)
+	assert.Equal(t, int64(0x1002), list[2].Certificate.SerialNumber.Int64())
+	assert.True(t, list[3].Revoked)
+	assert.Equal(t, revokedAt, list[3].RevokedAt)
+	assert.Equal(t, 0, list[3].Reason)
+
+	err = legacy.LoadOpenSSLIndex(filepath.Join(dir, "notfound.txt"), "", 0, ignoreBatch)
+	require.Error(t, err)
+
+	err = legacy.LoadOpenSSLIndex(indexFile, "", 0, func([]*legacy.Entry) error {
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+
+	tcases := []struct {
+		line string
+		err  string
+	}{
+		{"V\t301231235959Z\t\t1000", "line 1: invalid format: expected 6 fields, found 4"},
+		{"X\t301231235959Z\t\t1000\tunknown\t/CN=test", "line 1: unsupported status: \"X\""},
+		{"V\t301231235959Z\t\tXYZ\tunknown\t/CN=test", "line 1: invalid serial: \"XYZ\""},
+		{"R\t301231235959Z\t\t1000\tunknown\t/CN=test", "line 1: missing revocation date: 1000"},
+		{"R\t301231235959Z\t2301\t1000\tunknown\t/CN=test", "line 1: invalid time: \"2301\""},
+		{"R\t301231235959Z\t230102030405Z,unknown\t1000\tunknown\t/CN=test", "line 1: unsupported revocation reason: \"unknown\""},
+		{"V\t301231235959Z\t\t2000\tunknown\t/CN=test", "line 1: certificate not found: 2000"},
+		{"V\t301231235959Z\t\t2000\texpired.pem\t/CN=test", "line 1: serial mismatch in " + filepath.Join(certsDir, "expired.pem") + ": expected 2000"},
+	}
+	for _, tc := range tcases {
+		require.NoError(t, os.WriteFile(indexFile, []byte(tc.line+"\n"), 0600))
+		err = legacy.LoadOpenSSLIndex(indexFile, certsDir, 0, ignoreBatch)
+		require.Error(t, err, tc.line)
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
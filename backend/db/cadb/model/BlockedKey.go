@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/hex"
+
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+)
+
+// BlockedKey sources
+const (
+	// BlockedKeySourceKeyCompromise specifies the key of the certificate
+	// revoked with KeyCompromise reason
+	BlockedKeySourceKeyCompromise = "key_compromise"
+	// BlockedKeySourceAdmin specifies the key blocked by operator
+	BlockedKeySourceAdmin = "admin"
+)
+
+// BlockedKey provides the public key that is not allowed to be certified
+type BlockedKey struct {
+	ID uint64 `db:"id"`
+	// SPKIHash is hex encoded SHA256 of the SubjectPublicKeyInfo
+	SPKIHash string `db:"spki_hash"`
+	// Source specifies the reason the key is blocked
+	Source string `db:"source"`
+	// CertificateID is the ID of the revoked certificate,
+	// or 0 if the key is blocked by operator
+	CertificateID uint64   `db:"certificate_id"`
+	CreatedAt     xdb.Time `db:"created_at"`
+}
+
+// Validate returns error if the model is not valid
+func (r *BlockedKey) Validate() error {
+	if r.ID == 0 {
+		return errors.New("invalid ID")
+	}
+	if b, err := hex.DecodeString(r.SPKIHash); err != nil || len(b) != 32 {
+		return errors.New("invalid SPKI hash")
+	}
+	if r.Source == "" || len(r.Source) > 32 {
+		return errors.New("invalid source")
+	}
+	return nil
+}
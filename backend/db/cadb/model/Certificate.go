@@ -3,13 +3,27 @@ package model
 import (
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/json"
 	"math/big"
+	"strconv"
 
 	"github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/xdb"
 	"github.com/effective-security/xpki/certutil"
 )
 
+// MetadataCrlShard specifies the metadata key for the CRL shard,
+// which the certificate is assigned to at issuance
+const MetadataCrlShard = "crl_shard"
+
+// MetadataRenewedFrom specifies the metadata key for the ID of the certificate,
+// which the certificate was renewed or rekeyed from
+const MetadataRenewedFrom = "renewed_from"
+
+// MetadataLint specifies the metadata key for the warnings
+// of pre-issuance linting, as JSON list
+const MetadataLint = "lint"
+
 // Certificate provides X509 Cert information
 type Certificate struct {
 	ID               uint64            `db:"id"`
@@ -28,6 +42,7 @@ type Certificate struct {
 	Label            string            `db:"label"`
 	Locations        []string          `db:"locations"`
 	Metadata         map[string]string `db:"metadata"`
+	External         bool              `db:"external"`
 }
 
 // Certificates defines a list of Certificate
@@ -52,6 +67,7 @@ func (r *Certificate) ToPB() *pb.Certificate {
 		Label:        r.Label,
 		Locations:    r.Locations,
 		Metadata:     r.Metadata,
+		External:     r.External,
 	}
 }
 
@@ -72,6 +88,25 @@ func (r *Certificate) FileName() string {
 	return r.IKID[:4] + "/" + sn + ".pem"
 }
 
+// CrlShard returns the CRL shard of the certificate,
+// or 0 if the certificate is not assigned to a shard
+func (r *Certificate) CrlShard() uint32 {
+	shard, err := strconv.ParseUint(r.Metadata[MetadataCrlShard], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(shard)
+}
+
+// LintWarnings returns the warnings of pre-issuance linting of the certificate
+func (r *Certificate) LintWarnings() []*pb.LintResult {
+	var list []*pb.LintResult
+	if v := r.Metadata[MetadataLint]; v != "" {
+		_ = json.Unmarshal([]byte(v), &list)
+	}
+	return list
+}
+
 // CertificateFromPB returns Certificate
 func CertificateFromPB(r *pb.Certificate) *Certificate {
 	return &Certificate{
@@ -91,6 +126,7 @@ func CertificateFromPB(r *pb.Certificate) *Certificate {
 		Label:            r.Label,
 		Locations:        r.Locations,
 		Metadata:         r.Metadata,
+		External:         r.External,
 	}
 }
 
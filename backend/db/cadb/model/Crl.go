@@ -13,6 +13,18 @@ type Crl struct {
 	NextUpdate xdb.Time `db:"next_update"`
 	Issuer     string   `db:"issuer"`
 	Pem        string   `db:"pem"`
+	CrlNumber  uint64   `db:"crl_number"`
+	// BaseNumber specifies the number of the complete CRL,
+	// which the delta CRL is based on, or 0 for complete CRL
+	BaseNumber uint64 `db:"base_number"`
+	// Shard specifies the shard of the partitioned CRL,
+	// or 0 for the CRL of all certificates
+	Shard uint32 `db:"shard"`
+}
+
+// IsDelta returns true for delta CRL
+func (r *Crl) IsDelta() bool {
+	return r.BaseNumber > 0
 }
 
 // ToDTO returns DTO
@@ -24,5 +36,8 @@ func (r *Crl) ToDTO() *pb.Crl {
 		NextUpdate: r.NextUpdate.String(),
 		Issuer:     r.Issuer,
 		Pem:        r.Pem,
+		CrlNumber:  r.CrlNumber,
+		BaseNumber: r.BaseNumber,
+		Shard:      r.Shard,
 	}
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+)
+
+// OcspResponder provides delegated OCSP responder of the issuer
+type OcspResponder struct {
+	ID           uint64   `db:"id"`
+	IKID         string   `db:"ikid"`
+	SerialNumber string   `db:"serial_number"`
+	NotBefore    xdb.Time `db:"not_before"`
+	NotAfter     xdb.Time `db:"not_after"`
+	// Pem is PEM encoded responder certificate
+	Pem string `db:"pem"`
+	// Key is the URI of the key in the crypto provider,
+	// the key is never exported
+	Key string `db:"key"`
+}
+
+// Validate returns error if the model is not valid
+func (r *OcspResponder) Validate() error {
+	if r.ID == 0 {
+		return errors.New("invalid ID")
+	}
+	if r.IKID == "" || r.SerialNumber == "" {
+		return errors.New("invalid issuer and serial")
+	}
+	if r.Pem == "" {
+		return errors.New("missing certificate")
+	}
+	if r.Key == "" {
+		return errors.New("missing key")
+	}
+	if !strings.HasPrefix(r.Key, "pkcs11:") {
+		return errors.New("invalid key URI")
+	}
+	if !r.NotBefore.UTC().Before(r.NotAfter.UTC()) {
+		return errors.New("invalid validity period")
+	}
+	return nil
+}
+
+// IsValid returns true if the responder can sign at the specified time
+func (r *OcspResponder) IsValid(now time.Time) bool {
+	return !now.Before(r.NotBefore.UTC()) && now.Before(r.NotAfter.UTC())
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+)
+
+// OCSP response statuses
+const (
+	// OcspStatusGood specifies the response for a valid certificate
+	OcspStatusGood = "good"
+	// OcspStatusRevoked specifies the response for a revoked certificate
+	OcspStatusRevoked = "revoked"
+)
+
+// OcspResponse provides pre-signed OCSP response of the certificate
+type OcspResponse struct {
+	// ID is the ID of the certificate
+	ID           uint64   `db:"id"`
+	IKID         string   `db:"ikid"`
+	SerialNumber string   `db:"serial_number"`
+	Status       string   `db:"status"`
+	ThisUpdate   xdb.Time `db:"this_update"`
+	NextUpdate   xdb.Time `db:"next_update"`
+	// Der is DER encoded OCSP response
+	Der []byte `db:"der"`
+}
+
+// Validate returns error if the model is not valid
+func (r *OcspResponse) Validate() error {
+	if r.ID == 0 {
+		return errors.New("invalid ID")
+	}
+	if r.IKID == "" || r.SerialNumber == "" {
+		return errors.New("invalid issuer and serial")
+	}
+	if r.Status != OcspStatusGood && r.Status != OcspStatusRevoked {
+		return errors.Errorf("invalid status: %q", r.Status)
+	}
+	if len(r.Der) == 0 {
+		return errors.New("missing response")
+	}
+	return nil
+}
+
+// IsValid returns true if the response can be served at the specified time
+func (r *OcspResponse) IsValid(now time.Time) bool {
+	return !now.Before(r.ThisUpdate.UTC()) && now.Before(r.NextUpdate.UTC())
+}
+
+// OcspResponses defines a list of OcspResponse
+type OcspResponses []*OcspResponse
+
+// ByID returns the responses by ID
+func (list OcspResponses) ByID() map[uint64]*OcspResponse {
+	m := make(map[uint64]*OcspResponse, len(list))
+	for _, r := range list {
+		m[r.ID] = r
+	}
+	return m
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PendingRevocation provides the certificate to revoke at the specified time
+type PendingRevocation struct {
+	ID            uint64 `db:"id"`
+	CertificateID uint64 `db:"certificate_id"`
+	// Reason specifies the revocation reason from RFC 5280
+	Reason    int       `db:"reason"`
+	RevokeAt  time.Time `db:"revoke_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Validate returns error if the model is not valid
+func (r *PendingRevocation) Validate() error {
+	if r.ID == 0 {
+		return errors.New("invalid ID")
+	}
+	if r.CertificateID == 0 {
+		return errors.New("invalid certificate ID")
+	}
+	if r.RevokeAt.IsZero() {
+		return errors.New("invalid revoke_at")
+	}
+	return nil
+}
+
+// PendingRevocations defines a list of PendingRevocation
+type PendingRevocations []*PendingRevocation
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"time"
+
+	"github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+)
+
+// Publish job kinds
+const (
+	// PublishKindCert specifies to publish the certificate with RefID
+	PublishKindCert = "cert"
+	// PublishKindIssuer specifies to publish the issuer certificate with RefID
+	PublishKindIssuer = "issuer"
+	// PublishKindCrl specifies to publish the latest CRL and CRL shards for IKID,
+	// the CRL is generated again if not found or expired
+	PublishKindCrl = "crl"
+	// PublishKindDeltaCrl specifies to publish the latest delta CRL for IKID
+	PublishKindDeltaCrl = "delta_crl"
+	// PublishKindRoots specifies to publish the root store
+	PublishKindRoots = "roots"
+)
+
+// Publish job statuses
+const (
+	// PublishStatusPending specifies the job waiting to be published
+	PublishStatusPending = "pending"
+	// PublishStatusProcessing specifies the job claimed by a worker
+	PublishStatusProcessing = "processing"
+	// PublishStatusPublished specifies the job successfully published
+	PublishStatusPublished = "published"
+	// PublishStatusFailed specifies the job failed after max attempts
+	PublishStatusFailed = "failed"
+)
+
+// PublishJob provides the publish outbox job
+type PublishJob struct {
+	ID            uint64    `db:"id"`
+	Kind          string    `db:"kind"`
+	IKID          string    `db:"ikid"`
+	RefID         uint64    `db:"ref_id"`
+	Status        string    `db:"status"`
+	Attempts      int       `db:"attempts"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	LastError     string    `db:"last_error"`
+	Location      string    `db:"location"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// Validate returns error if the model is not valid
+func (j *PublishJob) Validate() error {
+	switch j.Kind {
+	case PublishKindCert, PublishKindIssuer:
+		if j.RefID == 0 {
+			return errors.Errorf("invalid ref_id for %s job", j.Kind)
+		}
+	case PublishKindCrl, PublishKindDeltaCrl:
+		if j.IKID == "" {
+			return errors.Errorf("invalid ikid for %s job", j.Kind)
+		}
+	case PublishKindRoots:
+	default:
+		return errors.Errorf("invalid kind: %q", j.Kind)
+	}
+	switch j.Status {
+	case PublishStatusPending, PublishStatusProcessing, PublishStatusPublished, PublishStatusFailed:
+	default:
+		return errors.Errorf("invalid status: %q", j.Status)
+	}
+	return nil
+}
+
+// ToDTO returns DTO
+func (j *PublishJob) ToDTO() *pb.PublishJob {
+	return &pb.PublishJob{
+		ID:            j.ID,
+		Kind:          j.Kind,
+		IKID:          j.IKID,
+		RefID:         j.RefID,
+		Status:        j.Status,
+		Attempts:      int32(j.Attempts),
+		NextAttemptAt: xdb.Time(j.NextAttemptAt).String(),
+		LastError:     j.LastError,
+		Location:      j.Location,
+		CreatedAt:     xdb.Time(j.CreatedAt).String(),
+		UpdatedAt:     xdb.Time(j.UpdatedAt).String(),
+	}
+}
+
+// PublishJobs defines a list of PublishJob
+type PublishJobs []*PublishJob
+
+// ToDTO returns DTO
+func (list PublishJobs) ToDTO() []*pb.PublishJob {
+	res := make([]*pb.PublishJob, len(list))
+	for i, j := range list {
+		res[i] = j.ToDTO()
+	}
+	return res
+}
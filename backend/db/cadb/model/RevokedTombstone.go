@@ -0,0 +1,26 @@
+package model
+
+import "github.com/effective-security/xdb"
+
+// RevokedTombstone provides the revocation info of the purged revoked certificate
+type RevokedTombstone struct {
+	IKID         string   `db:"ikid"`
+	SerialNumber string   `db:"serial_number"`
+	NotAfter     xdb.Time `db:"no_tafter"`
+	RevokedAt    xdb.Time `db:"revoked_at"`
+	Reason       int      `db:"reason"`
+}
+
+// ToRevokedCertificate returns the revocation info
+// without the certificate details
+func (r *RevokedTombstone) ToRevokedCertificate() *RevokedCertificate {
+	return &RevokedCertificate{
+		Certificate: Certificate{
+			IKID:         r.IKID,
+			SerialNumber: r.SerialNumber,
+			NotAfter:     r.NotAfter,
+		},
+		RevokedAt: r.RevokedAt,
+		Reason:    r.Reason,
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/hex"
+
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+)
+
+// MaxIdempotencyKeyLen specifies the max length of the idempotency key
+const MaxIdempotencyKeyLen = 256
+
+// SignRequest provides the idempotency key of the sign request,
+// the key is unique within the org.
+// The certificate is registered with the ID of the request,
+// so the certificate issued for an abandoned request can be found.
+type SignRequest struct {
+	ID             uint64 `db:"id"`
+	OrgID          uint64 `db:"org_id"`
+	IdempotencyKey string `db:"idempotency_key"`
+	// Digest is hex encoded SHA256 of the request
+	Digest string `db:"digest"`
+	// CertificateID is the ID of the issued certificate,
+	// or 0 if the request is in progress
+	CertificateID uint64   `db:"certificate_id"`
+	CreatedAt     xdb.Time `db:"created_at"`
+}
+
+// Validate returns error if the model is not valid
+func (r *SignRequest) Validate() error {
+	if r.ID == 0 {
+		return errors.New("invalid ID")
+	}
+	if r.IdempotencyKey == "" || len(r.IdempotencyKey) > MaxIdempotencyKeyLen {
+		return errors.New("invalid idempotency key")
+	}
+	if b, err := hex.DecodeString(r.Digest); err != nil || len(b) != 32 {
+		return errors.New("invalid digest")
+	}
+	return nil
+}
+
+// InProgress returns true if the certificate is not issued yet
+func (r *SignRequest) InProgress() bool {
+	return r.CertificateID == 0
+}
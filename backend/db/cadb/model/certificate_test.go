@@ -58,6 +58,7 @@ func TestCertificate(t *testing.T) {
 		Label:            "label",
 		Locations:        []string{"1"},
 		Metadata:         map[string]string{"requester": "test"},
+		External:         true,
 	}
 	dto := m.ToPB()
 	assert.Equal(t, uint64(123), dto.ID)
@@ -76,6 +77,7 @@ func TestCertificate(t *testing.T) {
 	assert.Equal(t, m.Locations, dto.Locations)
 	assert.Equal(t, m.Label, dto.Label)
 	assert.Equal(t, m.Metadata, dto.Metadata)
+	assert.True(t, dto.External)
 
 	fn := m.FileName()
 	assert.Contains(t, fn, "/")
@@ -95,6 +97,19 @@ func TestCertificate(t *testing.T) {
 	assert.Equal(t, uint64(0), m4.ID)
 	assert.Equal(t, m2.Label, m4.Label)
 	assert.Equal(t, m2.Metadata, m4.Metadata)
+
+	assert.Equal(t, uint32(0), m.CrlShard())
+	m.Metadata[model.MetadataCrlShard] = "invalid"
+	assert.Equal(t, uint32(0), m.CrlShard())
+	m.Metadata[model.MetadataCrlShard] = "3"
+	assert.Equal(t, uint32(3), m.CrlShard())
+
+	assert.Empty(t, m.LintWarnings())
+	m.Metadata[model.MetadataLint] = "invalid"
+	assert.Empty(t, m.LintWarnings())
+	m.Metadata[model.MetadataLint] = `[{"Name":"w_test","Severity":"warn"}]`
+	require.Len(t, m.LintWarnings(), 1)
+	assert.Equal(t, "w_test", m.LintWarnings()[0].Name)
 }
 
 func TestRevokedCertificate(t *testing.T) {
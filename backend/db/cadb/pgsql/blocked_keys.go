@@ -0,0 +1,78 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+const blockedKeyColumns = `id,spki_hash,source,certificate_id,created_at`
+
+// BlockKey registers the blocked key.
+// If the key is already blocked, then the existing record is returned.
+func (p *Provider) BlockKey(ctx context.Context, r *model.BlockedKey) (*model.BlockedKey, error) {
+	err := xdb.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE, "id", r.ID, "spki_hash", r.SPKIHash, "source", r.Source)
+
+	res, err := scanBlockedKey(p.sql.QueryRowContext(ctx, `
+			INSERT INTO blocked_keys(`+blockedKeyColumns+`)
+				VALUES($1, $2, $3, $4, $5)
+			ON CONFLICT (spki_hash) DO NOTHING
+			RETURNING `+blockedKeyColumns+`
+			;`, r.ID,
+		r.SPKIHash,
+		r.Source,
+		r.CertificateID,
+		time.Now().UTC(),
+	))
+	if err == nil {
+		return res, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		p.CheckErrIDConflict(ctx, err, r.ID)
+		return nil, err
+	}
+	return p.GetBlockedKey(ctx, r.SPKIHash)
+}
+
+// GetBlockedKey returns the blocked key by hex encoded SHA256 of SPKI
+func (p *Provider) GetBlockedKey(ctx context.Context, spkiHash string) (*model.BlockedKey, error) {
+	return scanBlockedKey(p.sql.QueryRowContext(ctx, `
+		SELECT `+blockedKeyColumns+`
+		FROM blocked_keys
+		WHERE spki_hash = $1
+		;`, spkiHash))
+}
+
+// UnblockKey removes the blocked key
+func (p *Provider) UnblockKey(ctx context.Context, spkiHash string) error {
+	_, err := p.sql.ExecContext(ctx, `DELETE FROM blocked_keys WHERE spki_hash=$1;`, spkiHash)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func scanBlockedKey(row xdb.Row) (*model.BlockedKey, error) {
+	res := new(model.BlockedKey)
+	err := row.Scan(
+		&res.ID,
+		&res.SPKIHash,
+		&res.Source,
+		&res.CertificateID,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package pgsql_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/x/guid"
+	"github.com/effective-security/xdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockedKeys(t *testing.T) {
+	h := sha256.Sum256([]byte(guid.MustCreate()))
+	hash := hex.EncodeToString(h[:])
+
+	r := &model.BlockedKey{
+		ID:       provider.NextID().UInt64(),
+		SPKIHash: "invalid",
+		Source:   model.BlockedKeySourceKeyCompromise,
+	}
+	_, err := provider.BlockKey(ctx, r)
+	assert.EqualError(t, err, "invalid SPKI hash")
+
+	_, err = provider.GetBlockedKey(ctx, hash)
+	require.Error(t, err)
+	assert.True(t, xdb.IsNotFoundError(err))
+
+	r.SPKIHash = hash
+	r.CertificateID = provider.NextID().UInt64()
+	r1, err := provider.BlockKey(ctx, r)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, r1.ID)
+	assert.Equal(t, r.CertificateID, r1.CertificateID)
+
+	// the same key returns the existing record
+	r2 := *r
+	r2.ID = provider.NextID().UInt64()
+	r2.Source = model.BlockedKeySourceAdmin
+	existing, err := provider.BlockKey(ctx, &r2)
+	require.NoError(t, err)
+	assert.Equal(t, *r1, *existing)
+
+	r3, err := provider.GetBlockedKey(ctx, hash)
+	require.NoError(t, err)
+	assert.Equal(t, *r1, *r3)
+
+	require.NoError(t, provider.UnblockKey(ctx, hash))
+	_, err = provider.GetBlockedKey(ctx, hash)
+	assert.True(t, xdb.IsNotFoundError(err))
+}
@@ -0,0 +1,161 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+// RegisterOcspResponse registers pre-signed OCSP response of the certificate.
+// The revoked response is not replaced by the good one,
+// in this case the stored response is returned.
+func (p *Provider) RegisterOcspResponse(ctx context.Context, r *model.OcspResponse) (*model.OcspResponse, error) {
+	err := xdb.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE,
+		"id", r.ID,
+		"ikid", r.IKID,
+		"serial", r.SerialNumber,
+		"status", r.Status)
+
+	res := new(model.OcspResponse)
+	err = p.sql.QueryRowContext(ctx, `
+			INSERT INTO ocsp_responses(id,ikid,serial_number,status,this_update,next_update,der)
+				VALUES($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (id)
+			DO UPDATE
+				SET status=$4,this_update=$5,next_update=$6,der=$7
+				WHERE ocsp_responses.status <> 'revoked' OR EXCLUDED.status = 'revoked'
+			RETURNING id,ikid,serial_number,status,this_update,next_update,der
+			;`, r.ID,
+		r.IKID,
+		r.SerialNumber,
+		r.Status,
+		r.ThisUpdate,
+		r.NextUpdate,
+		r.Der,
+	).Scan(&res.ID,
+		&res.IKID,
+		&res.SerialNumber,
+		&res.Status,
+		&res.ThisUpdate,
+		&res.NextUpdate,
+		&res.Der,
+	)
+	if err == sql.ErrNoRows {
+		// not updated, the certificate is revoked
+		return p.GetOcspResponse(ctx, r.IKID, r.SerialNumber)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// GetOcspResponse returns pre-signed OCSP response of the certificate
+func (p *Provider) GetOcspResponse(ctx context.Context, ikid, serial string) (*model.OcspResponse, error) {
+	res := new(model.OcspResponse)
+	err := p.sql.QueryRowContext(ctx, `
+		SELECT id,ikid,serial_number,status,this_update,next_update,der
+		FROM ocsp_responses
+		WHERE ikid = $1 AND serial_number = $2
+		;
+		`, ikid, serial).Scan(
+		&res.ID,
+		&res.IKID,
+		&res.SerialNumber,
+		&res.Status,
+		&res.ThisUpdate,
+		&res.NextUpdate,
+		&res.Der,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// ListOcspResponses returns pre-signed OCSP responses of the issuer,
+// for the certificates with ID in the specified range, inclusive.
+// The responses are returned without DER.
+func (p *Provider) ListOcspResponses(ctx context.Context, ikid string, fromID, toID uint64) (model.OcspResponses, error) {
+	res, err := p.sql.QueryContext(ctx, `
+		SELECT id,ikid,serial_number,status,this_update,next_update
+		FROM ocsp_responses
+		WHERE ikid = $1 AND id >= $2 AND id <= $3
+		ORDER BY id ASC
+		;
+		`, ikid, fromID, toID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	list := make(model.OcspResponses, 0, 100)
+	for res.Next() {
+		m := new(model.OcspResponse)
+		err = res.Scan(
+			&m.ID,
+			&m.IKID,
+			&m.SerialNumber,
+			&m.Status,
+			&m.ThisUpdate,
+			&m.NextUpdate,
+		)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, m)
+	}
+
+	return list, nil
+}
+
+// MarkOcspResponseRevoked marks pre-signed OCSP response of the certificate
+// as revoked and expired at the specified time, to be signed again.
+// The response is created without DER, if not registered yet.
+func (p *Provider) MarkOcspResponseRevoked(ctx context.Context, id uint64, ikid, serial string, at time.Time) error {
+	_, err := p.sql.ExecContext(ctx, `
+			INSERT INTO ocsp_responses(id,ikid,serial_number,status,this_update,next_update,der)
+				VALUES($1, $2, $3, 'revoked', $4, $4, '')
+			ON CONFLICT (id)
+			DO UPDATE
+				SET status='revoked',this_update=$4,next_update=$4
+			;`, id, ikid, serial, at.UTC())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// RemoveOcspResponse removes pre-signed OCSP response of the certificate
+func (p *Provider) RemoveOcspResponse(ctx context.Context, id uint64) error {
+	_, err := p.sql.ExecContext(ctx, `DELETE FROM ocsp_responses WHERE id=$1;`, id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// PurgeOcspResponses removes OCSP responses expired before the specified time
+func (p *Provider) PurgeOcspResponses(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.sql.ExecContext(ctx, `DELETE FROM ocsp_responses WHERE next_update < $1;`, before.UTC())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	logger.ContextKV(ctx, xlog.DEBUG, "purged", count, "before", before)
+	return count, nil
+}
@@ -0,0 +1,91 @@
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+// RegisterOcspResponder registers delegated OCSP responder of the issuer
+func (p *Provider) RegisterOcspResponder(ctx context.Context, r *model.OcspResponder) (*model.OcspResponder, error) {
+	err := xdb.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE,
+		"id", r.ID,
+		"ikid", r.IKID,
+		"serial", r.SerialNumber,
+		"not_after", r.NotAfter)
+
+	res := new(model.OcspResponder)
+	err = p.sql.QueryRowContext(ctx, `
+			INSERT INTO ocsp_responders(id,ikid,serial_number,not_before,not_after,pem,key)
+				VALUES($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id,ikid,serial_number,not_before,not_after,pem,key
+			;`, r.ID,
+		r.IKID,
+		r.SerialNumber,
+		r.NotBefore,
+		r.NotAfter,
+		r.Pem,
+		r.Key,
+	).Scan(&res.ID,
+		&res.IKID,
+		&res.SerialNumber,
+		&res.NotBefore,
+		&res.NotAfter,
+		&res.Pem,
+		&res.Key,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// GetOcspResponder returns delegated OCSP responder of the issuer,
+// with the latest expiration time
+func (p *Provider) GetOcspResponder(ctx context.Context, ikid string) (*model.OcspResponder, error) {
+	res := new(model.OcspResponder)
+	err := p.sql.QueryRowContext(ctx, `
+		SELECT id,ikid,serial_number,not_before,not_after,pem,key
+		FROM ocsp_responders
+		WHERE ikid = $1
+		ORDER BY not_after DESC
+		LIMIT 1
+		;
+		`, ikid).Scan(
+		&res.ID,
+		&res.IKID,
+		&res.SerialNumber,
+		&res.NotBefore,
+		&res.NotAfter,
+		&res.Pem,
+		&res.Key,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// PurgeOcspResponders removes OCSP responders expired before the specified time
+func (p *Provider) PurgeOcspResponders(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.sql.ExecContext(ctx, `DELETE FROM ocsp_responders WHERE not_after < $1;`, before.UTC())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	logger.ContextKV(ctx, xlog.DEBUG, "purged", count, "before", before)
+	return count, nil
+}
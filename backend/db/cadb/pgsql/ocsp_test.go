@@ -0,0 +1,113 @@
+package pgsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/x/guid"
+	"github.com/effective-security/xdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOcspResponses(t *testing.T) {
+	ikid := guid.MustCreate()
+	id := provider.NextID().UInt64()
+
+	r := &model.OcspResponse{
+		ID:           id,
+		IKID:         ikid,
+		SerialNumber: "1234",
+		Status:       "unknown",
+		ThisUpdate:   xdb.FromNow(-time.Minute),
+		NextUpdate:   xdb.FromNow(time.Hour),
+		Der:          []byte{1, 2, 3},
+	}
+	_, err := provider.RegisterOcspResponse(ctx, r)
+	assert.EqualError(t, err, `invalid status: "unknown"`)
+
+	r.Status = model.OcspStatusGood
+	r1, err := provider.RegisterOcspResponse(ctx, r)
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.RemoveOcspResponse(ctx, id)
+	}()
+	assert.Equal(t, *r, *r1)
+	assert.True(t, r1.IsValid(time.Now()))
+
+	// revoked
+	r.Status = model.OcspStatusRevoked
+	r.Der = []byte{4, 5, 6}
+	r2, err := provider.RegisterOcspResponse(ctx, r)
+	require.NoError(t, err)
+	assert.Equal(t, model.OcspStatusRevoked, r2.Status)
+	assert.Equal(t, r.Der, r2.Der)
+
+	// good does not replace revoked
+	good := *r
+	good.Status = model.OcspStatusGood
+	good.Der = []byte{7, 8, 9}
+	r3, err := provider.RegisterOcspResponse(ctx, &good)
+	require.NoError(t, err)
+	assert.Equal(t, *r2, *r3)
+
+	r4, err := provider.GetOcspResponse(ctx, ikid, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, *r2, *r4)
+
+	_, err = provider.GetOcspResponse(ctx, ikid, "notfound")
+	require.Error(t, err)
+	assert.True(t, xdb.IsNotFoundError(err))
+
+	list, err := provider.ListOcspResponses(ctx, ikid, id, id)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, model.OcspStatusRevoked, list[0].Status)
+	assert.Empty(t, list[0].Der)
+	assert.NotNil(t, list.ByID()[id])
+
+	list, err = provider.ListOcspResponses(ctx, ikid, id+1, id+100)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	// marked as revoked
+	at := time.Now().UTC()
+	err = provider.MarkOcspResponseRevoked(ctx, id, ikid, "1234", at)
+	require.NoError(t, err)
+	r5, err := provider.GetOcspResponse(ctx, ikid, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, model.OcspStatusRevoked, r5.Status)
+	assert.Equal(t, r2.Der, r5.Der)
+	assert.False(t, r5.IsValid(at))
+
+	// good does not replace marked
+	_, err = provider.RegisterOcspResponse(ctx, &good)
+	require.NoError(t, err)
+	r6, err := provider.GetOcspResponse(ctx, ikid, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, *r5, *r6)
+
+	id2 := provider.NextID().UInt64()
+	err = provider.MarkOcspResponseRevoked(ctx, id2, ikid, "5678", at)
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.RemoveOcspResponse(ctx, id2)
+	}()
+	r7, err := provider.GetOcspResponse(ctx, ikid, "5678")
+	require.NoError(t, err)
+	assert.Equal(t, model.OcspStatusRevoked, r7.Status)
+	assert.Empty(t, r7.Der)
+
+	// expired
+	r.NextUpdate = xdb.FromNow(-time.Hour)
+	_, err = provider.RegisterOcspResponse(ctx, r)
+	require.NoError(t, err)
+
+	count, err := provider.PurgeOcspResponses(ctx, time.Now())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(1))
+
+	_, err = provider.GetOcspResponse(ctx, ikid, "1234")
+	require.Error(t, err)
+}
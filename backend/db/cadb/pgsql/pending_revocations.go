@@ -0,0 +1,99 @@
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+const pendingRevocationColumns = `id,certificate_id,reason,revoke_at,created_at`
+
+// RegisterPendingRevocation schedules the certificate to revoke.
+// If the certificate is already scheduled, then the existing record
+// is returned with the earliest revoke_at.
+func (p *Provider) RegisterPendingRevocation(ctx context.Context, r *model.PendingRevocation) (*model.PendingRevocation, error) {
+	err := xdb.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE, "id", r.ID, "certificate_id", r.CertificateID, "revoke_at", r.RevokeAt)
+
+	res, err := scanPendingRevocation(p.sql.QueryRowContext(ctx, `
+			INSERT INTO pending_revocations(`+pendingRevocationColumns+`)
+				VALUES($1, $2, $3, $4, $5)
+			ON CONFLICT (certificate_id)
+			DO UPDATE
+				SET revoke_at=LEAST(pending_revocations.revoke_at, EXCLUDED.revoke_at)
+			RETURNING `+pendingRevocationColumns+`
+			;`, r.ID,
+		r.CertificateID,
+		r.Reason,
+		r.RevokeAt.UTC(),
+		time.Now().UTC(),
+	))
+	if err != nil {
+		p.CheckErrIDConflict(ctx, err, r.ID)
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListPendingRevocations returns the certificates to revoke before the specified time
+func (p *Provider) ListPendingRevocations(ctx context.Context, before time.Time, limit int) (model.PendingRevocations, error) {
+	res, err := p.sql.QueryContext(ctx, `
+		SELECT `+pendingRevocationColumns+`
+		FROM pending_revocations
+		WHERE revoke_at <= $1
+		ORDER BY revoke_at
+		LIMIT $2
+		;`, before.UTC(), limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	return scanPendingRevocations(res)
+}
+
+// RemovePendingRevocation removes the pending revocation
+func (p *Provider) RemovePendingRevocation(ctx context.Context, id uint64) error {
+	_, err := p.sql.ExecContext(ctx, `DELETE FROM pending_revocations WHERE id=$1;`, id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func scanPendingRevocation(row xdb.Row) (*model.PendingRevocation, error) {
+	res := new(model.PendingRevocation)
+	err := row.Scan(
+		&res.ID,
+		&res.CertificateID,
+		&res.Reason,
+		&res.RevokeAt,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	res.RevokeAt = res.RevokeAt.UTC()
+	res.CreatedAt = res.CreatedAt.UTC()
+	return res, nil
+}
+
+func scanPendingRevocations(rows xdb.Rows) (model.PendingRevocations, error) {
+	var list model.PendingRevocations
+	for rows.Next() {
+		m, err := scanPendingRevocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, errors.WithStack(rows.Err())
+}
@@ -0,0 +1,59 @@
+package pgsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingRevocations(t *testing.T) {
+	_, err := provider.RegisterPendingRevocation(ctx, &model.PendingRevocation{})
+	require.Error(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	r := &model.PendingRevocation{
+		ID:            provider.NextID().UInt64(),
+		CertificateID: provider.NextID().UInt64(),
+		Reason:        4,
+		RevokeAt:      now.Add(time.Hour),
+	}
+	r1, err := provider.RegisterPendingRevocation(ctx, r)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, r1.ID)
+	assert.Equal(t, r.CertificateID, r1.CertificateID)
+	assert.Equal(t, r.RevokeAt, r1.RevokeAt)
+
+	// the same certificate keeps the existing record with the earliest time
+	r2 := *r
+	r2.ID = provider.NextID().UInt64()
+	r2.RevokeAt = now.Add(time.Minute)
+	r3, err := provider.RegisterPendingRevocation(ctx, &r2)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, r3.ID)
+	assert.Equal(t, r2.RevokeAt, r3.RevokeAt)
+
+	find := func(list model.PendingRevocations) *model.PendingRevocation {
+		for _, p := range list {
+			if p.ID == r.ID {
+				return p
+			}
+		}
+		return nil
+	}
+
+	list, err := provider.ListPendingRevocations(ctx, now, 1000)
+	require.NoError(t, err)
+	assert.Nil(t, find(list))
+
+	list, err = provider.ListPendingRevocations(ctx, now.Add(2*time.Minute), 1000)
+	require.NoError(t, err)
+	assert.NotNil(t, find(list))
+
+	require.NoError(t, provider.RemovePendingRevocation(ctx, r.ID))
+	list, err = provider.ListPendingRevocations(ctx, now.Add(2*time.Minute), 1000)
+	require.NoError(t, err)
+	assert.Nil(t, find(list))
+}
@@ -0,0 +1,272 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+const publishJobColumns = `id,kind,ikid,ref_id,status,attempts,next_attempt_at,last_error,location,created_at,updated_at`
+
+// EnqueuePublishJob adds the job to the publish outbox.
+// If a pending job for the same object already exists,
+// then the existing job is returned and scheduled for the earliest attempt.
+func (p *Provider) EnqueuePublishJob(ctx context.Context, job *model.PublishJob) (*model.PublishJob, error) {
+	id := job.ID
+	if id == 0 {
+		id = p.NextID().UInt64()
+	}
+	if job.Status == "" {
+		job.Status = model.PublishStatusPending
+	}
+	err := xdb.Validate(job)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	next := job.NextAttemptAt.UTC()
+	if next.IsZero() {
+		next = now
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE, "kind", job.Kind, "ikid", job.IKID, "ref_id", job.RefID)
+
+	res, err := scanPublishJob(p.sql.QueryRowContext(ctx, `
+			INSERT INTO publish_jobs(`+publishJobColumns+`)
+				VALUES($1, $2, $3, $4, $5, 0, $6, '', $7, $8, $8)
+			ON CONFLICT (kind,ikid,ref_id) WHERE status = 'pending'
+			DO UPDATE
+				SET next_attempt_at=LEAST(publish_jobs.next_attempt_at, EXCLUDED.next_attempt_at),
+					updated_at=EXCLUDED.updated_at
+			RETURNING `+publishJobColumns+`
+			;`, id,
+		job.Kind,
+		job.IKID,
+		job.RefID,
+		job.Status,
+		next,
+		job.Location,
+		now,
+	))
+	if err != nil {
+		p.CheckErrIDConflict(ctx, err, id)
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// EnqueuePublishJobs adds the list of jobs to the publish outbox in a single statement.
+// The jobs in the list must refer to different objects.
+func (p *Provider) EnqueuePublishJobs(ctx context.Context, jobs model.PublishJobs) (model.PublishJobs, error) {
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	const columns = 8
+	now := time.Now().UTC()
+	values := make([]string, len(jobs))
+	args := make([]any, 0, len(jobs)*columns)
+	for i, job := range jobs {
+		id := job.ID
+		if id == 0 {
+			id = p.NextID().UInt64()
+		}
+		if job.Status == "" {
+			job.Status = model.PublishStatusPending
+		}
+		err := xdb.Validate(job)
+		if err != nil {
+			return nil, err
+		}
+		next := job.NextAttemptAt.UTC()
+		if next.IsZero() {
+			next = now
+		}
+
+		n := i * columns
+		values[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, 0, $%d, '', $%d, $%d, $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+8)
+		args = append(args, id, job.Kind, job.IKID, job.RefID, job.Status, next, job.Location, now)
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE, "count", len(jobs))
+
+	res, err := p.sql.QueryContext(ctx, `
+			INSERT INTO publish_jobs(`+publishJobColumns+`)
+				VALUES `+strings.Join(values, ",")+`
+			ON CONFLICT (kind,ikid,ref_id) WHERE status = 'pending'
+			DO UPDATE
+				SET next_attempt_at=LEAST(publish_jobs.next_attempt_at, EXCLUDED.next_attempt_at),
+					updated_at=EXCLUDED.updated_at
+			RETURNING `+publishJobColumns+`
+			;`, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	return scanPublishJobs(res)
+}
+
+// ClaimPublishJobs returns pending jobs due to publish at the specified time,
+// and marks them as processing until now+lease.
+// The processing jobs with expired lease are claimed again,
+// in case the worker did not complete them.
+func (p *Provider) ClaimPublishJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) (model.PublishJobs, error) {
+	if limit == 0 {
+		limit = 100
+	}
+
+	now = now.UTC()
+	res, err := p.sql.QueryContext(ctx, `
+		UPDATE publish_jobs
+			SET status='processing', next_attempt_at=$2, updated_at=$1
+		WHERE id IN (
+			SELECT id FROM publish_jobs
+			WHERE status IN ('pending','processing') AND next_attempt_at <= $1
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+publishJobColumns+`
+		;`, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	return scanPublishJobs(res)
+}
+
+// UpdatePublishJob updates the job status
+func (p *Provider) UpdatePublishJob(ctx context.Context, job *model.PublishJob) (*model.PublishJob, error) {
+	err := xdb.Validate(job)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := scanPublishJob(p.sql.QueryRowContext(ctx, `
+			UPDATE publish_jobs
+				SET status=$2,attempts=$3,next_attempt_at=$4,last_error=$5,location=$6,updated_at=$7
+			WHERE id=$1
+			RETURNING `+publishJobColumns+`
+			;`, job.ID,
+		job.Status,
+		job.Attempts,
+		job.NextAttemptAt.UTC(),
+		job.LastError,
+		job.Location,
+		time.Now().UTC(),
+	))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// ListPublishJobs returns the jobs with the specified status
+func (p *Provider) ListPublishJobs(ctx context.Context, status string, limit int, afterID uint64) (model.PublishJobs, error) {
+	if limit == 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	res, err := p.sql.QueryContext(ctx, `
+		SELECT `+publishJobColumns+`
+		FROM publish_jobs
+		WHERE status = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+		;`, status, afterID, limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	return scanPublishJobs(res)
+}
+
+// GetPublishJobsCounts returns number of jobs by status
+func (p *Provider) GetPublishJobsCounts(ctx context.Context) (map[string]int64, error) {
+	res, err := p.sql.QueryContext(ctx, `
+		SELECT status, COUNT(id)
+		FROM publish_jobs
+		GROUP BY status
+		;`)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	counts := map[string]int64{}
+	for res.Next() {
+		var status string
+		var count int64
+		if err = res.Scan(&status, &count); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		counts[status] = count
+	}
+	return counts, errors.WithStack(res.Err())
+}
+
+// PurgePublishJobs removes published jobs updated before the specified time
+func (p *Provider) PurgePublishJobs(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.sql.ExecContext(ctx, `
+		DELETE FROM publish_jobs
+		WHERE status = 'published' AND updated_at < $1
+		;`, before.UTC())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return count, nil
+}
+
+func scanPublishJob(row xdb.Row) (*model.PublishJob, error) {
+	res := new(model.PublishJob)
+	err := row.Scan(
+		&res.ID,
+		&res.Kind,
+		&res.IKID,
+		&res.RefID,
+		&res.Status,
+		&res.Attempts,
+		&res.NextAttemptAt,
+		&res.LastError,
+		&res.Location,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	res.NextAttemptAt = res.NextAttemptAt.UTC()
+	res.CreatedAt = res.CreatedAt.UTC()
+	res.UpdatedAt = res.UpdatedAt.UTC()
+	return res, nil
+}
+
+func scanPublishJobs(rows xdb.Rows) (model.PublishJobs, error) {
+	list := make(model.PublishJobs, 0, 100)
+	for rows.Next() {
+		m, err := scanPublishJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, errors.WithStack(rows.Err())
+}
@@ -0,0 +1,124 @@
+package pgsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/x/guid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishJobs(t *testing.T) {
+	ikid := guid.MustCreate()
+	refID := provider.NextID().UInt64()
+
+	_, err := provider.EnqueuePublishJob(ctx, &model.PublishJob{Kind: "invalid", IKID: ikid})
+	require.Error(t, err)
+
+	job, err := provider.EnqueuePublishJob(ctx, &model.PublishJob{
+		Kind:  model.PublishKindCert,
+		IKID:  ikid,
+		RefID: refID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.PublishStatusPending, job.Status)
+	assert.Equal(t, 0, job.Attempts)
+
+	// pending job is reused
+	job2, err := provider.EnqueuePublishJob(ctx, &model.PublishJob{
+		Kind:  model.PublishKindCert,
+		IKID:  ikid,
+		RefID: refID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, job2.ID)
+
+	now := time.Now().Add(time.Second)
+	list, err := provider.ClaimPublishJobs(ctx, now, time.Minute, 500)
+	require.NoError(t, err)
+	var claimed *model.PublishJob
+	for _, j := range list {
+		if j.ID == job.ID {
+			claimed = j
+		}
+	}
+	require.NotNil(t, claimed)
+	assert.Equal(t, model.PublishStatusProcessing, claimed.Status)
+
+	// processing job is not claimed until the lease expires
+	list, err = provider.ClaimPublishJobs(ctx, now, time.Minute, 500)
+	require.NoError(t, err)
+	for _, j := range list {
+		assert.NotEqual(t, job.ID, j.ID)
+	}
+
+	// new job is created while the previous is processing
+	job3, err := provider.EnqueuePublishJob(ctx, &model.PublishJob{
+		Kind:  model.PublishKindCert,
+		IKID:  ikid,
+		RefID: refID,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, job.ID, job3.ID)
+
+	claimed.Status = model.PublishStatusPublished
+	claimed.Attempts = 1
+	claimed.Location = "file:///cert.pem"
+	updated, err := provider.UpdatePublishJob(ctx, claimed)
+	require.NoError(t, err)
+	assert.Equal(t, model.PublishStatusPublished, updated.Status)
+	assert.Equal(t, 1, updated.Attempts)
+	assert.Equal(t, claimed.Location, updated.Location)
+
+	counts, err := provider.GetPublishJobsCounts(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, counts[model.PublishStatusPublished], int64(1))
+	assert.GreaterOrEqual(t, counts[model.PublishStatusPending], int64(1))
+
+	published, err := provider.ListPublishJobs(ctx, model.PublishStatusPublished, 500, job.ID-1)
+	require.NoError(t, err)
+	require.NotEmpty(t, published)
+	assert.Equal(t, job.ID, published[0].ID)
+
+	count, err := provider.PurgePublishJobs(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(1))
+
+	job3.Status = model.PublishStatusFailed
+	_, err = provider.UpdatePublishJob(ctx, job3)
+	require.NoError(t, err)
+}
+
+func TestEnqueuePublishJobs(t *testing.T) {
+	ikid := guid.MustCreate()
+
+	list, err := provider.EnqueuePublishJobs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	_, err = provider.EnqueuePublishJobs(ctx, model.PublishJobs{{Kind: "invalid", IKID: ikid}})
+	require.Error(t, err)
+
+	jobs := model.PublishJobs{
+		{Kind: model.PublishKindCert, IKID: ikid, RefID: provider.NextID().UInt64()},
+		{Kind: model.PublishKindCert, IKID: ikid, RefID: provider.NextID().UInt64()},
+	}
+	list, err = provider.EnqueuePublishJobs(ctx, jobs)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, j := range list {
+		assert.Equal(t, model.PublishStatusPending, j.Status)
+		assert.Equal(t, ikid, j.IKID)
+	}
+
+	// pending job is reused
+	job, err := provider.EnqueuePublishJob(ctx, &model.PublishJob{
+		Kind:  model.PublishKindCert,
+		IKID:  ikid,
+		RefID: jobs[0].RefID,
+	})
+	require.NoError(t, err)
+	assert.True(t, job.ID == list[0].ID || job.ID == list[1].ID)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/effective-security/trusty/backend/db/cadb/model"
@@ -12,9 +13,15 @@ import (
 	"github.com/pkg/errors"
 )
 
-// RegisterCertificate registers Cert
+// RegisterCertificate registers Cert,
+// the new ID is assigned if the ID of Cert is not set.
+// The registered certificate is updated only by the locally issued one,
+// the external certificate is not registered again.
 func (p *Provider) RegisterCertificate(ctx context.Context, crt *model.Certificate) (*model.Certificate, error) {
-	id := p.NextID()
+	id := xdb.NewID(crt.ID)
+	if crt.ID == 0 {
+		id = p.NextID()
+	}
 	err := xdb.Validate(crt)
 	if err != nil {
 		return nil, err
@@ -28,12 +35,13 @@ func (p *Provider) RegisterCertificate(ctx context.Context, crt *model.Certifica
 	}
 
 	row := p.sql.QueryRowContext(ctx, `
-			INSERT INTO certificates(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata)
-				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			INSERT INTO certificates(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,external)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 			ON CONFLICT (sha256)
 			DO UPDATE
-				SET org_id=$2,issuers_pem=$12,label=$14,locations=$15,metadata=$16
-			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata
+				SET org_id=$2,issuers_pem=$12,label=$14,locations=$15,metadata=$16,external=$17
+				WHERE NOT $17
+			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,external
 			;`, id, crt.OrgID, crt.SKID, crt.IKID, crt.SerialNumber,
 		crt.NotBefore, crt.NotAfter,
 		crt.Subject, crt.Issuer,
@@ -43,15 +51,113 @@ func (p *Provider) RegisterCertificate(ctx context.Context, crt *model.Certifica
 		crt.Label,
 		strings.Join(crt.Locations, ","),
 		string(b),
+		crt.External,
 	)
 	m, err := scanFullCertificate(row)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// the registered certificate is not updated by import
+			return nil, p.externalConflict(ctx, crt.ThumbprintSha256)
+		}
 		p.CheckErrIDConflict(ctx, err, id.UInt64())
 		return nil, err
 	}
 	return m, nil
 }
 
+// externalConflict returns the error for the external certificate,
+// which is already registered
+func (p *Provider) externalConflict(ctx context.Context, sha256 string) error {
+	var external bool
+	err := p.sql.QueryRowContext(ctx, `SELECT external FROM certificates WHERE sha256=$1;`, sha256).Scan(&external)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !external {
+		return errors.Errorf("certificate is issued locally and can not be registered as external: %s", sha256)
+	}
+	return errors.Errorf("certificate already exists: %s", sha256)
+}
+
+// RegisterCertificates registers the list of Cert in a single statement,
+// and returns the registered certificates in the order of the list
+func (p *Provider) RegisterCertificates(ctx context.Context, list []*model.Certificate) ([]*model.Certificate, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	const columns = 17
+	values := make([]string, len(list))
+	args := make([]any, 0, len(list)*columns)
+	for i, crt := range list {
+		err := xdb.Validate(crt)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := json.Marshal(crt.Metadata)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		params := make([]string, columns)
+		for j := range params {
+			params[j] = fmt.Sprintf("$%d", i*columns+j+1)
+		}
+		values[i] = "(" + strings.Join(params, ",") + ")"
+
+		args = append(args, p.NextID(), crt.OrgID, crt.SKID, crt.IKID, crt.SerialNumber,
+			crt.NotBefore, crt.NotAfter,
+			crt.Subject, crt.Issuer,
+			crt.ThumbprintSha256,
+			crt.Pem, crt.IssuersPem,
+			crt.Profile,
+			crt.Label,
+			strings.Join(crt.Locations, ","),
+			string(b),
+			crt.External,
+		)
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE, "count", len(list))
+
+	rows, err := p.sql.QueryContext(ctx, `
+			INSERT INTO certificates(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,external)
+				VALUES `+strings.Join(values, ",")+`
+			ON CONFLICT (sha256)
+			DO UPDATE
+				SET org_id=EXCLUDED.org_id,issuers_pem=EXCLUDED.issuers_pem,label=EXCLUDED.label,locations=EXCLUDED.locations,metadata=EXCLUDED.metadata,external=EXCLUDED.external
+				WHERE NOT EXCLUDED.external
+			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,external
+			;`, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	// the order of RETURNING is not guaranteed
+	bySha := make(map[string]*model.Certificate, len(list))
+	for rows.Next() {
+		m, err := scanFullCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		bySha[m.ThumbprintSha256] = m
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res := make([]*model.Certificate, len(list))
+	for i, crt := range list {
+		res[i] = bySha[crt.ThumbprintSha256]
+		if res[i] == nil {
+			return nil, errors.Errorf("certificate was not registered: %s", crt.ThumbprintSha256)
+		}
+	}
+	return res, nil
+}
+
 func scanFullCertificate(row xdb.Row) (*model.Certificate, error) {
 	res := new(model.Certificate)
 	var locations string
@@ -72,6 +178,7 @@ func scanFullCertificate(row xdb.Row) (*model.Certificate, error) {
 		&res.Label,
 		&locations,
 		&meta,
+		&res.External,
 	)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -105,6 +212,7 @@ func scanShortCertificate(row *sql.Rows) (*model.Certificate, error) {
 		&res.Label,
 		&locations,
 		&meta,
+		&res.External,
 	)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -137,7 +245,7 @@ func (p *Provider) UpdateCertificateLabel(ctx context.Context, id uint64, label
 			UPDATE certificates
 			SET label=$2
 			WHERE id=$1
-			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata
+			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,external
 			;`, id, label))
 	if err != nil {
 		return nil, err
@@ -157,7 +265,8 @@ func (p *Provider) GetCertificate(ctx context.Context, id uint64) (*model.Certif
 			profile,
 			label,
 			locations,
-			metadata
+			metadata,
+			external
 		FROM certificates
 		WHERE id = $1
 		;
@@ -180,7 +289,8 @@ func (p *Provider) GetCertificatesBySKID(ctx context.Context, skid string) ([]*m
 				profile,
 				label,
 				locations,
-				metadata
+				metadata,
+				external
 			FROM certificates
 			WHERE skid = $1
 			;
@@ -217,7 +327,8 @@ func (p *Provider) GetCertificateByIKIDAndSerial(ctx context.Context, ikid, seri
 				profile,
 				label,
 				locations,
-				metadata
+				metadata,
+				external
 			FROM certificates
 			WHERE ikid = $1 AND serial_number = $2
 			;
@@ -239,7 +350,7 @@ func (p *Provider) ListOrgCertificates(ctx context.Context, orgID uint64, limit
 
 	res, err := p.sql.QueryContext(ctx, `
 		SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,profile,label,locations,metadata
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,profile,label,locations,metadata,external
 		FROM
 			certificates
 		WHERE org_id = $1 AND id > $2
@@ -265,7 +376,8 @@ func (p *Provider) ListOrgCertificates(ctx context.Context, orgID uint64, limit
 	return list, nil
 }
 
-// ListCertificates returns list of Certificate info
+// ListCertificates returns list of Certificate info,
+// if ikid is empty, then certificates of all issuers are returned
 func (p *Provider) ListCertificates(ctx context.Context, ikid string, limit int, afterID uint64) (model.Certificates, error) {
 	if limit == 0 {
 		limit = 100
@@ -281,11 +393,11 @@ func (p *Provider) ListCertificates(ctx context.Context, ikid string, limit int,
 
 	res, err := p.sql.QueryContext(ctx,
 		`SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,profile,label,locations,metadata
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,profile,label,locations,metadata,external
 		FROM
 			certificates
 		WHERE 
-			ikid = $1 AND id > $2
+			($1 = '' OR ikid = $1) AND id > $2
 		ORDER BY
 			id ASC
 		LIMIT $3
@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/effective-security/trusty/backend/db/cadb/model"
 	"github.com/effective-security/xdb"
@@ -9,7 +10,9 @@ import (
 	"github.com/pkg/errors"
 )
 
-// RegisterCrl registers CRL
+// RegisterCrl registers CRL,
+// the registered CRL is replaced only by CRL with greater number,
+// otherwise the registered CRL is returned
 func (p *Provider) RegisterCrl(ctx context.Context, crl *model.Crl) (*model.Crl, error) {
 	id := crl.ID
 	var err error
@@ -28,25 +31,32 @@ func (p *Provider) RegisterCrl(ctx context.Context, crl *model.Crl) (*model.Crl,
 	res := new(model.Crl)
 
 	err = p.sql.QueryRowContext(ctx, `
-			INSERT INTO crls(id,ikid,this_update,next_update,issuer,pem)
-				VALUES($1, $2, $3, $4, $5, $6)
+			INSERT INTO crls(id,ikid,this_update,next_update,issuer,pem,crl_number)
+				VALUES($1, $2, $3, $4, $5, $6, $7)
 			ON CONFLICT (ikid)
 			DO UPDATE
-				SET this_update=$3,next_update=$4,pem=$6
-			RETURNING id,ikid,this_update,next_update,issuer,pem
+				SET this_update=$3,next_update=$4,pem=$6,crl_number=$7
+				WHERE crls.crl_number < EXCLUDED.crl_number
+			RETURNING id,ikid,this_update,next_update,issuer,pem,crl_number
 			;`, id,
 		crl.IKID,
 		crl.ThisUpdate,
 		crl.NextUpdate,
 		crl.Issuer,
 		crl.Pem,
+		crl.CrlNumber,
 	).Scan(&res.ID,
 		&res.IKID,
 		&res.ThisUpdate,
 		&res.NextUpdate,
 		&res.Issuer,
 		&res.Pem,
+		&res.CrlNumber,
 	)
+	if err == sql.ErrNoRows {
+		// the registered CRL is not older
+		return p.GetCrl(ctx, crl.IKID)
+	}
 	if err != nil {
 		p.CheckErrIDConflict(ctx, err, id)
 		return nil, errors.WithStack(err)
@@ -72,7 +82,7 @@ func (p *Provider) RemoveCrl(ctx context.Context, id uint64) error {
 func (p *Provider) GetCrl(ctx context.Context, ikid string) (*model.Crl, error) {
 	res := new(model.Crl)
 	err := p.sql.QueryRowContext(ctx, `
-		SELECT id,ikid,this_update,next_update,issuer,pem
+		SELECT id,ikid,this_update,next_update,issuer,pem,crl_number
 		FROM crls
 		WHERE ikid = $1
 		;
@@ -83,6 +93,7 @@ func (p *Provider) GetCrl(ctx context.Context, ikid string) (*model.Crl, error)
 		&res.NextUpdate,
 		&res.Issuer,
 		&res.Pem,
+		&res.CrlNumber,
 	)
 	if err != nil {
 		//logger.KV(xlog.ERROR, "err", err)
@@ -91,3 +102,274 @@ func (p *Provider) GetCrl(ctx context.Context, ikid string) (*model.Crl, error)
 
 	return res, nil
 }
+
+// ListCrls returns CRLs of all issuers
+func (p *Provider) ListCrls(ctx context.Context) ([]*model.Crl, error) {
+	res, err := p.sql.QueryContext(ctx, `
+		SELECT id,ikid,this_update,next_update,issuer,pem,crl_number
+		FROM crls
+		ORDER BY id ASC
+		;`)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.Crl, 0, 10)
+	for res.Next() {
+		m := new(model.Crl)
+		err = res.Scan(
+			&m.ID,
+			&m.IKID,
+			&m.ThisUpdate,
+			&m.NextUpdate,
+			&m.Issuer,
+			&m.Pem,
+			&m.CrlNumber,
+		)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, m)
+	}
+	return list, errors.WithStack(res.Err())
+}
+
+// RegisterDeltaCrl registers delta CRL,
+// the registered CRL is replaced only by CRL with greater number,
+// otherwise the registered CRL is returned
+func (p *Provider) RegisterDeltaCrl(ctx context.Context, crl *model.Crl) (*model.Crl, error) {
+	id := crl.ID
+	if id == 0 {
+		id = p.NextID().UInt64()
+	}
+
+	err := xdb.Validate(crl)
+	if err != nil {
+		return nil, err
+	}
+	if !crl.IsDelta() {
+		return nil, errors.New("invalid base CRL number")
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE,
+		"issuer", crl.Issuer,
+		"ikid", crl.IKID,
+		"number", crl.CrlNumber,
+		"base", crl.BaseNumber)
+
+	res := new(model.Crl)
+	err = p.sql.QueryRowContext(ctx, `
+			INSERT INTO delta_crls(id,ikid,crl_number,base_number,this_update,next_update,issuer,pem)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (ikid)
+			DO UPDATE
+				SET crl_number=$3,base_number=$4,this_update=$5,next_update=$6,pem=$8
+				WHERE delta_crls.crl_number < EXCLUDED.crl_number
+			RETURNING id,ikid,crl_number,base_number,this_update,next_update,issuer,pem
+			;`, id,
+		crl.IKID,
+		crl.CrlNumber,
+		crl.BaseNumber,
+		crl.ThisUpdate,
+		crl.NextUpdate,
+		crl.Issuer,
+		crl.Pem,
+	).Scan(&res.ID,
+		&res.IKID,
+		&res.CrlNumber,
+		&res.BaseNumber,
+		&res.ThisUpdate,
+		&res.NextUpdate,
+		&res.Issuer,
+		&res.Pem,
+	)
+	if err == sql.ErrNoRows {
+		// the registered CRL is not older
+		return p.GetDeltaCrl(ctx, crl.IKID)
+	}
+	if err != nil {
+		p.CheckErrIDConflict(ctx, err, id)
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// GetDeltaCrl returns delta CRL by a specified issuer
+func (p *Provider) GetDeltaCrl(ctx context.Context, ikid string) (*model.Crl, error) {
+	res := new(model.Crl)
+	err := p.sql.QueryRowContext(ctx, `
+		SELECT id,ikid,crl_number,base_number,this_update,next_update,issuer,pem
+		FROM delta_crls
+		WHERE ikid = $1
+		;
+		`, ikid).Scan(
+		&res.ID,
+		&res.IKID,
+		&res.CrlNumber,
+		&res.BaseNumber,
+		&res.ThisUpdate,
+		&res.NextUpdate,
+		&res.Issuer,
+		&res.Pem,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// RegisterShardCrl registers CRL of the shard,
+// the registered CRL is replaced only by CRL with greater number,
+// otherwise the registered CRL is returned
+func (p *Provider) RegisterShardCrl(ctx context.Context, crl *model.Crl) (*model.Crl, error) {
+	id := crl.ID
+	if id == 0 {
+		id = p.NextID().UInt64()
+	}
+
+	err := xdb.Validate(crl)
+	if err != nil {
+		return nil, err
+	}
+	if crl.Shard == 0 {
+		return nil, errors.New("invalid shard")
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE,
+		"issuer", crl.Issuer,
+		"ikid", crl.IKID,
+		"shard", crl.Shard,
+		"number", crl.CrlNumber)
+
+	res := new(model.Crl)
+	err = p.sql.QueryRowContext(ctx, `
+			INSERT INTO shard_crls(id,ikid,shard,crl_number,this_update,next_update,issuer,pem)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (ikid,shard)
+			DO UPDATE
+				SET crl_number=$4,this_update=$5,next_update=$6,pem=$8
+				WHERE shard_crls.crl_number < EXCLUDED.crl_number
+			RETURNING id,ikid,shard,crl_number,this_update,next_update,issuer,pem
+			;`, id,
+		crl.IKID,
+		crl.Shard,
+		crl.CrlNumber,
+		crl.ThisUpdate,
+		crl.NextUpdate,
+		crl.Issuer,
+		crl.Pem,
+	).Scan(&res.ID,
+		&res.IKID,
+		&res.Shard,
+		&res.CrlNumber,
+		&res.ThisUpdate,
+		&res.NextUpdate,
+		&res.Issuer,
+		&res.Pem,
+	)
+	if err == sql.ErrNoRows {
+		// the registered CRL is not older
+		return p.GetShardCrl(ctx, crl.IKID, crl.Shard)
+	}
+	if err != nil {
+		p.CheckErrIDConflict(ctx, err, id)
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// GetShardCrl returns CRL of the shard by a specified issuer
+func (p *Provider) GetShardCrl(ctx context.Context, ikid string, shard uint32) (*model.Crl, error) {
+	res := new(model.Crl)
+	err := p.sql.QueryRowContext(ctx, `
+		SELECT id,ikid,shard,crl_number,this_update,next_update,issuer,pem
+		FROM shard_crls
+		WHERE ikid = $1 AND shard = $2
+		;
+		`, ikid, shard).Scan(
+		&res.ID,
+		&res.IKID,
+		&res.Shard,
+		&res.CrlNumber,
+		&res.ThisUpdate,
+		&res.NextUpdate,
+		&res.Issuer,
+		&res.Pem,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+// ListShardCrls returns CRL shards of all issuers
+func (p *Provider) ListShardCrls(ctx context.Context) ([]*model.Crl, error) {
+	res, err := p.sql.QueryContext(ctx, `
+		SELECT id,ikid,shard,crl_number,this_update,next_update,issuer,pem
+		FROM shard_crls
+		ORDER BY id ASC
+		;`)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.Crl, 0, 10)
+	for res.Next() {
+		m := new(model.Crl)
+		err = res.Scan(
+			&m.ID,
+			&m.IKID,
+			&m.Shard,
+			&m.CrlNumber,
+			&m.ThisUpdate,
+			&m.NextUpdate,
+			&m.Issuer,
+			&m.Pem,
+		)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, m)
+	}
+	return list, errors.WithStack(res.Err())
+}
+
+// NextCrlNumber returns the next CRL number for the issuer,
+// the number is shared by complete and delta CRLs
+func (p *Provider) NextCrlNumber(ctx context.Context, ikid string) (uint64, error) {
+	var number uint64
+	err := p.sql.QueryRowContext(ctx, `
+			INSERT INTO crl_numbers(ikid,"number")
+				VALUES($1, 1)
+			ON CONFLICT (ikid)
+			DO UPDATE
+				SET "number"=crl_numbers."number"+1
+			RETURNING "number"
+			;`, ikid,
+	).Scan(&number)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return number, nil
+}
+
+// NextShardCrlNumber returns the next CRL number for the shard of the issuer,
+// each shard has own sequence
+func (p *Provider) NextShardCrlNumber(ctx context.Context, ikid string, shard uint32) (uint64, error) {
+	var number uint64
+	err := p.sql.QueryRowContext(ctx, `
+			INSERT INTO shard_crl_numbers(ikid,shard,"number")
+				VALUES($1, $2, 1)
+			ON CONFLICT (ikid,shard)
+			DO UPDATE
+				SET "number"=shard_crl_numbers."number"+1
+			RETURNING "number"
+			;`, ikid, shard,
+	).Scan(&number)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return number, nil
+}
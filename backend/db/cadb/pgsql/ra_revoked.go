@@ -35,12 +35,12 @@ func (p *Provider) RegisterRevokedCertificate(ctx context.Context, revoked *mode
 		return nil, errors.WithStack(err)
 	}
 	m, err := scanFullRevokedCertificate(p.sql.QueryRowContext(ctx, `
-			INSERT INTO revoked(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason)
-				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			INSERT INTO revoked(id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason,external)
+				VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 			ON CONFLICT (sha256)
 			DO UPDATE
 				SET org_id=$2,issuers_pem=$12
-			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason
+			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason,external
 			;`, id, crt.OrgID, crt.SKID, crt.IKID, crt.SerialNumber,
 		crt.NotBefore, crt.NotAfter,
 		crt.Subject, crt.Issuer,
@@ -52,6 +52,7 @@ func (p *Provider) RegisterRevokedCertificate(ctx context.Context, revoked *mode
 		string(b),
 		revoked.RevokedAt,
 		revoked.Reason,
+		crt.External,
 	))
 	if err != nil {
 		p.CheckErrIDConflict(ctx, err, id)
@@ -60,7 +61,7 @@ func (p *Provider) RegisterRevokedCertificate(ctx context.Context, revoked *mode
 	return m, nil
 }
 
-func scanFullRevokedCertificate(row *sql.Row) (*model.RevokedCertificate, error) {
+func scanFullRevokedCertificate(row xdb.Row) (*model.RevokedCertificate, error) {
 	res := new(model.RevokedCertificate)
 	var locations string
 	var meta string
@@ -82,6 +83,7 @@ func scanFullRevokedCertificate(row *sql.Row) (*model.RevokedCertificate, error)
 		&meta,
 		&res.RevokedAt,
 		&res.Reason,
+		&res.Certificate.External,
 	)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -116,6 +118,7 @@ func scanShortRevokedCertificate(row *sql.Rows) (*model.RevokedCertificate, erro
 		&meta,
 		&res.RevokedAt,
 		&res.Reason,
+		&res.Certificate.External,
 	)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -147,7 +150,7 @@ func (p *Provider) RemoveRevokedCertificate(ctx context.Context, id uint64) erro
 func (p *Provider) ListOrgRevokedCertificates(ctx context.Context, orgID uint64, limit int, afterID uint64) (model.RevokedCertificates, error) {
 	res, err := p.sql.QueryContext(ctx, `
 		SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,label,locations,metadata,revoked_at,reason
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,label,locations,metadata,revoked_at,reason,external
 		FROM
 			revoked
 		WHERE org_id = $1 AND id > $2
@@ -186,7 +189,7 @@ func (p *Provider) ListRevokedCertificates(ctx context.Context, ikid string, lim
 
 	res, err := p.sql.QueryContext(ctx,
 		`SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,label,locations,metadata,revoked_at,reason
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,profile,label,locations,metadata,revoked_at,reason,external
 		FROM
 			revoked
 		WHERE 
@@ -258,11 +261,26 @@ func (p *Provider) RevokeCertificate(ctx context.Context, crt *model.Certificate
 	return revoked, nil
 }
 
+// GetRevokedCertificate returns revoked certificate by the ID of the certificate
+func (p *Provider) GetRevokedCertificate(ctx context.Context, id uint64) (*model.RevokedCertificate, error) {
+	m, err := scanFullRevokedCertificate(p.sql.QueryRowContext(ctx, `
+			SELECT
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason,external
+			FROM revoked
+			WHERE id = $1;`,
+		id))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return m, nil
+}
+
 // GetRevokedCertificateByIKIDAndSerial returns revoked certificate
 func (p *Provider) GetRevokedCertificateByIKIDAndSerial(ctx context.Context, ikid, serial string) (*model.RevokedCertificate, error) {
 	m, err := scanFullRevokedCertificate(p.sql.QueryRowContext(ctx, `
 			SELECT
-			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason
+			id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason,external
 			FROM revoked
 			WHERE ikid = $1 AND serial_number = $2;`,
 		ikid, serial))
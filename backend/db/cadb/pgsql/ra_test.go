@@ -110,6 +110,42 @@ func TestRegisterCertificate(t *testing.T) {
 	assert.Len(t, r2.Locations, 3)
 	assert.Len(t, r2.Metadata, 1)
 
+	// the locally issued certificate can not be imported
+	rext := *r2
+	rext.External = true
+	_, err = provider.RegisterCertificate(ctx, &rext)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate is issued locally")
+
+	// the imported certificate becomes local when issued
+	imported := *rc
+	imported.SKID = guid.MustCreate()
+	imported.SerialNumber = certutil.RandomString(10)
+	imported.ThumbprintSha256 = certutil.RandomString(64)
+	imported.External = true
+	ri, err := provider.RegisterCertificate(ctx, &imported)
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.RemoveCertificate(ctx, ri.ID)
+	}()
+	assert.True(t, ri.External)
+	// the imported certificate is not updated by import
+	reimported := imported
+	reimported.OrgID = orgID + 1
+	reimported.Label = "reimported"
+	_, err = provider.RegisterCertificate(ctx, &reimported)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate already exists")
+	ri2, err := provider.GetCertificate(ctx, ri.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ri.OrgID, ri2.OrgID)
+	assert.Equal(t, ri.Label, ri2.Label)
+
+	imported.External = false
+	ri, err = provider.RegisterCertificate(ctx, &imported)
+	require.NoError(t, err)
+	assert.False(t, ri.External)
+
 	rcx := *r2
 	rcx.ThumbprintSha256 = certutil.RandomString(64)
 	// the same IKID, Serial
@@ -177,6 +213,14 @@ func TestRegisterCertificate(t *testing.T) {
 	revoked2.Certificate.Locations = r.Locations
 	assert.Equal(t, *revoked, *revoked2)
 
+	revoked3, err := provider.GetRevokedCertificate(ctx, r4.ID)
+	require.NoError(t, err)
+	revoked3.Certificate.Locations = r.Locations
+	assert.Equal(t, *revoked, *revoked3)
+
+	_, err = provider.GetRevokedCertificate(ctx, provider.NextID().UInt64())
+	assert.True(t, xdb.IsNotFoundError(err))
+
 	cc2, err := provider.GetTableRowsCount(ctx, cadb.TableNameForCertificates)
 	require.NoError(t, err)
 	assert.Greater(t, cc, cc2)
@@ -193,6 +237,69 @@ func TestRegisterCertificate(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRegisterCertificates(t *testing.T) {
+	orgID := provider.NextID().UInt64()
+	ikid := guid.MustCreate()
+
+	list, err := provider.RegisterCertificates(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	var rcs []*model.Certificate
+	for i := 0; i < 3; i++ {
+		rcs = append(rcs, &model.Certificate{
+			OrgID:            orgID,
+			SKID:             guid.MustCreate(),
+			IKID:             ikid,
+			SerialNumber:     certutil.RandomString(10),
+			Subject:          "subj",
+			Issuer:           "iss",
+			NotBefore:        xdb.FromNow(-time.Hour),
+			NotAfter:         xdb.FromNow(time.Hour),
+			ThumbprintSha256: certutil.RandomString(64),
+			Pem:              "pem",
+			IssuersPem:       "ipem",
+			Profile:          "client",
+			Label:            "label",
+			Locations:        []string{"1", "2"},
+			Metadata:         map[string]string{"i": string(rune('0' + i))},
+		})
+	}
+
+	list, err = provider.RegisterCertificates(ctx, rcs)
+	require.NoError(t, err)
+	require.Len(t, list, len(rcs))
+	defer func() {
+		for _, r := range list {
+			_ = provider.RemoveCertificate(ctx, r.ID)
+		}
+	}()
+
+	for i, r := range list {
+		assert.NotZero(t, r.ID)
+		assert.Equal(t, rcs[i].SerialNumber, r.SerialNumber)
+		assert.Equal(t, rcs[i].ThumbprintSha256, r.ThumbprintSha256)
+		assert.Equal(t, rcs[i].Locations, r.Locations)
+		assert.Equal(t, rcs[i].Metadata, r.Metadata)
+
+		got, err := provider.GetCertificate(ctx, r.ID)
+		require.NoError(t, err)
+		assert.Equal(t, *r, *got)
+	}
+
+	// registered again
+	list[1].Label = "updated"
+	list2, err := provider.RegisterCertificates(ctx, list[1:])
+	require.NoError(t, err)
+	require.Len(t, list2, 2)
+	assert.Equal(t, list[1].ID, list2[0].ID)
+	assert.Equal(t, "updated", list2[0].Label)
+	assert.Equal(t, list[2].ID, list2[1].ID)
+
+	_, err = provider.RegisterCertificates(ctx, []*model.Certificate{{}})
+	require.Error(t, err)
+}
+
 func TestRegisterCertificateUniqueIdx(t *testing.T) {
 	orgID := provider.NextID().UInt64()
 	rc := &model.Certificate{
@@ -333,6 +440,7 @@ func TestRegisterCrl(t *testing.T) {
 		ThisUpdate: xdb.FromNow(-time.Hour),
 		NextUpdate: xdb.FromNow(time.Hour),
 		Pem:        "pem",
+		CrlNumber:  1,
 	}
 
 	r, err := provider.RegisterCrl(ctx, rc)
@@ -347,6 +455,7 @@ func TestRegisterCrl(t *testing.T) {
 	assert.Equal(t, rc.Pem, r.Pem)
 	assert.Equal(t, rc.ThisUpdate, r.ThisUpdate)
 	assert.Equal(t, rc.NextUpdate, r.NextUpdate)
+	assert.Equal(t, rc.CrlNumber, r.CrlNumber)
 
 	r2, err := provider.GetCrl(ctx, r.IKID)
 	require.NoError(t, err)
@@ -355,6 +464,158 @@ func TestRegisterCrl(t *testing.T) {
 	assert.Equal(t, rc.Pem, r2.Pem)
 	assert.Equal(t, rc.ThisUpdate, r2.ThisUpdate)
 	assert.Equal(t, rc.NextUpdate, r2.NextUpdate)
+
+	// the older CRL does not replace the registered one
+	stale := *rc
+	stale.Pem = "stale"
+	r3, err := provider.RegisterCrl(ctx, &stale)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, r3.ID)
+	assert.Equal(t, rc.Pem, r3.Pem)
+
+	newer := *rc
+	newer.Pem = "newer"
+	newer.CrlNumber = 2
+	r3, err = provider.RegisterCrl(ctx, &newer)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, r3.ID)
+	assert.Equal(t, "newer", r3.Pem)
+	assert.Equal(t, uint64(2), r3.CrlNumber)
+	rc.Pem = newer.Pem
+
+	list, err := provider.ListCrls(ctx)
+	require.NoError(t, err)
+	found := false
+	for _, c := range list {
+		if c.ID == r.ID {
+			found = true
+			assert.Equal(t, rc.Pem, c.Pem)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestRegisterDeltaCrl(t *testing.T) {
+	ikid := guid.MustCreate()
+
+	n1, err := provider.NextCrlNumber(ctx, ikid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), n1)
+	n2, err := provider.NextCrlNumber(ctx, ikid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), n2)
+
+	rc := &model.Crl{
+		IKID:       ikid,
+		Issuer:     "iss",
+		ThisUpdate: xdb.FromNow(-time.Hour),
+		NextUpdate: xdb.FromNow(time.Hour),
+		Pem:        "pem",
+		CrlNumber:  n2,
+	}
+	_, err = provider.RegisterDeltaCrl(ctx, rc)
+	require.Error(t, err)
+
+	rc.BaseNumber = n1
+	r, err := provider.RegisterDeltaCrl(ctx, rc)
+	require.NoError(t, err)
+	assert.True(t, r.IsDelta())
+	assert.Equal(t, n2, r.CrlNumber)
+	assert.Equal(t, n1, r.BaseNumber)
+
+	_, err = provider.GetDeltaCrl(ctx, guid.MustCreate())
+	require.Error(t, err)
+
+	r2, err := provider.GetDeltaCrl(ctx, ikid)
+	require.NoError(t, err)
+	assert.Equal(t, *r, *r2)
+
+	// the older CRL does not replace the registered one
+	stale := *rc
+	stale.CrlNumber = n1
+	stale.Pem = "stale"
+	r3, err := provider.RegisterDeltaCrl(ctx, &stale)
+	require.NoError(t, err)
+	assert.Equal(t, *r, *r3)
+}
+
+func TestRegisterShardCrl(t *testing.T) {
+	ikid := guid.MustCreate()
+
+	rc := &model.Crl{
+		IKID:       ikid,
+		Issuer:     "iss",
+		ThisUpdate: xdb.FromNow(-time.Hour),
+		NextUpdate: xdb.FromNow(time.Hour),
+		Pem:        "pem",
+		CrlNumber:  1,
+	}
+	_, err := provider.RegisterShardCrl(ctx, rc)
+	require.Error(t, err)
+
+	var list []*model.Crl
+	for shard := uint32(1); shard <= 2; shard++ {
+		rc.Shard = shard
+		rc.CrlNumber++
+		r, err := provider.RegisterShardCrl(ctx, rc)
+		require.NoError(t, err)
+		assert.Equal(t, shard, r.Shard)
+		assert.Equal(t, rc.CrlNumber, r.CrlNumber)
+		list = append(list, r)
+	}
+
+	// update
+	rc.Shard = 1
+	rc.CrlNumber = 10
+	r, err := provider.RegisterShardCrl(ctx, rc)
+	require.NoError(t, err)
+	assert.Equal(t, list[0].ID, r.ID)
+	assert.Equal(t, uint64(10), r.CrlNumber)
+
+	// the older CRL does not replace the registered one
+	rc.CrlNumber = 9
+	rc.Pem = "stale"
+	r3, err := provider.RegisterShardCrl(ctx, rc)
+	require.NoError(t, err)
+	assert.Equal(t, *r, *r3)
+
+	_, err = provider.GetShardCrl(ctx, ikid, 3)
+	require.Error(t, err)
+
+	r2, err := provider.GetShardCrl(ctx, ikid, 1)
+	require.NoError(t, err)
+	assert.Equal(t, *r, *r2)
+
+	all, err := provider.ListShardCrls(ctx)
+	require.NoError(t, err)
+	count := 0
+	for _, c := range all {
+		if c.IKID == ikid {
+			count++
+		}
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestNextShardCrlNumber(t *testing.T) {
+	ikid := guid.MustCreate()
+
+	n, err := provider.NextCrlNumber(ctx, ikid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), n)
+
+	// each shard has own sequence
+	for _, shard := range []uint32{1, 2} {
+		for expected := uint64(1); expected <= 2; expected++ {
+			n, err = provider.NextShardCrlNumber(ctx, ikid, shard)
+			require.NoError(t, err)
+			assert.Equal(t, expected, n)
+		}
+	}
+
+	n, err = provider.NextCrlNumber(ctx, ikid)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), n)
 }
 
 func TestListCertificate(t *testing.T) {
@@ -404,6 +665,11 @@ func TestListCertificate(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, list3, 0)
 
+	// all issuers
+	all, err := provider.ListCertificates(ctx, "", 500, first-1)
+	require.NoError(t, err)
+	assert.NotNil(t, all.Find(first))
+
 	last = uint64(0)
 	bulk := make([]*model.Certificate, 0, count)
 	for {
@@ -0,0 +1,138 @@
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+// PurgeExpiredCertificates removes certificates expired before the specified time,
+// and returns the removed certificates
+func (p *Provider) PurgeExpiredCertificates(ctx context.Context, before time.Time, limit int) (model.Certificates, error) {
+	if limit == 0 {
+		limit = 1000
+	}
+
+	res, err := p.sql.QueryContext(ctx, `
+		DELETE FROM certificates
+		WHERE id IN (
+			SELECT id FROM certificates
+			WHERE no_tafter < $1
+			ORDER BY id ASC
+			LIMIT $2
+		)
+		RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,external
+		;`, before.UTC(), limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.Certificate, 0, 100)
+	for res.Next() {
+		m, err := scanFullCertificate(res)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, m)
+	}
+	if err = res.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE, "before", before, "purged", len(list))
+	return list, nil
+}
+
+// PurgeExpiredRevokedCertificates removes revoked certificates expired before the specified time,
+// and returns the removed certificates.
+// The revocation info of the removed certificates is kept as tombstones,
+// to not answer OCSP requests with good status.
+func (p *Provider) PurgeExpiredRevokedCertificates(ctx context.Context, before time.Time, limit int) (model.RevokedCertificates, error) {
+	if limit == 0 {
+		limit = 1000
+	}
+
+	res, err := p.sql.QueryContext(ctx, `
+		WITH purged AS (
+			DELETE FROM revoked
+			WHERE id IN (
+				SELECT id FROM revoked
+				WHERE no_tafter < $1
+				ORDER BY id ASC
+				LIMIT $2
+			)
+			RETURNING id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason,external
+		), tombstones AS (
+			INSERT INTO revoked_tombstones(ikid,serial_number,no_tafter,revoked_at,reason)
+			SELECT ikid,serial_number,no_tafter,revoked_at,reason FROM purged
+			WHERE external = false
+			ON CONFLICT (ikid,serial_number) DO NOTHING
+		)
+		SELECT id,org_id,skid,ikid,serial_number,not_before,no_tafter,subject,issuer,sha256,pem,issuers_pem,profile,label,locations,metadata,revoked_at,reason,external
+		FROM purged
+		;`, before.UTC(), limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer res.Close()
+
+	list := make([]*model.RevokedCertificate, 0, 100)
+	for res.Next() {
+		m, err := scanFullRevokedCertificate(res)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, m)
+	}
+	if err = res.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE, "before", before, "purged", len(list))
+	return list, nil
+}
+
+// GetRevokedTombstone returns the revocation info of the purged revoked certificate
+func (p *Provider) GetRevokedTombstone(ctx context.Context, ikid, serial string) (*model.RevokedTombstone, error) {
+	m := new(model.RevokedTombstone)
+	err := p.sql.QueryRowContext(ctx, `
+		SELECT ikid,serial_number,no_tafter,revoked_at,reason
+		FROM revoked_tombstones
+		WHERE ikid = $1 AND serial_number = $2
+		;`, ikid, serial).Scan(
+		&m.IKID,
+		&m.SerialNumber,
+		&m.NotAfter,
+		&m.RevokedAt,
+		&m.Reason,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return m, nil
+}
+
+// PurgeNonces removes nonces used or expired before the specified time,
+// and returns the number of removed nonces.
+// Unused nonces without expiration are not removed.
+func (p *Provider) PurgeNonces(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.sql.ExecContext(ctx, `
+		DELETE FROM nonces
+		WHERE (used = true AND used_at < $1)
+			OR (expires_at > created_at AND expires_at < $1)
+		;`, before.UTC())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE, "before", before, "purged", count)
+	return count, nil
+}
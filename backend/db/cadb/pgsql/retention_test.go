@@ -0,0 +1,133 @@
+package pgsql_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/x/guid"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeExpired(t *testing.T) {
+	// use dates far in the past to not purge certificates of other tests
+	notAfter := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := notAfter.Add(24 * time.Hour)
+
+	newCert := func() *model.Certificate {
+		return &model.Certificate{
+			OrgID:            provider.NextID().UInt64(),
+			SKID:             guid.MustCreate(),
+			IKID:             guid.MustCreate(),
+			SerialNumber:     certutil.RandomString(10),
+			Subject:          "subj",
+			Issuer:           "iss",
+			NotBefore:        xdb.Time(notAfter.Add(-time.Hour)),
+			NotAfter:         xdb.Time(notAfter),
+			ThumbprintSha256: certutil.RandomString(64),
+			Pem:              "pem",
+			IssuersPem:       "ipem",
+			Profile:          "client",
+		}
+	}
+
+	crt, err := provider.RegisterCertificate(ctx, newCert())
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.RemoveCertificate(ctx, crt.ID)
+	}()
+
+	revoked, err := provider.RegisterRevokedCertificate(ctx, &model.RevokedCertificate{
+		Certificate: *newCert(),
+		RevokedAt:   xdb.Time(notAfter.Add(-time.Minute)),
+		Reason:      1,
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.RemoveRevokedCertificate(ctx, revoked.Certificate.ID)
+	}()
+
+	list, err := provider.PurgeExpiredCertificates(ctx, notAfter, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	list, err = provider.PurgeExpiredCertificates(ctx, before, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, crt.ID, list[0].ID)
+	assert.Equal(t, crt.Pem, list[0].Pem)
+
+	_, err = provider.GetCertificate(ctx, crt.ID)
+	assert.True(t, xdb.IsNotFoundError(err))
+
+	rlist, err := provider.PurgeExpiredRevokedCertificates(ctx, before, 10)
+	require.NoError(t, err)
+	require.Len(t, rlist, 1)
+	assert.Equal(t, revoked.Certificate.ID, rlist[0].Certificate.ID)
+	assert.Equal(t, 1, rlist[0].Reason)
+
+	_, err = provider.GetRevokedCertificateByIKIDAndSerial(ctx, revoked.Certificate.IKID, revoked.Certificate.SerialNumber)
+	assert.True(t, xdb.IsNotFoundError(err))
+
+	ts, err := provider.GetRevokedTombstone(ctx, revoked.Certificate.IKID, revoked.Certificate.SerialNumber)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ts.Reason)
+	assert.Equal(t, revoked.RevokedAt.UTC(), ts.RevokedAt.UTC())
+	assert.Equal(t, revoked.Certificate.NotAfter.UTC(), ts.NotAfter.UTC())
+
+	_, err = provider.GetRevokedTombstone(ctx, revoked.Certificate.IKID, crt.SerialNumber)
+	assert.True(t, xdb.IsNotFoundError(err))
+}
+
+func TestPurgeNonces(t *testing.T) {
+	now := time.Now().UTC()
+	token := func() string {
+		return fmt.Sprintf("p-%d", provider.NextID().UInt64())[:16]
+	}
+
+	expired, err := provider.CreateNonce(ctx, &model.Nonce{
+		Nonce:     token(),
+		CreatedAt: now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.DeleteNonce(ctx, expired.ID)
+	}()
+
+	used, err := provider.CreateNonce(ctx, &model.Nonce{
+		Nonce:     token(),
+		CreatedAt: now.Add(-2 * time.Hour),
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.DeleteNonce(ctx, used.ID)
+	}()
+	_, err = provider.UseNonce(ctx, used.Nonce)
+	require.NoError(t, err)
+
+	active, err := provider.CreateNonce(ctx, &model.Nonce{
+		Nonce:     token(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	defer func() {
+		_ = provider.DeleteNonce(ctx, active.ID)
+	}()
+
+	count, err := provider.PurgeNonces(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(2))
+
+	_, err = provider.UseNonce(ctx, expired.Nonce)
+	assert.True(t, xdb.IsNotFoundError(err))
+
+	_, err = provider.UseNonce(ctx, active.Nonce)
+	assert.NoError(t, err)
+}
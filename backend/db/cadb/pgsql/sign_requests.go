@@ -0,0 +1,105 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+const signRequestColumns = `id,org_id,idempotency_key,digest,certificate_id,created_at`
+
+// ReserveSignRequest registers the idempotency key of the sign request.
+// If the key already exists in the org, then the existing request is returned,
+// and the caller must compare its ID with the requested one.
+func (p *Provider) ReserveSignRequest(ctx context.Context, r *model.SignRequest) (*model.SignRequest, error) {
+	err := xdb.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE, "id", r.ID, "org_id", r.OrgID, "key", r.IdempotencyKey)
+
+	res, err := scanSignRequest(p.sql.QueryRowContext(ctx, `
+			INSERT INTO sign_requests(`+signRequestColumns+`)
+				VALUES($1, $2, $3, $4, 0, $5)
+			ON CONFLICT (org_id, idempotency_key) DO NOTHING
+			RETURNING `+signRequestColumns+`
+			;`, r.ID,
+		r.OrgID,
+		r.IdempotencyKey,
+		r.Digest,
+		time.Now().UTC(),
+	))
+	if err == nil {
+		return res, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		p.CheckErrIDConflict(ctx, err, r.ID)
+		return nil, err
+	}
+	return p.GetSignRequest(ctx, r.OrgID, r.IdempotencyKey)
+}
+
+// GetSignRequest returns the sign request by the idempotency key in the org
+func (p *Provider) GetSignRequest(ctx context.Context, orgID uint64, key string) (*model.SignRequest, error) {
+	return scanSignRequest(p.sql.QueryRowContext(ctx, `
+		SELECT `+signRequestColumns+`
+		FROM sign_requests
+		WHERE org_id = $1 AND idempotency_key = $2
+		;`, orgID, key))
+}
+
+// CompleteSignRequest sets the issued certificate of the sign request
+func (p *Provider) CompleteSignRequest(ctx context.Context, id, certID uint64) (*model.SignRequest, error) {
+	return scanSignRequest(p.sql.QueryRowContext(ctx, `
+		UPDATE sign_requests
+			SET certificate_id=$2
+		WHERE id=$1
+		RETURNING `+signRequestColumns+`
+		;`, id, certID))
+}
+
+// DeleteSignRequest deletes the sign request
+func (p *Provider) DeleteSignRequest(ctx context.Context, id uint64) error {
+	_, err := p.sql.ExecContext(ctx, `DELETE FROM sign_requests WHERE id=$1;`, id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// PurgeSignRequests removes sign requests created before the specified time
+func (p *Provider) PurgeSignRequests(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.sql.ExecContext(ctx, `DELETE FROM sign_requests WHERE created_at < $1;`, before.UTC())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	logger.ContextKV(ctx, xlog.DEBUG, "purged", count, "before", before)
+	return count, nil
+}
+
+func scanSignRequest(row xdb.Row) (*model.SignRequest, error) {
+	res := new(model.SignRequest)
+	err := row.Scan(
+		&res.ID,
+		&res.OrgID,
+		&res.IdempotencyKey,
+		&res.Digest,
+		&res.CertificateID,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return res, nil
+}
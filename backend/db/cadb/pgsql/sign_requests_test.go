@@ -0,0 +1,72 @@
+package pgsql_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/x/guid"
+	"github.com/effective-security/xdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignRequests(t *testing.T) {
+	key := guid.MustCreate()
+	digest := sha256.Sum256([]byte(key))
+
+	r := &model.SignRequest{
+		ID:             provider.NextID().UInt64(),
+		OrgID:          1000,
+		IdempotencyKey: key,
+		Digest:         "invalid",
+	}
+	_, err := provider.ReserveSignRequest(ctx, r)
+	assert.EqualError(t, err, "invalid digest")
+
+	r.Digest = hex.EncodeToString(digest[:])
+	r1, err := provider.ReserveSignRequest(ctx, r)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, r1.ID)
+	assert.True(t, r1.InProgress())
+
+	// the same key returns the existing request
+	r2 := *r
+	r2.ID = provider.NextID().UInt64()
+	existing, err := provider.ReserveSignRequest(ctx, &r2)
+	require.NoError(t, err)
+	assert.Equal(t, r.ID, existing.ID)
+
+	// the same key in other org is reserved
+	r5 := r2
+	r5.OrgID = 1001
+	other, err := provider.ReserveSignRequest(ctx, &r5)
+	require.NoError(t, err)
+	assert.Equal(t, r5.ID, other.ID)
+	require.NoError(t, provider.DeleteSignRequest(ctx, r5.ID))
+
+	certID := provider.NextID().UInt64()
+	r3, err := provider.CompleteSignRequest(ctx, r.ID, certID)
+	require.NoError(t, err)
+	assert.Equal(t, certID, r3.CertificateID)
+	assert.False(t, r3.InProgress())
+
+	r4, err := provider.GetSignRequest(ctx, r.OrgID, key)
+	require.NoError(t, err)
+	assert.Equal(t, *r3, *r4)
+
+	require.NoError(t, provider.DeleteSignRequest(ctx, r.ID))
+	_, err = provider.GetSignRequest(ctx, r.OrgID, key)
+	require.Error(t, err)
+	assert.True(t, xdb.IsNotFoundError(err))
+
+	_, err = provider.ReserveSignRequest(ctx, r)
+	require.NoError(t, err)
+	count, err := provider.PurgeSignRequests(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(1))
+	_, err = provider.GetSignRequest(ctx, r.OrgID, key)
+	assert.True(t, xdb.IsNotFoundError(err))
+}
@@ -0,0 +1,218 @@
+package ca
+
+import (
+	"context"
+	"io"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/authority"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxSignBatch specifies the max number of requests in SignCertificates
+	maxSignBatch = 1000
+	// signBatchChunk specifies the max number of certificates
+	// registered in a single DB statement
+	signBatchChunk = 100
+)
+
+// SignCertificates signs the list of certificate requests,
+// and returns the result for each request in the order of the list
+func (s *Service) SignCertificates(ctx context.Context, req *pb.SignCertificatesRequest) (*pb.SignCertificatesResponse, error) {
+	if req == nil || len(req.Requests) == 0 {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing requests")
+	}
+	if len(req.Requests) > maxSignBatch {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "the number of requests must not exceed %d", maxSignBatch)
+	}
+
+	res := &pb.SignCertificatesResponse{
+		Results: s.signBatch(ctx, req.Requests, 0),
+	}
+	return res, nil
+}
+
+// SignCertificatesStream signs the stream of certificate requests,
+// the results are sent in the order of the requests
+func (s *Service) SignCertificatesStream(stream pb.CA_SignCertificatesStreamServer) error {
+	ctx := stream.Context()
+
+	var recvErr error
+	reqs := make(chan *pb.SignCertificateRequest, signBatchChunk)
+	go func() {
+		defer close(reqs)
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					recvErr = err
+				}
+				return
+			}
+			select {
+			case reqs <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var index uint32
+	for req := range reqs {
+		// sign the received requests together, without waiting for a full chunk
+		batch := []*pb.SignCertificateRequest{req}
+	drain:
+		for len(batch) < signBatchChunk {
+			select {
+			case r, ok := <-reqs:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, r)
+			default:
+				break drain
+			}
+		}
+
+		for _, res := range s.signBatch(ctx, batch, index) {
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+		}
+		index += uint32(len(batch))
+	}
+	return recvErr
+}
+
+type issuerKey struct {
+	label   string
+	profile string
+}
+
+type resolvedIssuer struct {
+	ca      *authority.Issuer
+	profile *authority.CertProfile
+	err     error
+}
+
+// signBatch signs the requests, and registers the issued certificates in chunks.
+// The results are indexed from the offset.
+func (s *Service) signBatch(ctx context.Context, reqs []*pb.SignCertificateRequest, offset uint32) []*pb.SignCertificateResult {
+	results := make([]*pb.SignCertificateResult, len(reqs))
+
+	// the issuer and profile are resolved once per batch
+	resolved := map[issuerKey]resolvedIssuer{}
+	resolve := func(ctx context.Context, req *pb.SignCertificateRequest) (*authority.Issuer, *authority.CertProfile, error) {
+		key := issuerKey{label: req.IssuerLabel, profile: req.Profile}
+		r, ok := resolved[key]
+		if !ok {
+			r.ca, r.profile, r.err = s.resolveIssuer(ctx, req)
+			resolved[key] = r
+		}
+		return r.ca, r.profile, r.err
+	}
+
+	var pending []int
+	var issued []*model.Certificate
+	flush := func() {
+		list, errs := s.registerIssuedList(ctx, issued)
+		for i, idx := range pending {
+			if errs[i] != nil {
+				results[idx] = batchError(results[idx], errs[i])
+			} else {
+				results[idx].Certificate = list[i].ToPB()
+				results[idx].Warnings = list[i].LintWarnings()
+			}
+		}
+		pending = pending[:0]
+		issued = issued[:0]
+	}
+
+	for i, req := range reqs {
+		results[i] = &pb.SignCertificateResult{
+			Index: offset + uint32(i),
+		}
+
+		if req != nil && req.IdempotencyKey != "" {
+			res, err := s.signIdempotent(ctx, req)
+			if err != nil {
+				results[i] = batchError(results[i], err)
+			} else {
+				results[i].Certificate = res.Certificate
+				results[i].Warnings = res.Warnings
+			}
+			continue
+		}
+
+		mcert, err := s.issueCertificate(ctx, req, resolve)
+		if err != nil {
+			results[i] = batchError(results[i], err)
+			continue
+		}
+
+		pending = append(pending, i)
+		issued = append(issued, mcert)
+		if len(issued) >= signBatchChunk {
+			flush()
+		}
+	}
+	if len(issued) > 0 {
+		flush()
+	}
+
+	return results
+}
+
+// registerIssuedList registers the issued certificates in a single DB statement,
+// and enqueues them to publish.
+// If the statement fails, the certificates are registered one by one,
+// so the signed certificates are not lost by the failure of one of them.
+// The returned lists have the registered certificate or the error for each item.
+func (s *Service) registerIssuedList(ctx context.Context, list []*model.Certificate) ([]*model.Certificate, []error) {
+	for _, mcert := range list {
+		mcert.Locations = append(mcert.Locations, s.certLocation(mcert))
+	}
+
+	errs := make([]error, len(list))
+	registered, err := s.db.RegisterCertificates(ctx, list)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.WARNING,
+			"status", "failed to register certificates, registering one by one",
+			"count", len(list),
+			"err", err.Error())
+
+		registered = make([]*model.Certificate, len(list))
+		for i, mcert := range list {
+			registered[i], errs[i] = s.registerCertificate(ctx, mcert)
+		}
+	}
+
+	jobs := make(model.PublishJobs, 0, len(registered))
+	for _, mcert := range registered {
+		if mcert == nil {
+			continue
+		}
+		jobs = append(jobs, &model.PublishJob{
+			Kind:  model.PublishKindCert,
+			IKID:  mcert.IKID,
+			RefID: mcert.ID,
+		})
+		logIssued(ctx, mcert)
+	}
+	// the client must not fail as the certificates are already registered
+	s.enqueuePublishJobs(ctx, jobs)
+
+	return registered, errs
+}
+
+func batchError(res *pb.SignCertificateResult, err error) *pb.SignCertificateResult {
+	st := status.Convert(err)
+	res.Code = uint32(st.Code())
+	res.Error = st.Message()
+	return res
+}
@@ -0,0 +1,217 @@
+package ca
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/csr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockBatchDB struct {
+	*mockSignDB
+
+	// batches has the number of certificates in each RegisterCertificates call
+	batches []int
+	// registerErr is returned by RegisterCertificates
+	registerErr error
+	// itemErr is returned by the next RegisterCertificate
+	itemErr error
+}
+
+func (m *mockBatchDB) RegisterCertificate(ctx context.Context, crt *model.Certificate) (*model.Certificate, error) {
+	if err := m.itemErr; err != nil {
+		m.itemErr = nil
+		return nil, err
+	}
+	return m.mockSignDB.RegisterCertificate(ctx, crt)
+}
+
+func (m *mockBatchDB) RegisterCertificates(ctx context.Context, list []*model.Certificate) ([]*model.Certificate, error) {
+	if m.registerErr != nil {
+		return nil, m.registerErr
+	}
+	m.batches = append(m.batches, len(list))
+	res := make([]*model.Certificate, len(list))
+	for i, crt := range list {
+		res[i], _ = m.RegisterCertificate(ctx, crt)
+	}
+	return res, nil
+}
+
+type mockSignStream struct {
+	grpc.ServerStream
+
+	ctx     context.Context
+	reqs    []*pb.SignCertificateRequest
+	results []*pb.SignCertificateResult
+	recvErr error
+}
+
+func (m *mockSignStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockSignStream) Recv() (*pb.SignCertificateRequest, error) {
+	if len(m.reqs) == 0 {
+		if m.recvErr != nil {
+			return nil, m.recvErr
+		}
+		return nil, io.EOF
+	}
+	req := m.reqs[0]
+	m.reqs = m.reqs[1:]
+	return req, nil
+}
+
+func (m *mockSignStream) Send(res *pb.SignCertificateResult) error {
+	m.results = append(m.results, res)
+	return nil
+}
+
+func TestSignCertificates(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("server", &authority.CertProfile{
+		Usage:  []string{"server auth"},
+		Expiry: csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockBatchDB{
+		mockSignDB: &mockSignDB{
+			certs:    map[uint64]*model.Certificate{},
+			requests: map[string]*model.SignRequest{},
+		},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+	}
+
+	newRequest := func() *pb.SignCertificateRequest {
+		return &pb.SignCertificateRequest{
+			Profile:       "server",
+			Request:       csrForTest(t),
+			RequestFormat: pb.EncodingFormat_PEM,
+			Label:         "batch",
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := s.SignCertificates(ctx, &pb.SignCertificatesRequest{})
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = s.SignCertificates(ctx, &pb.SignCertificatesRequest{
+			Requests: make([]*pb.SignCertificateRequest, maxSignBatch+1),
+		})
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		db.batches = nil
+		unknown := newRequest()
+		unknown.Profile = "unknown"
+		idempotent := newRequest()
+		idempotent.IdempotencyKey = "batch1"
+
+		res, err := s.SignCertificates(ctx, &pb.SignCertificatesRequest{
+			Requests: []*pb.SignCertificateRequest{
+				newRequest(),
+				unknown,
+				nil,
+				idempotent,
+				newRequest(),
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 5)
+
+		for i, r := range res.Results {
+			assert.Equal(t, uint32(i), r.Index)
+		}
+		for _, i := range []int{0, 3, 4} {
+			r := res.Results[i]
+			require.NotNil(t, r.Certificate, "%d: %s", i, r.Error)
+			assert.Equal(t, uint32(0), r.Code)
+			assert.Equal(t, "batch", r.Certificate.Label)
+			assert.NotNil(t, db.certs[r.Certificate.ID])
+		}
+		assert.Equal(t, uint32(codes.NotFound), res.Results[1].Code)
+		assert.Contains(t, res.Results[1].Error, "issuer not found for profile: unknown")
+		assert.Equal(t, uint32(codes.InvalidArgument), res.Results[2].Code)
+		assert.Equal(t, "missing profile", res.Results[2].Error)
+
+		// the idempotent request is registered individually
+		assert.Equal(t, []int{2}, db.batches)
+		assert.Equal(t, res.Results[3].Certificate.ID, db.request(0, "batch1").CertificateID)
+	})
+
+	t.Run("register_failed", func(t *testing.T) {
+		db.registerErr = errors.New("db failed")
+		db.itemErr = errors.New("item failed")
+		defer func() { db.registerErr = nil }()
+
+		// the chunk is registered one by one
+		res, err := s.SignCertificates(ctx, &pb.SignCertificatesRequest{
+			Requests: []*pb.SignCertificateRequest{newRequest(), newRequest()},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Results, 2)
+		assert.Nil(t, res.Results[0].Certificate)
+		assert.NotZero(t, res.Results[0].Code)
+		assert.Contains(t, res.Results[0].Error, "failed to register certificate")
+
+		require.NotNil(t, res.Results[1].Certificate)
+		assert.Zero(t, res.Results[1].Code)
+		assert.Equal(t, res.Results[1].Certificate.ID, db.certs[res.Results[1].Certificate.ID].ID)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		db.batches = nil
+		count := signBatchChunk + 10
+		stream := &mockSignStream{ctx: ctx}
+		for i := 0; i < count; i++ {
+			stream.reqs = append(stream.reqs, newRequest())
+		}
+
+		err := s.SignCertificatesStream(stream)
+		require.NoError(t, err)
+		require.Len(t, stream.results, count)
+		total := 0
+		for i, r := range stream.results {
+			assert.Equal(t, uint32(i), r.Index)
+			assert.NotNil(t, r.Certificate)
+		}
+		for _, n := range db.batches {
+			assert.LessOrEqual(t, n, signBatchChunk)
+			total += n
+		}
+		assert.Equal(t, count, total)
+	})
+
+	t.Run("stream_failed", func(t *testing.T) {
+		stream := &mockSignStream{
+			ctx:     ctx,
+			reqs:    []*pb.SignCertificateRequest{newRequest()},
+			recvErr: status.Error(codes.Canceled, "canceled"),
+		}
+		err := s.SignCertificatesStream(stream)
+		require.Error(t, err)
+		assert.Equal(t, codes.Canceled, status.Code(err))
+		assert.Len(t, stream.results, 1)
+	})
+}
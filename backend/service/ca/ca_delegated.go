@@ -101,7 +101,9 @@ func (s *Service) RegisterDelegatedIssuer(ctx context.Context, req *pb.SignCerti
 	req.Request = csrPEM
 	req.RequestFormat = pb.EncodingFormat_PEM
 
-	signRes, err := s.SignCertificate(ctx, req)
+	// the idempotency key is not supported for delegated issuers,
+	// as the key is generated per request
+	signRes, err := s.signCertificate(ctx, req)
 	if err != nil {
 		return nil, httperror.WrapWithCtx(ctx, err, "failed to create key")
 	}
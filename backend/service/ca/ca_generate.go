@@ -0,0 +1,166 @@
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"filippo.io/age"
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/cryptoprov/inmemcrypto"
+	"github.com/effective-security/xpki/csr"
+	"github.com/pkg/errors"
+	"github.com/youmark/pkcs8"
+	"google.golang.org/grpc/codes"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// GenerateAndSignCertificate generates the key pair for the profile,
+// signs the certificate, and returns the password protected key bundle.
+// The key is generated in memory of the request, and is not retained.
+func (s *Service) GenerateAndSignCertificate(ctx context.Context, req *pb.GenerateCertificateRequest) (*pb.GenerateCertificateResponse, error) {
+	if req == nil || req.Profile == "" {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing profile")
+	}
+	kg := s.cfg.KeyGenerationProfile(req.Profile)
+	if kg == nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.FailedPrecondition, "profile %q does not allow key generation", req.Profile)
+	}
+	if req.Password == "" {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing password")
+	}
+	if req.Format != pb.KeyBundleFormat_PKCS12 && req.Format != pb.KeyBundleFormat_PKCS8 {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "unsupported format: %v", req.Format)
+	}
+	var recipient age.Recipient
+	if req.Recipient != "" {
+		r, err := age.ParseX25519Recipient(req.Recipient)
+		if err != nil {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid recipient: %s", err.Error())
+		}
+		recipient = r
+	}
+
+	algo, size := kg.KeyAlgorithm()
+	// the ID suffix makes the label unique for concurrent requests
+	keyLabel := fmt.Sprintf("%s-%s-%s-%d", s.cfg.ClusterName, req.Profile,
+		time.Now().UTC().Format("20060102-150405"), s.db.NextID().UInt64())
+	cp := csr.NewProvider(inmemcrypto.NewProvider())
+	csrPEM, keyPEM, _, _, err := cp.CreateRequestAndExportKey(cp.NewSigningCertificateRequest(keyLabel, algo, size, "", nil, nil))
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to generate key")
+	}
+	defer clear(keyPEM)
+
+	key, err := certutil.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to parse generated key")
+	}
+
+	res, err := s.signCertificate(ctx, &pb.SignCertificateRequest{
+		RequestFormat: pb.EncodingFormat_PEM,
+		Request:       csrPEM,
+		Profile:       req.Profile,
+		IssuerLabel:   req.IssuerLabel,
+		SAN:           req.SAN,
+		Subject:       req.Subject,
+		OrgID:         req.OrgID,
+		NotBefore:     req.NotBefore,
+		NotAfter:      req.NotAfter,
+		Label:         req.Label,
+		Metadata:      req.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := encodeKeyBundle(req.Format, key, res.Certificate, req.Password)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to encode key bundle")
+	}
+	if recipient != nil {
+		bundle, err = encryptKeyBundle(bundle, recipient)
+		if err != nil {
+			return nil, httperror.WrapWithCtx(ctx, err, "failed to encrypt key bundle")
+		}
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "generated key",
+		"id", res.Certificate.ID,
+		"profile", req.Profile,
+		"algo", algo,
+		"size", size,
+		"format", req.Format.String())
+
+	return &pb.GenerateCertificateResponse{
+		Certificate: res.Certificate,
+		Format:      req.Format,
+		Bundle:      bundle,
+		Warnings:    res.Warnings,
+	}, nil
+}
+
+// encodeKeyBundle returns the key and certificate chain
+// as PKCS#12, or PEM with encrypted PKCS#8 key
+func encodeKeyBundle(format pb.KeyBundleFormat, key crypto.Signer, crt *pb.Certificate, password string) ([]byte, error) {
+	switch format {
+	case pb.KeyBundleFormat_PKCS12:
+		cert, err := certutil.ParseFromPEM([]byte(crt.Pem))
+		if err != nil {
+			return nil, err
+		}
+		var chain []*x509.Certificate
+		if crt.IssuersPem != "" {
+			chain, err = certutil.ParseChainFromPEM([]byte(crt.IssuersPem))
+			if err != nil {
+				return nil, err
+			}
+		}
+		p12, err := pkcs12.Modern.Encode(key, cert, chain, password)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return p12, nil
+	case pb.KeyBundleFormat_PKCS8:
+		der, err := pkcs8.MarshalPrivateKey(key, []byte(password), nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		b := bytes.NewBuffer(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: der}))
+		clear(der)
+		b.WriteString(crt.Pem)
+		if crt.IssuersPem != "" {
+			if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+				b.WriteString("\n")
+			}
+			b.WriteString(crt.IssuersPem)
+		}
+		return b.Bytes(), nil
+	default:
+		return nil, errors.Errorf("unsupported format: %v", format)
+	}
+}
+
+// encryptKeyBundle encrypts the bundle with age to the recipient
+func encryptKeyBundle(bundle []byte, recipient age.Recipient) ([]byte, error) {
+	var b bytes.Buffer
+	w, err := age.Encrypt(&b, recipient)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err = w.Write(bundle); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = w.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b.Bytes(), nil
+}
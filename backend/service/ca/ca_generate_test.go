@@ -0,0 +1,125 @@
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/pem"
+	"io"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestGenerateAndSignCertificate(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	for _, name := range []string{"client", "server", "denied"} {
+		issuer.AddProfile(name, &authority.CertProfile{
+			Usage:  []string{"client auth"},
+			Expiry: csr.Duration(24 * time.Hour),
+		})
+	}
+
+	db := &mockSignDB{
+		certs:    map[uint64]*model.Certificate{},
+		requests: map[string]*model.SignRequest{},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+		KeyGeneration: map[string]*config.KeyGeneration{
+			"client": {Allowed: true, Algorithm: "RSA"},
+			"server": {Allowed: true},
+			"denied": {Allowed: false},
+		},
+	}
+
+	newRequest := func(profile string) *pb.GenerateCertificateRequest {
+		return &pb.GenerateCertificateRequest{
+			Profile:  profile,
+			Subject:  &pb.X509Subject{CommonName: "generated.test"},
+			SAN:      []string{"generated.test"},
+			Label:    "l1",
+			Password: "secret",
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			req  *pb.GenerateCertificateRequest
+			code codes.Code
+		}{
+			{&pb.GenerateCertificateRequest{}, codes.InvalidArgument},
+			{newRequest("denied"), codes.FailedPrecondition},
+			{newRequest("unknown"), codes.FailedPrecondition},
+			{&pb.GenerateCertificateRequest{Profile: "client"}, codes.InvalidArgument},
+			{&pb.GenerateCertificateRequest{Profile: "client", Password: "secret", Format: 10}, codes.InvalidArgument},
+			{&pb.GenerateCertificateRequest{Profile: "client", Password: "secret", Recipient: "invalid"}, codes.InvalidArgument},
+		} {
+			_, err := s.GenerateAndSignCertificate(ctx, tc.req)
+			require.Error(t, err)
+			assert.Equal(t, tc.code, status.Code(err), err.Error())
+		}
+	})
+
+	t.Run("pkcs12", func(t *testing.T) {
+		res, err := s.GenerateAndSignCertificate(ctx, newRequest("client"))
+		require.NoError(t, err)
+		assert.Equal(t, pb.KeyBundleFormat_PKCS12, res.Format)
+		assert.Equal(t, "l1", res.Certificate.Label)
+
+		key, crt, chain, err := pkcs12.DecodeChain(res.Bundle, "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "generated.test", crt.Subject.CommonName)
+		assert.Equal(t, []string{"generated.test"}, crt.DNSNames)
+		assert.Equal(t, "RSA", crt.PublicKeyAlgorithm.String())
+		assert.True(t, key.(crypto.Signer).Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(crt.PublicKey))
+		assert.NotEmpty(t, chain)
+
+		_, _, err = pkcs12.Decode(res.Bundle, "wrong")
+		assert.Error(t, err)
+	})
+
+	t.Run("pkcs8_recipient", func(t *testing.T) {
+		id, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+
+		req := newRequest("server")
+		req.Format = pb.KeyBundleFormat_PKCS8
+		req.Recipient = id.Recipient().String()
+		res, err := s.GenerateAndSignCertificate(ctx, req)
+		require.NoError(t, err)
+
+		r, err := age.Decrypt(bytes.NewReader(res.Bundle), id)
+		require.NoError(t, err)
+		bundle, err := io.ReadAll(r)
+		require.NoError(t, err)
+
+		block, rest := pem.Decode(bundle)
+		require.NotNil(t, block)
+		assert.Equal(t, "ENCRYPTED PRIVATE KEY", block.Type)
+		key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte("secret"))
+		require.NoError(t, err)
+
+		chain, err := certutil.ParseChainFromPEM(rest)
+		require.NoError(t, err)
+		require.Len(t, chain, 2)
+		assert.Equal(t, "ECDSA", chain[0].PublicKeyAlgorithm.String())
+		assert.True(t, key.(crypto.Signer).Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(chain[0].PublicKey))
+	})
+}
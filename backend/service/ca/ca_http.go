@@ -0,0 +1,154 @@
+package ca
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/effective-security/porto/restserver"
+	"github.com/effective-security/porto/xhttp/httperror"
+	"github.com/effective-security/porto/xhttp/marshal"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// protoRequest is a constraint for a pointer to a proto request message
+type protoRequest[T any] interface {
+	*T
+	proto.Message
+}
+
+// rpcHandler returns REST handler for the CA method.
+// The request is decoded from JSON body for POST,
+// or from the path and query parameters otherwise,
+// where the parameter name is the field name of the request,
+// and the fields of nested messages are specified as `Parent.Field`.
+func rpcHandler[T any, Req protoRequest[T], Res proto.Message](rpc func(context.Context, Req) (Res, error)) restserver.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p restserver.Params) {
+		req := Req(new(T))
+		if r.Method == http.MethodPost {
+			if err := marshal.DecodeBody(w, r, req); err != nil {
+				return
+			}
+		} else if err := bindParams(req, r, p); err != nil {
+			marshal.WriteJSON(w, r, httperror.InvalidParam("%s", err.Error()).WithCause(err))
+			return
+		}
+
+		res, err := rpc(r.Context(), req)
+		if err != nil {
+			marshal.WriteJSON(w, r, err)
+			return
+		}
+		marshal.WriteJSON(w, r, res)
+	}
+}
+
+// bindParams sets the fields of the request from the path and query parameters
+func bindParams(req proto.Message, r *http.Request, p restserver.Params) error {
+	msg := req.ProtoReflect()
+	for _, param := range p {
+		if err := setField(msg, param.Key, []string{param.Value}); err != nil {
+			return err
+		}
+	}
+	for name, values := range r.URL.Query() {
+		if err := setField(msg, name, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setField(msg protoreflect.Message, name string, values []string) error {
+	path := strings.Split(name, ".")
+	for i, fn := range path {
+		fields := msg.Descriptor().Fields()
+		fd := fields.ByName(protoreflect.Name(fn))
+		if fd == nil {
+			fd = fields.ByJSONName(fn)
+		}
+		if fd == nil {
+			return errors.Errorf("unknown parameter: %s", name)
+		}
+
+		if i < len(path)-1 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+				return errors.Errorf("invalid parameter: %s", name)
+			}
+			msg = msg.Mutable(fd).Message()
+			continue
+		}
+
+		if fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			return errors.Errorf("unsupported parameter: %s", name)
+		}
+		if fd.IsList() {
+			list := msg.Mutable(fd).List()
+			for _, s := range values {
+				v, err := parseValue(fd, s)
+				if err != nil {
+					return errors.WithMessagef(err, "invalid parameter: %s", name)
+				}
+				list.Append(v)
+			}
+			return nil
+		}
+		if len(values) != 1 {
+			return errors.Errorf("invalid parameter: %s: expected single value", name)
+		}
+		v, err := parseValue(fd, values[0])
+		if err != nil {
+			return errors.WithMessagef(err, "invalid parameter: %s", name)
+		}
+		msg.Set(fd, v)
+	}
+	return nil
+}
+
+func parseValue(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(v), errors.WithStack(err)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), errors.WithStack(err)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(v), errors.WithStack(err)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), errors.WithStack(err)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(v), errors.WithStack(err)
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), errors.WithStack(err)
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(v), errors.WithStack(err)
+	case protoreflect.BytesKind:
+		v, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			v, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+		}
+		return protoreflect.ValueOfBytes(v), errors.WithStack(err)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || fd.Enum().Values().ByNumber(protoreflect.EnumNumber(v)) == nil {
+			return protoreflect.Value{}, errors.Errorf("unknown value: %s", s)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	}
+	return protoreflect.Value{}, errors.Errorf("unsupported type: %s", fd.Kind())
+}
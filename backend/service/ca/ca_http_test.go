@@ -0,0 +1,161 @@
+package ca
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/effective-security/porto/restserver"
+	"github.com/effective-security/porto/xhttp/httperror"
+	v1 "github.com/effective-security/trusty/api"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/api/pb/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRpcHandler(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		var got *pb.GetCertificateRequest
+		h := rpcHandler(func(_ context.Context, req *pb.GetCertificateRequest) (*pb.CertificateResponse, error) {
+			got = req
+			return &pb.CertificateResponse{Certificate: &pb.Certificate{ID: 1234567890123, Label: "l1"}}, nil
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, v1.PathForCACert+"?ID=123&IssuerSerial.IKID=ikid&IssuerSerial.SerialNumber=456", nil)
+		h(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, uint64(123), got.ID)
+		require.NotNil(t, got.IssuerSerial)
+		assert.Equal(t, "ikid", got.IssuerSerial.IKID)
+		assert.Equal(t, "456", got.IssuerSerial.SerialNumber)
+
+		var res map[string]map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, "1234567890123", res["Certificate"]["ID"])
+		assert.Equal(t, "l1", res["Certificate"]["Label"])
+	})
+
+	t.Run("path", func(t *testing.T) {
+		var got *pb.ListOrgCertificatesRequest
+		h := rpcHandler(func(_ context.Context, req *pb.ListOrgCertificatesRequest) (*pb.CertificatesResponse, error) {
+			got = req
+			return &pb.CertificatesResponse{}, nil
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/ca/orgs/42/certs?Limit=10&After=5", nil)
+		h(w, r, restserver.Params{{Key: "OrgID", Value: "42"}})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, uint64(42), got.OrgID)
+		assert.Equal(t, int64(10), got.Limit)
+		assert.Equal(t, uint64(5), got.After)
+	})
+
+	t.Run("invalid_query", func(t *testing.T) {
+		h := rpcHandler(func(_ context.Context, req *pb.ListIssuersRequest) (*pb.IssuersInfoResponse, error) {
+			t.Fatal("must not be called")
+			return nil, nil
+		})
+
+		for _, q := range []string{"Limit=abc", "Bundle=maybe", "Unknown=1", "Limit=1&Limit=2", "Bundle.Label=1"} {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, v1.PathForCAIssuers+"?"+q, nil)
+			h(w, r, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code, q)
+		}
+	})
+
+	t.Run("enum", func(t *testing.T) {
+		msg := &pb.RevokeCertificateRequest{}
+		require.NoError(t, setField(msg.ProtoReflect(), "Reason", []string{"KEY_COMPROMISE"}))
+		assert.Equal(t, pb.Reason_KEY_COMPROMISE, msg.Reason)
+		require.NoError(t, setField(msg.ProtoReflect(), "Reason", []string{"3"}))
+		assert.Equal(t, pb.Reason(3), msg.Reason)
+		assert.EqualError(t, setField(msg.ProtoReflect(), "Reason", []string{"NONE"}), "invalid parameter: Reason: unknown value: NONE")
+	})
+
+	t.Run("body", func(t *testing.T) {
+		var got *pb.SignCertificateRequest
+		h := rpcHandler(func(_ context.Context, req *pb.SignCertificateRequest) (*pb.CertificateResponse, error) {
+			got = req
+			return &pb.CertificateResponse{}, nil
+		})
+
+		body := `{"RequestFormat":"PEM","Request":"Y3Ny","Profile":"server","OrgID":"7","SAN":["a","b"],"Metadata":{"k":"v"}}`
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, v1.PathForCASign, strings.NewReader(body))
+		h(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, pb.EncodingFormat_PEM, got.RequestFormat)
+		assert.Equal(t, []byte("csr"), got.Request)
+		assert.Equal(t, "server", got.Profile)
+		assert.Equal(t, uint64(7), got.OrgID)
+		assert.Equal(t, []string{"a", "b"}, got.SAN)
+		assert.Equal(t, map[string]string{"k": "v"}, got.Metadata)
+
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodPost, v1.PathForCASign, strings.NewReader(`{"Unknown":1}`))
+		h(w, r, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		h := rpcHandler(func(ctx context.Context, req *pb.IssuerInfoRequest) (*pb.IssuerInfo, error) {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found: %s", req.Label)
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, v1.PathForCAIssuer+"?Label=missing", nil)
+		h(w, r, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "issuer not found: missing")
+	})
+}
+
+type routeRecorder struct {
+	restserver.Router
+	routes []string
+}
+
+func (r *routeRecorder) GET(path string, _ restserver.Handle) {
+	r.routes = append(r.routes, "GET "+path)
+}
+
+func (r *routeRecorder) POST(path string, _ restserver.Handle) {
+	r.routes = append(r.routes, "POST "+path)
+}
+
+func TestRegisterRoute_OpenAPI(t *testing.T) {
+	b, err := openapi.Specs.ReadFile("ca.openapi.yaml")
+	require.NoError(t, err)
+
+	var spec struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(b, &spec))
+
+	var expected []string
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			// {OrgID} => :OrgID
+			path = strings.NewReplacer("{", ":", "}", "").Replace(path)
+			expected = append(expected, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(expected)
+
+	r := &routeRecorder{}
+	(&Service{}).RegisterRoute(r)
+	sort.Strings(r.routes)
+
+	assert.Equal(t, expected, r.routes)
+	assert.Len(t, r.routes, len(pb.CA_ServiceDesc.Methods))
+}
@@ -0,0 +1,101 @@
+package ca
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/certutil"
+	"google.golang.org/grpc/codes"
+)
+
+// ExternalProfile is the default profile name for imported certificates
+const ExternalProfile = "external"
+
+// ImportCertificate registers the certificate issued by an external CA.
+// The imported certificate is not updated by import again,
+// the certificate with the same key or issuer and serial is rejected.
+// The imported certificates are searched as issued ones,
+// but excluded from CRL and OCSP of the issuers.
+// The expiry of the certificates is not monitored,
+// and the owners are not notified.
+func (s *Service) ImportCertificate(ctx context.Context, req *pb.ImportCertificateRequest) (*pb.CertificateResponse, error) {
+	if req == nil || req.Pem == "" {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing certificate")
+	}
+
+	crt, err := certutil.ParseFromPEM([]byte(req.Pem))
+	if err != nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "unable to parse certificate: %s", err.Error())
+	}
+
+	if req.IssuersPem != "" {
+		issuers, err := certutil.ParseChainFromPEM([]byte(req.IssuersPem))
+		if err != nil {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "unable to parse issuers: %s", err.Error())
+		}
+		if len(issuers) == 0 || crt.CheckSignatureFrom(issuers[0]) != nil {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "certificate is not signed by the provided issuer")
+		}
+	}
+
+	ikid := certutil.GetAuthorityKeyID(crt)
+	if ikid == "" && bytes.Equal(crt.RawIssuer, crt.RawSubject) {
+		// self-signed roots may omit AKID
+		ikid = certutil.GetSubjectKeyID(crt)
+	}
+	if ikid == "" || len(crt.SubjectKeyId) == 0 {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "certificate must have Subject and Authority Key Identifiers")
+	}
+
+	// certificates issued by this CA must not be imported,
+	// as external certificates are excluded from CRL and OCSP
+	if issuer, err := s.ca.GetIssuerByKeyID(ikid); err == nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "certificate is issued by %q issuer", issuer.Label())
+	}
+
+	profile := req.Profile
+	if profile == "" {
+		profile = ExternalProfile
+	}
+
+	mcert := model.NewCertificate(crt, req.OrgID, profile, req.Pem, req.IssuersPem, req.Label, nil, req.Metadata)
+	mcert.IKID = ikid
+	mcert.External = true
+
+	mcert, err = s.db.RegisterCertificate(ctx, mcert)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to import certificate",
+			"err", err.Error())
+
+		if strings.Contains(err.Error(), "already exists") ||
+			strings.Contains(err.Error(), "idx_certificates_skid") ||
+			strings.Contains(err.Error(), "idx_certificates_ikid_serial") {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.AlreadyExists, "the certificate already exists")
+		}
+		if strings.Contains(err.Error(), "issued locally") {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.FailedPrecondition, "the certificate is issued locally")
+		}
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to import certificate")
+	}
+
+	metricskey.CACertImported.IncrCounter(1, profile)
+
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "imported certificate",
+		"id", mcert.ID,
+		"subject", mcert.Subject,
+		"issuer", mcert.Issuer,
+		"label", mcert.Label,
+	)
+
+	return &pb.CertificateResponse{
+		Certificate: mcert.ToPB(),
+	}, nil
+}
@@ -0,0 +1,106 @@
+package ca_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/correlation"
+	"github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/service/ca"
+	"github.com/effective-security/x/guid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportCertificate(t *testing.T) {
+	ctx := correlation.WithID(context.Background())
+	pref := fmt.Sprintf("request %s: ", correlation.ID(ctx))
+
+	_, err := authorityClient.ImportCertificate(ctx, &pb.ImportCertificateRequest{})
+	assert.EqualError(t, err, pref+"bad_request: missing certificate")
+
+	_, err = authorityClient.ImportCertificate(ctx, &pb.ImportCertificateRequest{
+		Pem: "abcd",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad_request: unable to parse certificate")
+
+	// certificate issued by trusty must not be imported
+	signed, err := authorityClient.SignCertificate(ctx, &pb.SignCertificateRequest{
+		Profile:       "test_server",
+		Request:       generateServerCSR(),
+		RequestFormat: pb.EncodingFormat_PEM,
+	})
+	require.NoError(t, err)
+	_, err = authorityClient.ImportCertificate(ctx, &pb.ImportCertificateRequest{
+		Pem: signed.Certificate.Pem,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad_request: certificate is issued by")
+
+	crtPem := generateExternalCert(t)
+	res, err := authorityClient.ImportCertificate(ctx, &pb.ImportCertificateRequest{
+		Pem:      crtPem,
+		OrgID:    1000,
+		Label:    "external",
+		Metadata: map[string]string{"source": "test"},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.Certificate.External)
+	assert.Equal(t, ca.ExternalProfile, res.Certificate.Profile)
+	assert.Equal(t, "external", res.Certificate.Label)
+	assert.Empty(t, res.Certificate.Locations)
+
+	crt, err := authorityClient.GetCertificate(ctx, &pb.GetCertificateRequest{ID: res.Certificate.ID})
+	require.NoError(t, err)
+	assert.True(t, crt.Certificate.External)
+
+	// import again does not reassign the certificate
+	_, err = authorityClient.ImportCertificate(ctx, &pb.ImportCertificateRequest{
+		Pem:   crtPem,
+		OrgID: 1001,
+		Label: "other",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the certificate already exists")
+	crt, err = authorityClient.GetCertificate(ctx, &pb.GetCertificateRequest{ID: res.Certificate.ID})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000), crt.Certificate.OrgID)
+	assert.Equal(t, "external", crt.Certificate.Label)
+
+	revoked, err := authorityClient.RevokeCertificate(ctx, &pb.RevokeCertificateRequest{
+		ID:     res.Certificate.ID,
+		Reason: pb.Reason_KEY_COMPROMISE,
+	})
+	require.NoError(t, err)
+	assert.True(t, revoked.Revoked.Certificate.External)
+}
+
+func generateExternalCert(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "external-" + guid.MustCreate()},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte(guid.MustCreate())[:20],
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,79 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/weakkey"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/certutil"
+	"google.golang.org/grpc/codes"
+)
+
+// checkPublicKey returns error if the key is not allowed by the profile,
+// is known to be weak, or is blocked
+func (s *Service) checkPublicKey(ctx context.Context, profile string, pub crypto.PublicKey) error {
+	kp := s.cfg.KeyPolicy(profile)
+	if err := weakkey.CheckSize(pub, kp.MinRSASize, kp.MinECDSASize); err != nil {
+		return keyRejected(ctx, profile, "weak key: %s", err.Error())
+	}
+	if err := s.weakKeys.Check(pub); err != nil {
+		return keyRejected(ctx, profile, "weak key: %s", err.Error())
+	}
+
+	hash, err := weakkey.SPKIHash(pub)
+	if err != nil {
+		return keyRejected(ctx, profile, "invalid key: %s", err.Error())
+	}
+	blocked, err := s.db.GetBlockedKey(ctx, hash)
+	if err == nil {
+		return keyRejected(ctx, profile, "blocked key: %s", blocked.Source)
+	}
+	if !xdb.IsNotFoundError(err) {
+		return httperror.WrapWithCtx(ctx, err, "unable to check blocked key")
+	}
+	return nil
+}
+
+// keyRejected logs the rejected key,
+// and returns the error to the client
+func keyRejected(ctx context.Context, profile, format string, args ...any) error {
+	err := httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, format, args...)
+	logger.ContextKV(ctx, xlog.WARNING,
+		"status", "public key rejected",
+		"profile", profile,
+		"err", err.Error())
+	return err
+}
+
+// blockKey blocks the key of the compromised certificate
+func (s *Service) blockKey(ctx context.Context, crt *model.Certificate) error {
+	x509crt, err := certutil.ParseFromPEM([]byte(crt.Pem))
+	if err != nil {
+		return err
+	}
+	hash, err := weakkey.SPKIHash(x509crt.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := s.db.BlockKey(ctx, &model.BlockedKey{
+		ID:            s.db.NextID().UInt64(),
+		SPKIHash:      hash,
+		Source:        model.BlockedKeySourceKeyCompromise,
+		CertificateID: crt.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "blocked key",
+		"id", crt.ID,
+		"spki_hash", hash,
+		"blocked_by", blocked.CertificateID)
+	return nil
+}
@@ -0,0 +1,159 @@
+package ca
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/weakkey"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rocaKey is the public key vulnerable to ROCA
+const rocaKey = `-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAlze9c7qGdjDLVR/ntk+4
+ZkfMcYsAnmfTFHfe3Xv7jRQqPCXCULtr0y0jG3aRJmEenoXO9uDveqr43gFB9yvA
+dLEhu0aJqpB7lNZ+yXsvfVp/96dkSN8oWYL/dd9Z7GQOvVniHUY3Xsd7zdw2eYOy
+HSXhhA2Ttwnj3c1jEYfC0y9q1cU99aL0ogGDqolcOvlkJu+mGb+6+WyboFa1gwRu
+kYxBHZWKiHCt/eihvXsPTzTlXmTXWdGJtA1xZDnCBWuZ90b5R0agXVIESTl0cCyH
+aQM/tLZmktJIU+Eu7ALBXemPg9kh3SCnYd3/YvDGCtYSXOWthHwlP5CImRBcQaNn
+cQIDAQAB
+-----END PUBLIC KEY-----`
+
+func TestCheckPublicKey(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("server", &authority.CertProfile{
+		Usage:  []string{"server auth"},
+		Expiry: csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockRenewDB{
+		mockSignDB: &mockSignDB{
+			certs:    map[uint64]*model.Certificate{},
+			requests: map[string]*model.SignRequest{},
+		},
+		revoked: map[uint64]*model.RevokedCertificate{},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+		KeyValidation:         map[string]*config.KeyPolicy{},
+	}
+
+	sign := func(format pb.EncodingFormat, request []byte) (*pb.CertificateResponse, error) {
+		return s.SignCertificate(ctx, &pb.SignCertificateRequest{
+			Profile:       "server",
+			Request:       request,
+			RequestFormat: format,
+			SAN:           []string{"keys.test"},
+		})
+	}
+	assertRejected := func(t *testing.T, err error, msg string) {
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), msg)
+	}
+
+	t.Run("size", func(t *testing.T) {
+		s.cfg.KeyValidation["*"] = &config.KeyPolicy{MinECDSASize: 384}
+		defer delete(s.cfg.KeyValidation, "*")
+
+		_, err := sign(pb.EncodingFormat_PEM, csrForTest(t))
+		assertRejected(t, err, "weak key: ECDSA curve P-256 is less than 384")
+
+		s.cfg.KeyValidation["server"] = &config.KeyPolicy{MinECDSASize: 256}
+		defer delete(s.cfg.KeyValidation, "server")
+		_, err = sign(pb.EncodingFormat_PEM, csrForTest(t))
+		require.NoError(t, err)
+	})
+
+	t.Run("roca", func(t *testing.T) {
+		s.cfg.KeyValidation["*"] = &config.KeyPolicy{AllowSPKI: true}
+		defer delete(s.cfg.KeyValidation, "*")
+
+		_, err := sign(pb.EncodingFormat_SPKI, []byte(rocaKey))
+		assertRejected(t, err, "weak key: key vulnerable to ROCA (CVE-2017-15361)")
+	})
+
+	t.Run("debian", func(t *testing.T) {
+		s.cfg.KeyValidation["*"] = &config.KeyPolicy{AllowSPKI: true}
+		defer delete(s.cfg.KeyValidation, "*")
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		require.NoError(t, err)
+
+		_, err = sign(pb.EncodingFormat_SPKI, der)
+		require.NoError(t, err)
+
+		s.weakKeys = &weakkey.Checker{}
+		defer func() { s.weakKeys = nil }()
+		require.NoError(t, s.weakKeys.LoadDebian(strings.NewReader(weakkey.DebianFingerprint(key.N))))
+
+		_, err = sign(pb.EncodingFormat_SPKI, der)
+		assertRejected(t, err, "weak key: Debian weak key (CVE-2008-0166)")
+	})
+
+	t.Run("key_compromise", func(t *testing.T) {
+		request := csrForTest(t)
+		res, err := sign(pb.EncodingFormat_PEM, request)
+		require.NoError(t, err)
+		other, err := sign(pb.EncodingFormat_PEM, request)
+		require.NoError(t, err)
+
+		// superseded certificate does not block the key
+		_, err = s.RevokeCertificate(ctx, &pb.RevokeCertificateRequest{
+			ID:     res.Certificate.ID,
+			Reason: pb.Reason_SUPERSEDED,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, db.blocked)
+		res, err = sign(pb.EncodingFormat_PEM, request)
+		require.NoError(t, err)
+
+		_, err = s.RevokeCertificate(ctx, &pb.RevokeCertificateRequest{
+			ID:     res.Certificate.ID,
+			Reason: pb.Reason_KEY_COMPROMISE,
+		})
+		require.NoError(t, err)
+
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+		require.NoError(t, err)
+		hash, err := weakkey.SPKIHash(crt.PublicKey)
+		require.NoError(t, err)
+		blocked := db.blocked[hash]
+		require.NotNil(t, blocked)
+		assert.Equal(t, model.BlockedKeySourceKeyCompromise, blocked.Source)
+		assert.Equal(t, res.Certificate.ID, blocked.CertificateID)
+
+		_, err = sign(pb.EncodingFormat_PEM, request)
+		assertRejected(t, err, "blocked key: key_compromise")
+
+		_, err = s.RenewCertificate(ctx, &pb.RenewCertificateRequest{ID: other.Certificate.ID})
+		assertRejected(t, err, "blocked key: key_compromise")
+
+		// the key of the other certificate is already blocked
+		_, err = s.RevokeCertificate(ctx, &pb.RevokeCertificateRequest{
+			ID:     other.Certificate.ID,
+			Reason: pb.Reason_KEY_COMPROMISE,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, res.Certificate.ID, db.blocked[hash].CertificateID)
+	})
+}
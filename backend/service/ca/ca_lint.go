@@ -0,0 +1,124 @@
+package ca
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"maps"
+	"strings"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certlint"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// lintCertificate checks TBS certificate by the linting policy of the profile,
+// returns error if the issuance is blocked, or the warnings
+func (s *Service) lintCertificate(ctx context.Context, profile string, tbs *x509.Certificate) ([]*pb.LintResult, error) {
+	lp := s.cfg.LintPolicy(profile)
+	if lp == nil {
+		return nil, nil
+	}
+	policy, err := lintPolicy(lp)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "invalid lint policy of profile %q", profile)
+	}
+	linter, err := s.linterFor(lp.Sources)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to create linter")
+	}
+
+	results, err := linter.Lint(tbs)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to lint certificate")
+	}
+
+	blocked, warnings := policy.Apply(results)
+	if len(blocked) > 0 {
+		list := make([]string, len(blocked))
+		for i, r := range blocked {
+			list[i] = r.Name
+			if r.Details != "" {
+				list[i] += ": " + r.Details
+			}
+		}
+		logger.ContextKV(ctx, xlog.WARNING,
+			"status", "certificate failed lint",
+			"profile", profile,
+			"lints", list)
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.FailedPrecondition, "certificate failed lint: %s", strings.Join(list, "; "))
+	}
+
+	var res []*pb.LintResult
+	for _, r := range warnings {
+		res = append(res, &pb.LintResult{
+			Name:     r.Name,
+			Source:   r.Source,
+			Severity: r.Severity.String(),
+			Details:  r.Details,
+		})
+	}
+	return res, nil
+}
+
+// linterFor returns the linter of the sources,
+// the linters are created once per the list of sources
+func (s *Service) linterFor(sources []string) (certlint.Linter, error) {
+	if s.linter != nil {
+		return s.linter, nil
+	}
+
+	key := strings.Join(sources, ",")
+	if l, ok := s.linters.Load(key); ok {
+		return l.(certlint.Linter), nil
+	}
+	l, err := certlint.NewZLinter(sources)
+	if err != nil {
+		return nil, err
+	}
+	s.linters.Store(key, l)
+	return l, nil
+}
+
+// lintPolicy returns the policy with the defaults
+func lintPolicy(lp *config.LintPolicy) (*certlint.Policy, error) {
+	p := &certlint.Policy{
+		Block:  certlint.Error,
+		Warn:   certlint.Warn,
+		Ignore: lp.Ignore,
+	}
+	var err error
+	if lp.Block != "" {
+		if p.Block, err = certlint.ParseSeverity(lp.Block); err != nil {
+			return nil, errors.WithMessage(err, "block")
+		}
+	}
+	if lp.Warn != "" {
+		if p.Warn, err = certlint.ParseSeverity(lp.Warn); err != nil {
+			return nil, errors.WithMessage(err, "warn")
+		}
+	}
+	return p, nil
+}
+
+// withLintWarnings returns metadata with the lint warnings
+func withLintWarnings(meta map[string]string, warnings []*pb.LintResult) map[string]string {
+	if _, ok := meta[model.MetadataLint]; !ok && len(warnings) == 0 {
+		return meta
+	}
+	meta = maps.Clone(meta)
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	delete(meta, model.MetadataLint)
+	if len(warnings) > 0 {
+		js, _ := json.Marshal(warnings)
+		meta[model.MetadataLint] = string(js)
+	}
+	return meta
+}
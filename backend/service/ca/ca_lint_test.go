@@ -0,0 +1,157 @@
+package ca
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certlint"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/csr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockLinter struct {
+	results []*certlint.Result
+	err     error
+	linted  []*x509.Certificate
+}
+
+func (m *mockLinter) Lint(crt *x509.Certificate) ([]*certlint.Result, error) {
+	m.linted = append(m.linted, crt)
+	return m.results, m.err
+}
+
+func TestLintCertificate(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("server", &authority.CertProfile{
+		Usage:  []string{"server auth", "digital signature"},
+		Expiry: csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockSignDB{
+		certs:    map[uint64]*model.Certificate{},
+		requests: map[string]*model.SignRequest{},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+		Linting: map[string]*config.LintPolicy{
+			"server": {},
+		},
+	}
+
+	newRequest := func() *pb.SignCertificateRequest {
+		return &pb.SignCertificateRequest{
+			Profile:       "server",
+			Request:       csrForTest(t),
+			RequestFormat: pb.EncodingFormat_PEM,
+			SAN:           []string{"trusty.com"},
+		}
+	}
+
+	t.Run("blocked", func(t *testing.T) {
+		_, err := s.SignCertificate(ctx, newRequest())
+		require.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Contains(t, err.Error(), "certificate failed lint: ")
+		assert.Contains(t, err.Error(), "e_sub_cert_aia_missing")
+		assert.Empty(t, db.certs)
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		s.cfg.Linting["server"] = &config.LintPolicy{
+			Block:  "fatal",
+			Warn:   "error",
+			Ignore: []string{"e_dnsname_not_valid_tld"},
+		}
+		defer func() { s.cfg.Linting["server"] = &config.LintPolicy{} }()
+
+		res, err := s.SignCertificate(ctx, newRequest())
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Warnings)
+		names := map[string]bool{}
+		for _, w := range res.Warnings {
+			assert.Equal(t, "error", w.Severity, w.Name)
+			names[w.Name] = true
+		}
+		assert.True(t, names["e_sub_cert_aia_missing"])
+		assert.Equal(t, res.Warnings, db.certs[res.Certificate.ID].LintWarnings())
+		assert.NotEmpty(t, res.Certificate.Metadata[model.MetadataLint])
+	})
+
+	t.Run("invalid_policy", func(t *testing.T) {
+		s.cfg.Linting["server"] = &config.LintPolicy{Block: "invalid"}
+		defer func() { s.cfg.Linting["server"] = &config.LintPolicy{} }()
+
+		_, err := s.SignCertificate(ctx, newRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid lint policy")
+	})
+
+	t.Run("linter", func(t *testing.T) {
+		linter := &mockLinter{}
+		s.linter = linter
+		defer func() { s.linter = nil }()
+
+		res, err := s.SignCertificate(ctx, newRequest())
+		require.NoError(t, err)
+		assert.Empty(t, res.Warnings)
+		assert.Empty(t, res.Certificate.Metadata[model.MetadataLint])
+
+		// the issued certificate has exactly the linted TBS
+		require.Len(t, linter.linted, 1)
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+		require.NoError(t, err)
+		tbs := linter.linted[0]
+		assert.Equal(t, tbs.RawTBSCertificate, crt.RawTBSCertificate)
+		assert.NotEqual(t, tbs.Signature, crt.Signature)
+		require.NoError(t, crt.CheckSignatureFrom(issuer.Bundle().Cert))
+
+		// the ephemeral key is created once
+		k1, err := s.tbsKey(issuer.Bundle().Cert.PublicKey)
+		require.NoError(t, err)
+		k2, err := s.tbsKey(issuer.Bundle().Cert.PublicKey)
+		require.NoError(t, err)
+		assert.Same(t, k1, k2)
+
+		linter.err = errors.New("lint failed")
+		_, err = s.SignCertificate(ctx, newRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to lint certificate")
+	})
+
+	t.Run("renew", func(t *testing.T) {
+		linter := &mockLinter{}
+		s.linter = linter
+		res, err := s.SignCertificate(ctx, newRequest())
+		require.NoError(t, err)
+
+		linter.results = []*certlint.Result{{Name: "w_test", Severity: certlint.Warn}}
+		renewed, err := s.RenewCertificate(ctx, &pb.RenewCertificateRequest{ID: res.Certificate.ID})
+		require.NoError(t, err)
+		require.Len(t, renewed.Warnings, 1)
+		assert.Equal(t, "w_test", renewed.Warnings[0].Name)
+
+		linter.results = []*certlint.Result{{Name: "e_test", Severity: certlint.Error}}
+		_, err = s.RenewCertificate(ctx, &pb.RenewCertificateRequest{ID: res.Certificate.ID})
+		require.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		// the profile without policy is not linted
+		delete(s.cfg.Linting, "server")
+		_, err = s.RenewCertificate(ctx, &pb.RenewCertificateRequest{ID: res.Certificate.ID})
+		require.NoError(t, err)
+	})
+}
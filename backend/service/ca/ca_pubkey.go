@@ -0,0 +1,290 @@
+package ca
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/pkg/errors"
+)
+
+var (
+	// signatureAlgorithms specifies the algorithms accepted for proof of possession
+	signatureAlgorithms = map[string]x509.SignatureAlgorithm{
+		"1.2.840.113549.1.1.11": x509.SHA256WithRSA,
+		"1.2.840.113549.1.1.12": x509.SHA384WithRSA,
+		"1.2.840.113549.1.1.13": x509.SHA512WithRSA,
+		"1.2.840.10045.4.3.2":   x509.ECDSAWithSHA256,
+		"1.2.840.10045.4.3.3":   x509.ECDSAWithSHA384,
+		"1.2.840.10045.4.3.4":   x509.ECDSAWithSHA512,
+		"1.3.101.112":           x509.PureEd25519,
+	}
+)
+
+// publicKeyRequest is the request without PKCS#10
+type publicKeyRequest struct {
+	PublicKey crypto.PublicKey
+	// Subject is provided by CRMF template
+	Subject pkix.Name
+}
+
+// parsePublicKeyRequest returns the public key from SPKI, SPKAC or CRMF request,
+// the proof of possession is verified for SPKAC and CRMF,
+// SPKI must be allowed by the key policy of the profile
+func parsePublicKeyRequest(format pb.EncodingFormat, request []byte) (*publicKeyRequest, error) {
+	switch format {
+	case pb.EncodingFormat_SPKI:
+		pub, err := x509.ParsePKIXPublicKey(pemOrDER(request))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to parse SPKI")
+		}
+		return &publicKeyRequest{PublicKey: pub}, nil
+	case pb.EncodingFormat_SPKAC:
+		return parseSPKAC(request)
+	case pb.EncodingFormat_CRMF:
+		return parseCRMF(pemOrDER(request))
+	default:
+		return nil, errors.Errorf("unsupported request_format: %v", format)
+	}
+}
+
+// pemOrDER returns DER bytes of the PEM block,
+// or the request if it's not PEM encoded
+func pemOrDER(request []byte) []byte {
+	block, _ := pem.Decode(request)
+	if block != nil {
+		return block.Bytes
+	}
+	return request
+}
+
+// signedPublicKeyAndChallenge is SPKAC:
+//
+//	SignedPublicKeyAndChallenge ::= SEQUENCE {
+//		publicKeyAndChallenge PublicKeyAndChallenge,
+//		signatureAlgorithm AlgorithmIdentifier,
+//		signature BIT STRING
+//	}
+type signedPublicKeyAndChallenge struct {
+	PublicKeyAndChallenge asn1.RawValue
+	SignatureAlgorithm    pkix.AlgorithmIdentifier
+	Signature             asn1.BitString
+}
+
+// publicKeyAndChallenge is signed in SPKAC:
+//
+//	PublicKeyAndChallenge ::= SEQUENCE {
+//		spki SubjectPublicKeyInfo,
+//		challenge IA5STRING
+//	}
+type publicKeyAndChallenge struct {
+	SPKI      asn1.RawValue
+	Challenge string `asn1:"ia5"`
+}
+
+// parseSPKAC parses base64 encoded SPKAC,
+// optionally prefixed with `SPKAC=` as produced by openssl.
+// The signature provides proof of possession only,
+// the challenge is not issued by the CA and is not verified,
+// so SPKAC does not protect from replay of the request.
+func parseSPKAC(request []byte) (*publicKeyRequest, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(string(request)), "SPKAC=")
+	s = strings.Join(strings.Fields(s), "")
+	der, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode SPKAC")
+	}
+
+	var spkac signedPublicKeyAndChallenge
+	if err = unmarshalDER(der, &spkac); err != nil {
+		return nil, errors.WithMessage(err, "unable to parse SPKAC")
+	}
+	var pkac publicKeyAndChallenge
+	if err = unmarshalDER(spkac.PublicKeyAndChallenge.FullBytes, &pkac); err != nil {
+		return nil, errors.WithMessage(err, "unable to parse SPKAC")
+	}
+	pub, err := x509.ParsePKIXPublicKey(pkac.SPKI.FullBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse SPKAC public key")
+	}
+	err = checkProofOfPossession(pub, spkac.SignatureAlgorithm, spkac.PublicKeyAndChallenge.FullBytes, spkac.Signature.RightAlign())
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid SPKAC signature")
+	}
+	return &publicKeyRequest{PublicKey: pub}, nil
+}
+
+// CRMF tags from RFC 4211:
+//
+//	CertReqMsg ::= SEQUENCE {
+//		certReq CertRequest,
+//		popo ProofOfPossession OPTIONAL,
+//		regInfo SEQUENCE SIZE(1..MAX) OF AttributeTypeAndValue OPTIONAL
+//	}
+//
+//	CertRequest ::= SEQUENCE {
+//		certReqId INTEGER,
+//		certTemplate CertTemplate,
+//		controls Controls OPTIONAL
+//	}
+//
+//	ProofOfPossession ::= CHOICE {
+//		raVerified [0] NULL,
+//		signature [1] POPOSigningKey,
+//		keyEncipherment [2] POPOPrivKey,
+//		keyAgreement [3] POPOPrivKey
+//	}
+//
+//	POPOSigningKey ::= SEQUENCE {
+//		poposkInput [0] POPOSigningKeyInput OPTIONAL,
+//		algorithmIdentifier AlgorithmIdentifier,
+//		signature BIT STRING
+//	}
+const (
+	crmfTemplateSubject   = 5
+	crmfTemplatePublicKey = 6
+	crmfPopoSignature     = 1
+	crmfPoposkInput       = 0
+)
+
+// parseCRMF parses DER encoded CertReqMsg, or CertReqMessages with a single message.
+// Only the signature proof of possession over certReq is accepted.
+func parseCRMF(der []byte) (*publicKeyRequest, error) {
+	msg, err := asn1Elements(der)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to parse CRMF")
+	}
+	if len(msg) > 0 && msg[0].Tag == asn1.TagSequence {
+		// CertReqMessages
+		inner, err := asn1Elements(msg[0].FullBytes)
+		if err == nil && len(inner) > 0 && inner[0].Tag == asn1.TagSequence {
+			if len(msg) != 1 {
+				return nil, errors.New("CRMF must have a single message")
+			}
+			msg = inner
+		}
+	}
+	if len(msg) < 2 {
+		return nil, errors.New("CRMF must have proof of possession")
+	}
+
+	certReq := msg[0]
+	certReqFields, err := asn1Elements(certReq.FullBytes)
+	if err != nil || len(certReqFields) < 2 {
+		return nil, errors.New("unable to parse CRMF certReq")
+	}
+	template, err := asn1Elements(certReqFields[1].FullBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to parse CRMF certTemplate")
+	}
+
+	res := &publicKeyRequest{}
+	for _, f := range template {
+		if f.Class != asn1.ClassContextSpecific {
+			continue
+		}
+		switch f.Tag {
+		case crmfTemplateSubject:
+			// Name is CHOICE, so the tag is explicit
+			var rdn pkix.RDNSequence
+			if err = unmarshalDER(f.Bytes, &rdn); err != nil {
+				return nil, errors.WithMessage(err, "unable to parse CRMF subject")
+			}
+			res.Subject.FillFromRDNSequence(&rdn)
+		case crmfTemplatePublicKey:
+			// the tag is implicit, restore SEQUENCE of SubjectPublicKeyInfo
+			spki, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSequence, IsCompound: true, Bytes: f.Bytes})
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			res.PublicKey, err = x509.ParsePKIXPublicKey(spki)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to parse CRMF public key")
+			}
+		}
+	}
+	if res.PublicKey == nil {
+		return nil, errors.New("CRMF template must have public key")
+	}
+
+	popo := msg[1]
+	if popo.Class != asn1.ClassContextSpecific || popo.Tag != crmfPopoSignature {
+		return nil, errors.New("CRMF must have signature proof of possession")
+	}
+	poposk, err := asn1Elements(popo.FullBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to parse CRMF proof of possession")
+	}
+	if len(poposk) > 0 && poposk[0].Class == asn1.ClassContextSpecific && poposk[0].Tag == crmfPoposkInput {
+		return nil, errors.New("CRMF poposkInput is not supported")
+	}
+	if len(poposk) != 2 {
+		return nil, errors.New("unable to parse CRMF proof of possession")
+	}
+	var algo pkix.AlgorithmIdentifier
+	var sig asn1.BitString
+	if err = unmarshalDER(poposk[0].FullBytes, &algo); err != nil {
+		return nil, errors.WithMessage(err, "unable to parse CRMF signature algorithm")
+	}
+	if err = unmarshalDER(poposk[1].FullBytes, &sig); err != nil {
+		return nil, errors.WithMessage(err, "unable to parse CRMF signature")
+	}
+
+	if err = checkProofOfPossession(res.PublicKey, algo, certReq.FullBytes, sig.RightAlign()); err != nil {
+		return nil, errors.WithMessage(err, "invalid CRMF proof of possession")
+	}
+	return res, nil
+}
+
+// checkProofOfPossession verifies the signature of the data by the key
+func checkProofOfPossession(pub crypto.PublicKey, algo pkix.AlgorithmIdentifier, signed, signature []byte) error {
+	sigAlgo, ok := signatureAlgorithms[algo.Algorithm.String()]
+	if !ok {
+		return errors.Errorf("unsupported signature algorithm: %s", algo.Algorithm.String())
+	}
+	crt := &x509.Certificate{PublicKey: pub}
+	if err := crt.CheckSignature(sigAlgo, signed, signature); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// asn1Elements returns the elements of the constructed value
+func asn1Elements(der []byte) ([]asn1.RawValue, error) {
+	var seq asn1.RawValue
+	if err := unmarshalDER(der, &seq); err != nil {
+		return nil, err
+	}
+	if !seq.IsCompound {
+		return nil, errors.New("expected constructed value")
+	}
+
+	var list []asn1.RawValue
+	rest := seq.Bytes
+	for len(rest) > 0 {
+		var el asn1.RawValue
+		var err error
+		rest, err = asn1.Unmarshal(rest, &el)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, el)
+	}
+	return list, nil
+}
+
+func unmarshalDER(der []byte, val any) error {
+	rest, err := asn1.Unmarshal(der, val)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(rest) > 0 {
+		return errors.New("trailing data")
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+func TestSignCertificatePublicKey(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("device", &authority.CertProfile{
+		Usage:  []string{"client auth"},
+		Expiry: csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockSignDB{
+		certs:    map[uint64]*model.Certificate{},
+		requests: map[string]*model.SignRequest{},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	sign := func(format pb.EncodingFormat, request []byte) (*x509.Certificate, error) {
+		res, err := s.SignCertificate(ctx, &pb.SignCertificateRequest{
+			Profile:       "device",
+			RequestFormat: format,
+			Request:       request,
+			Subject:       &pb.X509Subject{CommonName: "device.test"},
+			SAN:           []string{"device.test"},
+			Label:         "device",
+		})
+		if err != nil {
+			return nil, err
+		}
+		crt, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+		require.NoError(t, err)
+		require.NoError(t, crt.CheckSignatureFrom(issuer.Bundle().Cert))
+		return crt, nil
+	}
+
+	assertKey := func(t *testing.T, crt *x509.Certificate, key crypto.Signer) {
+		assert.True(t, key.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(crt.PublicKey))
+		assert.Equal(t, issuer.Bundle().Cert.SubjectKeyId, crt.AuthorityKeyId)
+		assert.Equal(t, testSubjectKeyID(t, key.Public()), crt.SubjectKeyId)
+		assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, crt.ExtKeyUsage)
+	}
+
+	t.Run("spki", func(t *testing.T) {
+		der, err := x509.MarshalPKIXPublicKey(ecKey.Public())
+		require.NoError(t, err)
+
+		// SPKI is not allowed by default
+		_, err = sign(pb.EncodingFormat_SPKI, der)
+		require.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Contains(t, err.Error(), "SPKI request is not allowed for profile: device")
+
+		s.cfg.KeyValidation = map[string]*config.KeyPolicy{
+			"device": {AllowSPKI: true},
+		}
+		defer func() { s.cfg.KeyValidation = nil }()
+
+		crt, err := sign(pb.EncodingFormat_SPKI, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		require.NoError(t, err)
+		assertKey(t, crt, ecKey)
+		assert.Equal(t, "device.test", crt.Subject.CommonName)
+		assert.Equal(t, []string{"device.test"}, crt.DNSNames)
+
+		crt, err = sign(pb.EncodingFormat_SPKI, der)
+		require.NoError(t, err)
+		assertKey(t, crt, ecKey)
+
+		_, err = sign(pb.EncodingFormat_SPKI, []byte("invalid"))
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("spkac", func(t *testing.T) {
+		spkac := spkacForTest(t, rsaKey, false)
+		crt, err := sign(pb.EncodingFormat_SPKAC, []byte("SPKAC="+spkac))
+		require.NoError(t, err)
+		assertKey(t, crt, rsaKey)
+		assert.Equal(t, "device.test", crt.Subject.CommonName)
+
+		_, err = sign(pb.EncodingFormat_SPKAC, []byte(spkacForTest(t, rsaKey, true)))
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "invalid SPKAC signature")
+	})
+
+	t.Run("crmf", func(t *testing.T) {
+		crt, err := s.SignCertificate(ctx, &pb.SignCertificateRequest{
+			Profile:       "device",
+			RequestFormat: pb.EncodingFormat_CRMF,
+			Request:       crmfForTest(t, ecKey, "crmf.test", crmfPopoValid),
+			SAN:           []string{"crmf.test"},
+		})
+		require.NoError(t, err)
+		c, err := certutil.ParseFromPEM([]byte(crt.Certificate.Pem))
+		require.NoError(t, err)
+		assertKey(t, c, ecKey)
+		assert.Equal(t, "crmf.test", c.Subject.CommonName)
+
+		for name, popo := range map[string]crmfPopo{
+			"invalid":     crmfPopoInvalid,
+			"missing":     crmfPopoMissing,
+			"ra_verified": crmfPopoRAVerified,
+		} {
+			_, err = sign(pb.EncodingFormat_CRMF, crmfForTest(t, ecKey, "crmf.test", popo))
+			require.Error(t, err, name)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), name)
+		}
+	})
+}
+
+func testSubjectKeyID(t *testing.T, pub crypto.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(der, &info)
+	require.NoError(t, err)
+	ski := sha1.Sum(info.PublicKey.Bytes)
+	return ski[:]
+}
+
+func spkacForTest(t *testing.T, key *rsa.PrivateKey, tamper bool) string {
+	spki, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	pkac, err := asn1.Marshal(publicKeyAndChallenge{
+		SPKI:      asn1.RawValue{FullBytes: spki},
+		Challenge: "challenge",
+	})
+	require.NoError(t, err)
+
+	h := sha256.Sum256(pkac)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+	require.NoError(t, err)
+	if tamper {
+		sig[0] ^= 0xff
+	}
+
+	der, err := asn1.Marshal(signedPublicKeyAndChallenge{
+		PublicKeyAndChallenge: asn1.RawValue{FullBytes: pkac},
+		SignatureAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256WithRSA, Parameters: asn1.NullRawValue},
+		Signature:             asn1.BitString{Bytes: sig, BitLength: len(sig) * 8},
+	})
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+type crmfPopo int
+
+const (
+	crmfPopoValid crmfPopo = iota
+	crmfPopoInvalid
+	crmfPopoMissing
+	crmfPopoRAVerified
+)
+
+// crmfForTest returns PEM encoded CertReqMessages
+func crmfForTest(t *testing.T, key *ecdsa.PrivateKey, cn string, popo crmfPopo) []byte {
+	marshal := func(v any) []byte {
+		b, err := asn1.Marshal(v)
+		require.NoError(t, err)
+		return b
+	}
+	// retag returns the value with IMPLICIT context specific tag
+	retag := func(der []byte, tag int) asn1.RawValue {
+		var v asn1.RawValue
+		_, err := asn1.Unmarshal(der, &v)
+		require.NoError(t, err)
+		return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: tag, IsCompound: true, Bytes: v.Bytes}
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	subject := marshal(pkix.Name{CommonName: cn}.ToRDNSequence())
+
+	template := marshal(struct {
+		Subject   asn1.RawValue
+		PublicKey asn1.RawValue
+	}{
+		Subject:   asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 5, IsCompound: true, Bytes: subject},
+		PublicKey: retag(spki, 6),
+	})
+	certReq := marshal(struct {
+		CertReqID    int
+		CertTemplate asn1.RawValue
+	}{
+		CertReqID:    1,
+		CertTemplate: asn1.RawValue{FullBytes: template},
+	})
+
+	msg := []asn1.RawValue{{FullBytes: certReq}}
+	switch popo {
+	case crmfPopoValid, crmfPopoInvalid:
+		h := sha256.Sum256(certReq)
+		sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+		require.NoError(t, err)
+		if popo == crmfPopoInvalid {
+			sig[len(sig)-1] ^= 0xff
+		}
+		poposk := marshal(struct {
+			Algorithm pkix.AlgorithmIdentifier
+			Signature asn1.BitString
+		}{
+			Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256},
+			Signature: asn1.BitString{Bytes: sig, BitLength: len(sig) * 8},
+		})
+		msg = append(msg, retag(poposk, 1))
+	case crmfPopoRAVerified:
+		msg = append(msg, asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0})
+	}
+
+	der := marshal([]asn1.RawValue{{FullBytes: marshal(msg)}})
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST MESSAGE", Bytes: der})
+}
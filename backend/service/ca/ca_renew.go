@@ -0,0 +1,274 @@
+package ca
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// RenewCertificate re-issues the certificate with the same key,
+// subject, SANs, profile, org, label and metadata, and a new validity period.
+// The extensions are issued by the current profile.
+func (s *Service) RenewCertificate(ctx context.Context, req *pb.RenewCertificateRequest) (*pb.CertificateResponse, error) {
+	grace, err := gracePeriod(req.RevokePredecessor, req.GracePeriod)
+	if err != nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, err.Error())
+	}
+
+	crt, err := s.renewable(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := certutil.ParseFromPEM([]byte(crt.Pem))
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to parse certificate")
+	}
+
+	// the key of the issued certificate does not require proof of possession
+	sreq := s.renewRequest(crt, old, req.NotBefore, req.NotAfter)
+	mcert, err := s.issueRequest(ctx, sreq, s.resolveIssuer, "", &publicKeyRequest{PublicKey: old.PublicKey})
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.registerIssued(ctx, mcert)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RevokePredecessor {
+		s.supersede(ctx, crt, grace)
+	}
+	return res, nil
+}
+
+// RekeyCertificate re-issues the certificate with a new key
+// from the request, and the subject, SANs, profile, org, label and metadata
+// of the existing certificate
+func (s *Service) RekeyCertificate(ctx context.Context, req *pb.RekeyCertificateRequest) (*pb.CertificateResponse, error) {
+	grace, err := gracePeriod(req.RevokePredecessor, req.GracePeriod)
+	if err != nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, err.Error())
+	}
+	if len(req.Request) == 0 {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing request")
+	}
+
+	crt, err := s.renewable(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := certutil.ParseFromPEM([]byte(crt.Pem))
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to parse certificate")
+	}
+
+	csrv, err := parseCertificateRequest(req.RequestFormat, req.Request)
+	if err != nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid request: %s", err.Error())
+	}
+	if bytes.Equal(csrv.RawSubjectPublicKeyInfo, old.RawSubjectPublicKeyInfo) {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "the request must have a new key, use RenewCertificate to renew with the same key")
+	}
+
+	sreq := s.renewRequest(crt, old, req.NotBefore, req.NotAfter)
+	sreq.RequestFormat = req.RequestFormat
+	sreq.Request = req.Request
+
+	res, err := s.signCertificate(ctx, sreq)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RevokePredecessor {
+		s.supersede(ctx, crt, grace)
+	}
+	return res, nil
+}
+
+// renewRequest returns the request to re-issue the certificate
+// with the subject, SANs, profile, org, label and metadata of the existing certificate
+func (s *Service) renewRequest(crt *model.Certificate, old *x509.Certificate, notBefore, notAfter string) *pb.SignCertificateRequest {
+	sreq := &pb.SignCertificateRequest{
+		Profile:   crt.Profile,
+		SAN:       certificateSAN(old),
+		Subject:   subjectToPB(old.Subject),
+		OrgID:     crt.OrgID,
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+		Label:     crt.Label,
+		Metadata:  renewedFrom(crt.Metadata, crt.ID),
+	}
+	// prefer the same issuer, if it still serves the profile,
+	// otherwise the current issuer of the profile is used
+	if ca, err := s.ca.GetIssuerByKeyID(crt.IKID); err == nil && ca.Profile(crt.Profile) != nil {
+		sreq.IssuerLabel = ca.Label()
+	}
+	return sreq
+}
+
+// renewable returns the certificate to renew or rekey
+func (s *Service) renewable(ctx context.Context, id uint64) (*model.Certificate, error) {
+	if id == 0 {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing certificate ID")
+	}
+
+	crt, err := s.db.GetCertificate(ctx, id)
+	if err != nil {
+		if xdb.IsNotFoundError(err) {
+			// the revoked certificates are removed from the certificates
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "certificate not found: %d", id)
+		}
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to find certificate")
+	}
+	if crt.External {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "external certificate can not be renewed: %d", id)
+	}
+	return crt, nil
+}
+
+// supersede revokes the predecessor with superseded reason,
+// immediately or after the grace period by the revocation worker.
+// The error is logged, and does not fail the caller.
+func (s *Service) supersede(ctx context.Context, crt *model.Certificate, grace time.Duration) {
+	if grace == 0 {
+		_, err := s.revokeCertificate(ctx, crt, pb.Reason_SUPERSEDED)
+		if err == nil {
+			logger.ContextKV(ctx, xlog.NOTICE,
+				"status", "revoked superseded certificate",
+				"id", crt.ID)
+			return
+		}
+		// the revocation is retried by the revocation worker
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to revoke superseded certificate",
+			"id", crt.ID,
+			"err", err.Error())
+	}
+
+	r, err := s.db.RegisterPendingRevocation(ctx, &model.PendingRevocation{
+		ID:            s.db.NextID().UInt64(),
+		CertificateID: crt.ID,
+		Reason:        int(pb.Reason_SUPERSEDED),
+		RevokeAt:      time.Now().Add(grace),
+	})
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to schedule revocation of superseded certificate",
+			"id", crt.ID,
+			"err", err.Error())
+		return
+	}
+
+	logger.ContextKV(ctx, xlog.INFO,
+		"status", "scheduled revocation of superseded certificate",
+		"id", crt.ID,
+		"revoke_at", r.RevokeAt)
+}
+
+// gracePeriod returns the period to revoke the predecessor after
+func gracePeriod(revoke bool, period string) (time.Duration, error) {
+	if period == "" {
+		return 0, nil
+	}
+	if !revoke {
+		return 0, errors.New("grace period requires to revoke the predecessor")
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d < 0 {
+		return 0, errors.Errorf("invalid grace period: %s", period)
+	}
+	return d, nil
+}
+
+// renewedFrom returns the metadata of the certificate renewed from id
+func renewedFrom(meta map[string]string, id uint64) map[string]string {
+	meta = maps.Clone(meta)
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	meta[model.MetadataRenewedFrom] = strconv.FormatUint(id, 10)
+	return meta
+}
+
+// parseCertificateRequest returns the certificate request,
+// and verifies its signature
+func parseCertificateRequest(format pb.EncodingFormat, req []byte) (*x509.CertificateRequest, error) {
+	der := req
+	switch format {
+	case pb.EncodingFormat_PEM:
+		block, _ := pem.Decode(req)
+		if block == nil {
+			return nil, errors.New("unable to parse PEM")
+		}
+		der = block.Bytes
+	case pb.EncodingFormat_DER:
+	default:
+		return nil, errors.Errorf("unsupported request_format: %v", format)
+	}
+
+	csrv, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = csrv.CheckSignature(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return csrv, nil
+}
+
+// certificateSAN returns Subject Alternative Names of the certificate
+func certificateSAN(crt *x509.Certificate) []string {
+	san := []string{}
+	san = append(san, crt.DNSNames...)
+	for _, ip := range crt.IPAddresses {
+		san = append(san, ip.String())
+	}
+	san = append(san, crt.EmailAddresses...)
+	for _, u := range crt.URIs {
+		san = append(san, u.String())
+	}
+	return san
+}
+
+// subjectToPB returns the subject of the certificate
+func subjectToPB(name pkix.Name) *pb.X509Subject {
+	subj := &pb.X509Subject{
+		CommonName:   name.CommonName,
+		SerialNumber: name.SerialNumber,
+	}
+
+	count := max(len(name.Country), len(name.Province), len(name.Locality),
+		len(name.Organization), len(name.OrganizationalUnit))
+	at := func(list []string, i int) string {
+		if i < len(list) {
+			return list[i]
+		}
+		return ""
+	}
+	for i := 0; i < count; i++ {
+		subj.Names = append(subj.Names, &pb.X509Name{
+			Country:            at(name.Country, i),
+			State:              at(name.Province, i),
+			Locality:           at(name.Locality, i),
+			Organisation:       at(name.Organization, i),
+			OrganisationalUnit: at(name.OrganizationalUnit, i),
+		})
+	}
+	return subj
+}
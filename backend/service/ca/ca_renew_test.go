@@ -0,0 +1,284 @@
+package ca
+
+import (
+	"context"
+	"crypto/x509"
+	"strconv"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockRenewDB struct {
+	*mockSignDB
+
+	revoked map[uint64]*model.RevokedCertificate
+	pending map[uint64]*model.PendingRevocation
+}
+
+func (m *mockRenewDB) RevokeCertificate(_ context.Context, crt *model.Certificate, at time.Time, reason int) (*model.RevokedCertificate, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.certs, crt.ID)
+	r := &model.RevokedCertificate{
+		Certificate: *crt,
+		RevokedAt:   xdb.Time(at),
+		Reason:      reason,
+	}
+	m.revoked[crt.ID] = r
+	return r, nil
+}
+
+func (m *mockRenewDB) RegisterPendingRevocation(_ context.Context, r *model.PendingRevocation) (*model.PendingRevocation, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := xdb.Validate(r); err != nil {
+		return nil, err
+	}
+	m.pending[r.CertificateID] = r
+	return r, nil
+}
+
+func (m *mockRenewDB) ListPendingRevocations(_ context.Context, before time.Time, limit int) (model.PendingRevocations, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var list model.PendingRevocations
+	for _, r := range m.pending {
+		if r.RevokeAt.Before(before) && len(list) < limit {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockRenewDB) RemovePendingRevocation(_ context.Context, id uint64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for k, r := range m.pending {
+		if r.ID == id {
+			delete(m.pending, k)
+		}
+	}
+	return nil
+}
+
+func TestRenewCertificate(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("server", &authority.CertProfile{
+		Usage:  []string{"server auth"},
+		Expiry: csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockRenewDB{
+		mockSignDB: &mockSignDB{
+			certs:    map[uint64]*model.Certificate{},
+			requests: map[string]*model.SignRequest{},
+		},
+		revoked: map[uint64]*model.RevokedCertificate{},
+		pending: map[uint64]*model.PendingRevocation{},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+	}
+
+	sign := func(t *testing.T) *pb.Certificate {
+		res, err := s.SignCertificate(ctx, &pb.SignCertificateRequest{
+			Profile:       "server",
+			Request:       csrForTest(t),
+			RequestFormat: pb.EncodingFormat_PEM,
+			SAN:           []string{"renew.test", "127.0.0.1"},
+			Subject: &pb.X509Subject{
+				CommonName: "renew.test",
+				Names: []*pb.X509Name{
+					{Organisation: "org1", OrganisationalUnit: "unit1"},
+				},
+			},
+			OrgID:    7,
+			Label:    "l1",
+			Metadata: map[string]string{"a": "1"},
+		})
+		require.NoError(t, err)
+		return res.Certificate
+	}
+
+	assertRenewed := func(t *testing.T, prev, crt *pb.Certificate) {
+		assert.NotEqual(t, prev.ID, crt.ID)
+		assert.NotEqual(t, prev.SerialNumber, crt.SerialNumber)
+		assert.Equal(t, prev.Subject, crt.Subject)
+		assert.Equal(t, prev.IKID, crt.IKID)
+		assert.Equal(t, prev.Profile, crt.Profile)
+		assert.Equal(t, prev.OrgID, crt.OrgID)
+		assert.Equal(t, prev.Label, crt.Label)
+		assert.Equal(t, "1", crt.Metadata["a"])
+		assert.Equal(t, strconv.FormatUint(prev.ID, 10), crt.Metadata[model.MetadataRenewedFrom])
+
+		pc, err := certutil.ParseFromPEM([]byte(prev.Pem))
+		require.NoError(t, err)
+		c, err := certutil.ParseFromPEM([]byte(crt.Pem))
+		require.NoError(t, err)
+		assert.Equal(t, pc.DNSNames, c.DNSNames)
+		assert.Equal(t, pc.IPAddresses, c.IPAddresses)
+		assert.Equal(t, pc.ExtKeyUsage, c.ExtKeyUsage)
+		require.NoError(t, c.CheckSignatureFrom(issuer.Bundle().Cert))
+	}
+
+	t.Run("renew", func(t *testing.T) {
+		prev := sign(t)
+		res, err := s.RenewCertificate(ctx, &pb.RenewCertificateRequest{
+			ID:       prev.ID,
+			NotAfter: xdb.Time(time.Now().Add(2 * time.Hour)).String(),
+		})
+		require.NoError(t, err)
+		crt := res.Certificate
+		assertRenewed(t, prev, crt)
+		assert.Equal(t, prev.SKID, crt.SKID)
+		assert.True(t, xdb.ParseTime(crt.NotAfter).UTC().Before(time.Now().Add(3*time.Hour)))
+		// not revoked
+		assert.NotNil(t, db.certs[prev.ID])
+	})
+
+	t.Run("renew_profile", func(t *testing.T) {
+		prev := sign(t)
+
+		// the extensions are issued by the current profile
+		profile := issuer.Profile("server")
+		issuer.AddProfile("server", &authority.CertProfile{
+			Usage:  []string{"server auth", "client auth"},
+			Expiry: csr.Duration(24 * time.Hour),
+		})
+		defer issuer.AddProfile("server", profile)
+
+		res, err := s.RenewCertificate(ctx, &pb.RenewCertificateRequest{ID: prev.ID})
+		require.NoError(t, err)
+		assert.Equal(t, prev.SKID, res.Certificate.SKID)
+		assert.Equal(t, prev.Subject, res.Certificate.Subject)
+
+		c, err := certutil.ParseFromPEM([]byte(res.Certificate.Pem))
+		require.NoError(t, err)
+		assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, c.ExtKeyUsage)
+		assert.Equal(t, []string{"renew.test"}, c.DNSNames)
+	})
+
+	t.Run("renew_revoke", func(t *testing.T) {
+		prev := sign(t)
+		res, err := s.RenewCertificate(ctx, &pb.RenewCertificateRequest{
+			ID:                prev.ID,
+			RevokePredecessor: true,
+		})
+		require.NoError(t, err)
+		assertRenewed(t, prev, res.Certificate)
+		require.NotNil(t, db.revoked[prev.ID])
+		assert.Equal(t, int(pb.Reason_SUPERSEDED), db.revoked[prev.ID].Reason)
+
+		_, err = s.RenewCertificate(ctx, &pb.RenewCertificateRequest{ID: prev.ID})
+		require.Error(t, err)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("grace_period", func(t *testing.T) {
+		prev := sign(t)
+		_, err := s.RenewCertificate(ctx, &pb.RenewCertificateRequest{
+			ID:                prev.ID,
+			RevokePredecessor: true,
+			GracePeriod:       "24h",
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, db.certs[prev.ID])
+
+		r := db.pending[prev.ID]
+		require.NotNil(t, r)
+		assert.Equal(t, int(pb.Reason_SUPERSEDED), r.Reason)
+		assert.True(t, r.RevokeAt.After(time.Now().Add(23*time.Hour)))
+
+		// not due yet
+		assert.Equal(t, 0, s.processPendingRevocations(ctx))
+		assert.NotNil(t, db.certs[prev.ID])
+
+		r.RevokeAt = time.Now().Add(-time.Second)
+		assert.Equal(t, 1, s.processPendingRevocations(ctx))
+		require.NotNil(t, db.revoked[prev.ID])
+		assert.Equal(t, int(pb.Reason_SUPERSEDED), db.revoked[prev.ID].Reason)
+		assert.Empty(t, db.pending)
+
+		// already revoked
+		db.pending[prev.ID] = r
+		assert.Equal(t, 1, s.processPendingRevocations(ctx))
+		assert.Empty(t, db.pending)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		prev := sign(t)
+		db.certs[1000] = &model.Certificate{ID: 1000, External: true}
+
+		for _, req := range []*pb.RenewCertificateRequest{
+			{},
+			{ID: prev.ID, GracePeriod: "1h"},
+			{ID: prev.ID, RevokePredecessor: true, GracePeriod: "abc"},
+			{ID: prev.ID, RevokePredecessor: true, GracePeriod: "-1h"},
+			{ID: prev.ID, NotBefore: xdb.Time(time.Now().Add(time.Hour)).String(), NotAfter: xdb.Time(time.Now()).String()},
+			{ID: 1000},
+		} {
+			_, err := s.RenewCertificate(ctx, req)
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), "%v: %s", req, err.Error())
+		}
+	})
+
+	t.Run("rekey", func(t *testing.T) {
+		prev := sign(t)
+		res, err := s.RekeyCertificate(ctx, &pb.RekeyCertificateRequest{
+			ID:                prev.ID,
+			RequestFormat:     pb.EncodingFormat_PEM,
+			Request:           csrForTest(t),
+			RevokePredecessor: true,
+		})
+		require.NoError(t, err)
+		crt := res.Certificate
+		assertRenewed(t, prev, crt)
+		assert.NotEqual(t, prev.SKID, crt.SKID)
+		require.NotNil(t, db.revoked[prev.ID])
+	})
+
+	t.Run("rekey_same_key", func(t *testing.T) {
+		req := csrForTest(t)
+		res, err := s.SignCertificate(ctx, &pb.SignCertificateRequest{
+			Profile:       "server",
+			Request:       req,
+			RequestFormat: pb.EncodingFormat_PEM,
+		})
+		require.NoError(t, err)
+
+		_, err = s.RekeyCertificate(ctx, &pb.RekeyCertificateRequest{
+			ID:            res.Certificate.ID,
+			RequestFormat: pb.EncodingFormat_PEM,
+			Request:       req,
+		})
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "the request must have a new key")
+
+		for _, r := range []*pb.RekeyCertificateRequest{
+			{ID: res.Certificate.ID},
+			{ID: res.Certificate.ID, RequestFormat: pb.EncodingFormat_PEM, Request: []byte("invalid")},
+			{ID: res.Certificate.ID, RequestFormat: pb.EncodingFormat_DER, Request: req},
+		} {
+			_, err = s.RekeyCertificate(ctx, r)
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+}
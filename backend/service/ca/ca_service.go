@@ -7,11 +7,14 @@ import (
 	"github.com/effective-security/porto/gserver"
 	"github.com/effective-security/porto/pkg/tasks"
 	"github.com/effective-security/porto/restserver"
+	v1 "github.com/effective-security/trusty/api"
 	pb "github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/trusty/backend/config"
 	"github.com/effective-security/trusty/backend/db/cadb"
 	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certlint"
 	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/pkg/weakkey"
 	"github.com/effective-security/x/fileutil"
 	"github.com/effective-security/xlog"
 	"github.com/effective-security/xpki/authority"
@@ -35,6 +38,31 @@ type Service struct {
 	cfg        *config.Configuration
 	registered bool
 	lock       sync.RWMutex
+
+	// publishCh wakes up the publish worker
+	publishCh      chan struct{}
+	stopCh         chan struct{}
+	workerOnce     sync.Once
+	ocspOnce       sync.Once
+	revocationOnce sync.Once
+	stopOnce       sync.Once
+
+	// crlScheduler coalesces CRL regeneration after revocations
+	crlScheduler *crlScheduler
+	// crlLocks serializes CRL regeneration per issuer
+	crlLocks sync.Map
+	// ocspResponders caches delegated OCSP responders by issuer
+	ocspResponders sync.Map
+	// ocspResponderLocks serializes OCSP responder issuance per issuer
+	ocspResponderLocks sync.Map
+	// tbsKeys caches the ephemeral keys to encode TBS certificates by key type
+	tbsKeys sync.Map
+	// linters caches the linters by sources
+	linters sync.Map
+	// linter overrides the linters of the profiles, if set
+	linter certlint.Linter
+	// weakKeys checks the public keys for known weaknesses
+	weakKeys *weakkey.Checker
 }
 
 // Factory returns a factory of the service
@@ -51,7 +79,13 @@ func Factory(server gserver.GServer) any {
 			db:        db,
 			publisher: publisher,
 			scheduler: scheduler,
+			publishCh: make(chan struct{}, 1),
+			stopCh:    make(chan struct{}),
 		}
+		svc.crlScheduler = newCrlScheduler(
+			cfg.CRLRegeneration.GetDelay(),
+			cfg.CRLRegeneration.GetMaxDelay(),
+			svc.regenerateCrl)
 
 		server.AddService(svc)
 	}
@@ -71,11 +105,43 @@ func (s *Service) IsReady() bool {
 
 // Close the subservices and it's resources
 func (s *Service) Close() {
+	s.stopOnce.Do(func() {
+		if s.crlScheduler != nil {
+			// the cancelled regenerations are completed before close
+			for _, ikid := range s.crlScheduler.Stop() {
+				s.regenerateCrl(ikid)
+			}
+		}
+		close(s.stopCh)
+	})
 	logger.KV(xlog.INFO, "closed", ServiceName)
 }
 
-// RegisterRoute adds the Status API endpoints to the overall URL router
+// RegisterRoute adds the CA API endpoints to the overall URL router
 func (s *Service) RegisterRoute(r restserver.Router) {
+	r.GET(v1.PathForCAProfileInfo, rpcHandler(s.ProfileInfo))
+	r.GET(v1.PathForCAIssuer, rpcHandler(s.GetIssuer))
+	r.GET(v1.PathForCAIssuers, rpcHandler(s.ListIssuers))
+	r.POST(v1.PathForCASign, rpcHandler(s.SignCertificate))
+	r.POST(v1.PathForCASignBatch, rpcHandler(s.SignCertificates))
+	r.POST(v1.PathForCARenew, rpcHandler(s.RenewCertificate))
+	r.POST(v1.PathForCARekey, rpcHandler(s.RekeyCertificate))
+	r.POST(v1.PathForCAGenerate, rpcHandler(s.GenerateAndSignCertificate))
+	r.GET(v1.PathForCACert, rpcHandler(s.GetCertificate))
+	r.GET(v1.PathForCACerts, rpcHandler(s.ListCertificates))
+	r.GET(v1.PathForCAOrgCerts, rpcHandler(s.ListOrgCertificates))
+	r.GET(v1.PathForCARevoked, rpcHandler(s.ListRevokedCertificates))
+	r.POST(v1.PathForCARevoke, rpcHandler(s.RevokeCertificate))
+	r.POST(v1.PathForCALabel, rpcHandler(s.UpdateCertificateLabel))
+	r.POST(v1.PathForCAImport, rpcHandler(s.ImportCertificate))
+	r.GET(v1.PathForCACRL, rpcHandler(s.GetCRL))
+	r.POST(v1.PathForCAOCSP, rpcHandler(s.SignOCSP))
+	r.POST(v1.PathForCAPublishCrls, rpcHandler(s.PublishCrls))
+	r.GET(v1.PathForCAPublishStatus, rpcHandler(s.GetPublishStatus))
+	r.POST(v1.PathForCAProfiles, rpcHandler(s.RegisterProfile))
+	r.GET(v1.PathForCADelegatedIssuers, rpcHandler(s.ListDelegatedIssuers))
+	r.POST(v1.PathForCARegisterDelegatedIssuer, rpcHandler(s.RegisterDelegatedIssuer))
+	r.POST(v1.PathForCAArchiveDelegatedIssuer, rpcHandler(s.ArchiveDelegatedIssuer))
 }
 
 // RegisterGRPC registers gRPC handler
@@ -87,7 +153,22 @@ func (s *Service) RegisterGRPC(r *grpc.Server) {
 // is ready to serve requests
 func (s *Service) OnStarted() error {
 	ctx := context.Background()
-	err := s.registerIssuers(ctx)
+	weakKeys, err := weakkey.New(s.cfg.WeakKeys.Debian...)
+	if err != nil {
+		return errors.WithMessage(err, "unable to load weak keys")
+	}
+	s.weakKeys = weakKeys
+	if weakKeys.DebianKeys() == 0 {
+		logger.KV(xlog.WARNING,
+			"status", "Debian weak keys are not checked",
+			"reason", "the bundled lists are empty, and weak_keys.debian is not configured")
+	} else {
+		logger.KV(xlog.INFO,
+			"status", "loaded Debian weak keys",
+			"count", weakKeys.DebianKeys())
+	}
+
+	err = s.registerIssuers(ctx)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -97,6 +178,10 @@ func (s *Service) OnStarted() error {
 		return errors.WithStack(err)
 	}
 	s.registerPublisherTask(ctx)
+	s.startPublishWorker()
+	s.startRevocationWorker()
+	s.loadOcspResponders(ctx)
+	s.startOcspUpdater()
 	return nil
 }
 
@@ -117,7 +202,7 @@ func (s *Service) registerIssuers(ctx context.Context) error {
 		bundle := ca.Bundle()
 		mcert := model.NewCertificate(bundle.Cert, 0, "ca", bundle.CertPEM, bundle.CACertsPEM, ca.Label(), nil, nil)
 
-		_, err := s.db.RegisterCertificate(ctx, mcert)
+		m, err := s.db.RegisterCertificate(ctx, mcert)
 		if err != nil {
 			logger.KV(xlog.ERROR,
 				"status", "failed to register issuer",
@@ -125,6 +210,12 @@ func (s *Service) registerIssuers(ctx context.Context) error {
 				"err", err.Error())
 			return errors.WithStack(err)
 		}
+
+		s.enqueuePublish(ctx, &model.PublishJob{
+			Kind:  model.PublishKindIssuer,
+			IKID:  m.IKID,
+			RefID: m.ID,
+		})
 	}
 	return nil
 }
@@ -171,6 +262,10 @@ func (s *Service) registerRoots(ctx context.Context) error {
 		}
 	}
 
+	s.enqueuePublish(ctx, &model.PublishJob{
+		Kind: model.PublishKindRoots,
+	})
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.registered = true
@@ -193,16 +288,25 @@ func (s *Service) registerPublisherTask(ctx context.Context) {
 			task := tasks.NewTaskAtIntervals(uint64(issuer.CrlRenewal().Hours()), tasks.Hours)
 			taskName := "crl_publisher_" + issuer.SubjectKID()
 			task = task.Do(taskName, func() {
-				_, err := s.publishCrl(ctx, issuer.SubjectKID())
-				if err != nil {
-					logger.ContextKV(ctx, xlog.ERROR,
-						"ikid", issuer.SubjectKID(),
-						"task", taskName,
-						"err", err.Error(),
-					)
-				}
+				s.regenerateCrl(issuer.SubjectKID())
 			})
 			s.scheduler = s.scheduler.Add(task)
+
+			if s.deltaCRLEnabled() {
+				renewal := s.cfg.DeltaCRL.Renewal
+				logger.ContextKV(ctx, xlog.NOTICE,
+					"ikid", issuer.SubjectKID(),
+					"scheduled", "delta_crl_publisher",
+					"interval", renewal.String(),
+				)
+
+				task := tasks.NewTaskAtIntervals(max(uint64(renewal.Minutes()), 1), tasks.Minutes)
+				taskName := "delta_crl_publisher_" + issuer.SubjectKID()
+				task = task.Do(taskName, func() {
+					s.regenerateDeltaCrl(issuer.SubjectKID())
+				})
+				s.scheduler = s.scheduler.Add(task)
+			}
 		} else {
 			logger.ContextKV(ctx, xlog.NOTICE,
 				"ikid", issuer.SubjectKID(),
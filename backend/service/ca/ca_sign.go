@@ -3,9 +3,11 @@ package ca
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/effective-security/porto/xhttp/httperror"
 	pb "github.com/effective-security/trusty/api/pb"
@@ -21,6 +23,51 @@ import (
 
 // SignCertificate returns the certificate
 func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRequest) (*pb.CertificateResponse, error) {
+	if req != nil && req.IdempotencyKey != "" {
+		return s.signIdempotent(ctx, req)
+	}
+	return s.signCertificate(ctx, req)
+}
+
+func (s *Service) signCertificate(ctx context.Context, req *pb.SignCertificateRequest) (*pb.CertificateResponse, error) {
+	mcert, err := s.issueCertificate(ctx, req, s.resolveIssuer)
+	if err != nil {
+		return nil, err
+	}
+	return s.registerIssued(ctx, mcert)
+}
+
+// issuerResolver returns the issuer and its profile for the request
+type issuerResolver func(ctx context.Context, req *pb.SignCertificateRequest) (*authority.Issuer, *authority.CertProfile, error)
+
+// resolveIssuer returns the issuer by the label of the request,
+// or the default issuer of the requested profile
+func (s *Service) resolveIssuer(ctx context.Context, req *pb.SignCertificateRequest) (*authority.Issuer, *authority.CertProfile, error) {
+	var err error
+	var ca *authority.Issuer
+	if req.IssuerLabel != "" {
+		ca, err = s.ca.GetIssuerByLabel(req.IssuerLabel)
+		if err != nil {
+			return nil, nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found: %s", req.IssuerLabel)
+		}
+	} else {
+		ca, err = s.ca.GetIssuerByProfile(req.Profile)
+		if err != nil {
+			return nil, nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found for profile: %s", req.Profile)
+		}
+	}
+
+	profile := ca.Profile(req.Profile)
+	if profile == nil {
+		msg := fmt.Sprintf("%q issuer does not support the requested profile: %q", ca.Label(), req.Profile)
+		return nil, nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, msg)
+	}
+	return ca, profile, nil
+}
+
+// issueCertificate signs the certificate by the resolved issuer,
+// the returned certificate must be registered
+func (s *Service) issueCertificate(ctx context.Context, req *pb.SignCertificateRequest, resolve issuerResolver) (*model.Certificate, error) {
 	if req == nil || req.Profile == "" {
 		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "missing profile")
 	}
@@ -29,6 +76,7 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 	}
 
 	var pemReq string
+	var pubReq *publicKeyRequest
 
 	switch req.RequestFormat {
 	case pb.EncodingFormat_PEM:
@@ -37,10 +85,26 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 		b := bytes.NewBuffer([]byte{})
 		_ = pem.Encode(b, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: req.Request})
 		pemReq = b.String()
+	case pb.EncodingFormat_SPKI, pb.EncodingFormat_SPKAC, pb.EncodingFormat_CRMF:
+		if req.RequestFormat == pb.EncodingFormat_SPKI && !s.cfg.KeyPolicy(req.Profile).AllowSPKI {
+			// SPKI has no proof of possession
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.FailedPrecondition, "SPKI request is not allowed for profile: %s", req.Profile)
+		}
+		var err error
+		pubReq, err = parsePublicKeyRequest(req.RequestFormat, req.Request)
+		if err != nil {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid request: %s", err.Error())
+		}
 	default:
 		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "unsupported request_format: %v", req.RequestFormat)
 	}
+	return s.issueRequest(ctx, req, resolve, pemReq, pubReq)
+}
 
+// issueRequest signs the certificate for PKCS#10 request in PEM,
+// or for the public key, by the resolved issuer.
+// The returned certificate must be registered.
+func (s *Service) issueRequest(ctx context.Context, req *pb.SignCertificateRequest, resolve issuerResolver, pemReq string, pubReq *publicKeyRequest) (*model.Certificate, error) {
 	var subj *csr.X509Subject
 	if req.Subject != nil {
 		subj = &csr.X509Subject{
@@ -60,23 +124,9 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 		}
 	}
 
-	var err error
-	var ca *authority.Issuer
-	if req.IssuerLabel != "" {
-		ca, err = s.ca.GetIssuerByLabel(req.IssuerLabel)
-		if err != nil {
-			return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found: %s", req.IssuerLabel)
-		}
-	} else {
-		ca, err = s.ca.GetIssuerByProfile(req.Profile)
-		if err != nil {
-			return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found for profile: %s", req.Profile)
-		}
-	}
-
-	if ca.Profile(req.Profile) == nil {
-		msg := fmt.Sprintf("%q issuer does not support the requested profile: %q", ca.Label(), req.Profile)
-		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, msg)
+	ca, profile, err := resolve(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
 	cr := csr.SignRequest{
@@ -85,6 +135,15 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 		SAN:     req.SAN,
 		Subject: subj,
 	}
+
+	shard, shardExt, err := s.crlShardExtension(ca, profile)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "failed to assign CRL shard")
+	}
+	if shardExt != nil {
+		// must be the first to take precedence over CRL DP in the request
+		cr.Extensions = append(cr.Extensions, *shardExt)
+	}
 	for _, ex := range req.Extensions {
 		cr.Extensions = append(cr.Extensions, csr.X509Extension{
 			ID:       toOID(ex.ID),
@@ -99,29 +158,91 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 	if req.NotAfter != "" {
 		cr.NotAfter = xdb.ParseTime(req.NotAfter).UTC()
 	}
+	if !cr.NotBefore.IsZero() && !cr.NotAfter.IsZero() && !cr.NotAfter.After(cr.NotBefore) {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid validity period: %s - %s",
+			cr.NotBefore.Format(time.RFC3339), cr.NotAfter.Format(time.RFC3339))
+	}
 
-	cert, pem, err := ca.Sign(cr)
+	tbs, err := s.prepareCertificate(ca, cr, pubReq)
 	if err != nil {
-		logger.ContextKV(ctx, xlog.WARNING,
-			"status", "failed to sign certificate",
-			"err", err.Error())
-
+		return nil, signFailed(ctx, ca, req.Profile, err)
+	}
+	if err = s.checkPublicKey(ctx, req.Profile, tbs.PublicKey); err != nil {
 		metricskey.CAFailSignCert.IncrCounter(1, ca.Label(), req.Profile)
-
-		str := err.Error()
-		if slices.ContainsString([]string{"invalid", "not allowed", "missing", "parse"}, str) {
-			return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "failed to sign certificate: %s", str)
-		}
-		return nil, httperror.WrapWithCtx(ctx, err, "failed to sign certificate")
+		return nil, err
+	}
+	warnings, err := s.lintCertificate(ctx, req.Profile, tbs)
+	if err != nil {
+		metricskey.CAFailSignCert.IncrCounter(1, ca.Label(), req.Profile)
+		return nil, err
+	}
+	cert, pem, err := signTBS(ca, tbs)
+	if err != nil {
+		return nil, signFailed(ctx, ca, req.Profile, err)
 	}
 
 	metricskey.CACertIssued.IncrCounter(1, ca.Label(), req.Profile)
 
-	mcert := model.NewCertificate(cert, req.OrgID, req.Profile, string(pem), ca.PEM(), req.Label, nil, req.Metadata)
-	fn := mcert.FileName()
-	mcert.Locations = append(mcert.Locations, s.cfg.RegistrationAuthority.Publisher.BaseURL+"/"+fn)
+	meta := withLintWarnings(withCrlShard(req.Metadata, shard), warnings)
+	return model.NewCertificate(cert, req.OrgID, req.Profile, string(pem), ca.PEM(), req.Label, nil, meta), nil
+}
+
+// prepareCertificate returns TBS certificate for the request,
+// with the profile applied, to be signed as is by the issuer
+func (s *Service) prepareCertificate(ca *authority.Issuer, cr csr.SignRequest, pubReq *publicKeyRequest) (*x509.Certificate, error) {
+	template, err := tbsTemplate(ca, cr, pubReq)
+	if err != nil {
+		return nil, err
+	}
+	return s.encodeTBS(ca, template)
+}
+
+// signFailed logs the failure to sign certificate,
+// and returns the error to the client
+func signFailed(ctx context.Context, ca *authority.Issuer, profile string, err error) error {
+	logger.ContextKV(ctx, xlog.WARNING,
+		"status", "failed to sign certificate",
+		"err", err.Error())
+
+	metricskey.CAFailSignCert.IncrCounter(1, ca.Label(), profile)
+
+	str := err.Error()
+	if slices.ContainsString([]string{"invalid", "not allowed", "missing", "parse"}, str) {
+		return httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "failed to sign certificate: %s", str)
+	}
+	return httperror.WrapWithCtx(ctx, err, "failed to sign certificate")
+}
+
+// registerIssued registers the issued certificate,
+// and enqueues it to publish
+func (s *Service) registerIssued(ctx context.Context, mcert *model.Certificate) (*pb.CertificateResponse, error) {
+	mcert.Locations = append(mcert.Locations, s.certLocation(mcert))
+
+	mcert, err := s.registerCertificate(ctx, mcert)
+	if err != nil {
+		return nil, err
+	}
+
+	// the certificate is published by the outbox worker,
+	// the client must not fail as the certificate is already registered
+	s.enqueuePublish(ctx, &model.PublishJob{
+		Kind:  model.PublishKindCert,
+		IKID:  mcert.IKID,
+		RefID: mcert.ID,
+	})
+
+	logIssued(ctx, mcert)
+	res := &pb.CertificateResponse{
+		Certificate: mcert.ToPB(),
+		Warnings:    mcert.LintWarnings(),
+	}
+	return res, nil
+}
 
-	mcert, err = s.db.RegisterCertificate(ctx, mcert)
+// registerCertificate registers the issued certificate,
+// and returns the error to the client
+func (s *Service) registerCertificate(ctx context.Context, mcert *model.Certificate) (*model.Certificate, error) {
+	mcert, err := s.db.RegisterCertificate(ctx, mcert)
 	if err != nil {
 		logger.ContextKV(ctx, xlog.ERROR,
 			"status", "failed to register certificate",
@@ -133,19 +254,15 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 
 		return nil, httperror.WrapWithCtx(ctx, err, "failed to register certificate")
 	}
+	return mcert, nil
+}
 
-	if s.publisher != nil {
-		_, err := s.publisher.PublishCertificate(context.Background(), mcert.ToPB(), fn)
-		if err != nil {
-			logger.ContextKV(ctx, xlog.ERROR,
-				"status", "failed to publish certificate",
-				"err", err.Error())
-
-			metricskey.CAFailPublishCert.IncrCounter(1, ca.Label())
-			return nil, httperror.WrapWithCtx(ctx, err, "failed to publish certificate")
-		}
-	}
+// certLocation returns the published location of the certificate
+func (s *Service) certLocation(mcert *model.Certificate) string {
+	return s.cfg.RegistrationAuthority.Publisher.BaseURL + "/" + mcert.FileName()
+}
 
+func logIssued(ctx context.Context, mcert *model.Certificate) {
 	logger.ContextKV(ctx, xlog.NOTICE,
 		"status", "signed certificate",
 		"id", mcert.ID,
@@ -154,10 +271,6 @@ func (s *Service) SignCertificate(ctx context.Context, req *pb.SignCertificateRe
 		"locations", mcert.Locations,
 		"meta", mcert.Metadata,
 	)
-	res := &pb.CertificateResponse{
-		Certificate: mcert.ToPB(),
-	}
-	return res, nil
 }
 
 func toOID(s []int64) []int {
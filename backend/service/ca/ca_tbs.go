@@ -0,0 +1,470 @@
+package ca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/csr"
+	"github.com/effective-security/xpki/oid"
+	"github.com/pkg/errors"
+)
+
+// The certificate to be signed (TBS) is built from the request
+// by the profile of the issuer, and encoded once.
+// The encoded TBS is checked by the key policy and the linter,
+// then exactly the same TBS is signed by the issuer's key.
+//
+// The x509 package does not encode TBS without signing,
+// so TBS is encoded by the ephemeral key of the same type as the issuer's key,
+// with the issuer's name and key ID, the ephemeral signature is never returned.
+
+var (
+	oidCertificatePolicies = asn1.ObjectIdentifier{2, 5, 29, 32}
+	oidOCSPNoCheck         = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+	oidQualifierCPS        = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 2, 1}
+	oidQualifierUserNotice = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 2, 2}
+)
+
+// tbsTemplate returns the certificate template for the request,
+// the profile is applied as by authority.Issuer.Sign.
+// The public key is taken from PKCS#10 request,
+// or from pubReq for the requests without PKCS#10.
+// The extensions not allowed by the profile are rejected.
+func tbsTemplate(ca *authority.Issuer, cr csr.SignRequest, pubReq *publicKeyRequest) (*x509.Certificate, error) {
+	profile := ca.Profile(cr.Profile)
+	if profile == nil {
+		return nil, errors.New("unsupported profile: " + cr.Profile)
+	}
+
+	var requested *x509.Certificate
+	if pubReq != nil {
+		requested = &x509.Certificate{
+			Subject:   pubReq.Subject,
+			PublicKey: pubReq.PublicKey,
+		}
+	} else {
+		var err error
+		requested, err = csr.ParsePEM([]byte(cr.Request))
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to parse CSR")
+		}
+	}
+
+	// copy out only the fields from the request authorized by policy
+	template := &x509.Certificate{
+		PublicKey:          requested.PublicKey,
+		SignatureAlgorithm: csr.DefaultSigAlgo(ca.Signer()),
+	}
+	if profile.AllowedCSRFields == nil {
+		template.Subject = requested.Subject
+		template.DNSNames = requested.DNSNames
+		template.IPAddresses = requested.IPAddresses
+		template.URIs = requested.URIs
+		template.EmailAddresses = requested.EmailAddresses
+	} else {
+		if profile.AllowedCSRFields.Subject {
+			template.Subject = requested.Subject
+		}
+		if profile.AllowedCSRFields.DNSNames {
+			template.DNSNames = requested.DNSNames
+		}
+		if profile.AllowedCSRFields.IPAddresses {
+			template.IPAddresses = requested.IPAddresses
+		}
+		if profile.AllowedCSRFields.URIs {
+			template.URIs = requested.URIs
+		}
+		if profile.AllowedCSRFields.EmailAddresses {
+			template.EmailAddresses = requested.EmailAddresses
+		}
+	}
+
+	template.Subject = csr.PopulateName(cr.Subject, template.Subject)
+	// allow Names to be signed
+	template.Subject.ExtraNames = template.Subject.Names
+
+	if err := checkAllowedNames(profile, template); err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+
+	for _, ext := range profile.Extensions {
+		raw, err := ext.GetValue()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{
+			Id:       asn1.ObjectIdentifier(ext.ID),
+			Critical: ext.Critical,
+			Value:    raw,
+		})
+	}
+	for _, ext := range cr.Extensions {
+		if !profile.IsAllowedExtention(ext.ID) {
+			return nil, errors.Errorf("extension not allowed: %s", ext.ID.String())
+		}
+		id := asn1.ObjectIdentifier(ext.ID)
+		if certutil.FindExtension(template.ExtraExtensions, id) == nil {
+			raw, err := ext.GetValue()
+			if err != nil {
+				return nil, err
+			}
+			template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{
+				Id:       id,
+				Critical: ext.Critical,
+				Value:    raw,
+			})
+		}
+	}
+	for _, ext := range requested.ExtraExtensions {
+		if !profile.IsAllowedExtention(csr.OID(ext.Id)) {
+			return nil, errors.Errorf("extension not allowed: %s", ext.Id.String())
+		}
+		if certutil.FindExtension(template.ExtraExtensions, ext.Id) == nil {
+			template.ExtraExtensions = append(template.ExtraExtensions, ext)
+		}
+	}
+	csr.SetSAN(template, cr.SAN)
+
+	if err = fillTemplate(ca, template, profile, cr.NotBefore, cr.NotAfter); err != nil {
+		return nil, errors.WithMessage(err, "failed to populate template")
+	}
+	return template, nil
+}
+
+// checkAllowedNames returns error if the names of the template
+// are not allowed by the profile
+func checkAllowedNames(profile *authority.CertProfile, template *x509.Certificate) error {
+	if profile.AllowedNamesRegex != nil && template.Subject.CommonName != "" {
+		if !profile.AllowedNamesRegex.MatchString(template.Subject.CommonName) {
+			return errors.New("CommonName does not match allowed list: " + template.Subject.CommonName)
+		}
+	}
+	if profile.AllowedDNSRegex != nil {
+		for _, name := range template.DNSNames {
+			if !profile.AllowedDNSRegex.MatchString(name) {
+				return errors.New("DNS Name does not match allowed list: " + name)
+			}
+		}
+	}
+	if profile.AllowedEmailRegex != nil {
+		for _, name := range template.EmailAddresses {
+			if !profile.AllowedEmailRegex.MatchString(name) {
+				return errors.New("Email does not match allowed list: " + name)
+			}
+		}
+	}
+	if profile.AllowedURIRegex != nil {
+		for _, u := range template.URIs {
+			if uri := u.String(); !profile.AllowedURIRegex.MatchString(uri) {
+				return errors.New("URI does not match allowed list: " + uri)
+			}
+		}
+	}
+	return nil
+}
+
+// newSerialNumber returns random positive serial number,
+// not longer than 20 octets as required by RFC 5280 4.1.2.2
+func newSerialNumber() (*big.Int, error) {
+	serial := make([]byte, 20)
+	if _, err := io.ReadFull(rand.Reader, serial); err != nil {
+		return nil, errors.Wrap(err, "failed to generate serial number")
+	}
+	serial[0] &= 0x7F
+	return new(big.Int).SetBytes(serial), nil
+}
+
+// fillTemplate sets the validity, usages, constraints and AIA of the template
+// by the profile and the issuer
+func fillTemplate(ca *authority.Issuer, template *x509.Certificate, profile *authority.CertProfile, notBefore, notAfter time.Time) error {
+	ski, err := subjectKeyID(template.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	expiry := profile.Expiry.TimeDuration()
+	if expiry == 0 && notAfter.IsZero() {
+		return errors.Errorf("expiry is not set")
+	}
+
+	ku, eku, _ := profile.Usages()
+	if ku == 0 && len(eku) == 0 && profile.FindExtension(oid.ExtensionKeyUsage) == nil {
+		return errors.Errorf("invalid profile: no key usages")
+	}
+
+	if notBefore.IsZero() {
+		backdate := -1 * profile.Backdate.TimeDuration()
+		if backdate == 0 {
+			backdate = -5 * time.Minute
+		}
+		notBefore = time.Now().Round(time.Minute).Add(backdate)
+	}
+	if notAfter.IsZero() {
+		notAfter = notBefore.Add(expiry)
+	}
+	template.NotBefore = notBefore.UTC()
+	template.NotAfter = notAfter.UTC()
+	if issuer := ca.Bundle().Cert; template.NotAfter.After(issuer.NotAfter) {
+		template.NotAfter = issuer.NotAfter
+	}
+	template.KeyUsage = ku
+	template.ExtKeyUsage = eku
+
+	isOCSPResponder := false
+	template.IsCA = profile.CAConstraint.IsCA
+	template.BasicConstraintsValid = true
+	if template.IsCA {
+		template.MaxPathLen = profile.CAConstraint.MaxPathLen
+		template.MaxPathLenZero = template.MaxPathLen == 0
+		template.DNSNames = nil
+		template.IPAddresses = nil
+		template.EmailAddresses = nil
+		template.URIs = nil
+	} else {
+		template.MaxPathLen = -1
+		template.MaxPathLenZero = false
+		// do not include OCSP and CDP to delegated OCSP responder cert
+		isOCSPResponder = certutil.IsOCSPSigner(template) &&
+			(profile.OCSPNoCheck || certutil.HasOCSPNoCheck(template))
+	}
+	template.SubjectKeyId = ski
+
+	if u := ca.OcspURL(); !isOCSPResponder && u != "" {
+		template.OCSPServer = []string{u}
+	}
+	if u := ca.CrlURL(); !isOCSPResponder && u != "" {
+		template.CRLDistributionPoints = []string{u}
+	}
+	if u := ca.AiaURL(); u != "" {
+		template.IssuingCertificateURL = []string{u}
+	}
+	if len(profile.Policies) != 0 {
+		ext, err := policiesExtension(profile.Policies, profile.PoliciesCritical)
+		if err != nil {
+			return errors.WithMessage(err, "invalid profile policies")
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, *ext)
+	}
+	if profile.OCSPNoCheck {
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{
+			Id:    oidOCSPNoCheck,
+			Value: []byte{0x05, 0x00},
+		})
+	}
+	return nil
+}
+
+// subjectKeyID returns SHA-1 of the public key, RFC 5280 4.2.1.2
+func subjectKeyID(pub crypto.PublicKey) ([]byte, error) {
+	spki, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, errors.Wrap(err, "unsupported public key")
+	}
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err = asn1.Unmarshal(spki, &info); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ski := sha1.Sum(info.PublicKey.Bytes)
+	return ski[:], nil
+}
+
+type policyInformation struct {
+	PolicyIdentifier asn1.ObjectIdentifier
+	Qualifiers       []any `asn1:"tag:optional,omitempty"`
+}
+
+type cpsPolicyQualifier struct {
+	PolicyQualifierID asn1.ObjectIdentifier
+	Qualifier         string `asn1:"tag:optional,ia5"`
+}
+
+type userNotice struct {
+	ExplicitText string `asn1:"tag:optional,utf8"`
+}
+
+type userNoticePolicyQualifier struct {
+	PolicyQualifierID asn1.ObjectIdentifier
+	Qualifier         userNotice
+}
+
+// policiesExtension returns Certificate Policies extension
+func policiesExtension(policies []csr.CertificatePolicy, critical bool) (*pkix.Extension, error) {
+	list := make([]policyInformation, 0, len(policies))
+	for _, policy := range policies {
+		pi := policyInformation{
+			PolicyIdentifier: asn1.ObjectIdentifier(policy.ID),
+		}
+		for _, q := range policy.Qualifiers {
+			switch q.Type {
+			case "id-qt-unotice":
+				pi.Qualifiers = append(pi.Qualifiers, userNoticePolicyQualifier{
+					PolicyQualifierID: oidQualifierUserNotice,
+					Qualifier:         userNotice{ExplicitText: q.Value},
+				})
+			case "id-qt-cps":
+				pi.Qualifiers = append(pi.Qualifiers, cpsPolicyQualifier{
+					PolicyQualifierID: oidQualifierCPS,
+					Qualifier:         q.Value,
+				})
+			default:
+				return nil, errors.New("invalid qualifier type in Policies " + q.Type)
+			}
+		}
+		list = append(list, pi)
+	}
+	value, err := asn1.Marshal(list)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &pkix.Extension{
+		Id:       oidCertificatePolicies,
+		Critical: critical,
+		Value:    value,
+	}, nil
+}
+
+// encodeTBS returns TBS certificate of the template to be signed by the issuer,
+// RawTBSCertificate of the returned certificate is signed as is by signTBS
+func (s *Service) encodeTBS(ca *authority.Issuer, template *x509.Certificate) (*x509.Certificate, error) {
+	issuer := ca.Bundle().Cert
+	key, err := s.tbsKey(issuer.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	// the parent provides the issuer name and the authority key ID
+	parent := *issuer
+	parent.PublicKey = key.Public()
+
+	der, err := x509.CreateCertificate(rand.Reader, template, &parent, template.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "create certificate")
+	}
+	tbs, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tbs, nil
+}
+
+// tbsKey returns the ephemeral key to encode TBS certificates
+// of the issuers with the key of the same type,
+// the keys are created once per the type
+func (s *Service) tbsKey(pub crypto.PublicKey) (crypto.Signer, error) {
+	var kind string
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		kind = "rsa"
+	case *ecdsa.PublicKey:
+		kind = "ecdsa"
+	case ed25519.PublicKey:
+		kind = "ed25519"
+	default:
+		return nil, errors.Errorf("unsupported issuer key: %T", pub)
+	}
+	if key, ok := s.tbsKeys.Load(kind); ok {
+		return key.(crypto.Signer), nil
+	}
+
+	var key crypto.Signer
+	var err error
+	switch kind {
+	case "rsa":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ecdsa":
+		key, err = ecdsa.GenerateKey(pub.(*ecdsa.PublicKey).Curve, rand.Reader)
+	default:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	actual, _ := s.tbsKeys.LoadOrStore(kind, key)
+	return actual.(crypto.Signer), nil
+}
+
+// signTBS signs the encoded TBS certificate by the issuer's key,
+// and returns the issued certificate
+func signTBS(ca *authority.Issuer, tbs *x509.Certificate) (*x509.Certificate, []byte, error) {
+	opts, err := signerOpts(tbs.SignatureAlgorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	digest := tbs.RawTBSCertificate
+	if h := opts.HashFunc(); h != 0 {
+		hh := h.New()
+		hh.Write(digest)
+		digest = hh.Sum(nil)
+	}
+	signature, err := ca.Signer().Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to sign certificate")
+	}
+
+	// the encoded TBS and its signature algorithm are kept as is,
+	// only the ephemeral signature is replaced
+	var c struct {
+		TBSCertificate     asn1.RawValue
+		SignatureAlgorithm asn1.RawValue
+		SignatureValue     asn1.BitString
+	}
+	if _, err = asn1.Unmarshal(tbs.Raw, &c); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	c.SignatureValue = asn1.BitString{Bytes: signature, BitLength: len(signature) * 8}
+	der, err := asn1.Marshal(c)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	crt, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	if err = crt.CheckSignatureFrom(ca.Bundle().Cert); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid signature of the issuer")
+	}
+	return crt, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// signerOpts returns the options to sign by the signature algorithm
+func signerOpts(alg x509.SignatureAlgorithm) (crypto.SignerOpts, error) {
+	switch alg {
+	case x509.SHA256WithRSA, x509.ECDSAWithSHA256:
+		return crypto.SHA256, nil
+	case x509.SHA384WithRSA, x509.ECDSAWithSHA384:
+		return crypto.SHA384, nil
+	case x509.SHA512WithRSA, x509.ECDSAWithSHA512:
+		return crypto.SHA512, nil
+	case x509.SHA256WithRSAPSS:
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}, nil
+	case x509.SHA384WithRSAPSS:
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA384}, nil
+	case x509.SHA512WithRSAPSS:
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA512}, nil
+	case x509.PureEd25519:
+		return crypto.Hash(0), nil
+	}
+	return nil, errors.Errorf("unsupported signature algorithm: %s", alg)
+}
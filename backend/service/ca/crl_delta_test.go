@@ -0,0 +1,298 @@
+package ca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCrlDB struct {
+	cadb.CaDb
+
+	revoked model.RevokedCertificates
+	numbers map[string]uint64
+	// shardNumbers has the last CRL number by shard
+	shardNumbers map[uint32]uint64
+	crl          *model.Crl
+	delta        *model.Crl
+	shards       []*model.Crl
+}
+
+func (m *mockCrlDB) ListRevokedCertificates(_ context.Context, _ string, limit int, afterID uint64) (model.RevokedCertificates, error) {
+	var list model.RevokedCertificates
+	for _, r := range m.revoked {
+		if r.Certificate.ID > afterID && (limit == 0 || len(list) < limit) {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockCrlDB) NextCrlNumber(_ context.Context, ikid string) (uint64, error) {
+	m.numbers[ikid]++
+	return m.numbers[ikid], nil
+}
+
+func (m *mockCrlDB) NextShardCrlNumber(_ context.Context, _ string, shard uint32) (uint64, error) {
+	if m.shardNumbers == nil {
+		m.shardNumbers = map[uint32]uint64{}
+	}
+	m.shardNumbers[shard]++
+	return m.shardNumbers[shard], nil
+}
+
+func (m *mockCrlDB) RegisterCrl(_ context.Context, crl *model.Crl) (*model.Crl, error) {
+	m.crl = crl
+	return crl, nil
+}
+
+func (m *mockCrlDB) GetCrl(_ context.Context, _ string) (*model.Crl, error) {
+	if m.crl == nil {
+		return nil, errors.New("not found")
+	}
+	return m.crl, nil
+}
+
+func (m *mockCrlDB) RegisterDeltaCrl(_ context.Context, crl *model.Crl) (*model.Crl, error) {
+	m.delta = crl
+	return crl, nil
+}
+
+func (m *mockCrlDB) RegisterShardCrl(_ context.Context, crl *model.Crl) (*model.Crl, error) {
+	m.shards = append(m.shards, crl)
+	return crl, nil
+}
+
+func newTestIssuer(t *testing.T) *authority.Issuer {
+	return newTestIssuerWithLabel(t, "crl", []byte{2, 2, 2, 2})
+}
+
+func newTestIssuerWithLabel(t *testing.T, label string, skid []byte) *authority.Issuer {
+	newCert := func(cn string, skid []byte, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		tmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(time.Now().UnixNano()),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(24 * time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+			SubjectKeyId:          skid,
+		}
+		if parent == nil {
+			parent, parentKey = tmpl, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+		require.NoError(t, err)
+		crt, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return crt, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	root, rootKey, rootPem := newCert("[TEST] CRL Root", []byte{1, 1, 1, 1}, nil, nil)
+	_, key, caPem := newCert("[TEST] CRL Issuer "+label, skid, root, rootKey)
+
+	issuer, err := authority.CreateIssuer(&authority.IssuerConfig{
+		Label: label,
+		AIA: &authority.AIAConfig{
+			CrlURL:     "http://localhost/v1/crl/${ISSUER_ID}",
+			CRLExpiry:  12 * time.Hour,
+			OCSPExpiry: 8 * time.Hour,
+		},
+	}, caPem, nil, rootPem, key)
+	require.NoError(t, err)
+	return issuer
+}
+
+func revokedCert(id uint64, revokedAt time.Time) *model.RevokedCertificate {
+	return &model.RevokedCertificate{
+		Certificate: model.Certificate{
+			ID:           id,
+			SerialNumber: big.NewInt(int64(id)).String(),
+		},
+		RevokedAt: xdb.Time(revokedAt),
+		Reason:    1,
+	}
+}
+
+func parseCrl(t *testing.T, crl string) *x509.RevocationList {
+	block, _ := pem.Decode([]byte(crl))
+	require.NotNil(t, block)
+	rl, err := x509.ParseRevocationList(block.Bytes)
+	require.NoError(t, err)
+	return rl
+}
+
+func findExtension(list []pkix.Extension, oid asn1.ObjectIdentifier) *pkix.Extension {
+	for _, e := range list {
+		if e.Id.Equal(oid) {
+			return &e
+		}
+	}
+	return nil
+}
+
+func TestCreateDeltaCRL(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	db := &mockCrlDB{
+		numbers: map[string]uint64{},
+		revoked: model.RevokedCertificates{
+			revokedCert(1, time.Now().Add(-time.Hour)),
+		},
+	}
+	s := &Service{
+		db: db,
+		cfg: &config.Configuration{
+			DeltaCRL: config.DeltaCRL{Renewal: time.Hour},
+		},
+	}
+	crlURL := "http://localhost/v1/crl/" + issuer.SubjectKID()
+
+	_, err := s.createDeltaCRL(ctx, issuer)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to find complete CRL")
+
+	base, err := s.createGenericCRL(ctx, issuer)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), base.CrlNumber)
+	assert.Zero(t, base.BaseNumber)
+
+	rl := parseCrl(t, base.Pem)
+	require.NoError(t, rl.CheckSignatureFrom(issuer.Bundle().Cert))
+	assert.Equal(t, int64(1), rl.Number.Int64())
+	assert.Equal(t, issuer.Bundle().Cert.SubjectKeyId, rl.AuthorityKeyId)
+	require.Len(t, rl.RevokedCertificateEntries, 1)
+	assert.Equal(t, 1, rl.RevokedCertificateEntries[0].ReasonCode)
+
+	idp := findExtension(rl.Extensions, oidExtensionIssuingDistributionPoint)
+	require.NotNil(t, idp)
+	assert.True(t, idp.Critical)
+	assert.Contains(t, string(idp.Value), crlURL)
+
+	freshest := findExtension(rl.Extensions, oidExtensionFreshestCRL)
+	require.NotNil(t, freshest)
+	assert.Contains(t, string(freshest.Value), crlURL+"-delta")
+
+	// revoked after the complete CRL
+	db.revoked = append(db.revoked, revokedCert(2, time.Now()))
+
+	delta, err := s.createDeltaCRL(ctx, issuer)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), delta.CrlNumber)
+	assert.Equal(t, uint64(1), delta.BaseNumber)
+
+	rl = parseCrl(t, delta.Pem)
+	require.NoError(t, rl.CheckSignatureFrom(issuer.Bundle().Cert))
+	assert.Equal(t, int64(2), rl.Number.Int64())
+	require.Len(t, rl.RevokedCertificateEntries, 1)
+	assert.Equal(t, int64(2), rl.RevokedCertificateEntries[0].SerialNumber.Int64())
+	assert.Equal(t, rl.ThisUpdate.Add(2*time.Hour), rl.NextUpdate)
+
+	indicator := findExtension(rl.Extensions, oidExtensionDeltaCRLIndicator)
+	require.NotNil(t, indicator)
+	assert.True(t, indicator.Critical)
+	var baseNumber *big.Int
+	_, err = asn1.Unmarshal(indicator.Value, &baseNumber)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), baseNumber.Int64())
+
+	idp = findExtension(rl.Extensions, oidExtensionIssuingDistributionPoint)
+	require.NotNil(t, idp)
+	assert.Contains(t, string(idp.Value), crlURL+"-delta")
+	assert.Nil(t, findExtension(rl.Extensions, oidExtensionFreshestCRL))
+
+	t.Run("delta disabled", func(t *testing.T) {
+		s.cfg.DeltaCRL.Renewal = 0
+		base, err := s.createGenericCRL(ctx, issuer)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), base.CrlNumber)
+		rl := parseCrl(t, base.Pem)
+		assert.Len(t, rl.RevokedCertificateEntries, 2)
+		assert.Nil(t, findExtension(rl.Extensions, oidExtensionFreshestCRL))
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		for id := uint64(3); id <= 2*crlBatchSize+1; id++ {
+			db.revoked = append(db.revoked, revokedCert(id, time.Now().Add(-time.Hour)))
+		}
+		base, err := s.createGenericCRL(ctx, issuer)
+		require.NoError(t, err)
+		rl := parseCrl(t, base.Pem)
+		require.NoError(t, rl.CheckSignatureFrom(issuer.Bundle().Cert))
+		require.Len(t, rl.RevokedCertificateEntries, 2*crlBatchSize+1)
+		assert.Equal(t, int64(2*crlBatchSize+1), rl.RevokedCertificateEntries[2*crlBatchSize].SerialNumber.Int64())
+	})
+}
+
+func TestRegenerateCrl(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	ikid := issuer.SubjectKID()
+	db := &mockCrlDB{
+		numbers: map[string]uint64{},
+		revoked: model.RevokedCertificates{
+			revokedCert(1, time.Now().Add(-time.Hour)),
+		},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.crlScheduler = nil
+	s.cfg.DeltaCRL = config.DeltaCRL{Renewal: time.Hour}
+
+	// the CRL is regenerated in DB without publisher
+	s.scheduleCrl(ctx, ikid)
+	require.NotNil(t, db.crl)
+	assert.Equal(t, uint64(1), db.crl.CrlNumber)
+	require.NotNil(t, db.delta)
+	assert.Equal(t, uint64(2), db.delta.CrlNumber)
+	assert.Equal(t, uint64(1), db.delta.BaseNumber)
+
+	s.regenerateDeltaCrl(ikid)
+	assert.Equal(t, uint64(3), db.delta.CrlNumber)
+	assert.Equal(t, uint64(1), db.delta.BaseNumber)
+
+	// the unknown issuer is not retried
+	s.regenerateCrl("unknown")
+
+	t.Run("publish_stored", func(t *testing.T) {
+		pub := &mockPublisher{}
+		s.publisher = pub
+		defer func() { s.publisher = nil }()
+
+		location, err := s.publishStoredCrls(ctx, ikid)
+		require.NoError(t, err)
+		assert.Equal(t, "file:///crl/"+ikid+".crl", location)
+		assert.Equal(t, uint64(1), db.crl.CrlNumber, "the latest CRL must be published")
+
+		// the expired CRL is generated again
+		db.crl.NextUpdate = xdb.Time(time.Now().Add(-time.Minute))
+		_, err = s.publishStoredCrls(ctx, ikid)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), db.crl.CrlNumber)
+		assert.Len(t, pub.published, 2)
+
+		_, err = s.publishStoredCrls(ctx, "unknown")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,98 @@
+package ca
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	oidExtensionDeltaCRLIndicator        = asn1.ObjectIdentifier{2, 5, 29, 27}
+	oidExtensionIssuingDistributionPoint = asn1.ObjectIdentifier{2, 5, 29, 28}
+	oidExtensionCRLDistributionPoints    = asn1.ObjectIdentifier{2, 5, 29, 31}
+	oidExtensionFreshestCRL              = asn1.ObjectIdentifier{2, 5, 29, 46}
+)
+
+// RFC 5280, 4.2.1.13
+type distributionPointName struct {
+	FullName []asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+type distributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+}
+
+// RFC 5280, 5.2.5
+type issuingDistributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+}
+
+func fullName(url string) distributionPointName {
+	return distributionPointName{
+		FullName: []asn1.RawValue{
+			{Tag: 6, Class: asn1.ClassContextSpecific, Bytes: []byte(url)},
+		},
+	}
+}
+
+// issuingDistributionPointExtension returns critical IDP extension,
+// which identifies the CRL distribution point
+func issuingDistributionPointExtension(url string) (pkix.Extension, error) {
+	val, err := asn1.Marshal(issuingDistributionPoint{
+		DistributionPoint: fullName(url),
+	})
+	if err != nil {
+		return pkix.Extension{}, errors.WithStack(err)
+	}
+	return pkix.Extension{
+		Id:       oidExtensionIssuingDistributionPoint,
+		Critical: true,
+		Value:    val,
+	}, nil
+}
+
+// freshestCRLExtension returns FreshestCRL extension,
+// which points to delta CRL of the complete CRL
+func freshestCRLExtension(url string) (pkix.Extension, error) {
+	val, err := asn1.Marshal([]distributionPoint{
+		{DistributionPoint: fullName(url)},
+	})
+	if err != nil {
+		return pkix.Extension{}, errors.WithStack(err)
+	}
+	return pkix.Extension{
+		Id:    oidExtensionFreshestCRL,
+		Value: val,
+	}, nil
+}
+
+// crlDistributionPointsExtension returns CRL DP extension of a certificate,
+// which points to the CRL of its shard
+func crlDistributionPointsExtension(url string) (pkix.Extension, error) {
+	val, err := asn1.Marshal([]distributionPoint{
+		{DistributionPoint: fullName(url)},
+	})
+	if err != nil {
+		return pkix.Extension{}, errors.WithStack(err)
+	}
+	return pkix.Extension{
+		Id:    oidExtensionCRLDistributionPoints,
+		Value: val,
+	}, nil
+}
+
+// deltaCRLIndicatorExtension returns critical Delta CRL Indicator extension,
+// with the number of the complete CRL
+func deltaCRLIndicatorExtension(baseNumber uint64) (pkix.Extension, error) {
+	val, err := asn1.Marshal(new(big.Int).SetUint64(baseNumber))
+	if err != nil {
+		return pkix.Extension{}, errors.WithStack(err)
+	}
+	return pkix.Extension{
+		Id:       oidExtensionDeltaCRLIndicator,
+		Critical: true,
+		Value:    val,
+	}, nil
+}
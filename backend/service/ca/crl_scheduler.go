@@ -0,0 +1,121 @@
+package ca
+
+import (
+	"sync"
+	"time"
+)
+
+// crlScheduler coalesces CRL regeneration requests per issuer.
+// The regeneration is started once no requests are received for the delay period,
+// but not later than max delay after the first request.
+// At most one regeneration per issuer is running at a time,
+// the requests received while running are served by the next run.
+type crlScheduler struct {
+	delay    time.Duration
+	maxDelay time.Duration
+	run      func(ikid string)
+
+	lock    sync.Mutex
+	pending map[string]*crlRequest
+	running map[string]bool
+	dirty   map[string]bool
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+type crlRequest struct {
+	first time.Time
+	timer *time.Timer
+}
+
+func newCrlScheduler(delay, maxDelay time.Duration, run func(ikid string)) *crlScheduler {
+	return &crlScheduler{
+		delay:    delay,
+		maxDelay: maxDelay,
+		run:      run,
+		pending:  map[string]*crlRequest{},
+		running:  map[string]bool{},
+		dirty:    map[string]bool{},
+	}
+}
+
+// Schedule requests CRL regeneration of the issuer
+func (c *crlScheduler) Schedule(ikid string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stopped {
+		return
+	}
+
+	now := time.Now()
+	req := c.pending[ikid]
+	if req == nil {
+		req = &crlRequest{first: now}
+		req.timer = time.AfterFunc(c.delay, func() { c.fire(ikid, req) })
+		c.pending[ikid] = req
+		return
+	}
+
+	d := c.delay
+	if deadline := req.first.Add(c.maxDelay); now.Add(d).After(deadline) {
+		d = deadline.Sub(now)
+	}
+	req.timer.Reset(d)
+}
+
+func (c *crlScheduler) fire(ikid string, req *crlRequest) {
+	c.lock.Lock()
+	if c.stopped || c.pending[ikid] != req {
+		// already served, the timer was reset after it fired
+		c.lock.Unlock()
+		return
+	}
+	delete(c.pending, ikid)
+
+	if c.running[ikid] {
+		c.dirty[ikid] = true
+		c.lock.Unlock()
+		return
+	}
+	c.running[ikid] = true
+	c.wg.Add(1)
+	c.lock.Unlock()
+
+	defer c.wg.Done()
+	for {
+		c.run(ikid)
+
+		c.lock.Lock()
+		if c.stopped || !c.dirty[ikid] {
+			delete(c.running, ikid)
+			delete(c.dirty, ikid)
+			c.lock.Unlock()
+			return
+		}
+		delete(c.dirty, ikid)
+		c.lock.Unlock()
+	}
+}
+
+// Stop cancels the pending requests, waits for the running regenerations,
+// and returns the issuers with cancelled requests
+func (c *crlScheduler) Stop() []string {
+	c.lock.Lock()
+	c.stopped = true
+	var cancelled []string
+	for ikid, req := range c.pending {
+		req.timer.Stop()
+		cancelled = append(cancelled, ikid)
+	}
+	for ikid := range c.dirty {
+		if c.pending[ikid] == nil {
+			cancelled = append(cancelled, ikid)
+		}
+	}
+	c.pending = map[string]*crlRequest{}
+	c.lock.Unlock()
+
+	c.wg.Wait()
+	return cancelled
+}
@@ -0,0 +1,115 @@
+package ca
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type crlRuns struct {
+	lock       sync.Mutex
+	runs       map[string]int
+	concurrent int32
+	overlapped atomic.Bool
+	block      chan struct{}
+}
+
+func (r *crlRuns) run(ikid string) {
+	if atomic.AddInt32(&r.concurrent, 1) > 1 {
+		r.overlapped.Store(true)
+	}
+	defer atomic.AddInt32(&r.concurrent, -1)
+
+	if r.block != nil {
+		<-r.block
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.runs[ikid]++
+}
+
+func (r *crlRuns) count(ikid string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.runs[ikid]
+}
+
+func TestCrlSchedulerCoalesce(t *testing.T) {
+	runs := &crlRuns{runs: map[string]int{}}
+	c := newCrlScheduler(50*time.Millisecond, time.Second, runs.run)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Schedule("ikid1")
+		}()
+	}
+	c.Schedule("ikid2")
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		return runs.count("ikid1") == 1 && runs.count("ikid2") == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// no more runs
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, runs.count("ikid1"))
+	assert.Empty(t, c.Stop())
+}
+
+func TestCrlSchedulerMaxDelay(t *testing.T) {
+	runs := &crlRuns{runs: map[string]int{}}
+	c := newCrlScheduler(50*time.Millisecond, 100*time.Millisecond, runs.run)
+	defer c.Stop()
+
+	// continuous requests must not postpone the regeneration forever
+	for i := 0; i < 30; i++ {
+		c.Schedule("ikid")
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.GreaterOrEqual(t, runs.count("ikid"), 2)
+	assert.Less(t, runs.count("ikid"), 10)
+}
+
+func TestCrlSchedulerSingleFlight(t *testing.T) {
+	runs := &crlRuns{
+		runs:  map[string]int{},
+		block: make(chan struct{}),
+	}
+	c := newCrlScheduler(10*time.Millisecond, 50*time.Millisecond, runs.run)
+
+	c.Schedule("ikid")
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs.concurrent) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// requests while running are served by the next run
+	for i := 0; i < 3; i++ {
+		c.Schedule("ikid")
+		time.Sleep(30 * time.Millisecond)
+	}
+	close(runs.block)
+
+	require.Eventually(t, func() bool {
+		return runs.count("ikid") == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, runs.overlapped.Load())
+
+	t.Run("stop", func(t *testing.T) {
+		c.Schedule("pending")
+		assert.Equal(t, []string{"pending"}, c.Stop())
+
+		// ignored after stop
+		c.Schedule("ikid")
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, 2, runs.count("ikid"))
+		assert.Zero(t, runs.count("pending"))
+	})
+}
@@ -0,0 +1,166 @@
+package ca
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"maps"
+	"math/big"
+	"strconv"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/pkg/crlbuilder"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/csr"
+	"github.com/pkg/errors"
+)
+
+// crlShards returns the number of CRL shards of the issuer,
+// or 0 if the CRL is not partitioned
+func (s *Service) crlShards(issuer *authority.Issuer) uint32 {
+	if s.cfg == nil || issuer.CrlURL() == "" {
+		return 0
+	}
+	return s.cfg.CRLShardsCount(issuer.Label())
+}
+
+// crlShardExtension assigns a random shard to the certificate,
+// and returns CRL DP extension with the URL of the shard.
+// CA and OCSP responder certificates are not assigned to a shard,
+// as well as certificates of profiles that do not allow CRL DP extension.
+func (s *Service) crlShardExtension(issuer *authority.Issuer, profile *authority.CertProfile) (uint32, *csr.X509Extension, error) {
+	shards := s.crlShards(issuer)
+	if shards == 0 ||
+		profile.CAConstraint.IsCA ||
+		profile.OCSPNoCheck ||
+		!profile.IsAllowedExtention(csr.OID(oidExtensionCRLDistributionPoints)) {
+		return 0, nil, nil
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(shards)))
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	shard := uint32(n.Uint64()) + 1
+
+	ext, err := crlDistributionPointsExtension(certpublisher.ShardCRLName(issuer.CrlURL(), shard))
+	if err != nil {
+		return 0, nil, err
+	}
+	return shard, &csr.X509Extension{
+		ID:    csr.OID(ext.Id),
+		Value: hex.EncodeToString(ext.Value),
+	}, nil
+}
+
+// withCrlShard returns metadata of the certificate with the assigned shard,
+// the shard provided by the requester is ignored
+func withCrlShard(meta map[string]string, shard uint32) map[string]string {
+	if _, ok := meta[model.MetadataCrlShard]; !ok && shard == 0 {
+		return meta
+	}
+	meta = maps.Clone(meta)
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	delete(meta, model.MetadataCrlShard)
+	if shard > 0 {
+		meta[model.MetadataCrlShard] = strconv.FormatUint(uint64(shard), 10)
+	}
+	return meta
+}
+
+// createShardCRLs creates and registers CRLs of all shards of the issuer,
+// the CRLs are built in one pass over the revoked certificates.
+// The entries of each shard are spooled to a temporary file by its builder,
+// so the memory used per shard is bounded by the encoding buffers,
+// at the cost of a temporary file per shard while the CRLs are built.
+// Each shard has own sequence of CRL numbers.
+// The certificates without a shard are included in the complete CRL only.
+func (s *Service) createShardCRLs(ctx context.Context, issuer *authority.Issuer) ([]*pb.Crl, error) {
+	shards := s.crlShards(issuer)
+	if shards == 0 {
+		return nil, nil
+	}
+
+	bundle := issuer.Bundle()
+	now := time.Now().UTC()
+	expiryTime := now.Add(issuer.CrlExpiry())
+
+	builders := make([]*crlbuilder.Builder, shards+1)
+	numbers := make([]uint64, shards+1)
+	defer func() {
+		for _, b := range builders {
+			if b != nil {
+				_ = b.Close()
+			}
+		}
+	}()
+	for shard := uint32(1); shard <= shards; shard++ {
+		number, err := s.db.NextShardCrlNumber(ctx, issuer.SubjectKID(), shard)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get CRL number")
+		}
+
+		idp, err := issuingDistributionPointExtension(certpublisher.ShardCRLName(issuer.CrlURL(), shard))
+		if err != nil {
+			return nil, err
+		}
+		template := &x509.RevocationList{
+			Number:          new(big.Int).SetUint64(number),
+			ThisUpdate:      now,
+			NextUpdate:      expiryTime,
+			ExtraExtensions: []pkix.Extension{idp},
+		}
+
+		builders[shard], err = crlbuilder.New(template, bundle.Cert, issuer.Signer())
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to create CRL shard %d", shard)
+		}
+		numbers[shard] = number
+	}
+
+	err := s.forEachRevoked(ctx, issuer, func(ri *model.RevokedCertificate) error {
+		shard := ri.Certificate.CrlShard()
+		if shard == 0 || shard > shards {
+			return nil
+		}
+		return addRevocationEntry(builders[shard], ri)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.Crl, 0, shards)
+	for shard := uint32(1); shard <= shards; shard++ {
+		crlPem, err := builders[shard].FinishPEM()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to create CRL shard %d", shard)
+		}
+		// remove the spooled entries of the shard
+		_ = builders[shard].Close()
+		builders[shard] = nil
+
+		mcrl, err := s.db.RegisterShardCrl(ctx, &model.Crl{
+			IKID:       issuer.SubjectKID(),
+			ThisUpdate: xdb.Time(now),
+			NextUpdate: xdb.Time(expiryTime),
+			Issuer:     bundle.Subject.String(),
+			Pem:        crlPem,
+			CrlNumber:  numbers[shard],
+			Shard:      shard,
+		})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to register CRL shard %d", shard)
+		}
+		list = append(list, mcrl.ToDTO())
+	}
+
+	return list, nil
+}
@@ -0,0 +1,161 @@
+package ca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrlShardExtension(t *testing.T) {
+	issuer := newTestIssuer(t)
+	crlURL := "http://localhost/v1/crl/" + issuer.SubjectKID()
+
+	profile := &authority.CertProfile{
+		Usage:  []string{"signing", "server auth"},
+		Expiry: csr.Duration(time.Hour),
+	}
+	issuer.AddProfile("server", profile)
+
+	s := &Service{
+		cfg: &config.Configuration{
+			CRLShards: map[string]uint32{"crl": 4},
+		},
+	}
+
+	shard, ext, err := s.crlShardExtension(issuer, profile)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+	assert.True(t, shard >= 1 && shard <= 4)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "sharded"},
+	}, key)
+	require.NoError(t, err)
+
+	crt, _, err := issuer.Sign(csr.SignRequest{
+		Request:    string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})),
+		Profile:    "server",
+		Extensions: []csr.X509Extension{*ext},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{fmt.Sprintf("%s/%d", crlURL, shard)}, crt.CRLDistributionPoints)
+
+	t.Run("not sharded", func(t *testing.T) {
+		ca := &authority.CertProfile{CAConstraint: authority.CAConstraint{IsCA: true}}
+		restricted := &authority.CertProfile{AllowedExtensions: []csr.OID{{1, 2, 3}}}
+		for _, p := range []*authority.CertProfile{ca, restricted} {
+			shard, ext, err := s.crlShardExtension(issuer, p)
+			require.NoError(t, err)
+			assert.Zero(t, shard)
+			assert.Nil(t, ext)
+		}
+
+		shard, ext, err := (&Service{cfg: &config.Configuration{}}).crlShardExtension(issuer, profile)
+		require.NoError(t, err)
+		assert.Zero(t, shard)
+		assert.Nil(t, ext)
+	})
+}
+
+func TestWithCrlShard(t *testing.T) {
+	assert.Nil(t, withCrlShard(nil, 0))
+	assert.Equal(t, map[string]string{model.MetadataCrlShard: "2"}, withCrlShard(nil, 2))
+
+	meta := map[string]string{"requester": "test", model.MetadataCrlShard: "5"}
+	assert.Equal(t, map[string]string{"requester": "test"}, withCrlShard(meta, 0))
+	assert.Equal(t, map[string]string{"requester": "test", model.MetadataCrlShard: "1"}, withCrlShard(meta, 1))
+	// the request is not modified
+	assert.Equal(t, "5", meta[model.MetadataCrlShard])
+}
+
+func TestCreateShardCRLs(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	crlURL := "http://localhost/v1/crl/" + issuer.SubjectKID()
+
+	sharded := func(id uint64, shard string) *model.RevokedCertificate {
+		r := revokedCert(id, time.Now().Add(-time.Hour))
+		r.Certificate.Metadata = map[string]string{model.MetadataCrlShard: shard}
+		return r
+	}
+
+	db := &mockCrlDB{
+		numbers: map[string]uint64{},
+		revoked: model.RevokedCertificates{
+			revokedCert(1, time.Now().Add(-time.Hour)),
+			sharded(2, "1"),
+			sharded(3, "2"),
+			sharded(4, "2"),
+			// the number of shards was decreased
+			sharded(5, "3"),
+		},
+	}
+	s := &Service{
+		db:  db,
+		cfg: &config.Configuration{},
+	}
+
+	list, err := s.createShardCRLs(ctx, issuer)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	s.cfg.CRLShards = map[string]uint32{"crl": 2}
+	list, err = s.createShardCRLs(ctx, issuer)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Len(t, db.shards, 2)
+
+	for i, expected := range [][]int64{{2}, {3, 4}} {
+		crl := list[i]
+		shard := uint32(i + 1)
+		assert.Equal(t, shard, crl.Shard)
+		// each shard has own sequence
+		assert.Equal(t, uint64(1), crl.CrlNumber)
+		assert.Zero(t, crl.BaseNumber)
+
+		rl := parseCrl(t, crl.Pem)
+		require.NoError(t, rl.CheckSignatureFrom(issuer.Bundle().Cert))
+		assert.Equal(t, int64(1), rl.Number.Int64())
+
+		var serials []int64
+		for _, e := range rl.RevokedCertificateEntries {
+			serials = append(serials, e.SerialNumber.Int64())
+		}
+		assert.Equal(t, expected, serials)
+
+		idp := findExtension(rl.Extensions, oidExtensionIssuingDistributionPoint)
+		require.NotNil(t, idp)
+		assert.True(t, idp.Critical)
+		assert.Contains(t, string(idp.Value), fmt.Sprintf("%s/%d", crlURL, shard))
+		assert.Nil(t, findExtension(rl.Extensions, oidExtensionFreshestCRL))
+	}
+
+	// the complete CRL includes all certificates
+	base, err := s.createGenericCRL(ctx, issuer)
+	require.NoError(t, err)
+	assert.Len(t, parseCrl(t, base.Pem).RevokedCertificateEntries, 5)
+	assert.Equal(t, uint64(1), base.CrlNumber)
+
+	list, err = s.createShardCRLs(ctx, issuer)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, crl := range list {
+		assert.Equal(t, uint64(2), crl.CrlNumber)
+	}
+}
@@ -2,21 +2,23 @@ package ca
 
 import (
 	"context"
-	"crypto/rand"
+	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/pem"
 	"math/big"
+	"sync"
 	"time"
 
+	"github.com/effective-security/porto/xhttp/correlation"
 	"github.com/effective-security/porto/xhttp/httperror"
 	pb "github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/pkg/crlbuilder"
 	"github.com/effective-security/trusty/pkg/metricskey"
 	"github.com/effective-security/xdb"
 	"github.com/effective-security/xlog"
 	"github.com/effective-security/xpki/authority"
 	"github.com/pkg/errors"
-	"golang.org/x/crypto/ocsp"
 	"google.golang.org/grpc/codes"
 )
 
@@ -44,21 +46,62 @@ func (s *Service) RevokeCertificate(ctx context.Context, in *pb.RevokeCertificat
 		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid parameter")
 	}
 
-	revoked, err := s.db.RevokeCertificate(ctx, crt, time.Now().UTC(), int(in.Reason))
+	revoked, err := s.revokeCertificate(ctx, crt, in.Reason)
 	if err != nil {
 		return nil, httperror.WrapWithCtx(ctx, err, "unable to revoke certificate")
 	}
 
-	metricskey.CACertRevoked.IncrCounter(1, crt.IKID)
-
-	s.publishCrlInBackground(crt.IKID)
-
 	res := &pb.RevokedCertificateResponse{
 		Revoked: revoked.ToDTO(),
 	}
 	return res, nil
 }
 
+// revokeCertificate revokes the certificate,
+// and schedules the update of its revocation status.
+// The key of the certificate revoked with KeyCompromise reason is blocked.
+func (s *Service) revokeCertificate(ctx context.Context, crt *model.Certificate, reason pb.Reason) (*model.RevokedCertificate, error) {
+	if reason == pb.Reason_KEY_COMPROMISE {
+		// the key is blocked before revocation, so the request can be retried on failure
+		if err := s.blockKey(ctx, crt); err != nil {
+			return nil, errors.WithMessage(err, "unable to block key")
+		}
+	}
+	revoked, err := s.db.RevokeCertificate(ctx, crt, time.Now().UTC(), int(reason))
+	if err != nil {
+		return nil, err
+	}
+
+	metricskey.CACertRevoked.IncrCounter(1, crt.IKID)
+
+	// external certificates are tracked only, and not included in CRL
+	if !crt.External {
+		s.refreshRevokedOCSP(ctx, revoked)
+		s.notifyRevoked(ctx, crt)
+		s.scheduleCrl(ctx, crt.IKID)
+	}
+	return revoked, nil
+}
+
+// RevocationSubscriber is implemented by the services in the same process,
+// that are notified when a certificate is revoked,
+// for example to remove the cached revocation status
+type RevocationSubscriber interface {
+	OnCertificateRevoked(ctx context.Context, ikid, serial string)
+}
+
+// notifyRevoked notifies the discovered subscribers about the revoked certificate
+func (s *Service) notifyRevoked(ctx context.Context, crt *model.Certificate) {
+	if s.server == nil || s.server.Discovery() == nil {
+		return
+	}
+	var sub RevocationSubscriber
+	_ = s.server.Discovery().ForEach(&sub, func(string) error {
+		sub.OnCertificateRevoked(ctx, crt.IKID, crt.SerialNumber)
+		return nil
+	})
+}
+
 // PublishCrls returns published CRLs
 func (s *Service) PublishCrls(ctx context.Context, req *pb.PublishCrlsRequest) (*pb.CrlsResponse, error) {
 	return s.publishCrl(ctx, req.IKID)
@@ -66,6 +109,31 @@ func (s *Service) PublishCrls(ctx context.Context, req *pb.PublishCrlsRequest) (
 
 // GetCRL returns the CRL
 func (s *Service) GetCRL(ctx context.Context, in *pb.GetCrlRequest) (*pb.CrlResponse, error) {
+	if in.Delta {
+		crl, err := s.db.GetDeltaCrl(ctx, in.IKID)
+		if err != nil {
+			if xdb.IsNotFoundError(err) {
+				return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "delta CRL not found")
+			}
+			return nil, httperror.WrapWithCtx(ctx, err, "unable to get delta CRL")
+		}
+		return &pb.CrlResponse{
+			Crl: crl.ToDTO(),
+		}, nil
+	}
+	if in.Shard > 0 {
+		crl, err := s.db.GetShardCrl(ctx, in.IKID, in.Shard)
+		if err != nil {
+			if xdb.IsNotFoundError(err) {
+				return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "CRL shard not found")
+			}
+			return nil, httperror.WrapWithCtx(ctx, err, "unable to get CRL shard")
+		}
+		return &pb.CrlResponse{
+			Crl: crl.ToDTO(),
+		}, nil
+	}
+
 	crl, err := s.db.GetCrl(ctx, in.IKID)
 	if err == nil {
 		return &pb.CrlResponse{
@@ -92,92 +160,111 @@ func (s *Service) GetCRL(ctx context.Context, in *pb.GetCrlRequest) (*pb.CrlResp
 	return res, nil
 }
 
-// SignOCSP returns OCSP response
-func (s *Service) SignOCSP(ctx context.Context, in *pb.OCSPRequest) (*pb.OCSPResponse, error) {
-	ocspRequest, err := ocsp.ParseRequest(in.Der)
-	if err != nil ||
-		ocspRequest.SerialNumber == nil {
-		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid request")
-	}
+// deltaOverlap specifies the period before the complete CRL issuance,
+// which revocations are included in delta CRL as well
+const deltaOverlap = time.Minute
 
-	var ica *authority.Issuer
-	if len(ocspRequest.IssuerKeyHash) > 0 {
-		ica, err = s.ca.GetIssuerByKeyHash(ocspRequest.HashAlgorithm, ocspRequest.IssuerKeyHash)
-	} else if len(ocspRequest.IssuerNameHash) > 0 {
-		ica, err = s.ca.GetIssuerByNameHash(ocspRequest.HashAlgorithm, ocspRequest.IssuerNameHash)
-	} else {
-		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "issuer not specified")
-	}
+func (s *Service) deltaCRLEnabled() bool {
+	return s.cfg != nil && s.cfg.DeltaCRL.Enabled()
+}
 
-	if err != nil {
-		return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found")
-	}
+// crlBatchSize specifies the number of revoked certificates read from DB at a time,
+// the CRL is encoded as the entries are read to keep the memory bounded
+const crlBatchSize = 1000
 
-	serial := ocspRequest.SerialNumber.String()
+// forEachRevoked calls the function for each revoked certificate of the issuer,
+// the external certificates are skipped
+func (s *Service) forEachRevoked(ctx context.Context, issuer *authority.Issuer, fn func(ri *model.RevokedCertificate) error) error {
+	last := uint64(0)
+	for {
+		revokedInfoList, err := s.db.ListRevokedCertificates(ctx, issuer.SubjectKID(), crlBatchSize, last)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(revokedInfoList) == 0 {
+			break
+		}
 
-	req := &authority.OCSPSignRequest{
-		SerialNumber: ocspRequest.SerialNumber,
-		Status:       authority.OCSPStatusGood,
-		IssuerHash:   ocspRequest.HashAlgorithm,
+		for _, ri := range revokedInfoList {
+			last = ri.Certificate.ID
+			if ri.Certificate.External {
+				continue
+			}
+			if err = fn(ri); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
 
-	ikid := ica.Bundle().IssuerID
-	ri, err := s.db.GetRevokedCertificateByIKIDAndSerial(ctx, ikid, serial)
-	if err != nil && !xdb.IsNotFoundError(err) {
-		return nil, httperror.WrapWithCtx(ctx, err, "unable to get revoked certificate")
+// addRevocationEntry adds CRL entry of the revoked certificate
+func addRevocationEntry(b *crlbuilder.Builder, ri *model.RevokedCertificate) error {
+	sn, ok := new(big.Int).SetString(ri.Certificate.SerialNumber, 10)
+	if !ok {
+		return errors.Errorf("invalid serial number: %s", ri.Certificate.SerialNumber)
 	}
-
-	if ri != nil {
-		req.Status = authority.OCSPStatusRevoked
-		req.Reason = ocsp.Unspecified
-		req.RevokedAt = ri.RevokedAt.UTC()
+	err := b.Add(sn, ri.RevokedAt.UTC(), ri.Reason)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to add CRL entry: %s", ri.Certificate.SerialNumber)
 	}
+	return nil
+}
 
-	logger.ContextKV(ctx, xlog.TRACE, "ikid", ikid, "serial", serial, "status", req.Status)
-
-	der, err := ica.SignOCSP(req)
+// buildCRL creates CRL of the issuer in PEM format,
+// with the certificates revoked since the specified time
+func (s *Service) buildCRL(ctx context.Context, issuer *authority.Issuer, template *x509.RevocationList, since time.Time) (string, error) {
+	b, err := crlbuilder.New(template, issuer.Bundle().Cert, issuer.Signer())
 	if err != nil {
-		return nil, httperror.WrapWithCtx(ctx, err, "unable to sign OCSP")
+		return "", err
 	}
+	defer b.Close()
 
-	metricskey.CAOcspSigned.IncrCounter(1, ikid, req.Status)
-
-	return &pb.OCSPResponse{Der: der}, nil
+	err = s.forEachRevoked(ctx, issuer, func(ri *model.RevokedCertificate) error {
+		if ri.RevokedAt.UTC().Before(since) {
+			return nil
+		}
+		return addRevocationEntry(b, ri)
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.FinishPEM()
 }
 
+// createGenericCRL creates and registers complete CRL of the issuer
 func (s *Service) createGenericCRL(ctx context.Context, issuer *authority.Issuer) (*pb.Crl, error) {
 	bundle := issuer.Bundle()
 	now := time.Now().UTC()
 	expiryTime := now.Add(issuer.CrlExpiry())
 
-	revokedCerts := make([]pkix.RevokedCertificate, 0, 1000)
-	last := uint64(0)
-	for {
-		revokedInfoList, err := s.db.ListRevokedCertificates(ctx, issuer.SubjectKID(), 0, last)
+	number, err := s.db.NextCrlNumber(ctx, issuer.SubjectKID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get CRL number")
+	}
+
+	template := &x509.RevocationList{
+		Number:     new(big.Int).SetUint64(number),
+		ThisUpdate: now,
+		NextUpdate: expiryTime,
+	}
+	if crlURL := issuer.CrlURL(); crlURL != "" {
+		idp, err := issuingDistributionPointExtension(crlURL)
 		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		if len(revokedInfoList) == 0 {
-			break
+			return nil, err
 		}
+		template.ExtraExtensions = append(template.ExtraExtensions, idp)
 
-		for _, ri := range revokedInfoList {
-			sn := new(big.Int)
-			sn, _ = sn.SetString(ri.Certificate.SerialNumber, 10)
-			revokedCerts = append(revokedCerts, pkix.RevokedCertificate{
-				SerialNumber:   sn,
-				RevocationTime: ri.RevokedAt.UTC(),
-			})
-			last = ri.Certificate.ID
+		if s.deltaCRLEnabled() {
+			freshest, err := freshestCRLExtension(certpublisher.DeltaCRLName(crlURL))
+			if err != nil {
+				return nil, err
+			}
+			template.ExtraExtensions = append(template.ExtraExtensions, freshest)
 		}
 	}
 
-	// TODO:
-	// template := &x509.RevocationList{
-	// }
-	// crlBytes, err := x509.CreateRevocationList(rand.Reader, revokedCerts, bundle.Cert,issuer.Signer(),)
-
-	crlBytes, err := bundle.Cert.CreateCRL(rand.Reader, issuer.Signer(), revokedCerts, now, expiryTime)
+	crlPem, err := s.buildCRL(ctx, issuer, template, time.Time{})
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to create CRL")
 	}
@@ -187,7 +274,8 @@ func (s *Service) createGenericCRL(ctx context.Context, issuer *authority.Issuer
 		ThisUpdate: xdb.Time(now),
 		NextUpdate: xdb.Time(expiryTime),
 		Issuer:     bundle.Subject.String(),
-		Pem:        string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlBytes})),
+		Pem:        crlPem,
+		CrlNumber:  number,
 	})
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed to register CRL")
@@ -196,6 +284,65 @@ func (s *Service) createGenericCRL(ctx context.Context, issuer *authority.Issuer
 	return mcrl.ToDTO(), nil
 }
 
+// createDeltaCRL creates and registers delta CRL of the issuer,
+// with revocations since the current complete CRL
+func (s *Service) createDeltaCRL(ctx context.Context, issuer *authority.Issuer) (*pb.Crl, error) {
+	base, err := s.db.GetCrl(ctx, issuer.SubjectKID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to find complete CRL")
+	}
+	if base.CrlNumber == 0 {
+		return nil, errors.Errorf("complete CRL has no CRL number: %s", issuer.SubjectKID())
+	}
+
+	bundle := issuer.Bundle()
+	now := time.Now().UTC()
+	expiryTime := now.Add(s.cfg.DeltaCRL.GetExpiry())
+
+	number, err := s.db.NextCrlNumber(ctx, issuer.SubjectKID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get CRL number")
+	}
+
+	indicator, err := deltaCRLIndicatorExtension(base.CrlNumber)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.RevocationList{
+		Number:          new(big.Int).SetUint64(number),
+		ThisUpdate:      now,
+		NextUpdate:      expiryTime,
+		ExtraExtensions: []pkix.Extension{indicator},
+	}
+	if crlURL := issuer.CrlURL(); crlURL != "" {
+		idp, err := issuingDistributionPointExtension(certpublisher.DeltaCRLName(crlURL))
+		if err != nil {
+			return nil, err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, idp)
+	}
+
+	crlPem, err := s.buildCRL(ctx, issuer, template, base.ThisUpdate.UTC().Add(-deltaOverlap))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create delta CRL")
+	}
+
+	mcrl, err := s.db.RegisterDeltaCrl(ctx, &model.Crl{
+		IKID:       issuer.SubjectKID(),
+		ThisUpdate: xdb.Time(now),
+		NextUpdate: xdb.Time(expiryTime),
+		Issuer:     bundle.Subject.String(),
+		Pem:        crlPem,
+		CrlNumber:  number,
+		BaseNumber: base.CrlNumber,
+	})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to register delta CRL")
+	}
+
+	return mcrl.ToDTO(), nil
+}
+
 func (s *Service) publishCrl(ctx context.Context, ikID string) (*pb.CrlsResponse, error) {
 	logger.ContextKV(ctx, xlog.INFO,
 		"ikid", ikID)
@@ -203,30 +350,215 @@ func (s *Service) publishCrl(ctx context.Context, ikID string) (*pb.CrlsResponse
 	res := &pb.CrlsResponse{}
 	for _, issuer := range s.ca.Issuers() {
 		if ikID == "" || ikID == issuer.SubjectKID() {
-			crl, err := s.createGenericCRL(ctx, issuer)
+			crls, err := s.publishIssuerCrl(ctx, issuer)
+			res.Crls = append(res.Crls, crls...)
 			if err != nil {
 				return res, httperror.WrapWithCtx(ctx, err, "failed to generate CRLs")
 			}
-			res.Crls = append(res.Crls, crl)
-
-			_, err = s.publisher.PublishCRL(ctx, crl)
-			if err != nil {
-				return res, httperror.WrapWithCtx(ctx, err, "failed to publish CRLs")
-			}
 		}
 	}
 
 	return res, nil
 }
 
-func (s *Service) publishCrlInBackground(ikID string) {
-	go func() {
-		_, err := s.publishCrl(context.Background(), ikID)
+// publishIssuerCrl creates and publishes CRLs of the issuer,
+// the CRLs failed to publish are retried by the outbox worker
+func (s *Service) publishIssuerCrl(ctx context.Context, issuer *authority.Issuer) ([]*pb.Crl, error) {
+	crls, err := s.generateCrls(ctx, issuer)
+	if err != nil {
+		return crls, err
+	}
+	if s.publisher == nil {
+		return crls, nil
+	}
+
+	for _, crl := range crls {
+		_, err = s.publisher.PublishCRL(ctx, crl)
+		if err != nil {
+			break
+		}
+		metricskey.CACrlPublished.IncrCounter(1)
+	}
+	if err != nil {
+		// the CRL is registered, retry to publish by the outbox worker
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to publish CRL",
+			"ikid", issuer.SubjectKID(),
+			"err", err.Error())
+		metricskey.CAFailPublishCrl.IncrCounter(1, issuer.Label())
+
+		s.enqueuePublish(ctx, &model.PublishJob{
+			Kind: model.PublishKindCrl,
+			IKID: issuer.SubjectKID(),
+		})
+	}
+
+	if s.deltaCRLEnabled() {
+		s.enqueuePublish(ctx, &model.PublishJob{
+			Kind: model.PublishKindDeltaCrl,
+			IKID: issuer.SubjectKID(),
+		})
+	}
+	return crls, nil
+}
+
+// generateCrls creates and registers the complete CRL and CRL shards of the issuer,
+// and the delta CRL based on the new complete CRL, if enabled.
+// The returned list does not include the delta CRL.
+func (s *Service) generateCrls(ctx context.Context, issuer *authority.Issuer) ([]*pb.Crl, error) {
+	defer s.lockCrl(issuer.SubjectKID())()
+
+	crl, err := s.createGenericCRL(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	crls := []*pb.Crl{crl}
+
+	shards, err := s.createShardCRLs(ctx, issuer)
+	crls = append(crls, shards...)
+	if err != nil {
+		return crls, err
+	}
+
+	if s.deltaCRLEnabled() {
+		_, err = s.createDeltaCRL(ctx, issuer)
+		if err != nil {
+			return crls, err
+		}
+	}
+	return crls, nil
+}
+
+// scheduleCrl requests CRL regeneration of the issuer after revocation,
+// the requests are coalesced by the scheduler
+func (s *Service) scheduleCrl(ctx context.Context, ikid string) {
+	if s.crlScheduler == nil {
+		s.regenerateCrl(ikid)
+		return
+	}
+	s.crlScheduler.Schedule(ikid)
+}
+
+// regenerateCrl creates CRLs of the issuer in DB,
+// and enqueues them to publish by the outbox worker.
+// On failure the regeneration is scheduled again.
+func (s *Service) regenerateCrl(ikid string) {
+	ctx := correlation.WithID(context.Background())
+
+	issuer, err := s.ca.GetIssuerByKeyID(ikid)
+	if err == nil {
+		_, err = s.generateCrls(ctx, issuer)
+	}
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to regenerate CRL",
+			"ikid", ikid,
+			"err", err.Error())
+		metricskey.CAFailPublishCrl.IncrCounter(1, s.issuerLabel(ikid))
+
+		if issuer != nil && s.crlScheduler != nil {
+			s.crlScheduler.Schedule(ikid)
+		}
+		return
+	}
+
+	s.enqueuePublish(ctx, &model.PublishJob{
+		Kind: model.PublishKindCrl,
+		IKID: ikid,
+	})
+	if s.deltaCRLEnabled() {
+		s.enqueuePublish(ctx, &model.PublishJob{
+			Kind: model.PublishKindDeltaCrl,
+			IKID: ikid,
+		})
+	}
+}
+
+// regenerateDeltaCrl creates delta CRL of the issuer in DB,
+// and enqueues it to publish by the outbox worker
+func (s *Service) regenerateDeltaCrl(ikid string) {
+	ctx := correlation.WithID(context.Background())
+
+	issuer, err := s.ca.GetIssuerByKeyID(ikid)
+	if err == nil {
+		unlock := s.lockCrl(ikid)
+		_, err = s.createDeltaCRL(ctx, issuer)
+		unlock()
+	}
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to regenerate delta CRL",
+			"ikid", ikid,
+			"err", err.Error())
+		metricskey.CAFailPublishCrl.IncrCounter(1, s.issuerLabel(ikid))
+		return
+	}
+
+	s.enqueuePublish(ctx, &model.PublishJob{
+		Kind: model.PublishKindDeltaCrl,
+		IKID: ikid,
+	})
+}
+
+// publishStoredCrls publishes the latest CRL and CRL shards of the issuer,
+// the CRLs are generated again if not found or expired
+func (s *Service) publishStoredCrls(ctx context.Context, ikid string) (string, error) {
+	issuer, err := s.ca.GetIssuerByKeyID(ikid)
+	if err != nil {
+		return "", errors.WithMessagef(err, "unable to find issuer %s", ikid)
+	}
+
+	var crls []*pb.Crl
+	crl, err := s.db.GetCrl(ctx, ikid)
+	if err != nil || crl.NextUpdate.UTC().Before(time.Now()) {
+		crls, err = s.generateCrls(ctx, issuer)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		crls = append(crls, crl.ToDTO())
+		for shard := uint32(1); shard <= s.crlShards(issuer); shard++ {
+			m, err := s.db.GetShardCrl(ctx, ikid, shard)
+			if err != nil {
+				return "", errors.WithMessagef(err, "unable to find CRL shard %d", shard)
+			}
+			crls = append(crls, m.ToDTO())
+		}
+	}
+
+	location := ""
+	for i, crl := range crls {
+		l, err := s.publisher.PublishCRL(ctx, crl)
 		if err != nil {
-			logger.KV(xlog.ERROR,
-				"ikid", ikID,
-				"err", err.Error(),
-			)
+			return "", err
+		}
+		if i == 0 {
+			location = l
 		}
-	}()
+		metricskey.CACrlPublished.IncrCounter(1)
+	}
+	return location, nil
+}
+
+// publishStoredDeltaCrl publishes the latest delta CRL of the issuer
+func (s *Service) publishStoredDeltaCrl(ctx context.Context, ikid string) (string, error) {
+	crl, err := s.db.GetDeltaCrl(ctx, ikid)
+	if err != nil {
+		return "", errors.WithMessagef(err, "unable to find delta CRL %s", ikid)
+	}
+	location, err := s.publisher.PublishCRL(ctx, crl.ToDTO())
+	if err != nil {
+		return "", err
+	}
+	metricskey.CACrlPublished.IncrCounter(1)
+	return location, nil
+}
+
+// lockCrl serializes CRL regeneration of the issuer,
+// and returns the unlock function
+func (s *Service) lockCrl(ikid string) func() {
+	v, _ := s.crlLocks.LoadOrStore(ikid, new(sync.Mutex))
+	m := v.(*sync.Mutex)
+	m.Lock()
+	return m.Unlock
 }
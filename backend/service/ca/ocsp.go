@@ -0,0 +1,195 @@
+package ca
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/trusty/pkg/ocsputil"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+// ocspMaxCertIDs specifies the maximum number of certificates in OCSP request
+const ocspMaxCertIDs = 100
+
+// SignOCSP returns OCSP response.
+// The request may contain multiple certificates of the same issuer,
+// the nonce of the request is echoed if enabled for the issuer.
+// If IKID is specified, then the request for certificates
+// of other issuers is rejected.
+func (s *Service) SignOCSP(ctx context.Context, in *pb.OCSPRequest) (*pb.OCSPResponse, error) {
+	ocspRequest, err := ocsputil.ParseRequest(in.Der)
+	if err != nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid request")
+	}
+	if len(ocspRequest.CertIDs) > ocspMaxCertIDs {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "too many certificates in request")
+	}
+
+	var ica *authority.Issuer
+	for _, id := range ocspRequest.CertIDs {
+		issuer, err := s.ocspIssuer(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if ica == nil {
+			ica = issuer
+		} else if ica != issuer {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.PermissionDenied, "certificates of different issuers")
+		}
+	}
+	if in.IKID != "" && in.IKID != ica.SubjectKID() {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.PermissionDenied, "issuer does not match the responder")
+	}
+
+	nonce := ocspRequest.Nonce != nil && s.ocspNonceEnabled(ica.Label())
+	if len(ocspRequest.CertIDs) == 1 && !nonce {
+		return s.signOCSP(ctx, ica, ocspRequest.CertIDs[0])
+	}
+
+	thisUpdate := time.Now().UTC().Truncate(time.Minute)
+	nextUpdate := thisUpdate.Add(ica.OcspExpiry())
+	template := &ocsputil.Response{}
+	for _, id := range ocspRequest.CertIDs {
+		ri, err := s.ocspRevocation(ctx, ica, id)
+		if err != nil {
+			return nil, err
+		}
+		req := ocspSignRequest(id.SerialNumber, ri)
+		template.Responses = append(template.Responses, ocspSingleResponse(id, req, thisUpdate, nextUpdate))
+		metricskey.CAOcspSigned.IncrCounter(1, ica.SubjectKID(), req.Status)
+	}
+	if nonce {
+		template.Extensions = []pkix.Extension{*ocspRequest.Nonce}
+	}
+
+	der, err := s.createOCSP(ctx, ica, template)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to sign OCSP")
+	}
+
+	logger.ContextKV(ctx, xlog.TRACE,
+		"ikid", ica.SubjectKID(),
+		"certs", len(template.Responses),
+		"nonce", nonce)
+
+	return &pb.OCSPResponse{Der: der}, nil
+}
+
+// signOCSP returns OCSP response for a single certificate,
+// pre-signed if available
+func (s *Service) signOCSP(ctx context.Context, ica *authority.Issuer, id *ocsputil.CertID) (*pb.OCSPResponse, error) {
+	serial := id.SerialNumber.String()
+	ikid := ica.SubjectKID()
+
+	if der := s.storedOCSP(ctx, ica, serial, id.HashAlgorithm); der != nil {
+		return &pb.OCSPResponse{Der: der}, nil
+	}
+
+	ri, err := s.ocspRevocation(ctx, ica, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req := ocspSignRequest(id.SerialNumber, ri)
+
+	logger.ContextKV(ctx, xlog.TRACE, "ikid", ikid, "serial", serial, "status", req.Status)
+
+	thisUpdate := time.Now().UTC().Truncate(time.Minute)
+	der, err := s.createOCSP(ctx, ica, &ocsputil.Response{
+		Responses: []*ocsputil.SingleResponse{
+			ocspSingleResponse(id, req, thisUpdate, thisUpdate.Add(ica.OcspExpiry())),
+		},
+	})
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to sign OCSP")
+	}
+
+	metricskey.CAOcspSigned.IncrCounter(1, ikid, req.Status)
+
+	return &pb.OCSPResponse{Der: der}, nil
+}
+
+// createOCSP returns OCSP response signed by the responder of the issuer
+func (s *Service) createOCSP(ctx context.Context, ica *authority.Issuer, template *ocsputil.Response) ([]byte, error) {
+	responder, err := s.ocspResponder(ctx, ica)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get OCSP responder")
+	}
+	return ocsputil.CreateResponse(template, ica.Bundle().Cert, responder.Cert, responder.Signer)
+}
+
+// ocspSingleResponse returns the single response of OCSP sign request
+func ocspSingleResponse(id *ocsputil.CertID, req *authority.OCSPSignRequest, thisUpdate, nextUpdate time.Time) *ocsputil.SingleResponse {
+	return &ocsputil.SingleResponse{
+		CertID:           id,
+		Status:           authority.OCSPStatusCode[req.Status],
+		RevokedAt:        req.RevokedAt,
+		RevocationReason: req.Reason,
+		ThisUpdate:       thisUpdate,
+		NextUpdate:       nextUpdate,
+	}
+}
+
+// ocspIssuer returns the issuer of the certificate
+func (s *Service) ocspIssuer(ctx context.Context, id *ocsputil.CertID) (*authority.Issuer, error) {
+	var ica *authority.Issuer
+	var err error
+	if len(id.IssuerKeyHash) > 0 {
+		ica, err = s.ca.GetIssuerByKeyHash(id.HashAlgorithm, id.IssuerKeyHash)
+	} else if len(id.IssuerNameHash) > 0 {
+		ica, err = s.ca.GetIssuerByNameHash(id.HashAlgorithm, id.IssuerNameHash)
+	} else {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "issuer not specified")
+	}
+	if err != nil {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "issuer not found")
+	}
+	return ica, nil
+}
+
+// ocspRevocation returns the revocation info of the certificate,
+// or nil if the certificate is not revoked.
+// The revoked certificates purged by the retention task
+// are found by their tombstones.
+func (s *Service) ocspRevocation(ctx context.Context, ica *authority.Issuer, id *ocsputil.CertID) (*model.RevokedCertificate, error) {
+	ikid := ica.SubjectKID()
+	serial := id.SerialNumber.String()
+	ri, err := s.db.GetRevokedCertificateByIKIDAndSerial(ctx, ikid, serial)
+	if err != nil {
+		if xdb.IsNotFoundError(err) {
+			return s.ocspTombstone(ctx, ikid, serial)
+		}
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to get revoked certificate")
+	}
+	// external certificates are tracked only
+	if ri.Certificate.External {
+		return nil, nil
+	}
+	return ri, nil
+}
+
+// ocspTombstone returns the revocation info of the purged revoked certificate,
+// or nil if the certificate was not revoked
+func (s *Service) ocspTombstone(ctx context.Context, ikid, serial string) (*model.RevokedCertificate, error) {
+	ts, err := s.db.GetRevokedTombstone(ctx, ikid, serial)
+	if err != nil {
+		if xdb.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to get revoked tombstone")
+	}
+	return ts.ToRevokedCertificate(), nil
+}
+
+func (s *Service) ocspNonceEnabled(label string) bool {
+	return s.cfg != nil && s.cfg.OCSPNonceEnabled(label)
+}
@@ -0,0 +1,288 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/certutil"
+	"github.com/effective-security/xpki/cryptoprov"
+	"github.com/effective-security/xpki/csr"
+	"github.com/pkg/errors"
+)
+
+// ocspResponderCN specifies the common name of delegated OCSP responders
+const ocspResponderCN = "OCSP Responder"
+
+func (s *Service) ocspResponderEnabled() bool {
+	return s.cfg != nil && s.cfg.OCSPResponder.Enabled()
+}
+
+// ocspResponder returns OCSP responder of the issuer.
+// If delegated responders are enabled, then the responder is loaded from DB,
+// or issued if missing or due to renew.
+// Otherwise the responder of the issuer is returned.
+// The issuer's key is not used to sign OCSP responses,
+// if delegated responders are enabled but the issuer does not support the profile.
+func (s *Service) ocspResponder(ctx context.Context, issuer *authority.Issuer) (*authority.OCSPResponder, error) {
+	if !s.ocspResponderEnabled() {
+		return issuer.CreateDelegatedOCSPSigner()
+	}
+
+	ikid := issuer.SubjectKID()
+	if issuer.Profile(s.cfg.OCSPResponder.Profile) == nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "issuer does not support OCSP responder profile",
+			"issuer", issuer.Label(),
+			"profile", s.cfg.OCSPResponder.Profile)
+		metricskey.CAFailOcspResponder.IncrCounter(1, ikid)
+		return nil, errors.Errorf("issuer %q does not support OCSP responder profile %q",
+			issuer.Label(), s.cfg.OCSPResponder.Profile)
+	}
+
+	now := time.Now()
+	if r := s.cachedOcspResponder(issuer, now); r != nil {
+		return r, nil
+	}
+
+	unlock := s.lockOcspResponder(ikid)
+	defer unlock()
+
+	// the responder may be renewed by a concurrent request
+	if r := s.cachedOcspResponder(issuer, now); r != nil {
+		return r, nil
+	}
+
+	r, err := s.loadOcspResponder(ctx, issuer, now)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		r, err = s.issueOcspResponder(ctx, issuer, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.ocspResponders.Store(ikid, r)
+	return r, nil
+}
+
+// cachedOcspResponder returns the responder of the issuer,
+// if it's loaded and not due to renew
+func (s *Service) cachedOcspResponder(issuer *authority.Issuer, now time.Time) *authority.OCSPResponder {
+	v, ok := s.ocspResponders.Load(issuer.SubjectKID())
+	if !ok {
+		return nil
+	}
+	r := v.(*authority.OCSPResponder)
+	if s.ocspResponderDue(issuer, r.Cert, now) {
+		return nil
+	}
+	return r
+}
+
+// ocspResponderDue returns true if the responder certificate needs to be renewed,
+// so the responses signed at the specified time do not outlive it
+func (s *Service) ocspResponderDue(issuer *authority.Issuer, crt *x509.Certificate, now time.Time) bool {
+	validity := crt.NotAfter.Sub(crt.NotBefore)
+	renew := s.cfg.OCSPResponder.GetRenewBefore(validity, issuer.OcspExpiry())
+	return now.Before(crt.NotBefore) || !now.Add(renew).Before(crt.NotAfter)
+}
+
+// loadOcspResponder returns the latest responder of the issuer from DB,
+// or nil if it's not found or due to renew
+func (s *Service) loadOcspResponder(ctx context.Context, issuer *authority.Issuer, now time.Time) (*authority.OCSPResponder, error) {
+	m, err := s.db.GetOcspResponder(ctx, issuer.SubjectKID())
+	if err != nil {
+		if xdb.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, errors.WithMessage(err, "unable to get OCSP responder")
+	}
+
+	crt, err := certutil.ParseFromPEM([]byte(m.Pem))
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to parse OCSP responder")
+	}
+	if s.ocspResponderDue(issuer, crt, now) {
+		return nil, nil
+	}
+
+	signer, err := s.ca.Crypto().NewSignerFromPEM([]byte(m.Key))
+	if err != nil {
+		// the key may be removed from the crypto provider,
+		// or not persisted by the in-memory provider
+		logger.ContextKV(ctx, xlog.WARNING,
+			"status", "unable to load OCSP responder key",
+			"ikid", m.IKID,
+			"serial", m.SerialNumber,
+			"err", err.Error())
+		return nil, nil
+	}
+
+	logger.ContextKV(ctx, xlog.DEBUG,
+		"status", "loaded OCSP responder",
+		"ikid", m.IKID,
+		"serial", m.SerialNumber,
+		"expires", crt.NotAfter.UTC().Format(time.RFC3339))
+
+	return &authority.OCSPResponder{
+		Signer: signer,
+		Cert:   crt,
+	}, nil
+}
+
+// issueOcspResponder creates the responder key in the crypto provider,
+// and issues the responder certificate with the configured profile
+func (s *Service) issueOcspResponder(ctx context.Context, issuer *authority.Issuer, now time.Time) (*authority.OCSPResponder, error) {
+	profileName := s.cfg.OCSPResponder.Profile
+	profile := issuer.Profile(profileName)
+	if !profile.OCSPNoCheck || !slices.Contains(profile.Usage, "ocsp signing") {
+		return nil, errors.Errorf("profile %q must have OCSP signing usage and OCSP no check extension", profileName)
+	}
+
+	prov, err := s.ocspResponderCrypto()
+	if err != nil {
+		return nil, err
+	}
+
+	keyLabel := fmt.Sprintf("%s-ocsp-%s-%s", s.cfg.ClusterName, issuer.Label(), now.UTC().Format("20060102-150405"))
+	cp := csr.NewProvider(prov)
+	req := cp.NewSigningCertificateRequest(keyLabel, "ECDSA", 256, ocspResponderCN, nil, nil)
+
+	// the key is not exported, only its URI is stored
+	csrPEM, key, keyID, err := cp.GenerateKeyAndRequest(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to create OCSP responder key")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("OCSP responder key of %T type does not support crypto.Signer", key)
+	}
+	keyURI, err := providerKeyURI(prov, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	crt, pem, err := issuer.Sign(csr.SignRequest{
+		Request: string(csrPEM),
+		Profile: profileName,
+		Subject: &csr.X509Subject{CommonName: ocspResponderCN},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to sign OCSP responder")
+	}
+	if !slices.Contains(crt.ExtKeyUsage, x509.ExtKeyUsageOCSPSigning) {
+		return nil, errors.Errorf("OCSP responder of profile %q has no OCSP signing usage", profileName)
+	}
+	if s.ocspResponderDue(issuer, crt, now) {
+		return nil, errors.Errorf("validity of profile %q is too short for OCSP expiry: %s",
+			profileName, issuer.OcspExpiry())
+	}
+
+	m, err := s.db.RegisterOcspResponder(ctx, &model.OcspResponder{
+		ID:           s.db.NextID().UInt64(),
+		IKID:         issuer.SubjectKID(),
+		SerialNumber: crt.SerialNumber.String(),
+		NotBefore:    xdb.Time(crt.NotBefore),
+		NotAfter:     xdb.Time(crt.NotAfter),
+		Pem:          string(pem),
+		Key:          keyURI,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to register OCSP responder")
+	}
+
+	metricskey.CAOcspResponderIssued.IncrCounter(1, m.IKID)
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "issued OCSP responder",
+		"ikid", m.IKID,
+		"serial", m.SerialNumber,
+		"key", keyLabel,
+		"expires", crt.NotAfter.UTC().Format(time.RFC3339))
+
+	count, err := s.db.PurgeOcspResponders(ctx, now)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to purge OCSP responders",
+			"err", err.Error())
+	} else {
+		metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForResponders)
+	}
+
+	return &authority.OCSPResponder{
+		Signer: signer,
+		Cert:   crt,
+	}, nil
+}
+
+// ocspResponderCrypto returns the crypto provider for responder keys
+func (s *Service) ocspResponderCrypto() (cryptoprov.Provider, error) {
+	cfg := &s.cfg.OCSPResponder
+	if cfg.CryptoProvider != "" {
+		prov, err := s.ca.Crypto().ByManufacturer(cfg.CryptoProvider, cfg.CryptoModel)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "unable to load crypto provider %s, model %q",
+				cfg.CryptoProvider, cfg.CryptoModel)
+		}
+		return prov, nil
+	}
+	return s.ca.Crypto().Default(), nil
+}
+
+// providerKeyURI returns the URI of the key in the crypto provider.
+// The URI is built for the providers that do not return it on export,
+// such as in-memory provider, which keys are found only in the same process.
+func providerKeyURI(prov cryptoprov.Provider, keyID string) (string, error) {
+	uri, _, err := prov.ExportKey(keyID)
+	if err != nil {
+		return "", errors.WithMessage(err, "unable to find OCSP responder key")
+	}
+	if strings.HasPrefix(uri, "pkcs11:") {
+		return uri, nil
+	}
+
+	serial := "0"
+	if sp, ok := prov.(interface{ Serial() string }); ok && sp.Serial() != "" {
+		serial = sp.Serial()
+	}
+	return fmt.Sprintf("pkcs11:manufacturer=%s;model=%s;serial=%s;id=%s;type=private",
+		prov.Manufacturer(), prov.Model(), serial, keyID), nil
+}
+
+// lockOcspResponder serializes issuance of the responder of the issuer,
+// and returns the unlock function
+func (s *Service) lockOcspResponder(ikid string) func() {
+	v, _ := s.ocspResponderLocks.LoadOrStore(ikid, new(sync.Mutex))
+	m := v.(*sync.Mutex)
+	m.Lock()
+	return m.Unlock
+}
+
+// loadOcspResponders loads or issues the responders of all issuers,
+// so the first OCSP requests are not delayed
+func (s *Service) loadOcspResponders(ctx context.Context) {
+	if !s.ocspResponderEnabled() {
+		return
+	}
+	for _, issuer := range s.ca.Issuers() {
+		if _, err := s.ocspResponder(ctx, issuer); err != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to load OCSP responder",
+				"ikid", issuer.SubjectKID(),
+				"err", err.Error())
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/ocsputil"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/cryptoprov"
+	"github.com/effective-security/xpki/cryptoprov/inmemcrypto"
+	"github.com/effective-security/xpki/csr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type mockResponderDB struct {
+	mockOcspDB
+
+	lastID     uint64
+	responders []*model.OcspResponder
+}
+
+func (m *mockResponderDB) NextID() xdb.ID {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.lastID++
+	return xdb.NewID(m.lastID)
+}
+
+func (m *mockResponderDB) RegisterOcspResponder(_ context.Context, r *model.OcspResponder) (*model.OcspResponder, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.responders = append(m.responders, r)
+	return r, nil
+}
+
+func (m *mockResponderDB) GetOcspResponder(_ context.Context, ikid string) (*model.OcspResponder, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var latest *model.OcspResponder
+	for _, r := range m.responders {
+		if r.IKID == ikid && (latest == nil || r.NotAfter.UTC().After(latest.NotAfter.UTC())) {
+			latest = r
+		}
+	}
+	if latest == nil {
+		return nil, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func (m *mockResponderDB) PurgeOcspResponders(_ context.Context, before time.Time) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var list []*model.OcspResponder
+	for _, r := range m.responders {
+		if !r.NotAfter.UTC().Before(before) {
+			list = append(list, r)
+		}
+	}
+	count := int64(len(m.responders) - len(list))
+	m.responders = list
+	return count, nil
+}
+
+func (m *mockResponderDB) count() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.responders)
+}
+
+func newResponderTestService(t *testing.T, issuer *authority.Issuer, db *mockResponderDB) *Service {
+	cp, err := cryptoprov.New(inmemcrypto.NewProvider(), nil)
+	require.NoError(t, err)
+	a, err := authority.NewAuthority(&authority.Config{Authority: &authority.CAConfig{}}, cp)
+	require.NoError(t, err)
+	require.NoError(t, a.AddIssuer(issuer))
+
+	return &Service{
+		ca: a,
+		db: db,
+		// the CRLs are not regenerated after revocations by the tests
+		crlScheduler: newCrlScheduler(time.Hour, time.Hour, func(string) {}),
+		cfg: &config.Configuration{
+			ClusterName: "test",
+			OCSPResponder: config.OCSPResponder{
+				Profile: "ocsp",
+			},
+		},
+	}
+}
+
+func TestOcspResponder(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("ocsp", &authority.CertProfile{
+		Usage:       []string{"ocsp signing"},
+		OCSPNoCheck: true,
+		Expiry:      csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockResponderDB{
+		mockOcspDB: mockOcspDB{responses: map[uint64]*model.OcspResponse{}},
+	}
+	s := newResponderTestService(t, issuer, db)
+
+	r, err := s.ocspResponder(ctx, issuer)
+	require.NoError(t, err)
+	assert.Equal(t, ocspResponderCN, r.Cert.Subject.CommonName)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, r.Cert.ExtKeyUsage)
+	assert.NotNil(t, findExtension(r.Cert.Extensions, oidOCSPNoCheck))
+	assert.NoError(t, r.Cert.CheckSignatureFrom(issuer.Bundle().Cert))
+	assert.Equal(t, 1, db.count())
+
+	r2, err := s.ocspResponder(ctx, issuer)
+	require.NoError(t, err)
+	assert.Same(t, r, r2)
+
+	t.Run("sign", func(t *testing.T) {
+		der, err := ocsputil.CreateRequest(ocspCertIDs(t, issuer, 1), nil)
+		require.NoError(t, err)
+
+		res, err := s.SignOCSP(ctx, &pb.OCSPRequest{Der: der, IKID: issuer.SubjectKID()})
+		require.NoError(t, err)
+
+		resp, err := ocsp.ParseResponse(res.Der, issuer.Bundle().Cert)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Good, resp.Status)
+		require.NotNil(t, resp.Certificate)
+		assert.Equal(t, r.Cert.Raw, resp.Certificate.Raw)
+		assert.False(t, resp.NextUpdate.After(r.Cert.NotAfter))
+
+		_, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der, IKID: "other"})
+		require.Error(t, err)
+		assert.Equal(t, "unauthorized: issuer does not match the responder", err.Error())
+		assert.Equal(t, 401, httperror.Status(err))
+	})
+
+	t.Run("presign", func(t *testing.T) {
+		m, err := s.presignOCSP(ctx, issuer, ocspCert(issuer, 7, time.Now().Add(time.Hour)), nil)
+		require.NoError(t, err)
+
+		resp, err := ocsp.ParseResponse(m.Der, issuer.Bundle().Cert)
+		require.NoError(t, err)
+		assert.Equal(t, r.Cert.Raw, resp.Certificate.Raw)
+		assert.Equal(t, crypto.SHA1, resp.IssuerHash)
+	})
+
+	t.Run("load", func(t *testing.T) {
+		// only the key URI is stored
+		assert.True(t, strings.HasPrefix(db.responders[0].Key, "pkcs11:manufacturer=inmem;"), db.responders[0].Key)
+
+		// the responder is loaded from DB after restart,
+		// with the key from the same crypto provider
+		s2 := newResponderTestService(t, issuer, db)
+		s2.ca = s.ca
+		r3, err := s2.ocspResponder(ctx, issuer)
+		require.NoError(t, err)
+		assert.Equal(t, r.Cert.Raw, r3.Cert.Raw)
+		assert.Equal(t, r.Signer.Public(), r3.Signer.Public())
+		assert.Equal(t, 1, db.count())
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		// the responder expiring before OCSP expiry is renewed
+		crt, pem, err := issuer.Sign(csr.SignRequest{
+			Request:  string(csrForTest(t)),
+			Profile:  "ocsp",
+			NotAfter: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		expiring := &model.OcspResponder{
+			ID:           100,
+			IKID:         issuer.SubjectKID(),
+			SerialNumber: crt.SerialNumber.String(),
+			NotBefore:    xdb.Time(crt.NotBefore),
+			NotAfter:     xdb.Time(crt.NotAfter),
+			Pem:          string(pem),
+			Key:          "pkcs11:manufacturer=inmem;model=inmem;serial=0;id=not-loaded;type=private",
+		}
+		expired := *expiring
+		expired.ID = 101
+		expired.NotAfter = xdb.FromNow(-time.Minute)
+		db.responders = []*model.OcspResponder{expiring, &expired}
+		s.ocspResponders.Store(issuer.SubjectKID(), &authority.OCSPResponder{Signer: r.Signer, Cert: crt})
+
+		r4, err := s.ocspResponder(ctx, issuer)
+		require.NoError(t, err)
+		assert.NotEqual(t, r.Cert.SerialNumber, r4.Cert.SerialNumber)
+		assert.NotEqual(t, crt.SerialNumber, r4.Cert.SerialNumber)
+		// the expired responder is purged
+		assert.Equal(t, 2, db.count())
+	})
+
+	t.Run("key_not_found", func(t *testing.T) {
+		// the responder is issued again, if its key is not found
+		s2 := newResponderTestService(t, issuer, db)
+		count := db.count()
+		r5, err := s2.ocspResponder(ctx, issuer)
+		require.NoError(t, err)
+		assert.NoError(t, r5.Cert.CheckSignatureFrom(issuer.Bundle().Cert))
+		assert.Equal(t, count+1, db.count())
+	})
+
+	t.Run("invalid_profile", func(t *testing.T) {
+		other := newTestIssuerWithLabel(t, "other", []byte{3, 3, 3, 3})
+		other.AddProfile("ocsp", &authority.CertProfile{
+			Usage:  []string{"ocsp signing"},
+			Expiry: csr.Duration(24 * time.Hour),
+		})
+		s := newResponderTestService(t, other, &mockResponderDB{})
+		_, err := s.ocspResponder(ctx, other)
+		assert.EqualError(t, err, `profile "ocsp" must have OCSP signing usage and OCSP no check extension`)
+	})
+
+	t.Run("short_validity", func(t *testing.T) {
+		other := newTestIssuerWithLabel(t, "other", []byte{3, 3, 3, 3})
+		other.AddProfile("ocsp", &authority.CertProfile{
+			Usage:       []string{"ocsp signing"},
+			OCSPNoCheck: true,
+			Expiry:      csr.Duration(4 * time.Hour),
+		})
+		s := newResponderTestService(t, other, &mockResponderDB{})
+		_, err := s.ocspResponder(ctx, other)
+		assert.EqualError(t, err, `validity of profile "ocsp" is too short for OCSP expiry: 8h0m0s`)
+	})
+
+	t.Run("no_profile", func(t *testing.T) {
+		// the issuer's key is not used if the responder is enabled
+		other := newTestIssuerWithLabel(t, "other", []byte{3, 3, 3, 3})
+		s := newResponderTestService(t, other, &mockResponderDB{})
+		_, err := s.ocspResponder(ctx, other)
+		assert.EqualError(t, err, `issuer "other" does not support OCSP responder profile "ocsp"`)
+	})
+}
+
+func csrForTest(t *testing.T) []byte {
+	cp := csr.NewProvider(inmemcrypto.NewProvider())
+	req := cp.NewSigningCertificateRequest("test", "ECDSA", 256, ocspResponderCN, nil, nil)
+	csrPEM, _, _, _, err := cp.CreateRequestAndExportKey(req)
+	require.NoError(t, err)
+	return csrPEM
+}
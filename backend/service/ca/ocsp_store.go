@@ -0,0 +1,305 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"math/big"
+	"runtime/debug"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/correlation"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/trusty/pkg/ocsputil"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+)
+
+// ocspBatchSize specifies the number of certificates read from DB at a time
+// by the OCSP updater
+const ocspBatchSize = 1000
+
+// ocspStoreHash specifies the hash of CertID in pre-signed responses,
+// as required by the lightweight OCSP profile
+const ocspStoreHash = crypto.SHA1
+
+func (s *Service) ocspStoreEnabled() bool {
+	return s.cfg != nil && s.cfg.OCSPStore.Enabled()
+}
+
+// ocspSignRequest returns OCSP sign request for the certificate,
+// the status is revoked if the revocation info is provided
+func ocspSignRequest(serial *big.Int, ri *model.RevokedCertificate) *authority.OCSPSignRequest {
+	req := &authority.OCSPSignRequest{
+		SerialNumber: serial,
+		Status:       authority.OCSPStatusGood,
+	}
+	if ri != nil {
+		req.Status = authority.OCSPStatusRevoked
+		req.Reason = ri.Reason
+		req.RevokedAt = ri.RevokedAt.UTC()
+	}
+	return req
+}
+
+// storedOCSP returns pre-signed OCSP response, if it's available
+// for the request with the specified hash
+func (s *Service) storedOCSP(ctx context.Context, issuer *authority.Issuer, serial string, hash crypto.Hash) []byte {
+	if !s.ocspStoreEnabled() || hash != ocspStoreHash {
+		return nil
+	}
+
+	r, err := s.db.GetOcspResponse(ctx, issuer.SubjectKID(), serial)
+	if err != nil {
+		if !xdb.IsNotFoundError(err) {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to get OCSP response",
+				"ikid", issuer.SubjectKID(),
+				"serial", serial,
+				"err", err.Error())
+		}
+		return nil
+	}
+	if !r.IsValid(time.Now()) {
+		return nil
+	}
+
+	metricskey.CAOcspFromStore.IncrCounter(1, issuer.SubjectKID(), r.Status)
+	return r.Der
+}
+
+// presignOCSP signs and registers OCSP response of the certificate
+func (s *Service) presignOCSP(ctx context.Context, issuer *authority.Issuer, crt *model.Certificate, ri *model.RevokedCertificate) (*model.OcspResponse, error) {
+	serial, ok := new(big.Int).SetString(crt.SerialNumber, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid serial number: %s", crt.SerialNumber)
+	}
+
+	expiry := issuer.OcspExpiry()
+	if expiry <= 0 {
+		return nil, errors.Errorf("OCSP expiry is not configured: %s", issuer.Label())
+	}
+
+	thisUpdate := time.Now().UTC().Truncate(time.Minute)
+	nextUpdate := thisUpdate.Add(expiry)
+
+	req := ocspSignRequest(serial, ri)
+	id := &ocsputil.CertID{
+		HashAlgorithm:  ocspStoreHash,
+		IssuerNameHash: issuer.NameHash(ocspStoreHash),
+		IssuerKeyHash:  issuer.KeyHash(ocspStoreHash),
+		SerialNumber:   serial,
+	}
+
+	der, err := s.createOCSP(ctx, issuer, &ocsputil.Response{
+		Responses: []*ocsputil.SingleResponse{
+			ocspSingleResponse(id, req, thisUpdate, nextUpdate),
+		},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to sign OCSP")
+	}
+
+	r, err := s.db.RegisterOcspResponse(ctx, &model.OcspResponse{
+		ID:           crt.ID,
+		IKID:         issuer.SubjectKID(),
+		SerialNumber: crt.SerialNumber,
+		Status:       req.Status,
+		ThisUpdate:   xdb.Time(thisUpdate),
+		NextUpdate:   xdb.Time(nextUpdate),
+		Der:          der,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to register OCSP response")
+	}
+
+	metricskey.CAOcspPresigned.IncrCounter(1, issuer.SubjectKID(), req.Status)
+	return r, nil
+}
+
+// refreshRevokedOCSP signs OCSP response of the revoked certificate.
+// If failed, the stored response is marked as revoked and expired,
+// so the responder falls back to signing per request,
+// and the updater does not replace it by the good one.
+func (s *Service) refreshRevokedOCSP(ctx context.Context, ri *model.RevokedCertificate) {
+	if !s.ocspStoreEnabled() {
+		return
+	}
+
+	issuer, err := s.ca.GetIssuerByKeyID(ri.Certificate.IKID)
+	if err == nil {
+		_, err = s.presignOCSP(ctx, issuer, &ri.Certificate, ri)
+	}
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to sign OCSP for revoked certificate",
+			"ikid", ri.Certificate.IKID,
+			"serial", ri.Certificate.SerialNumber,
+			"err", err.Error())
+
+		err = s.db.MarkOcspResponseRevoked(ctx, ri.Certificate.ID, ri.Certificate.IKID, ri.Certificate.SerialNumber, time.Now())
+		if err != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to mark OCSP response",
+				"id", ri.Certificate.ID,
+				"err", err.Error())
+		}
+	}
+}
+
+// startOcspUpdater starts the OCSP updater, if enabled
+func (s *Service) startOcspUpdater() {
+	if !s.ocspStoreEnabled() {
+		return
+	}
+	s.ocspOnce.Do(func() {
+		go s.ocspUpdater(s.cfg.OCSPStore.Interval)
+	})
+}
+
+func (s *Service) ocspUpdater(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.KV(xlog.INFO, "status", "started OCSP updater", "interval", interval.String())
+	for {
+		s.updateOcspResponses(correlation.WithID(context.Background()))
+
+		select {
+		case <-s.stopCh:
+			logger.KV(xlog.INFO, "status", "stopped OCSP updater")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateOcspResponses signs OCSP responses of all issuers,
+// and returns the number of signed responses
+func (s *Service) updateOcspResponses(ctx context.Context) int {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"reason", "recover",
+				"err", r,
+				"stack", debug.Stack())
+		}
+	}()
+
+	now := time.Now().UTC()
+	total := 0
+	for _, issuer := range s.ca.Issuers() {
+		count, err := s.updateIssuerOcsp(ctx, issuer, now)
+		total += count
+		if err != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to update OCSP responses",
+				"ikid", issuer.SubjectKID(),
+				"signed", count,
+				"err", err.Error())
+			continue
+		}
+		logger.ContextKV(ctx, xlog.DEBUG,
+			"status", "updated OCSP responses",
+			"ikid", issuer.SubjectKID(),
+			"signed", count)
+	}
+
+	count, err := s.db.PurgeOcspResponses(ctx, now)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to purge OCSP responses",
+			"err", err.Error())
+	} else {
+		metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForOcsp)
+	}
+	return total
+}
+
+// updateIssuerOcsp signs OCSP responses of unexpired certificates of the issuer,
+// which are missing, have a different status, or are due to refresh
+func (s *Service) updateIssuerOcsp(ctx context.Context, issuer *authority.Issuer, now time.Time) (int, error) {
+	due := now.Add(s.cfg.OCSPStore.GetRefreshBefore(issuer.OcspExpiry()))
+	ikid := issuer.SubjectKID()
+	signed := 0
+
+	// stale returns true if the response of the certificate needs to be signed
+	stale := func(stored map[uint64]*model.OcspResponse, crt *model.Certificate, status string) bool {
+		if crt.External || crt.NotAfter.UTC().Before(now) {
+			return false
+		}
+		r := stored[crt.ID]
+		return r == nil || r.Status != status || r.NextUpdate.UTC().Before(due)
+	}
+
+	last := uint64(0)
+	for {
+		if s.stopped() {
+			return signed, nil
+		}
+		list, err := s.db.ListCertificates(ctx, ikid, ocspBatchSize, last)
+		if err != nil {
+			return signed, errors.WithStack(err)
+		}
+		if len(list) == 0 {
+			break
+		}
+		stored, err := s.db.ListOcspResponses(ctx, ikid, list[0].ID, list[len(list)-1].ID)
+		if err != nil {
+			return signed, errors.WithStack(err)
+		}
+		byID := stored.ByID()
+		for _, crt := range list {
+			last = crt.ID
+			if stale(byID, crt, model.OcspStatusGood) {
+				if _, err = s.presignOCSP(ctx, issuer, crt, nil); err != nil {
+					return signed, err
+				}
+				signed++
+			}
+		}
+	}
+
+	last = 0
+	for {
+		if s.stopped() {
+			return signed, nil
+		}
+		list, err := s.db.ListRevokedCertificates(ctx, ikid, ocspBatchSize, last)
+		if err != nil {
+			return signed, errors.WithStack(err)
+		}
+		if len(list) == 0 {
+			break
+		}
+		stored, err := s.db.ListOcspResponses(ctx, ikid, list[0].Certificate.ID, list[len(list)-1].Certificate.ID)
+		if err != nil {
+			return signed, errors.WithStack(err)
+		}
+		byID := stored.ByID()
+		for _, ri := range list {
+			last = ri.Certificate.ID
+			if stale(byID, &ri.Certificate, model.OcspStatusRevoked) {
+				if _, err = s.presignOCSP(ctx, issuer, &ri.Certificate, ri); err != nil {
+					return signed, err
+				}
+				signed++
+			}
+		}
+	}
+
+	return signed, nil
+}
+
+// stopped returns true if the service is closed
+func (s *Service) stopped() bool {
+	select {
+	case <-s.stopCh:
+		return true
+	default:
+		return false
+	}
+}
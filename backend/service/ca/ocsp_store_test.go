@@ -0,0 +1,316 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"database/sql"
+	"math/big"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type mockOcspDB struct {
+	cadb.CaDb
+
+	lock       sync.Mutex
+	certs      model.Certificates
+	revoked    model.RevokedCertificates
+	tombstones []*model.RevokedTombstone
+	responses  map[uint64]*model.OcspResponse
+}
+
+func (m *mockOcspDB) ListCertificates(_ context.Context, _ string, limit int, afterID uint64) (model.Certificates, error) {
+	var list model.Certificates
+	for _, c := range m.certs {
+		if c.ID > afterID && len(list) < limit {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockOcspDB) ListRevokedCertificates(_ context.Context, _ string, limit int, afterID uint64) (model.RevokedCertificates, error) {
+	var list model.RevokedCertificates
+	for _, r := range m.revoked {
+		if r.Certificate.ID > afterID && len(list) < limit {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockOcspDB) GetRevokedCertificateByIKIDAndSerial(_ context.Context, ikid, serial string) (*model.RevokedCertificate, error) {
+	for _, r := range m.revoked {
+		if r.Certificate.IKID == ikid && r.Certificate.SerialNumber == serial {
+			return r, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockOcspDB) GetRevokedTombstone(_ context.Context, ikid, serial string) (*model.RevokedTombstone, error) {
+	for _, r := range m.tombstones {
+		if r.IKID == ikid && r.SerialNumber == serial {
+			return r, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockOcspDB) RegisterOcspResponse(_ context.Context, r *model.OcspResponse) (*model.OcspResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if old := m.responses[r.ID]; old != nil && old.Status == model.OcspStatusRevoked && r.Status != model.OcspStatusRevoked {
+		return old, nil
+	}
+	m.responses[r.ID] = r
+	return r, nil
+}
+
+func (m *mockOcspDB) GetOcspResponse(_ context.Context, ikid, serial string) (*model.OcspResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, r := range m.responses {
+		if r.IKID == ikid && r.SerialNumber == serial {
+			return r, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockOcspDB) ListOcspResponses(_ context.Context, ikid string, fromID, toID uint64) (model.OcspResponses, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var list model.OcspResponses
+	for _, r := range m.responses {
+		if r.IKID == ikid && r.ID >= fromID && r.ID <= toID {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockOcspDB) MarkOcspResponseRevoked(_ context.Context, id uint64, ikid, serial string, at time.Time) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	r := m.responses[id]
+	if r == nil {
+		r = &model.OcspResponse{ID: id, IKID: ikid, SerialNumber: serial}
+		m.responses[id] = r
+	}
+	r.Status = model.OcspStatusRevoked
+	r.ThisUpdate = xdb.Time(at)
+	r.NextUpdate = xdb.Time(at)
+	return nil
+}
+
+func (m *mockOcspDB) RemoveOcspResponse(_ context.Context, id uint64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.responses, id)
+	return nil
+}
+
+func (m *mockOcspDB) PurgeOcspResponses(_ context.Context, before time.Time) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	count := int64(0)
+	for id, r := range m.responses {
+		if r.NextUpdate.UTC().Before(before) {
+			delete(m.responses, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockOcspDB) response(id uint64) *model.OcspResponse {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.responses[id]
+}
+
+func newTestAuthority(t *testing.T, issuer *authority.Issuer) *authority.Authority {
+	a, err := authority.NewAuthority(&authority.Config{Authority: &authority.CAConfig{}}, nil)
+	require.NoError(t, err)
+	require.NoError(t, a.AddIssuer(issuer))
+	return a
+}
+
+func ocspCert(issuer *authority.Issuer, id uint64, notAfter time.Time) *model.Certificate {
+	return &model.Certificate{
+		ID:           id,
+		IKID:         issuer.SubjectKID(),
+		SerialNumber: strconv.FormatUint(id, 10),
+		NotAfter:     xdb.Time(notAfter),
+	}
+}
+
+func TestOcspStore(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	now := time.Now().UTC()
+
+	db := &mockOcspDB{
+		certs: model.Certificates{
+			ocspCert(issuer, 1, now.Add(time.Hour)),
+			ocspCert(issuer, 2, now.Add(-time.Hour)), // expired
+			ocspCert(issuer, 3, now.Add(time.Hour)),
+			ocspCert(issuer, 5, now.Add(time.Hour)),
+		},
+		responses: map[uint64]*model.OcspResponse{},
+	}
+	db.certs[3].External = true
+	revoked := &model.RevokedCertificate{
+		Certificate: *ocspCert(issuer, 4, now.Add(time.Hour)),
+		RevokedAt:   xdb.Time(now.Add(-time.Minute)),
+		Reason:      ocsp.KeyCompromise,
+	}
+	db.revoked = model.RevokedCertificates{revoked}
+
+	s := &Service{
+		db: db,
+		ca: newTestAuthority(t, issuer),
+		cfg: &config.Configuration{
+			OCSPStore: config.OCSPStore{Interval: time.Hour},
+		},
+	}
+
+	assert.Equal(t, 3, s.updateOcspResponses(ctx))
+	assert.Nil(t, db.response(2))
+	assert.Nil(t, db.response(5))
+
+	check := func(t *testing.T, der []byte, id uint64, status int) *ocsp.Response {
+		res, err := ocsp.ParseResponse(der, issuer.Bundle().Cert)
+		require.NoError(t, err)
+		assert.Equal(t, int64(id), res.SerialNumber.Int64())
+		assert.Equal(t, status, res.Status)
+		return res
+	}
+	check(t, db.response(1).Der, 1, ocsp.Good)
+	res := check(t, db.response(4).Der, 4, ocsp.Revoked)
+	assert.Equal(t, ocsp.KeyCompromise, res.RevocationReason)
+	assert.Equal(t, model.OcspStatusRevoked, db.response(4).Status)
+
+	// the valid responses are not signed again
+	assert.Equal(t, 0, s.updateOcspResponses(ctx))
+
+	t.Run("refresh", func(t *testing.T) {
+		r := db.response(3)
+		r.NextUpdate = xdb.Time(now.Add(time.Minute))
+		assert.Equal(t, 1, s.updateOcspResponses(ctx))
+		assert.True(t, db.response(3).NextUpdate.UTC().After(now.Add(time.Minute)))
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		ri := &model.RevokedCertificate{
+			Certificate: *db.certs[0],
+			RevokedAt:   xdb.Time(now),
+			Reason:      ocsp.Superseded,
+		}
+		s.refreshRevokedOCSP(ctx, ri)
+		check(t, db.response(1).Der, 1, ocsp.Revoked)
+
+		// the updater does not replace the revoked response
+		assert.Equal(t, 1, s.updateOcspResponses(ctx))
+		assert.Equal(t, model.OcspStatusRevoked, db.response(1).Status)
+		db.certs = db.certs[1:]
+		// ordered by ID
+		db.revoked = model.RevokedCertificates{ri, revoked}
+		assert.Equal(t, 0, s.updateOcspResponses(ctx))
+
+		// unknown issuer
+		ri = &model.RevokedCertificate{Certificate: *ocspCert(issuer, 3, now.Add(time.Hour))}
+		ri.Certificate.IKID = "unknown"
+		s.refreshRevokedOCSP(ctx, ri)
+		r := db.response(3)
+		require.NotNil(t, r)
+		assert.Equal(t, model.OcspStatusRevoked, r.Status)
+		assert.False(t, r.IsValid(time.Now()))
+
+		// the updater does not replace the marked response
+		good := *r
+		good.Status = model.OcspStatusGood
+		_, err := db.RegisterOcspResponse(ctx, &good)
+		require.NoError(t, err)
+		assert.Equal(t, model.OcspStatusRevoked, db.response(3).Status)
+
+		// not registered yet
+		ri = &model.RevokedCertificate{Certificate: *ocspCert(issuer, 6, now.Add(time.Hour))}
+		ri.Certificate.IKID = "unknown"
+		s.refreshRevokedOCSP(ctx, ri)
+		require.NotNil(t, db.response(6))
+		assert.Equal(t, model.OcspStatusRevoked, db.response(6).Status)
+		assert.Empty(t, db.response(6).Der)
+	})
+
+	t.Run("serve", func(t *testing.T) {
+		req := func(id uint64, hash crypto.Hash) *pb.OCSPRequest {
+			der, err := ocsp.CreateRequest(
+				&x509.Certificate{SerialNumber: new(big.Int).SetUint64(id)},
+				issuer.Bundle().Cert,
+				&ocsp.RequestOptions{Hash: hash})
+			require.NoError(t, err)
+			return &pb.OCSPRequest{Der: der}
+		}
+
+		res, err := s.SignOCSP(ctx, req(4, crypto.SHA1))
+		require.NoError(t, err)
+		assert.Equal(t, db.response(4).Der, res.Der)
+
+		// live signing for other hash
+		res, err = s.SignOCSP(ctx, req(4, crypto.SHA256))
+		require.NoError(t, err)
+		assert.NotEqual(t, db.response(4).Der, res.Der)
+		check(t, res.Der, 4, ocsp.Revoked)
+
+		// live signing for expired response
+		db.response(4).NextUpdate = xdb.Time(now.Add(-time.Second))
+		res, err = s.SignOCSP(ctx, req(4, crypto.SHA1))
+		require.NoError(t, err)
+		assert.NotEqual(t, db.response(4).Der, res.Der)
+		check(t, res.Der, 4, ocsp.Revoked)
+
+		// live signing for unknown response
+		res, err = s.SignOCSP(ctx, req(100, crypto.SHA1))
+		require.NoError(t, err)
+		check(t, res.Der, 100, ocsp.Good)
+	})
+}
+
+func TestOcspStoreDisabled(t *testing.T) {
+	s := &Service{cfg: &config.Configuration{}}
+	assert.False(t, s.ocspStoreEnabled())
+	assert.Nil(t, s.storedOCSP(context.Background(), nil, "1", crypto.SHA1))
+	// no-op
+	s.refreshRevokedOCSP(context.Background(), nil)
+	s.startOcspUpdater()
+}
+
+func TestOcspSignRequest(t *testing.T) {
+	req := ocspSignRequest(nil, nil)
+	assert.Equal(t, authority.OCSPStatusGood, req.Status)
+
+	at := time.Now()
+	req = ocspSignRequest(nil, &model.RevokedCertificate{RevokedAt: xdb.Time(at), Reason: ocsp.CACompromise})
+	assert.Equal(t, authority.OCSPStatusRevoked, req.Status)
+	assert.Equal(t, ocsp.CACompromise, req.Reason)
+	assert.Equal(t, at.UTC(), req.RevokedAt)
+
+	_, err := (&Service{}).presignOCSP(context.Background(), nil, &model.Certificate{SerialNumber: "x"}, nil)
+	assert.Equal(t, "invalid serial number: x", errors.Cause(err).Error())
+}
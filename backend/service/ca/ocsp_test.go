@@ -0,0 +1,217 @@
+package ca
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/ocsputil"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func ocspCertIDs(t *testing.T, issuer *authority.Issuer, serials ...int64) []*ocsputil.CertID {
+	var ids []*ocsputil.CertID
+	for _, serial := range serials {
+		der, err := ocsp.CreateRequest(
+			&x509.Certificate{SerialNumber: big.NewInt(serial)},
+			issuer.Bundle().Cert,
+			&ocsp.RequestOptions{Hash: crypto.SHA256})
+		require.NoError(t, err)
+		req, err := ocsputil.ParseRequest(der)
+		require.NoError(t, err)
+		ids = append(ids, req.CertIDs...)
+	}
+	return ids
+}
+
+func TestSignOCSPMultiple(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	other := newTestIssuerWithLabel(t, "other", []byte{3, 3, 3, 3})
+
+	a := newTestAuthority(t, issuer)
+	require.NoError(t, a.AddIssuer(other))
+
+	now := time.Now().UTC()
+	external := ocspCert(issuer, 3, now.Add(time.Hour))
+	external.External = true
+	s := &Service{
+		ca: a,
+		db: &mockOcspDB{
+			revoked: model.RevokedCertificates{
+				{
+					Certificate: *ocspCert(issuer, 2, now.Add(time.Hour)),
+					RevokedAt:   xdb.Time(now.Add(-time.Minute)),
+					Reason:      ocsp.KeyCompromise,
+				},
+				{
+					Certificate: *external,
+					RevokedAt:   xdb.Time(now.Add(-time.Minute)),
+				},
+			},
+			tombstones: []*model.RevokedTombstone{
+				{
+					IKID:         issuer.SubjectKID(),
+					SerialNumber: "4",
+					NotAfter:     xdb.Time(now.Add(-time.Hour)),
+					RevokedAt:    xdb.Time(now.Add(-2 * time.Hour)),
+					Reason:       ocsp.Superseded,
+				},
+			},
+		},
+		cfg: &config.Configuration{
+			OCSPNonce: []string{"crl"},
+		},
+	}
+
+	nonce := []byte("0123456789abcdef")
+
+	t.Run("multiple", func(t *testing.T) {
+		der, err := ocsputil.CreateRequest(ocspCertIDs(t, issuer, 1, 2, 3), nonce)
+		require.NoError(t, err)
+
+		res, err := s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.NoError(t, err)
+
+		r, err := ocsputil.ParseResponse(res.Der)
+		require.NoError(t, err)
+		assert.NoError(t, r.CheckSignatureFrom(issuer.Bundle().Cert))
+		require.NotNil(t, r.Nonce())
+		req, err := ocsputil.ParseRequest(der)
+		require.NoError(t, err)
+		assert.Equal(t, *req.Nonce, *r.Nonce())
+
+		require.Len(t, r.Responses, 3)
+		assert.Equal(t, ocsp.Good, r.Responses[0].Status)
+		assert.Equal(t, ocsp.Revoked, r.Responses[1].Status)
+		assert.Equal(t, ocsp.KeyCompromise, r.Responses[1].RevocationReason)
+		// external certificates are tracked only
+		assert.Equal(t, ocsp.Good, r.Responses[2].Status)
+		for i, single := range r.Responses {
+			assert.Equal(t, int64(i+1), single.CertID.SerialNumber.Int64())
+			assert.Equal(t, 8*time.Hour, single.NextUpdate.Sub(single.ThisUpdate))
+		}
+	})
+
+	t.Run("single_nonce", func(t *testing.T) {
+		der, err := ocsputil.CreateRequest(ocspCertIDs(t, issuer, 2), nonce)
+		require.NoError(t, err)
+
+		res, err := s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.NoError(t, err)
+
+		// compatible with x/crypto
+		x, err := ocsp.ParseResponse(res.Der, issuer.Bundle().Cert)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Revoked, x.Status)
+
+		r, err := ocsputil.ParseResponse(res.Der)
+		require.NoError(t, err)
+		assert.NotNil(t, r.Nonce())
+	})
+
+	t.Run("tombstone", func(t *testing.T) {
+		// purged revoked certificates stay revoked
+		der, err := ocsputil.CreateRequest(ocspCertIDs(t, issuer, 4), nil)
+		require.NoError(t, err)
+
+		res, err := s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.NoError(t, err)
+
+		x, err := ocsp.ParseResponse(res.Der, issuer.Bundle().Cert)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Revoked, x.Status)
+		assert.Equal(t, ocsp.Superseded, x.RevocationReason)
+		assert.Equal(t, now.Add(-2*time.Hour).Truncate(time.Second), x.RevokedAt)
+
+		der, err = ocsputil.CreateRequest(ocspCertIDs(t, issuer, 4, 5), nonce)
+		require.NoError(t, err)
+
+		res, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.NoError(t, err)
+
+		r, err := ocsputil.ParseResponse(res.Der)
+		require.NoError(t, err)
+		require.Len(t, r.Responses, 2)
+		assert.Equal(t, ocsp.Revoked, r.Responses[0].Status)
+		assert.Equal(t, ocsp.Good, r.Responses[1].Status)
+	})
+
+	t.Run("nonce_disabled", func(t *testing.T) {
+		der, err := ocsputil.CreateRequest(ocspCertIDs(t, other, 1, 2), nonce)
+		require.NoError(t, err)
+
+		res, err := s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.NoError(t, err)
+
+		r, err := ocsputil.ParseResponse(res.Der)
+		require.NoError(t, err)
+		assert.NoError(t, r.CheckSignatureFrom(other.Bundle().Cert))
+		assert.Nil(t, r.Nonce())
+		assert.Len(t, r.Responses, 2)
+
+		der, err = ocsputil.CreateRequest(ocspCertIDs(t, other, 1), nonce)
+		require.NoError(t, err)
+		res, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.NoError(t, err)
+		r, err = ocsputil.ParseResponse(res.Der)
+		require.NoError(t, err)
+		assert.Nil(t, r.Nonce())
+	})
+
+	t.Run("mixed_issuers", func(t *testing.T) {
+		ids := append(ocspCertIDs(t, issuer, 1), ocspCertIDs(t, other, 2)...)
+		der, err := ocsputil.CreateRequest(ids, nil)
+		require.NoError(t, err)
+
+		_, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "certificates of different issuers")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := s.SignOCSP(ctx, &pb.OCSPRequest{Der: []byte{1, 2, 3}})
+		require.Error(t, err)
+		assert.Equal(t, 400, httperror.Status(err))
+
+		var serials []int64
+		for i := 0; i <= ocspMaxCertIDs; i++ {
+			serials = append(serials, int64(i+1))
+		}
+		der, err := ocsputil.CreateRequest(ocspCertIDs(t, issuer, serials...), nil)
+		require.NoError(t, err)
+		_, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too many certificates in request")
+
+		der, err = ocsputil.CreateRequest([]*ocsputil.CertID{{
+			HashAlgorithm: crypto.SHA1,
+			IssuerKeyHash: []byte{1, 2, 3},
+			SerialNumber:  big.NewInt(1),
+		}}, nil)
+		require.NoError(t, err)
+		_, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.Error(t, err)
+		assert.Equal(t, 404, httperror.Status(err))
+
+		der, err = ocsputil.CreateRequest([]*ocsputil.CertID{{
+			HashAlgorithm: crypto.SHA1,
+			SerialNumber:  big.NewInt(1),
+		}}, nil)
+		require.NoError(t, err)
+		_, err = s.SignOCSP(ctx, &pb.OCSPRequest{Der: der})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "issuer not specified")
+	})
+}
@@ -0,0 +1,115 @@
+package ca
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/correlation"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+const (
+	// revocationInterval specifies the interval to poll the pending revocations
+	revocationInterval = time.Minute
+	// revocationBatch specifies the number of pending revocations to process at once
+	revocationBatch = 100
+)
+
+// startRevocationWorker starts the worker of pending revocations, only once
+func (s *Service) startRevocationWorker() {
+	s.revocationOnce.Do(func() {
+		go s.revocationWorker()
+	})
+}
+
+func (s *Service) revocationWorker() {
+	ticker := time.NewTicker(revocationInterval)
+	defer ticker.Stop()
+
+	logger.KV(xlog.INFO, "status", "started revocation worker")
+	for {
+		s.processPendingRevocations(correlation.WithID(context.Background()))
+
+		select {
+		case <-s.stopCh:
+			logger.KV(xlog.INFO, "status", "stopped revocation worker")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processPendingRevocations revokes the due certificates,
+// and returns the number of processed revocations.
+// The failed revocations are retried on the next interval.
+func (s *Service) processPendingRevocations(ctx context.Context) int {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"reason", "recover",
+				"err", r,
+				"stack", debug.Stack())
+		}
+	}()
+
+	total := 0
+	for {
+		list, err := s.db.ListPendingRevocations(ctx, time.Now(), revocationBatch)
+		if err != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to list pending revocations",
+				"err", err.Error())
+			break
+		}
+
+		failed := 0
+		for _, r := range list {
+			err = s.revokePending(ctx, r)
+			if err == nil {
+				err = s.db.RemovePendingRevocation(ctx, r.ID)
+			}
+			if err != nil {
+				failed++
+				logger.ContextKV(ctx, xlog.ERROR,
+					"status", "failed to process pending revocation",
+					"id", r.ID,
+					"certificate_id", r.CertificateID,
+					"err", err.Error())
+			}
+		}
+
+		total += len(list)
+		if failed > 0 || len(list) < revocationBatch {
+			break
+		}
+	}
+	return total
+}
+
+// revokePending revokes the certificate of the pending revocation
+func (s *Service) revokePending(ctx context.Context, r *model.PendingRevocation) error {
+	crt, err := s.db.GetCertificate(ctx, r.CertificateID)
+	if err != nil {
+		if xdb.IsNotFoundError(err) {
+			// already revoked
+			return nil
+		}
+		return errors.WithMessagef(err, "unable to find certificate %d", r.CertificateID)
+	}
+
+	_, err = s.revokeCertificate(ctx, crt, pb.Reason(r.Reason))
+	if err != nil {
+		return errors.WithMessagef(err, "unable to revoke certificate %d", crt.ID)
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "revoked pending certificate",
+		"id", crt.ID,
+		"reason", pb.Reason(r.Reason).String())
+	return nil
+}
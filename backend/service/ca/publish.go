@@ -0,0 +1,316 @@
+package ca
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/correlation"
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// publishInterval specifies the interval to poll the publish outbox
+	publishInterval = 10 * time.Second
+	// publishBatch specifies the number of jobs to claim at once
+	publishBatch = 100
+	// publishLease specifies the time the claimed job is reserved for the worker
+	publishLease = 5 * time.Minute
+	// publishRetryMin specifies the delay before the first retry
+	publishRetryMin = 10 * time.Second
+	// publishRetryMax specifies the max delay between retries
+	publishRetryMax = time.Hour
+	// publishMaxAttempts specifies the number of attempts before the job is failed
+	publishMaxAttempts = 20
+)
+
+// GetPublishStatus returns the status of the publish outbox
+func (s *Service) GetPublishStatus(ctx context.Context, req *pb.PublishStatusRequest) (*pb.PublishStatusResponse, error) {
+	counts, err := s.db.GetPublishJobsCounts(ctx)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to get publish status")
+	}
+
+	res := &pb.PublishStatusResponse{
+		Counts: counts,
+	}
+	if req.Status != "" {
+		switch req.Status {
+		case model.PublishStatusPending, model.PublishStatusProcessing, model.PublishStatusPublished, model.PublishStatusFailed:
+		default:
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "invalid status: %s", req.Status)
+		}
+
+		list, err := s.db.ListPublishJobs(ctx, req.Status, int(req.Limit), req.After)
+		if err != nil {
+			return nil, httperror.WrapWithCtx(ctx, err, "unable to list publish jobs")
+		}
+		res.Jobs = list.ToDTO()
+	}
+	return res, nil
+}
+
+// enqueuePublish adds the job to the publish outbox, and wakes up the worker.
+// The error is logged, and does not fail the caller.
+func (s *Service) enqueuePublish(ctx context.Context, job *model.PublishJob) {
+	if s.publisher == nil {
+		return
+	}
+
+	m, err := s.db.EnqueuePublishJob(ctx, job)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to enqueue publish job",
+			"kind", job.Kind,
+			"ikid", job.IKID,
+			"ref_id", job.RefID,
+			"err", err.Error())
+		return
+	}
+
+	logger.ContextKV(ctx, xlog.DEBUG,
+		"status", "enqueued publish job",
+		"id", m.ID,
+		"kind", m.Kind,
+		"ikid", m.IKID,
+		"ref_id", m.RefID)
+
+	select {
+	case s.publishCh <- struct{}{}:
+	default:
+	}
+}
+
+// enqueuePublishJobs adds the list of jobs to the publish outbox, and wakes up the worker.
+// The error is logged, and does not fail the caller.
+func (s *Service) enqueuePublishJobs(ctx context.Context, jobs model.PublishJobs) {
+	if s.publisher == nil || len(jobs) == 0 {
+		return
+	}
+
+	list, err := s.db.EnqueuePublishJobs(ctx, jobs)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to enqueue publish jobs",
+			"count", len(jobs),
+			"err", err.Error())
+		return
+	}
+
+	logger.ContextKV(ctx, xlog.DEBUG,
+		"status", "enqueued publish jobs",
+		"count", len(list))
+
+	select {
+	case s.publishCh <- struct{}{}:
+	default:
+	}
+}
+
+// startPublishWorker starts the outbox worker, only once
+func (s *Service) startPublishWorker() {
+	if s.publisher == nil {
+		return
+	}
+	s.workerOnce.Do(func() {
+		go s.publishWorker()
+	})
+}
+
+func (s *Service) publishWorker() {
+	ticker := time.NewTicker(publishInterval)
+	defer ticker.Stop()
+
+	logger.KV(xlog.INFO, "status", "started publish worker")
+	for {
+		select {
+		case <-s.stopCh:
+			logger.KV(xlog.INFO, "status", "stopped publish worker")
+			return
+		case <-ticker.C:
+		case <-s.publishCh:
+		}
+
+		s.processPublishJobs(correlation.WithID(context.Background()))
+	}
+}
+
+// processPublishJobs publishes due jobs, and returns the number of processed jobs
+func (s *Service) processPublishJobs(ctx context.Context) int {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"reason", "recover",
+				"err", r,
+				"stack", debug.Stack())
+		}
+	}()
+
+	total := 0
+	for {
+		list, err := s.db.ClaimPublishJobs(ctx, time.Now(), publishLease, publishBatch)
+		if err != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to claim publish jobs",
+				"err", err.Error())
+			break
+		}
+
+		for _, job := range list {
+			s.processPublishJob(ctx, job)
+		}
+
+		total += len(list)
+		if len(list) < publishBatch {
+			break
+		}
+	}
+
+	if counts, err := s.db.GetPublishJobsCounts(ctx); err == nil {
+		for _, status := range []string{model.PublishStatusPending, model.PublishStatusProcessing, model.PublishStatusFailed} {
+			metricskey.CAPublishQueue.SetGauge(float64(counts[status]), status)
+		}
+	}
+	return total
+}
+
+func (s *Service) processPublishJob(ctx context.Context, job *model.PublishJob) {
+	location, err := s.publishJob(ctx, job)
+
+	job.Attempts++
+	if err == nil {
+		job.Status = model.PublishStatusPublished
+		job.Location = location
+		job.LastError = ""
+	} else {
+		job.LastError = err.Error()
+		if job.Attempts >= publishMaxAttempts || publishNotFound(job, err) {
+			job.Status = model.PublishStatusFailed
+		} else {
+			job.Status = model.PublishStatusPending
+			job.NextAttemptAt = time.Now().Add(publishBackoff(job.Attempts))
+		}
+
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to publish",
+			"id", job.ID,
+			"kind", job.Kind,
+			"ikid", job.IKID,
+			"ref_id", job.RefID,
+			"attempts", job.Attempts,
+			"next_attempt_at", job.NextAttemptAt,
+			"err", err.Error())
+
+		if job.Kind == model.PublishKindCrl || job.Kind == model.PublishKindDeltaCrl {
+			metricskey.CAFailPublishCrl.IncrCounter(1, s.issuerLabel(job.IKID))
+		} else {
+			metricskey.CAFailPublishCert.IncrCounter(1, s.issuerLabel(job.IKID))
+		}
+	}
+	metricskey.CAPublishJobs.IncrCounter(1, job.Kind, job.Status)
+
+	_, err = s.db.UpdatePublishJob(ctx, job)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to update publish job",
+			"id", job.ID,
+			"err", err.Error())
+	}
+}
+
+// publishJob publishes the job object, and returns the location
+func (s *Service) publishJob(ctx context.Context, job *model.PublishJob) (string, error) {
+	switch job.Kind {
+	case model.PublishKindCert:
+		crt, err := s.publishedCertificate(ctx, job.RefID)
+		if err != nil {
+			return "", errors.WithMessagef(err, "unable to find certificate %d", job.RefID)
+		}
+		return s.publisher.PublishCertificate(ctx, crt.ToPB(), crt.FileName())
+	case model.PublishKindIssuer:
+		crt, err := s.db.GetCertificate(ctx, job.RefID)
+		if err != nil {
+			return "", errors.WithMessagef(err, "unable to find certificate %d", job.RefID)
+		}
+		_, err = s.publisher.PublishCertificate(ctx, crt.ToPB(), crt.FileName())
+		if err != nil {
+			return "", err
+		}
+		return s.publisher.PublishIssuer(ctx, crt.ToPB(), s.issuerFileName(crt.SKID))
+	case model.PublishKindRoots:
+		roots, err := s.db.GetRootCertificates(ctx)
+		if err != nil {
+			return "", errors.WithMessage(err, "unable to get root certificates")
+		}
+		return s.publisher.PublishRoots(ctx, roots.ToDTO())
+	case model.PublishKindCrl:
+		return s.publishStoredCrls(ctx, job.IKID)
+	case model.PublishKindDeltaCrl:
+		return s.publishStoredDeltaCrl(ctx, job.IKID)
+	default:
+		return "", errors.Errorf("unsupported kind: %s", job.Kind)
+	}
+}
+
+// publishedCertificate returns the certificate to publish,
+// the certificate revoked before the job is processed is published as well
+func (s *Service) publishedCertificate(ctx context.Context, id uint64) (*model.Certificate, error) {
+	crt, err := s.db.GetCertificate(ctx, id)
+	if err == nil || !xdb.IsNotFoundError(err) {
+		return crt, err
+	}
+	ri, err := s.db.GetRevokedCertificate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &ri.Certificate, nil
+}
+
+// publishNotFound returns true if the certificate of the job is not found,
+// for example it's purged by retention, so the job is failed without retries
+func publishNotFound(job *model.PublishJob, err error) bool {
+	return (job.Kind == model.PublishKindCert || job.Kind == model.PublishKindIssuer) &&
+		xdb.IsNotFoundError(err)
+}
+
+// issuerFileName returns the file name of the issuer certificate,
+// as specified by its AIA URL
+func (s *Service) issuerFileName(skid string) string {
+	aia := ""
+	if s.ca != nil {
+		if issuer, err := s.ca.GetIssuerByKeyID(skid); err == nil {
+			aia = issuer.AiaURL()
+		}
+	}
+	return certpublisher.IssuerFileName(aia, skid)
+}
+
+// issuerLabel returns the label of the issuer, or IKID if not found
+func (s *Service) issuerLabel(ikid string) string {
+	if s.ca != nil {
+		if issuer, err := s.ca.GetIssuerByKeyID(ikid); err == nil {
+			return issuer.Label()
+		}
+	}
+	return ikid
+}
+
+// publishBackoff returns exponential delay for the next attempt
+func publishBackoff(attempts int) time.Duration {
+	d := publishRetryMin
+	for i := 1; i < attempts && d < publishRetryMax; i++ {
+		d *= 2
+	}
+	if d > publishRetryMax {
+		d = publishRetryMax
+	}
+	return d
+}
@@ -0,0 +1,291 @@
+package ca
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOutboxDB struct {
+	cadb.CaDb
+
+	lock    sync.Mutex
+	jobs    map[uint64]*model.PublishJob
+	certs   map[uint64]*model.Certificate
+	revoked map[uint64]*model.RevokedCertificate
+	next    uint64
+}
+
+func (m *mockOutboxDB) EnqueuePublishJob(_ context.Context, job *model.PublishJob) (*model.PublishJob, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	job.Status = model.PublishStatusPending
+	if err := xdb.Validate(job); err != nil {
+		return nil, err
+	}
+	for _, j := range m.jobs {
+		if j.Status == model.PublishStatusPending && j.Kind == job.Kind && j.IKID == job.IKID && j.RefID == job.RefID {
+			return j, nil
+		}
+	}
+	m.next++
+	c := *job
+	c.ID = m.next
+	c.NextAttemptAt = time.Now()
+	m.jobs[c.ID] = &c
+	return &c, nil
+}
+
+func (m *mockOutboxDB) ClaimPublishJobs(_ context.Context, now time.Time, lease time.Duration, limit int) (model.PublishJobs, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var list model.PublishJobs
+	for _, j := range m.jobs {
+		if len(list) < limit &&
+			(j.Status == model.PublishStatusPending || j.Status == model.PublishStatusProcessing) &&
+			!j.NextAttemptAt.After(now) {
+			j.Status = model.PublishStatusProcessing
+			j.NextAttemptAt = now.Add(lease)
+			c := *j
+			list = append(list, &c)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockOutboxDB) UpdatePublishJob(_ context.Context, job *model.PublishJob) (*model.PublishJob, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	c := *job
+	m.jobs[job.ID] = &c
+	return &c, nil
+}
+
+func (m *mockOutboxDB) GetPublishJobsCounts(_ context.Context) (map[string]int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	counts := map[string]int64{}
+	for _, j := range m.jobs {
+		counts[j.Status]++
+	}
+	return counts, nil
+}
+
+func (m *mockOutboxDB) ListPublishJobs(_ context.Context, status string, _ int, _ uint64) (model.PublishJobs, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var list model.PublishJobs
+	for _, j := range m.jobs {
+		if j.Status == status {
+			list = append(list, j)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockOutboxDB) GetRootCertificates(_ context.Context) (model.RootCertificates, error) {
+	return model.RootCertificates{{ID: 1}}, nil
+}
+
+func (m *mockOutboxDB) GetCertificate(_ context.Context, id uint64) (*model.Certificate, error) {
+	crt := m.certs[id]
+	if crt == nil {
+		return nil, sql.ErrNoRows
+	}
+	return crt, nil
+}
+
+func (m *mockOutboxDB) GetRevokedCertificate(_ context.Context, id uint64) (*model.RevokedCertificate, error) {
+	ri := m.revoked[id]
+	if ri == nil {
+		return nil, sql.ErrNoRows
+	}
+	return ri, nil
+}
+
+type mockPublisher struct {
+	err       error
+	published []string
+}
+
+func (p *mockPublisher) PublishCertificate(_ context.Context, _ *pb.Certificate, filename string) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.published = append(p.published, filename)
+	return "file:///" + filename, nil
+}
+
+func (p *mockPublisher) PublishCRL(_ context.Context, crl *pb.Crl) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.published = append(p.published, crl.IKID+".crl")
+	return "file:///crl/" + crl.IKID + ".crl", nil
+}
+
+func (p *mockPublisher) PublishIssuer(_ context.Context, _ *pb.Certificate, filename string) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.published = append(p.published, filename)
+	return "file:///aia/" + filename, nil
+}
+
+func (p *mockPublisher) PublishRoots(_ context.Context, roots []*pb.RootCertificate) (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	p.published = append(p.published, "roots.pem")
+	return "file:///roots.pem", nil
+}
+
+func TestPublishOutbox(t *testing.T) {
+	ctx := context.Background()
+	db := &mockOutboxDB{
+		jobs: map[uint64]*model.PublishJob{},
+		certs: map[uint64]*model.Certificate{
+			1: {ID: 1, IKID: "ikid", SerialNumber: "123456789012345678"},
+		},
+	}
+	pub := &mockPublisher{err: errors.New("storage is down")}
+	s := &Service{
+		db:        db,
+		publisher: pub,
+		publishCh: make(chan struct{}, 1),
+	}
+
+	job := &model.PublishJob{Kind: model.PublishKindCert, IKID: "ikid", RefID: 1}
+	s.enqueuePublish(ctx, job)
+	s.enqueuePublish(ctx, job)
+	assert.Len(t, db.jobs, 1, "pending job must be deduplicated")
+	assert.Len(t, s.publishCh, 1)
+
+	// invalid job is not enqueued
+	s.enqueuePublish(ctx, &model.PublishJob{Kind: "unknown", RefID: 1})
+	assert.Len(t, db.jobs, 1)
+
+	assert.Equal(t, 1, s.processPublishJobs(ctx))
+	j := db.jobs[1]
+	assert.Equal(t, model.PublishStatusPending, j.Status)
+	assert.Equal(t, 1, j.Attempts)
+	assert.Equal(t, "storage is down", j.LastError)
+	assert.True(t, j.NextAttemptAt.After(time.Now()))
+
+	// not due yet
+	assert.Equal(t, 0, s.processPublishJobs(ctx))
+
+	pub.err = nil
+	j.NextAttemptAt = time.Now()
+	assert.Equal(t, 1, s.processPublishJobs(ctx))
+	j = db.jobs[1]
+	assert.Equal(t, model.PublishStatusPublished, j.Status)
+	assert.Equal(t, 2, j.Attempts)
+	assert.Empty(t, j.LastError)
+	assert.Equal(t, "file:///"+db.certs[1].FileName(), j.Location)
+	assert.Len(t, pub.published, 1)
+
+	t.Run("failed", func(t *testing.T) {
+		pub.err = errors.New("storage is down")
+		defer func() {
+			pub.err = nil
+		}()
+		s.enqueuePublish(ctx, &model.PublishJob{Kind: model.PublishKindCert, IKID: "ikid", RefID: 1})
+		for i := 0; i < publishMaxAttempts; i++ {
+			for _, j := range db.jobs {
+				j.NextAttemptAt = time.Now()
+			}
+			s.processPublishJobs(ctx)
+		}
+		j := db.jobs[2]
+		require.NotNil(t, j)
+		assert.Equal(t, model.PublishStatusFailed, j.Status)
+		assert.Equal(t, publishMaxAttempts, j.Attempts)
+		assert.Equal(t, "storage is down", j.LastError)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		db.revoked = map[uint64]*model.RevokedCertificate{
+			3: {Certificate: model.Certificate{ID: 3, IKID: "ikid", SerialNumber: "123456789012345673"}},
+		}
+		s.enqueuePublish(ctx, &model.PublishJob{Kind: model.PublishKindCert, IKID: "ikid", RefID: 3})
+		assert.Equal(t, 1, s.processPublishJobs(ctx))
+		j := db.jobs[3]
+		require.NotNil(t, j)
+		assert.Equal(t, model.PublishStatusPublished, j.Status)
+		assert.Equal(t, "file:///"+db.revoked[3].Certificate.FileName(), j.Location)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		s.enqueuePublish(ctx, &model.PublishJob{Kind: model.PublishKindCert, IKID: "ikid", RefID: 4})
+		assert.Equal(t, 1, s.processPublishJobs(ctx))
+		j := db.jobs[4]
+		require.NotNil(t, j)
+		assert.Equal(t, model.PublishStatusFailed, j.Status)
+		assert.Equal(t, 1, j.Attempts)
+		assert.Contains(t, j.LastError, "unable to find certificate 4")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		res, err := s.GetPublishStatus(ctx, &pb.PublishStatusRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), res.Counts[model.PublishStatusPublished])
+		assert.Equal(t, int64(2), res.Counts[model.PublishStatusFailed])
+		assert.Empty(t, res.Jobs)
+
+		res, err = s.GetPublishStatus(ctx, &pb.PublishStatusRequest{Status: model.PublishStatusFailed})
+		require.NoError(t, err)
+		require.Len(t, res.Jobs, 2)
+		assert.ElementsMatch(t, []uint64{1, 4}, []uint64{res.Jobs[0].RefID, res.Jobs[1].RefID})
+
+		_, err = s.GetPublishStatus(ctx, &pb.PublishStatusRequest{Status: "invalid"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+	})
+}
+
+func TestPublishIssuerAndRoots(t *testing.T) {
+	ctx := context.Background()
+	db := &mockOutboxDB{
+		jobs: map[uint64]*model.PublishJob{},
+		certs: map[uint64]*model.Certificate{
+			1: {ID: 1, IKID: "root", SKID: "skid", Profile: "ca", SerialNumber: "123456789012345678"},
+		},
+	}
+	pub := &mockPublisher{}
+	s := &Service{
+		db:        db,
+		publisher: pub,
+		publishCh: make(chan struct{}, 1),
+	}
+
+	s.enqueuePublish(ctx, &model.PublishJob{Kind: model.PublishKindIssuer, IKID: "root", RefID: 1})
+	s.enqueuePublish(ctx, &model.PublishJob{Kind: model.PublishKindRoots})
+	assert.Equal(t, 2, s.processPublishJobs(ctx))
+
+	assert.Equal(t, "file:///aia/skid.crt", db.jobs[1].Location)
+	assert.Equal(t, "file:///roots.pem", db.jobs[2].Location)
+	assert.ElementsMatch(t, []string{db.certs[1].FileName(), "skid.crt", "roots.pem"}, pub.published)
+}
+
+func TestPublishBackoff(t *testing.T) {
+	assert.Equal(t, publishRetryMin, publishBackoff(1))
+	assert.Equal(t, 2*publishRetryMin, publishBackoff(2))
+	assert.Equal(t, 8*publishRetryMin, publishBackoff(4))
+	assert.Equal(t, publishRetryMax, publishBackoff(100))
+}
@@ -0,0 +1,159 @@
+package ca
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// signRequestLease specifies the period after which the request in progress
+// is considered abandoned, and can be retried with the same idempotency key
+const signRequestLease = 5 * time.Minute
+
+// signIdempotent signs the certificate once per idempotency key of the request.
+// The retry of the same request returns the issued certificate,
+// and the request with the same key but different content is rejected.
+func (s *Service) signIdempotent(ctx context.Context, req *pb.SignCertificateRequest) (*pb.CertificateResponse, error) {
+	if len(req.IdempotencyKey) > model.MaxIdempotencyKeyLen {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.InvalidArgument, "idempotency key must not exceed %d characters", model.MaxIdempotencyKeyLen)
+	}
+
+	digest, err := signRequestDigest(req)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to digest the request")
+	}
+
+	sr, reserved, err := s.reserveSignRequest(ctx, req.OrgID, req.IdempotencyKey, digest)
+	if err != nil {
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to register idempotency key")
+	}
+	if !reserved {
+		return s.replaySignRequest(ctx, sr, digest)
+	}
+
+	var res *pb.CertificateResponse
+	mcert, err := s.issueCertificate(ctx, req, s.resolveIssuer)
+	if err == nil {
+		// the certificate is registered with the ID of the request,
+		// so it can be found if the request is not completed
+		mcert.ID = sr.ID
+		res, err = s.registerIssued(ctx, mcert)
+	}
+	if err != nil {
+		// release the key, so the request can be retried
+		if derr := s.db.DeleteSignRequest(ctx, sr.ID); derr != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"status", "failed to delete sign request",
+				"key", sr.IdempotencyKey,
+				"err", derr.Error())
+		}
+		return nil, err
+	}
+
+	_, err = s.db.CompleteSignRequest(ctx, sr.ID, res.Certificate.ID)
+	if err != nil {
+		// the certificate is issued, the client must not fail
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to complete sign request",
+			"key", sr.IdempotencyKey,
+			"id", res.Certificate.ID,
+			"err", err.Error())
+	}
+	return res, nil
+}
+
+// reserveSignRequest registers the idempotency key of the request in the org,
+// and returns true if the key is reserved by this call,
+// or false and the existing request otherwise
+func (s *Service) reserveSignRequest(ctx context.Context, orgID uint64, key, digest string) (*model.SignRequest, bool, error) {
+	id := s.db.NextID().UInt64()
+	sr, err := s.db.ReserveSignRequest(ctx, &model.SignRequest{
+		ID:             id,
+		OrgID:          orgID,
+		IdempotencyKey: key,
+		Digest:         digest,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if sr.ID == id {
+		return sr, true, nil
+	}
+
+	if sr.InProgress() && sr.Digest == digest && time.Since(sr.CreatedAt.UTC()) > signRequestLease {
+		logger.ContextKV(ctx, xlog.WARNING,
+			"status", "abandoned sign request",
+			"key", key,
+			"created_at", sr.CreatedAt)
+
+		// the certificate may be issued, but the request is not completed
+		crt, err := s.db.GetCertificate(ctx, sr.ID)
+		if err == nil {
+			sr, err = s.db.CompleteSignRequest(ctx, sr.ID, crt.ID)
+			if err != nil {
+				return nil, false, err
+			}
+			return sr, false, nil
+		}
+		if !xdb.IsNotFoundError(err) {
+			return nil, false, err
+		}
+
+		if err = s.db.DeleteSignRequest(ctx, sr.ID); err != nil {
+			return nil, false, err
+		}
+		return s.reserveSignRequest(ctx, orgID, key, digest)
+	}
+	return sr, false, nil
+}
+
+// replaySignRequest returns the certificate issued for the request
+func (s *Service) replaySignRequest(ctx context.Context, sr *model.SignRequest, digest string) (*pb.CertificateResponse, error) {
+	if sr.Digest != digest {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.AlreadyExists, "the idempotency key was used with a different request")
+	}
+	if sr.InProgress() {
+		return nil, httperror.NewGrpcFromCtx(ctx, codes.Aborted, "the request with the idempotency key is in progress")
+	}
+
+	crt, err := s.db.GetCertificate(ctx, sr.CertificateID)
+	if err != nil {
+		if xdb.IsNotFoundError(err) {
+			return nil, httperror.NewGrpcFromCtx(ctx, codes.NotFound, "the certificate of the idempotency key is revoked or removed")
+		}
+		return nil, httperror.WrapWithCtx(ctx, err, "unable to find certificate")
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "replayed sign request",
+		"key", sr.IdempotencyKey,
+		"id", crt.ID)
+
+	return &pb.CertificateResponse{
+		Certificate: crt.ToPB(),
+		Warnings:    crt.LintWarnings(),
+	}, nil
+}
+
+// signRequestDigest returns hex encoded SHA256 of the request without the idempotency key
+func signRequestDigest(req *pb.SignCertificateRequest) (string, error) {
+	r := proto.Clone(req).(*pb.SignCertificateRequest)
+	r.IdempotencyKey = ""
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(r)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	d := sha256.Sum256(b)
+	return hex.EncodeToString(d[:]), nil
+}
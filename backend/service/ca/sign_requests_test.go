@@ -0,0 +1,322 @@
+package ca
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/effective-security/porto/xhttp/httperror"
+	pb "github.com/effective-security/trusty/api/pb"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/effective-security/xpki/csr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type mockSignDB struct {
+	cadb.CaDb
+
+	lock     sync.Mutex
+	lastID   uint64
+	certs    map[uint64]*model.Certificate
+	requests map[string]*model.SignRequest
+	blocked  map[string]*model.BlockedKey
+	// completeErr is returned by CompleteSignRequest
+	completeErr error
+}
+
+func (m *mockSignDB) NextID() xdb.ID {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.lastID++
+	return xdb.NewID(m.lastID)
+}
+
+func (m *mockSignDB) RegisterCertificate(_ context.Context, crt *model.Certificate) (*model.Certificate, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if crt.ID == 0 {
+		m.lastID++
+		crt.ID = m.lastID
+	}
+	m.certs[crt.ID] = crt
+	return crt, nil
+}
+
+func (m *mockSignDB) GetCertificate(_ context.Context, id uint64) (*model.Certificate, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	crt := m.certs[id]
+	if crt == nil {
+		return nil, sql.ErrNoRows
+	}
+	return crt, nil
+}
+
+func (m *mockSignDB) ReserveSignRequest(_ context.Context, r *model.SignRequest) (*model.SignRequest, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := requestKey(r.OrgID, r.IdempotencyKey)
+	if existing := m.requests[key]; existing != nil {
+		return existing, nil
+	}
+	res := *r
+	res.CreatedAt = xdb.Now()
+	m.requests[key] = &res
+	return &res, nil
+}
+
+func (m *mockSignDB) CompleteSignRequest(_ context.Context, id, certID uint64) (*model.SignRequest, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.completeErr != nil {
+		return nil, m.completeErr
+	}
+	for _, r := range m.requests {
+		if r.ID == id {
+			r.CertificateID = certID
+			return r, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockSignDB) DeleteSignRequest(_ context.Context, id uint64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for key, r := range m.requests {
+		if r.ID == id {
+			delete(m.requests, key)
+		}
+	}
+	return nil
+}
+
+func (m *mockSignDB) BlockKey(_ context.Context, r *model.BlockedKey) (*model.BlockedKey, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if existing := m.blocked[r.SPKIHash]; existing != nil {
+		return existing, nil
+	}
+	if m.blocked == nil {
+		m.blocked = map[string]*model.BlockedKey{}
+	}
+	res := *r
+	res.CreatedAt = xdb.Now()
+	m.blocked[r.SPKIHash] = &res
+	return &res, nil
+}
+
+func (m *mockSignDB) GetBlockedKey(_ context.Context, spkiHash string) (*model.BlockedKey, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	r := m.blocked[spkiHash]
+	if r == nil {
+		return nil, sql.ErrNoRows
+	}
+	return r, nil
+}
+
+func (m *mockSignDB) request(orgID uint64, key string) *model.SignRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.requests[requestKey(orgID, key)]
+}
+
+// requestKey returns the key of the sign request in the org
+func requestKey(orgID uint64, key string) string {
+	return fmt.Sprintf("%d/%s", orgID, key)
+}
+
+func TestSignIdempotent(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	issuer.AddProfile("server", &authority.CertProfile{
+		Usage:  []string{"server auth"},
+		Expiry: csr.Duration(24 * time.Hour),
+	})
+
+	db := &mockSignDB{
+		certs:    map[uint64]*model.Certificate{},
+		requests: map[string]*model.SignRequest{},
+	}
+	s := newResponderTestService(t, issuer, nil)
+	s.db = db
+	s.cfg = &config.Configuration{
+		RegistrationAuthority: &config.RegistrationAuthority{},
+	}
+
+	newRequest := func(key string) *pb.SignCertificateRequest {
+		return &pb.SignCertificateRequest{
+			Profile:        "server",
+			Request:        csrForTest(t),
+			RequestFormat:  pb.EncodingFormat_PEM,
+			IdempotencyKey: key,
+			Metadata:       map[string]string{"a": "1", "b": "2"},
+		}
+	}
+
+	req := newRequest("key1")
+	res, err := s.SignCertificate(ctx, req)
+	require.NoError(t, err)
+	require.NotNil(t, res.Certificate)
+	assert.Equal(t, res.Certificate.ID, db.request(0, "key1").CertificateID)
+
+	t.Run("replay", func(t *testing.T) {
+		res2, err := s.SignCertificate(ctx, cloneRequest(req))
+		require.NoError(t, err)
+		assert.Equal(t, res.Certificate.ID, res2.Certificate.ID)
+		assert.Equal(t, res.Certificate.Pem, res2.Certificate.Pem)
+		assert.Len(t, db.certs, 1)
+	})
+
+	t.Run("different_request", func(t *testing.T) {
+		other := cloneRequest(req)
+		other.Label = "other"
+		_, err := s.SignCertificate(ctx, other)
+		require.Error(t, err)
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		assert.Contains(t, err.Error(), "the idempotency key was used with a different request")
+	})
+
+	t.Run("without_key", func(t *testing.T) {
+		// the request without idempotency key is signed again
+		other := cloneRequest(req)
+		other.IdempotencyKey = ""
+		res2, err := s.SignCertificate(ctx, other)
+		require.NoError(t, err)
+		assert.NotEqual(t, res.Certificate.ID, res2.Certificate.ID)
+		delete(db.certs, res2.Certificate.ID)
+	})
+
+	t.Run("in_progress", func(t *testing.T) {
+		r := newRequest("key2")
+		digest, err := signRequestDigest(r)
+		require.NoError(t, err)
+		db.requests[requestKey(0, "key2")] = &model.SignRequest{ID: 1000, IdempotencyKey: "key2", Digest: digest, CreatedAt: xdb.Now()}
+
+		_, err = s.SignCertificate(ctx, r)
+		require.Error(t, err)
+		assert.Equal(t, codes.Aborted, status.Code(err))
+
+		// the abandoned request is signed again
+		db.request(0, "key2").CreatedAt = xdb.FromNow(-signRequestLease - time.Minute)
+		res, err := s.SignCertificate(ctx, r)
+		require.NoError(t, err)
+		assert.NotEqual(t, uint64(1000), db.request(0, "key2").ID)
+		assert.Equal(t, res.Certificate.ID, db.request(0, "key2").CertificateID)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		// the key is released if signing failed
+		r := newRequest("key3")
+		r.Profile = "unknown"
+		r.IssuerLabel = issuer.Label()
+		_, err := s.SignCertificate(ctx, r)
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, db.request(0, "key3"))
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		sr := db.request(0, "key1")
+		crt := db.certs[sr.CertificateID]
+		delete(db.certs, sr.CertificateID)
+		defer func() { db.certs[crt.ID] = crt }()
+
+		_, err := s.SignCertificate(ctx, cloneRequest(req))
+		require.Error(t, err)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("complete_failed", func(t *testing.T) {
+		db.completeErr = errors.New("failed")
+		defer func() { db.completeErr = nil }()
+
+		res, err := s.SignCertificate(ctx, newRequest("key4"))
+		require.NoError(t, err)
+		assert.NotNil(t, res.Certificate)
+		assert.True(t, db.request(0, "key4").InProgress())
+	})
+
+	t.Run("abandoned_issued", func(t *testing.T) {
+		db.completeErr = errors.New("failed")
+		r := newRequest("key5")
+		res, err := s.SignCertificate(ctx, r)
+		db.completeErr = nil
+		require.NoError(t, err)
+		require.True(t, db.request(0, "key5").InProgress())
+		count := len(db.certs)
+
+		// the issued certificate is returned, and not signed again
+		db.request(0, "key5").CreatedAt = xdb.FromNow(-signRequestLease - time.Minute)
+		res2, err := s.SignCertificate(ctx, cloneRequest(r))
+		require.NoError(t, err)
+		assert.Equal(t, res.Certificate.ID, res2.Certificate.ID)
+		assert.Equal(t, res.Certificate.ID, db.request(0, "key5").CertificateID)
+		assert.Len(t, db.certs, count)
+	})
+
+	t.Run("org", func(t *testing.T) {
+		// the key is unique within the org
+		r := cloneRequest(req)
+		r.OrgID = 1000
+		res2, err := s.SignCertificate(ctx, r)
+		require.NoError(t, err)
+		assert.NotEqual(t, res.Certificate.ID, res2.Certificate.ID)
+		assert.Equal(t, res2.Certificate.ID, db.request(1000, "key1").CertificateID)
+		assert.Equal(t, res.Certificate.ID, db.request(0, "key1").CertificateID)
+	})
+
+	t.Run("too_long", func(t *testing.T) {
+		r := newRequest(string(make([]byte, model.MaxIdempotencyKeyLen+1)))
+		_, err := s.SignCertificate(ctx, r)
+		require.Error(t, err)
+		assert.Equal(t, 400, httperror.Status(err))
+	})
+}
+
+func TestSignRequestDigest(t *testing.T) {
+	r1 := &pb.SignCertificateRequest{
+		Profile:        "server",
+		Request:        []byte("csr"),
+		IdempotencyKey: "key1",
+		Metadata:       map[string]string{"a": "1", "b": "2", "c": "3"},
+	}
+	d1, err := signRequestDigest(r1)
+	require.NoError(t, err)
+	assert.Len(t, d1, 64)
+	assert.Equal(t, "key1", r1.IdempotencyKey)
+
+	r2 := cloneRequest(r1)
+	r2.IdempotencyKey = "key2"
+	d2, err := signRequestDigest(r2)
+	require.NoError(t, err)
+	assert.Equal(t, d1, d2)
+
+	r2.SAN = []string{"localhost"}
+	d2, err = signRequestDigest(r2)
+	require.NoError(t, err)
+	assert.NotEqual(t, d1, d2)
+}
+
+func cloneRequest(req *pb.SignCertificateRequest) *pb.SignCertificateRequest {
+	return proto.Clone(req).(*pb.SignCertificateRequest)
+}
@@ -3,11 +3,14 @@ package cis
 import (
 	"encoding/pem"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/effective-security/porto/restserver"
 	"github.com/effective-security/porto/xhttp/header"
 	"github.com/effective-security/porto/xhttp/httperror"
 	"github.com/effective-security/porto/xhttp/marshal"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
 	"github.com/effective-security/trusty/pkg/metricskey"
 	"github.com/effective-security/xdb"
 	"github.com/effective-security/xlog"
@@ -19,7 +22,7 @@ const (
 	skidTag = "skid"
 )
 */
-// GetCRLHandler returns CRL
+// GetCRLHandler returns CRL, or CRL shard if specified
 func (s *Service) GetCRLHandler() restserver.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p restserver.Params) {
 		ikid := p.ByName("issuer_id")
@@ -28,10 +31,24 @@ func (s *Service) GetCRLHandler() restserver.Handle {
 				ikid = ikid[0 : len(ikid)-4]
 			}
 		*/
-		logger.KV(xlog.TRACE, "ikid", ikid)
+		shardID := p.ByName("shard")
+		logger.KV(xlog.TRACE, "ikid", ikid, "shard", shardID)
 
 		ctx := r.Context()
-		m, err := s.db.GetCrl(ctx, ikid)
+		var m *model.Crl
+		var err error
+		if shardID != "" {
+			shard, perr := strconv.ParseUint(strings.TrimSuffix(shardID, ".crl"), 10, 32)
+			if perr != nil || shard == 0 {
+				marshal.WriteJSON(w, r, httperror.InvalidParam("invalid shard: %s", shardID))
+				return
+			}
+			m, err = s.db.GetShardCrl(ctx, ikid, uint32(shard))
+		} else if base, ok := strings.CutSuffix(ikid, "-delta"); ok {
+			m, err = s.db.GetDeltaCrl(ctx, base)
+		} else {
+			m, err = s.db.GetCrl(ctx, ikid)
+		}
 		if err != nil {
 			if xdb.IsNotFoundError(err) {
 				// metrics for Not Found
@@ -48,11 +65,8 @@ func (s *Service) GetCRLHandler() restserver.Handle {
 
 		metricskey.AIADownloadSuccessCrl.IncrCounter(1)
 
-		wh := w.Header()
-		wh.Set(header.ContentType, "application/pkix-crl")
-		wh.Set("Cache-Control", "no-store")
-		wh.Set("Pragma", "no-cache")
-		_, _ = w.Write(block.Bytes)
+		w.Header().Set(header.ContentType, "application/pkix-crl")
+		writeCacheable(w, r, block.Bytes, etag(block.Bytes), m.ThisUpdate.UTC(), m.NextUpdate.UTC(), 0)
 	}
 }
 
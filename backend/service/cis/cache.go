@@ -0,0 +1,86 @@
+package cis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/effective-security/trusty/pkg/metricskey"
+)
+
+// etag returns the strong entity tag of the response
+func etag(der []byte) string {
+	h := sha256.Sum256(der)
+	return `"` + hex.EncodeToString(h[:]) + `"`
+}
+
+// setNoCacheHeaders sets the headers for the response that must not be cached
+func setNoCacheHeaders(wh http.Header) {
+	wh.Set("Cache-Control", "no-store")
+	wh.Set("Pragma", "no-cache")
+}
+
+// setCacheHeaders sets the caching headers as described in RFC 5019, section 6.2.
+// The response with expired or missing next update time is not cached.
+// If maxAge is specified, then max-age is not greater than its value.
+// Returns false, if the response is not cacheable.
+func setCacheHeaders(wh http.Header, tag string, thisUpdate, nextUpdate, now time.Time, maxAge time.Duration) bool {
+	age := nextUpdate.Sub(now)
+	if nextUpdate.IsZero() || age <= 0 {
+		setNoCacheHeaders(wh)
+		return false
+	}
+	if maxAge > 0 && age > maxAge {
+		age = maxAge
+	}
+
+	wh.Set("Cache-Control", fmt.Sprintf("max-age=%d, public, no-transform, must-revalidate", int64(age/time.Second)))
+	wh.Set("Expires", nextUpdate.UTC().Format(http.TimeFormat))
+	wh.Set("ETag", tag)
+	if !thisUpdate.IsZero() {
+		wh.Set("Last-Modified", thisUpdate.UTC().Format(http.TimeFormat))
+	}
+	return true
+}
+
+// writeCacheable writes the response with the caching headers,
+// or Not Modified status for the matching conditional request
+func writeCacheable(w http.ResponseWriter, r *http.Request, der []byte, tag string, thisUpdate, nextUpdate time.Time, maxAge time.Duration) {
+	if setCacheHeaders(w.Header(), tag, thisUpdate, nextUpdate, time.Now(), maxAge) &&
+		notModified(r, tag, thisUpdate) {
+		metricskey.AIANotModified.IncrCounter(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = w.Write(der)
+}
+
+// notModified returns true if the conditional GET request
+// matches the entity tag, or the response is not modified since the requested time.
+// As described in RFC 9110, If-Modified-Since is ignored when If-None-Match is present.
+func notModified(r *http.Request, tag string, thisUpdate time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == "*" || t == tag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !thisUpdate.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err == nil && !thisUpdate.Truncate(time.Second).After(since) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package cis
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCacheHeaders(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	thisUpdate := now.Add(-time.Hour)
+	nextUpdate := now.Add(8 * time.Hour)
+	tag := etag([]byte{1, 2, 3})
+
+	wh := http.Header{}
+	assert.True(t, setCacheHeaders(wh, tag, thisUpdate, nextUpdate, now, 0))
+	assert.Equal(t, "max-age=28800, public, no-transform, must-revalidate", wh.Get("Cache-Control"))
+	assert.Equal(t, "Mon, 01 Jan 2024 08:00:00 GMT", wh.Get("Expires"))
+	assert.Equal(t, "Sun, 31 Dec 2023 23:00:00 GMT", wh.Get("Last-Modified"))
+	assert.Equal(t, `"039058c6f2c0cb492c533b0a4d14ef77cc0f78abccced5287d84a1a2011cfb81"`, wh.Get("ETag"))
+	assert.Empty(t, wh.Get("Pragma"))
+
+	wh = http.Header{}
+	assert.True(t, setCacheHeaders(wh, tag, thisUpdate, nextUpdate, now, time.Hour))
+	assert.Equal(t, "max-age=3600, public, no-transform, must-revalidate", wh.Get("Cache-Control"))
+
+	for _, next := range []time.Time{{}, now, now.Add(-time.Second)} {
+		wh = http.Header{}
+		assert.False(t, setCacheHeaders(wh, tag, thisUpdate, next, now, 0))
+		assert.Equal(t, "no-store", wh.Get("Cache-Control"))
+		assert.Equal(t, "no-cache", wh.Get("Pragma"))
+		assert.Empty(t, wh.Get("ETag"))
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	thisUpdate := time.Now().Add(-time.Hour)
+	tag := etag([]byte{1, 2, 3})
+
+	tcases := []struct {
+		method string
+		hdrs   map[string]string
+		exp    bool
+	}{
+		{http.MethodGet, nil, false},
+		{http.MethodGet, map[string]string{"If-None-Match": tag}, true},
+		{http.MethodHead, map[string]string{"If-None-Match": tag}, true},
+		{http.MethodPost, map[string]string{"If-None-Match": tag}, false},
+		{http.MethodGet, map[string]string{"If-None-Match": `"other", W/` + tag}, true},
+		{http.MethodGet, map[string]string{"If-None-Match": "*"}, true},
+		{http.MethodGet, map[string]string{"If-None-Match": `"other"`}, false},
+		{http.MethodGet, map[string]string{"If-Modified-Since": thisUpdate.UTC().Format(http.TimeFormat)}, true},
+		{http.MethodGet, map[string]string{"If-Modified-Since": thisUpdate.Add(-time.Minute).UTC().Format(http.TimeFormat)}, false},
+		{http.MethodGet, map[string]string{"If-Modified-Since": "invalid"}, false},
+		// If-Modified-Since is ignored
+		{http.MethodGet, map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": thisUpdate.UTC().Format(http.TimeFormat),
+		}, false},
+	}
+
+	for i, tc := range tcases {
+		r, err := http.NewRequest(tc.method, "/", nil)
+		require.NoError(t, err)
+		for k, v := range tc.hdrs {
+			r.Header.Set(k, v)
+		}
+		assert.Equal(t, tc.exp, notModified(r, tag, thisUpdate), "[%d] %s: %v", i, tc.method, tc.hdrs)
+	}
+}
+
+func TestWriteCacheable(t *testing.T) {
+	der := []byte{1, 2, 3}
+	tag := etag(der)
+	thisUpdate := time.Now().Add(-time.Hour)
+
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	r.Header.Set("If-None-Match", tag)
+
+	w := httptest.NewRecorder()
+	writeCacheable(w, r, der, tag, thisUpdate, time.Now().Add(time.Hour), 0)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+	assert.Equal(t, tag, w.Header().Get("ETag"))
+
+	// expired response is not cacheable
+	w = httptest.NewRecorder()
+	writeCacheable(w, r, der, tag, thisUpdate, time.Now().Add(-time.Minute), 0)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, der, w.Body.Bytes())
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
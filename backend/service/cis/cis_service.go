@@ -32,6 +32,9 @@ type Service struct {
 	grpClient io.Closer
 	ca        pb.CAServer
 	lock      sync.RWMutex
+
+	// ocspCache is nil, if OCSP responses are not cached
+	ocspCache *ocspCache
 }
 
 // Factory returns a factory of the service
@@ -47,6 +50,9 @@ func Factory(server gserver.GServer) any {
 			db:            db,
 			clientFactory: clientFactory,
 		}
+		if cfg.OCSPCache.Enabled() {
+			svc.ocspCache = newOCSPCache(cfg.OCSPCache.Size, cfg.OCSPCache.MaxAge)
+		}
 
 		server.AddService(svc)
 	}
@@ -75,6 +81,7 @@ func (s *Service) Close() {
 // RegisterRoute adds the Status API endpoints to the overall URL router
 func (s *Service) RegisterRoute(r restserver.Router) {
 	r.GET(v1.PathForCRLByID, s.GetCRLHandler())
+	r.GET(v1.PathForCRLShardByID, s.GetCRLHandler())
 	r.GET(v1.PathForAIACertByID, s.GetCertHandler())
 
 	r.GET(v1.PathForOCSP+"/:body", s.GetOcspHandler())
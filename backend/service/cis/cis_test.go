@@ -24,6 +24,7 @@ import (
 	"github.com/effective-security/trusty/backend/service/ca"
 	"github.com/effective-security/trusty/backend/service/cis"
 	"github.com/effective-security/trusty/tests/testutils"
+	"github.com/effective-security/xdb"
 	"github.com/effective-security/xpki/certutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -140,6 +141,56 @@ func Test_getCRLHandler(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 
+	t.Run("delta_not_found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForCRLDP, nil)
+		assert.NoError(t, err)
+
+		h(w, r, restserver.Params{
+			{
+				Key:   "issuer_id",
+				Value: "notfound-delta",
+			},
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("shard_not_found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForCRLDP, nil)
+		assert.NoError(t, err)
+
+		h(w, r, restserver.Params{
+			{
+				Key:   "issuer_id",
+				Value: "notfound",
+			},
+			{
+				Key:   "shard",
+				Value: "1",
+			},
+		})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("shard_invalid", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, err := http.NewRequest(http.MethodGet, v1.PathForCRLDP, nil)
+		assert.NoError(t, err)
+
+		h(w, r, restserver.Params{
+			{
+				Key:   "issuer_id",
+				Value: "notfound",
+			},
+			{
+				Key:   "shard",
+				Value: "invalid",
+			},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	for _, crl := range crls {
 		t.Run(crl.IKID, func(t *testing.T) {
 			w := httptest.NewRecorder()
@@ -160,6 +211,20 @@ func Test_getCRLHandler(t *testing.T) {
 			_, err = x509.ParseCRL(w.Body.Bytes())
 			require.NoError(t, err)
 
+			assert.Contains(t, hdr.Get("Cache-Control"), "public, no-transform, must-revalidate")
+			tag := hdr.Get("ETag")
+			require.NotEmpty(t, tag)
+
+			w = httptest.NewRecorder()
+			r.Header.Set("If-None-Match", tag)
+			h(w, r, restserver.Params{
+				{
+					Key:   "issuer_id",
+					Value: crl.IKID,
+				},
+			})
+			assert.Equal(t, http.StatusNotModified, w.Code)
+
 			//dat, err := os.ReadFile(file)
 			//require.NoError(t, err)
 			//assert.Equal(t, dat, w.Body.Bytes())
@@ -226,8 +291,10 @@ func populateCrl(t *testing.T, db cadb.CaDb, files []string) []*model.Crl {
 
 		m, err := db.RegisterCrl(context.Background(),
 			&model.Crl{
-				IKID: path.Base(file),
-				Pem:  string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlBytes})),
+				IKID:       path.Base(file),
+				ThisUpdate: xdb.FromNow(-time.Hour),
+				NextUpdate: xdb.FromNow(time.Hour),
+				Pem:        string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlBytes})),
 			})
 		require.NoError(t, err)
 		list = append(list, m)
@@ -1,10 +1,12 @@
 package cis
 
 import (
+	"context"
 	"encoding/base64"
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/effective-security/porto/restserver"
 	"github.com/effective-security/porto/xhttp/header"
@@ -12,6 +14,7 @@ import (
 	"github.com/effective-security/porto/xhttp/marshal"
 	pb "github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/trusty/pkg/ocsputil"
 	"github.com/effective-security/xlog"
 	"golang.org/x/crypto/ocsp"
 )
@@ -75,44 +78,69 @@ func (s *Service) GetOcspHandler() restserver.Handle {
 			marshal.WriteJSON(w, r, httperror.InvalidRequest("unable to parse request"))
 			return
 		}
-		s.ocspResponse(w, r, requestBody)
+		s.ocspResponse(w, r, p.ByName("issuer_id"), requestBody)
 	}
 }
 
 // OcspHandler returns OCSP
 func (s *Service) OcspHandler() restserver.Handle {
-	return func(w http.ResponseWriter, r *http.Request, _ restserver.Params) {
+	return func(w http.ResponseWriter, r *http.Request, p restserver.Params) {
 		requestBody, err := io.ReadAll(r.Body)
 		if err != nil {
 			marshal.WriteJSON(w, r, httperror.Unexpected("unable to read request"))
 			return
 		}
 
-		s.ocspResponse(w, r, requestBody)
+		s.ocspResponse(w, r, p.ByName("issuer_id"), requestBody)
 	}
 }
 
-func (s *Service) ocspResponse(w http.ResponseWriter, r *http.Request, requestBody []byte) {
+// ocspResponse writes OCSP response for the request,
+// if ikid is specified, then only the certificates of the issuer are served
+func (s *Service) ocspResponse(w http.ResponseWriter, r *http.Request, ikid string, requestBody []byte) {
 	// All responses after this point will be OCSP.
 	// We could check for the content type of the request, but that
 	// seems unnecessariliy restrictive.
 	wh := w.Header()
 	wh.Set(header.ContentType, "application/ocsp-response")
-	wh.Set("Cache-Control", "no-store")
-	wh.Set("Pragma", "no-cache")
 
 	// logger.Tracef("req=%x", requestBody)
 
-	res, err := s.ca.SignOCSP(r.Context(), &pb.OCSPRequest{Der: requestBody})
+	ctx := r.Context()
+	now := time.Now()
+
+	// only the requests of a single certificate without nonce are cached
+	var key string
+	var gen uint64
+	ocspRequest, err := ocsputil.ParseRequest(requestBody)
+	if s.ocspCache != nil && err == nil &&
+		len(ocspRequest.CertIDs) == 1 && ocspRequest.Nonce == nil {
+		// the response is cached per path, as the request to the responder
+		// of other issuer is rejected
+		key = ikid + "/" + certIDKey(ocspRequest.CertIDs[0])
+		if e := s.ocspCache.Get(key, now); e != nil {
+			metricskey.AIAOcspCacheHit.IncrCounter(1)
+			metricskey.AIADownloadSuccessOCSP.IncrCounter(1)
+			writeCacheable(w, r, e.der, e.etag, e.thisUpdate, e.nextUpdate, s.ocspCache.maxAge)
+			return
+		}
+		gen = s.ocspCache.Generation()
+	}
+
+	res, err := s.ca.SignOCSP(ctx, &pb.OCSPRequest{
+		Der:  requestBody,
+		IKID: ikid,
+	})
 	if err != nil {
-		logger.ContextKV(r.Context(), xlog.WARNING, "err", err.Error())
+		logger.ContextKV(ctx, xlog.WARNING, "err", err.Error())
 		metricskey.AIADownloadFailOCSP.IncrCounter(1)
+		setNoCacheHeaders(wh)
 
 		switch httperror.Status(err) {
 		case http.StatusBadRequest:
 			_, _ = w.Write(malformedRequestErrorResponse)
 			return
-		case http.StatusNotFound:
+		case http.StatusNotFound, http.StatusUnauthorized:
 			_, _ = w.Write(unauthorizedErrorResponse)
 			return
 		}
@@ -123,5 +151,59 @@ func (s *Service) ocspResponse(w http.ResponseWriter, r *http.Request, requestBo
 
 	metricskey.AIADownloadSuccessOCSP.IncrCounter(1)
 
-	_, _ = w.Write(res.Der)
+	// the response is signed by CA, the signature is not verified here
+	ocspResponse, err := ocsputil.ParseResponse(res.Der)
+	if err != nil || ocspResponse.Nonce() != nil {
+		if err != nil {
+			logger.ContextKV(ctx, xlog.WARNING,
+				"reason", "unable to parse OCSP response",
+				"err", err.Error())
+		}
+		setNoCacheHeaders(wh)
+		_, _ = w.Write(res.Der)
+		return
+	}
+
+	// the response of multiple certificates is cacheable
+	// until the earliest next update
+	e := &ocspCacheEntry{
+		key:  key,
+		ikid: ikid,
+		der:  res.Der,
+		etag: etag(res.Der),
+	}
+	for i, single := range ocspResponse.Responses {
+		if i == 0 || single.ThisUpdate.After(e.thisUpdate) {
+			e.thisUpdate = single.ThisUpdate
+		}
+		if i == 0 || single.NextUpdate.Before(e.nextUpdate) {
+			e.nextUpdate = single.NextUpdate
+		}
+	}
+	if key != "" {
+		e.serial = ocspResponse.Responses[0].CertID.SerialNumber.String()
+		s.ocspCache.Add(e, gen, now)
+	}
+
+	var maxAge time.Duration
+	if s.ocspCache != nil {
+		maxAge = s.ocspCache.maxAge
+	}
+	writeCacheable(w, r, e.der, e.etag, e.thisUpdate, e.nextUpdate, maxAge)
+}
+
+// OnCertificateRevoked removes the cached OCSP responses of the revoked certificate.
+// It's called only by CA in the same process, if CA is remote,
+// then the cached response is served until its next update time,
+// or the configured max age of the cache.
+func (s *Service) OnCertificateRevoked(ctx context.Context, ikid, serial string) {
+	if s.ocspCache == nil {
+		return
+	}
+	removed := s.ocspCache.Remove(ikid, serial)
+	logger.ContextKV(ctx, xlog.DEBUG,
+		"status", "removed cached OCSP",
+		"ikid", ikid,
+		"serial", serial,
+		"removed", removed)
 }
@@ -0,0 +1,177 @@
+package cis
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/effective-security/trusty/pkg/ocsputil"
+)
+
+// ocspCacheEntry is the cached OCSP response
+type ocspCacheEntry struct {
+	key string
+	// ikid is the issuer of the responder path,
+	// or empty if the issuer is not specified
+	ikid   string
+	serial string
+	der    []byte
+	etag   string
+
+	thisUpdate time.Time
+	nextUpdate time.Time
+	// expires specifies the time to remove the entry from the cache
+	expires time.Time
+}
+
+// ocspCache is LRU cache of OCSP responses, keyed by the CertID hash.
+// The entry expires at the next update time of the response, or maxAge,
+// whichever is earlier. The entries are removed on revocation
+// only in the process where CA revoked the certificate,
+// other processes serve the cached response until it expires.
+type ocspCache struct {
+	lock   sync.Mutex
+	size   int
+	maxAge time.Duration
+
+	ll    *list.List
+	items map[string]*list.Element
+	// bySerial indexes the keys by serial number,
+	// as the request of the same certificate may use different hashes,
+	// and the issuer is not known for the requests without issuer path
+	bySerial map[string][]string
+	// gen is incremented on each removal, so the response
+	// signed before the revocation is not added to the cache
+	gen uint64
+}
+
+func newOCSPCache(size int, maxAge time.Duration) *ocspCache {
+	return &ocspCache{
+		size:     size,
+		maxAge:   maxAge,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		bySerial: make(map[string][]string),
+	}
+}
+
+// certIDKey returns the cache key for the CertID of the request
+func certIDKey(req *ocsputil.CertID) string {
+	h := sha256.New()
+	for _, b := range [][]byte{
+		{byte(req.HashAlgorithm)},
+		req.IssuerNameHash,
+		req.IssuerKeyHash,
+		req.SerialNumber.Bytes(),
+	} {
+		// length-prefixed, so the fields are not ambiguous
+		_, _ = h.Write([]byte{byte(len(b))})
+		_, _ = h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached response, if it's not expired
+func (c *ocspCache) Get(key string, now time.Time) *ocspCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*ocspCacheEntry)
+	if !now.Before(e.expires) {
+		c.removeElement(el)
+		return nil
+	}
+	c.ll.MoveToFront(el)
+	return e
+}
+
+// Generation returns the current generation of the cache,
+// it must be obtained before the response is requested from CA
+func (c *ocspCache) Generation() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.gen
+}
+
+// Add adds the response to the cache, the least recently used entry is evicted
+// if the cache is full. The response without next update time,
+// or requested before a certificate was revoked, is not cached.
+func (c *ocspCache) Add(e *ocspCacheEntry, gen uint64, now time.Time) {
+	if e.nextUpdate.IsZero() || !now.Before(e.nextUpdate) {
+		return
+	}
+	e.expires = e.nextUpdate
+	if c.maxAge > 0 && now.Add(c.maxAge).Before(e.expires) {
+		e.expires = now.Add(c.maxAge)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+	if el, ok := c.items[e.key]; ok {
+		c.removeElement(el)
+	}
+	c.items[e.key] = c.ll.PushFront(e)
+	c.bySerial[e.serial] = append(c.bySerial[e.serial], e.key)
+
+	for c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Remove removes the responses of the certificate with the issuer and serial number.
+// The responses cached without issuer are removed for any issuer,
+// as the issuer of the request is not known.
+func (c *ocspCache) Remove(ikid, serial string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.gen++
+	removed := 0
+	keys := append([]string(nil), c.bySerial[serial]...)
+	for _, key := range keys {
+		el, ok := c.items[key]
+		if !ok {
+			continue
+		}
+		if e := el.Value.(*ocspCacheEntry); e.ikid == "" || e.ikid == ikid {
+			c.removeElement(el)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of cached responses
+func (c *ocspCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.ll.Len()
+}
+
+func (c *ocspCache) removeElement(el *list.Element) {
+	e := c.ll.Remove(el).(*ocspCacheEntry)
+	delete(c.items, e.key)
+
+	keys := c.bySerial[e.serial]
+	for i, key := range keys {
+		if key == e.key {
+			keys = append(keys[:i], keys[i+1:]...)
+			break
+		}
+	}
+	if len(keys) == 0 {
+		delete(c.bySerial, e.serial)
+	} else {
+		c.bySerial[e.serial] = keys
+	}
+}
@@ -0,0 +1,119 @@
+package cis
+
+import (
+	"crypto"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/pkg/ocsputil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOCSPCache(t *testing.T) {
+	now := time.Now()
+	c := newOCSPCache(2, time.Hour)
+
+	entry := func(key, serial string, nextUpdate time.Time) *ocspCacheEntry {
+		return &ocspCacheEntry{
+			key:        key,
+			serial:     serial,
+			der:        []byte(key),
+			thisUpdate: now,
+			nextUpdate: nextUpdate,
+		}
+	}
+
+	// not cacheable
+	c.Add(entry("k0", "0", time.Time{}), c.Generation(), now)
+	c.Add(entry("k0", "0", now.Add(-time.Second)), c.Generation(), now)
+	assert.Equal(t, 0, c.Len())
+
+	c.Add(entry("k1", "1", now.Add(time.Minute)), c.Generation(), now)
+	c.Add(entry("k2", "1", now.Add(8*time.Hour)), c.Generation(), now)
+	assert.Equal(t, 2, c.Len())
+
+	e := c.Get("k2", now)
+	if assert.NotNil(t, e) {
+		// limited by maxAge
+		assert.Equal(t, now.Add(time.Hour), e.expires)
+	}
+	assert.Nil(t, c.Get("k1", now.Add(time.Minute)), "expired")
+	assert.Equal(t, 1, c.Len())
+
+	c.Add(entry("k3", "3", now.Add(time.Hour)), c.Generation(), now)
+	c.Add(entry("k4", "4", now.Add(time.Hour)), c.Generation(), now)
+	assert.Equal(t, 2, c.Len())
+	assert.Nil(t, c.Get("k2", now), "evicted")
+	assert.NotNil(t, c.Get("k3", now))
+
+	// replaced
+	c.Add(entry("k3", "3", now.Add(time.Hour)), c.Generation(), now)
+	assert.Equal(t, 2, c.Len())
+	assert.Len(t, c.bySerial["3"], 1)
+
+	// signed before revocation
+	gen := c.Generation()
+	assert.Equal(t, 1, c.Remove("ikid", "3"))
+	assert.Equal(t, 0, c.Remove("ikid", "3"))
+	c.Add(entry("k3", "3", now.Add(time.Hour)), gen, now)
+	assert.Nil(t, c.Get("k3", now))
+	assert.Equal(t, 1, c.Len())
+	assert.Len(t, c.bySerial, 1)
+}
+
+func TestOCSPCacheRemove(t *testing.T) {
+	now := time.Now()
+	c := newOCSPCache(10, time.Hour)
+
+	add := func(key, ikid, serial string) {
+		c.Add(&ocspCacheEntry{
+			key:        key,
+			ikid:       ikid,
+			serial:     serial,
+			thisUpdate: now,
+			nextUpdate: now.Add(time.Hour),
+		}, c.Generation(), now)
+	}
+	add("a/1", "a", "1")
+	add("b/1", "b", "1")
+	add("/1", "", "1")
+	add("a/2", "a", "2")
+	assert.Equal(t, 4, c.Len())
+
+	// the same serial of other issuer is not removed
+	assert.Equal(t, 2, c.Remove("a", "1"))
+	assert.Nil(t, c.Get("a/1", now))
+	assert.Nil(t, c.Get("/1", now))
+	assert.NotNil(t, c.Get("b/1", now))
+	assert.NotNil(t, c.Get("a/2", now))
+	assert.Equal(t, []string{"b/1"}, c.bySerial["1"])
+
+	assert.Equal(t, 0, c.Remove("c", "2"))
+	assert.Equal(t, 1, c.Remove("b", "1"))
+	assert.Equal(t, 1, c.Len())
+	assert.Len(t, c.bySerial, 1)
+}
+
+func TestCertIDKey(t *testing.T) {
+	req := &ocsputil.CertID{
+		HashAlgorithm: crypto.SHA1,
+		IssuerKeyHash: []byte{1, 2, 3},
+		SerialNumber:  big.NewInt(1234),
+	}
+	key := certIDKey(req)
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, certIDKey(req))
+
+	keys := map[string]bool{key: true}
+	for i, r := range []*ocsputil.CertID{
+		{HashAlgorithm: crypto.SHA256, IssuerKeyHash: []byte{1, 2, 3}, SerialNumber: big.NewInt(1234)},
+		{HashAlgorithm: crypto.SHA1, IssuerNameHash: []byte{1, 2, 3}, SerialNumber: big.NewInt(1234)},
+		{HashAlgorithm: crypto.SHA1, IssuerKeyHash: []byte{1, 2, 3}, SerialNumber: big.NewInt(1235)},
+	} {
+		k := certIDKey(r)
+		assert.False(t, keys[k], strconv.Itoa(i))
+		keys[k] = true
+	}
+}
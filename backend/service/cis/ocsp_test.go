@@ -6,16 +6,19 @@ import (
 	"crypto"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/base64"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/effective-security/porto/restserver"
 	"github.com/effective-security/porto/xhttp/header"
 	v1 "github.com/effective-security/trusty/api"
 	pb "github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/trusty/backend/service/cis"
+	"github.com/effective-security/trusty/pkg/ocsputil"
 	"github.com/effective-security/xpki/certutil"
 	"github.com/effective-security/xpki/cryptoprov/inmemcrypto"
 	"github.com/effective-security/xpki/csr"
@@ -201,7 +204,112 @@ func Test_ocspResponse2(t *testing.T) {
 		res, err := ocsp.ParseResponse(w.Body.Bytes(), iss)
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
+		assert.Contains(t, hdr.Get("Cache-Control"), "public, no-transform, must-revalidate")
+		assert.Equal(t, res.NextUpdate.UTC().Format(http.TimeFormat), hdr.Get("Expires"))
+		assert.NotEmpty(t, hdr.Get("ETag"))
+		assert.NotEmpty(t, hdr.Get("Last-Modified"))
 	}
+
+	js, err := ocspReqs[0].Marshal()
+	require.NoError(t, err)
+
+	get := func(t *testing.T, hdrs ...string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(http.MethodGet, v1.PathForOCSP, nil)
+		require.NoError(t, err)
+		for i := 0; i < len(hdrs); i += 2 {
+			r.Header.Set(hdrs[i], hdrs[i+1])
+		}
+		w := httptest.NewRecorder()
+		svc.GetOcspHandler()(w, r, restserver.Params{
+			{
+				Key:   "body",
+				Value: base64.StdEncoding.EncodeToString(js),
+			},
+		})
+		return w
+	}
+
+	t.Run("multiple_with_nonce", func(t *testing.T) {
+		var ids []*ocsputil.CertID
+		for _, ocspReq := range ocspReqs[:4] {
+			js, err := ocspReq.Marshal()
+			require.NoError(t, err)
+			req, err := ocsputil.ParseRequest(js)
+			require.NoError(t, err)
+			ids = append(ids, req.CertIDs...)
+		}
+		js, err := ocsputil.CreateRequest(ids, []byte("0123456789abcdef"))
+		require.NoError(t, err)
+
+		r, err := http.NewRequest(http.MethodPost, v1.PathForOCSP, bytes.NewReader(js))
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		h(w, r, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+		res, err := ocsputil.ParseResponse(w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Len(t, res.Responses, 4)
+		assert.NotNil(t, res.Nonce())
+	})
+
+	t.Run("by_issuer_id", func(t *testing.T) {
+		post := func(ikid string) *httptest.ResponseRecorder {
+			r, err := http.NewRequest(http.MethodPost, v1.PathForOCSPByID, bytes.NewReader(js))
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			h(w, r, restserver.Params{
+				{
+					Key:   "issuer_id",
+					Value: ikid,
+				},
+			})
+			require.Equal(t, http.StatusOK, w.Code)
+			return w
+		}
+
+		w := post(certutil.GetSubjectKeyID(iss))
+		res, err := ocsp.ParseResponse(w.Body.Bytes(), iss)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Good, res.Status)
+
+		// the responder of other issuer
+		w = post("other")
+		_, err = ocsp.ParseResponse(w.Body.Bytes(), iss)
+		assert.Equal(t, ocsp.ResponseError{Status: ocsp.Unauthorized}, err)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	})
+
+	w := get(t)
+	require.Equal(t, http.StatusOK, w.Code)
+	tag := w.Header().Get("ETag")
+
+	// cached
+	w2 := get(t)
+	require.Equal(t, http.StatusOK, w2.Code)
+	assert.Equal(t, w.Body.Bytes(), w2.Body.Bytes())
+
+	w2 = get(t, "If-None-Match", tag)
+	assert.Equal(t, http.StatusNotModified, w2.Code)
+	assert.Empty(t, w2.Body.Bytes())
+
+	w2 = get(t, "If-Modified-Since", time.Now().UTC().Format(http.TimeFormat))
+	assert.Equal(t, http.StatusNotModified, w2.Code)
+
+	_, err = svc.CAClient().RevokeCertificate(context.Background(), &pb.RevokeCertificateRequest{
+		ID:     res.Certificate.ID,
+		Reason: pb.Reason_KEY_COMPROMISE,
+	})
+	require.NoError(t, err)
+
+	// the cached response is removed on revocation
+	w2 = get(t, "If-None-Match", tag)
+	require.Equal(t, http.StatusOK, w2.Code)
+	assert.NotEqual(t, tag, w2.Header().Get("ETag"))
+	ocspRes, err := ocsp.ParseResponse(w2.Body.Bytes(), iss)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Revoked, ocspRes.Status)
 }
 
 func generateCSR() []byte {
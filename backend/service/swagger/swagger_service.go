@@ -1,20 +1,33 @@
 package swagger
 
 import (
+	"net/http"
+	"os"
+
 	"github.com/effective-security/porto/gserver"
 	"github.com/effective-security/porto/restserver"
+	"github.com/effective-security/porto/xhttp/header"
+	"github.com/effective-security/porto/xhttp/httperror"
+	"github.com/effective-security/porto/xhttp/marshal"
+	v1 "github.com/effective-security/trusty/api"
+	"github.com/effective-security/trusty/api/pb/openapi"
+	"github.com/effective-security/trusty/backend/config"
 	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
 )
 
 // ServiceName provides the Service Name for this package
 const ServiceName = "swagger"
 
+// contentTypeYAML specifies Content-Type of OpenAPI specs
+const contentTypeYAML = "application/yaml"
+
 var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/backend/service", "swagger")
 
 // Service defines the Swagger service
 type Service struct {
 	server gserver.GServer
-	cfg    *gserver.Config
+	cfg    *gserver.SwaggerCfg
 }
 
 // Factory returns a factory of the service
@@ -23,10 +36,10 @@ func Factory(server gserver.GServer) any {
 		logger.Panic("swagger.Factory: invalid parameter")
 	}
 
-	return func() {
+	return func(cfg *config.Configuration) {
 		svc := &Service{
 			server: server,
-			cfg:    server.Configuration(),
+			cfg:    &cfg.Swagger,
 		}
 
 		server.AddService(svc)
@@ -48,34 +61,37 @@ func (s *Service) Close() {
 	logger.KV(xlog.INFO, "closed", ServiceName)
 }
 
-// RegisterRoute adds the Status API endpoints to the overall URL router
+// RegisterRoute adds the Swagger API endpoints to the overall URL router
 func (s *Service) RegisterRoute(r restserver.Router) {
-	/* TODO: swagger
-	if s.cfg.Swagger.Enabled {
+	if s.cfg.Enabled {
 		r.GET(v1.PathForSwagger, s.swagger())
 	}
-	*/
 }
 
-/*
+// swagger returns OpenAPI spec of the service,
+// from the configured file or the spec built into the server
 func (s *Service) swagger() restserver.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p restserver.Params) {
 		svc := p.ByName("service")
 
-		f := s.cfg.Swagger.Files[svc]
-		if f == "" {
-			marshal.WriteJSON(w, r, httperror.NotFound("file not found for: %s", svc))
-			return
-
+		var sw []byte
+		var err error
+		if f := s.cfg.Files[svc]; f != "" {
+			sw, err = os.ReadFile(f)
+			if err != nil {
+				marshal.WriteJSON(w, r, httperror.Unexpected("unable to load swagger file: %s", f).
+					WithCause(errors.WithStack(err)))
+				return
+			}
+		} else {
+			sw, err = openapi.Specs.ReadFile(svc + ".openapi.yaml")
+			if err != nil {
+				marshal.WriteJSON(w, r, httperror.NotFound("file not found for: %s", svc))
+				return
+			}
 		}
-		sw, err := os.ReadFile(f)
-		if err != nil {
-			marshal.WriteJSON(w, r, httperror.Unexpected("unable to load swagger file: %s", f).
-				WithCause(errors.WithStack(err)))
-			return
-		}
-		w.Header().Set(header.ContentType, header.ApplicationJSON)
-		w.Write(sw)
+
+		w.Header().Set(header.ContentType, contentTypeYAML)
+		_, _ = w.Write(sw)
 	}
 }
-*/
@@ -0,0 +1,63 @@
+package swagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/effective-security/porto/gserver"
+	"github.com/effective-security/porto/restserver"
+	"github.com/effective-security/porto/xhttp/header"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwagger(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "custom.yaml")
+	require.NoError(t, os.WriteFile(custom, []byte("openapi: 3.0.3\n"), 0644))
+
+	svc := &Service{
+		cfg: &gserver.SwaggerCfg{
+			Enabled: true,
+			Files: map[string]string{
+				"custom":  custom,
+				"missing": "/tmp/trusty/notfound.yaml",
+			},
+		},
+	}
+	router := restserver.NewRouter(http.NotFound)
+	svc.RegisterRoute(router)
+	h := router.Handler()
+
+	get := func(svc string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/swagger/"+svc, nil))
+		return w
+	}
+
+	for _, name := range []string{"ca", "cis", "status"} {
+		w := get(name)
+		require.Equal(t, http.StatusOK, w.Code, name)
+		assert.Equal(t, contentTypeYAML, w.Header().Get(header.ContentType))
+		assert.Contains(t, w.Body.String(), "openapi: 3.0.3")
+	}
+
+	w := get("custom")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "openapi: 3.0.3\n", w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, get("notfound").Code)
+	assert.Equal(t, http.StatusInternalServerError, get("missing").Code)
+
+	t.Run("disabled", func(t *testing.T) {
+		svc := &Service{cfg: &gserver.SwaggerCfg{}}
+		router := restserver.NewRouter(http.NotFound)
+		svc.RegisterRoute(router)
+
+		w := httptest.NewRecorder()
+		router.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/swagger/ca", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,569 @@
+package reconcile
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/effective-security/porto/pkg/tasks"
+	"github.com/effective-security/porto/xhttp/correlation"
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/trusty/pkg/storage"
+	"github.com/effective-security/xlog"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+)
+
+var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/backend/tasks", "reconcile")
+
+// TaskName is the name of this task
+const TaskName = "publisher_reconcile"
+
+// Drift types
+const (
+	// DriftMissing specifies the object not found in the bucket
+	DriftMissing = "missing"
+	// DriftStale specifies the object that does not match DB
+	DriftStale = "stale"
+	// DriftMetadata specifies the object with invalid metadata
+	DriftMetadata = "metadata"
+	// DriftOrphaned specifies the object in the bucket not found in DB
+	DriftOrphaned = "orphaned"
+)
+
+// clockSkew specifies the allowed time difference between DB and storage
+const clockSkew = time.Minute
+
+var (
+	driftKinds = []string{model.PublishKindCert, model.PublishKindIssuer, model.PublishKindCrl, model.PublishKindRoots}
+	driftTypes = []string{DriftMissing, DriftStale, DriftMetadata, DriftOrphaned}
+)
+
+// Task defines the publisher reconciliation task
+type Task struct {
+	name     string
+	schedule string
+	db       cadb.CaDb
+	cfg      *certpublisher.Config
+	ctx      context.Context
+
+	// aia provides AIA URLs of the issuers by SKID,
+	// the issuers are not reconciled if nil
+	aia map[string]string
+
+	// metadata specifies to check metadata of the published objects
+	metadata bool
+	// dryRun specifies to report the drift only, without republishing
+	dryRun bool
+
+	// now is used in tests
+	now func() time.Time
+}
+
+// Report provides the drift counts by kind and type
+type Report map[string]map[string]int
+
+func (r Report) add(kind, typ string) {
+	m := r[kind]
+	if m == nil {
+		m = map[string]int{}
+		r[kind] = m
+	}
+	m[typ]++
+}
+
+// bucket provides the listed objects,
+// and the keys of the objects expected by DB
+type bucket struct {
+	kind     string
+	location string
+	options  *storage.Options
+	objects  map[string]*storage.ObjectInfo
+	expected map[string]bool
+}
+
+// target provides the buckets of the destination,
+// nil bucket is not published to
+type target struct {
+	name     string
+	policies map[string]certpublisher.Policy
+	certs    *bucket
+	crls     *bucket
+	issuers  *bucket
+	roots    *bucket
+}
+
+func (t *Task) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"reason", "recover",
+				"err", r,
+				"stack", debug.Stack())
+		}
+	}()
+
+	logger.ContextKV(t.ctx, xlog.TRACE, "task", TaskName)
+
+	report, err := t.reconcile(t.ctx)
+	if err != nil {
+		logger.ContextKV(t.ctx, xlog.ERROR,
+			"task", TaskName,
+			"err", err.Error())
+		return
+	}
+
+	for _, kind := range driftKinds {
+		for _, typ := range driftTypes {
+			metricskey.CAPublishDrift.SetGauge(float64(report[kind][typ]), kind, typ)
+		}
+	}
+	logger.ContextKV(t.ctx, xlog.NOTICE, "task", TaskName, "drift", report)
+}
+
+// reconcile compares the published objects in all destinations with DB,
+// and enqueues publish jobs for missing or stale objects
+func (t *Task) reconcile(ctx context.Context) (Report, error) {
+	report := Report{}
+	buckets := map[string]*bucket{}
+
+	var targets []*target
+	for _, d := range append([]*certpublisher.Destination{t.cfg.Primary()}, t.cfg.Destinations...) {
+		if d == nil {
+			continue
+		}
+		tg := &target{
+			name:     d.Name,
+			policies: certpublisher.MergePolicies(t.cfg.Policies, d.Policies),
+		}
+
+		var err error
+		opts := d.StorageOptions()
+		if tg.certs, err = t.bucket(ctx, buckets, d.CertsBucket, model.PublishKindCert, opts); err != nil {
+			return nil, err
+		}
+		if tg.crls, err = t.bucket(ctx, buckets, d.CRLBucket, model.PublishKindCrl, opts); err != nil {
+			return nil, err
+		}
+		if t.aia != nil {
+			if tg.issuers, err = t.bucket(ctx, buckets, d.IssuersLocation(), model.PublishKindIssuer, opts); err != nil {
+				return nil, err
+			}
+		}
+		if tg.roots, err = t.bucket(ctx, buckets, d.RootsBucket, model.PublishKindRoots, opts); err != nil {
+			return nil, err
+		}
+		targets = append(targets, tg)
+	}
+
+	if err := t.reconcileCerts(ctx, targets, report); err != nil {
+		return nil, err
+	}
+	if err := t.reconcileCrls(ctx, targets, report); err != nil {
+		return nil, err
+	}
+	if err := t.reconcileRoots(ctx, targets, report); err != nil {
+		return nil, err
+	}
+
+	for _, b := range buckets {
+		for key := range b.objects {
+			if !b.expected[key] {
+				report.add(b.kind, DriftOrphaned)
+				logger.ContextKV(ctx, xlog.WARNING,
+					"drift", DriftOrphaned,
+					"bucket", b.location,
+					"object", key)
+			}
+		}
+	}
+	return report, nil
+}
+
+// bucket returns the listed bucket, or nil if the location is not configured.
+// The same location can be used for several kinds and destinations.
+func (t *Task) bucket(ctx context.Context, buckets map[string]*bucket, location, kind string, opts *storage.Options) (*bucket, error) {
+	if location == "" {
+		return nil, nil
+	}
+	location = strings.TrimSuffix(location, "/")
+	if b := buckets[location]; b != nil {
+		return b, nil
+	}
+
+	base := location + "/"
+	if !strings.Contains(location, "://") {
+		abs, err := filepath.Abs(location)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		base = abs + "/"
+	}
+
+	list, err := storage.List(ctx, location+"/", opts)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to list bucket: %s", location)
+	}
+
+	b := &bucket{
+		kind:     kind,
+		location: location,
+		options:  opts,
+		objects:  make(map[string]*storage.ObjectInfo, len(list)),
+		expected: map[string]bool{},
+	}
+	for _, o := range list {
+		b.objects[strings.TrimPrefix(o.Path, base)] = o
+	}
+	buckets[location] = b
+	return b, nil
+}
+
+// check returns the drift of the object in the bucket, and adds it to the report
+func (t *Task) check(
+	ctx context.Context,
+	report Report,
+	kind string,
+	tg *target,
+	b *bucket,
+	key string,
+	policy string,
+	stale func(obj *storage.ObjectInfo) bool,
+) string {
+	b.expected[key] = true
+
+	drift := ""
+	obj := b.objects[key]
+	if obj == nil {
+		drift = DriftMissing
+	} else if stale(obj) {
+		drift = DriftStale
+	} else if t.metadata && !t.hasContentType(ctx, b, obj, tg.policies[policy].ContentType) {
+		drift = DriftMetadata
+	}
+
+	if drift != "" {
+		report.add(kind, drift)
+		logger.ContextKV(ctx, xlog.WARNING,
+			"drift", drift,
+			"destination", tg.name,
+			"bucket", b.location,
+			"object", key)
+	}
+	return drift
+}
+
+// exactSize returns stale check for the expected content
+func exactSize(data []byte) func(obj *storage.ObjectInfo) bool {
+	return func(obj *storage.ObjectInfo) bool {
+		return obj.Size != int64(len(data))
+	}
+}
+
+func (t *Task) reconcileCerts(ctx context.Context, targets []*target, report Report) error {
+	last := uint64(0)
+	for {
+		list, err := t.db.ListCertificates(ctx, "", 500, last)
+		if err != nil {
+			return errors.WithMessage(err, "unable to list certificates")
+		}
+		if len(list) == 0 {
+			return nil
+		}
+
+		for _, crt := range list {
+			last = crt.ID
+			// external certificates are not published
+			if crt.External {
+				continue
+			}
+
+			kind := model.PublishKindCert
+			if crt.Profile == "ca" {
+				kind = model.PublishKindIssuer
+			}
+
+			key := crt.FileName()
+			// the published chain can't be smaller than the certificate
+			minSize := int64(len(strings.TrimSpace(crt.Pem)))
+			stale := func(obj *storage.ObjectInfo) bool {
+				return obj.Size < minSize
+			}
+
+			drift := ""
+			for _, tg := range targets {
+				if tg.certs == nil {
+					continue
+				}
+				if d := t.check(ctx, report, kind, tg, tg.certs, key, certpublisher.KindCert, stale); drift == "" {
+					drift = d
+				}
+			}
+			if kind == model.PublishKindIssuer {
+				if d := t.checkIssuer(ctx, targets, report, crt); drift == "" {
+					drift = d
+				}
+			}
+
+			if drift != "" {
+				t.enqueue(ctx, drift, key, &model.PublishJob{
+					Kind:  kind,
+					IKID:  crt.IKID,
+					RefID: crt.ID,
+				})
+			}
+		}
+	}
+}
+
+// checkIssuer returns the drift of the issuer certificate and PKCS#7 bundle
+// published at AIA location
+func (t *Task) checkIssuer(ctx context.Context, targets []*target, report Report, crt *model.Certificate) string {
+	aia, ok := t.aia[crt.SKID]
+	if !ok {
+		return ""
+	}
+
+	dto := crt.ToPB()
+	certs, err := certpublisher.PEMCertificates(dto.Pem)
+	if err != nil {
+		return ""
+	}
+	p7c, err := certpublisher.IssuerPKCS7(dto)
+	if err != nil {
+		return ""
+	}
+
+	key := certpublisher.IssuerFileName(aia, crt.SKID)
+	drift := ""
+	for _, tg := range targets {
+		if tg.issuers == nil {
+			continue
+		}
+		if d := t.check(ctx, report, model.PublishKindIssuer, tg, tg.issuers, key, certpublisher.KindIssuer, exactSize(certs[0])); drift == "" {
+			drift = d
+		}
+		if d := t.check(ctx, report, model.PublishKindIssuer, tg, tg.issuers, certpublisher.P7CFileName(key), certpublisher.KindP7C, exactSize(p7c)); drift == "" {
+			drift = d
+		}
+	}
+	return drift
+}
+
+func (t *Task) reconcileCrls(ctx context.Context, targets []*target, report Report) error {
+	list, err := t.db.ListCrls(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "unable to list CRLs")
+	}
+
+	shards, err := t.db.ListShardCrls(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "unable to list CRL shards")
+	}
+	list = append(list, shards...)
+
+	// CRL shards are republished with the complete CRL
+	enqueued := map[string]bool{}
+
+	now := t.now().UTC()
+	for _, crl := range list {
+		key := crl.IKID + ".crl"
+		if crl.Shard > 0 {
+			key = certpublisher.ShardCRLName(key, crl.Shard)
+		} else {
+			for _, tg := range targets {
+				if tg.crls != nil {
+					// delta CRLs are republished with the complete CRL
+					tg.crls.expected[certpublisher.DeltaCRLName(key)] = true
+				}
+			}
+		}
+
+		der, derErr := certpublisher.CRLDer(crl.ToDTO())
+		stale := func(obj *storage.ObjectInfo) bool {
+			// expired, or published before the latest CRL
+			return crl.NextUpdate.UTC().Before(now) ||
+				obj.ModTime.Add(clockSkew).Before(crl.ThisUpdate.UTC()) ||
+				(derErr == nil && int64(len(der)) != obj.Size)
+		}
+
+		drift := ""
+		for _, tg := range targets {
+			if tg.crls == nil {
+				continue
+			}
+			if d := t.check(ctx, report, model.PublishKindCrl, tg, tg.crls, key, certpublisher.KindCRL, stale); drift == "" {
+				drift = d
+			}
+		}
+
+		if drift != "" && !enqueued[crl.IKID] {
+			enqueued[crl.IKID] = true
+			t.enqueue(ctx, drift, key, &model.PublishJob{
+				Kind: model.PublishKindCrl,
+				IKID: crl.IKID,
+			})
+		}
+	}
+	return nil
+}
+
+func (t *Task) reconcileRoots(ctx context.Context, targets []*target, report Report) error {
+	configured := false
+	for _, tg := range targets {
+		configured = configured || tg.roots != nil
+	}
+	if !configured {
+		return nil
+	}
+
+	list, err := t.db.GetRootCertificates(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "unable to list root certificates")
+	}
+	roots := list.ToDTO()
+	bundle := certpublisher.RootsBundle(roots)
+	p7c, err := certpublisher.RootsPKCS7(roots)
+	if err != nil {
+		// nothing to publish
+		return nil
+	}
+
+	drift := ""
+	for _, tg := range targets {
+		if tg.roots == nil {
+			continue
+		}
+		if d := t.check(ctx, report, model.PublishKindRoots, tg, tg.roots, certpublisher.RootsFileName, certpublisher.KindRoots, exactSize(bundle)); drift == "" {
+			drift = d
+		}
+		if d := t.check(ctx, report, model.PublishKindRoots, tg, tg.roots, certpublisher.RootsP7CFileName, certpublisher.KindP7C, exactSize(p7c)); drift == "" {
+			drift = d
+		}
+	}
+
+	if drift != "" {
+		t.enqueue(ctx, drift, certpublisher.RootsFileName, &model.PublishJob{
+			Kind: model.PublishKindRoots,
+		})
+	}
+	return nil
+}
+
+// hasContentType returns false if the storage supports metadata,
+// and the object has different Content-Type
+func (t *Task) hasContentType(ctx context.Context, b *bucket, obj *storage.ObjectInfo, contentType string) bool {
+	info, err := storage.Stat(ctx, obj.Path, b.options)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.WARNING,
+			"object", obj.Path,
+			"err", err.Error())
+		return true
+	}
+	if len(info.Metadata) == 0 {
+		return true
+	}
+	return info.Metadata["Content-Type"] == contentType
+}
+
+// enqueue adds the job to the publish outbox, unless dry run
+func (t *Task) enqueue(ctx context.Context, drift, key string, job *model.PublishJob) {
+	logger.ContextKV(ctx, xlog.NOTICE,
+		"status", "republish",
+		"drift", drift,
+		"object", key,
+		"kind", job.Kind,
+		"ref_id", job.RefID,
+		"dry_run", t.dryRun)
+
+	if t.dryRun {
+		return
+	}
+	_, err := t.db.EnqueuePublishJob(ctx, job)
+	if err != nil {
+		logger.ContextKV(ctx, xlog.ERROR,
+			"status", "failed to enqueue publish job",
+			"object", key,
+			"err", err.Error())
+	}
+}
+
+func create(
+	name string,
+	db cadb.CaDb,
+	cfg *config.Publisher,
+	ca *authority.Authority,
+	schedule string,
+	args []string,
+) (*Task, error) {
+	flagSet := flag.NewFlagSet("flags", flag.ContinueOnError)
+	metadataPtr := flagSet.Bool("metadata", false, "check metadata of the published objects")
+	dryRunPtr := flagSet.Bool("dry-run", false, "report the drift only, without republishing")
+
+	err := flagSet.Parse(args)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to parse arguments: %v", args)
+	}
+
+	task := &Task{
+		name:     name,
+		schedule: schedule,
+		db:       db,
+		cfg:      cfg.PublisherConfig(),
+		ctx:      correlation.WithID(context.Background()),
+		metadata: *metadataPtr,
+		dryRun:   *dryRunPtr,
+		now:      time.Now,
+	}
+	if ca != nil {
+		task.aia = map[string]string{}
+		for _, issuer := range ca.Issuers() {
+			task.aia[issuer.SubjectKID()] = issuer.AiaURL()
+		}
+	}
+
+	logger.KV(xlog.INFO,
+		"cert_bucket", cfg.CertsBucket,
+		"crl_bucket", cfg.CRLBucket,
+		"destinations", len(cfg.Destinations),
+		"issuers", len(task.aia),
+		"metadata", task.metadata,
+		"dry_run", task.dryRun)
+
+	return task, nil
+}
+
+// Factory returns a task factory
+func Factory(
+	s tasks.Scheduler,
+	name string,
+	schedule string,
+	args ...string,
+) any {
+	return func(cfg *config.Configuration, db cadb.CaDb, ca *authority.Authority) error {
+		if cfg.RegistrationAuthority == nil {
+			return errors.New("publisher is not configured")
+		}
+		task, err := create(name, db, &cfg.RegistrationAuthority.Publisher, ca, schedule, args)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		job, err := tasks.NewTask(task.schedule)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to schedule a job on schedule: %q", task.schedule)
+		}
+
+		t := job.Do(task.name, task.run)
+		s.Add(t)
+		return nil
+	}
+}
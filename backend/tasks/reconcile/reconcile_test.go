@@ -0,0 +1,289 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/config"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/pkg/certpublisher"
+	"github.com/effective-security/trusty/tests/testutils"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+)
+
+type mockDB struct {
+	cadb.CaDb
+
+	certs  model.Certificates
+	crls   []*model.Crl
+	shards []*model.Crl
+	roots  model.RootCertificates
+	jobs   []*model.PublishJob
+	err    error
+}
+
+func (m *mockDB) GetRootCertificates(_ context.Context) (model.RootCertificates, error) {
+	return m.roots, m.err
+}
+
+func (m *mockDB) ListCertificates(_ context.Context, ikid string, limit int, afterID uint64) (model.Certificates, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var list model.Certificates
+	for _, c := range m.certs {
+		if c.ID > afterID && (ikid == "" || ikid == c.IKID) && len(list) < limit {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockDB) ListCrls(_ context.Context) ([]*model.Crl, error) {
+	return m.crls, m.err
+}
+
+func (m *mockDB) ListShardCrls(_ context.Context) ([]*model.Crl, error) {
+	return m.shards, m.err
+}
+
+func (m *mockDB) EnqueuePublishJob(_ context.Context, job *model.PublishJob) (*model.PublishJob, error) {
+	m.jobs = append(m.jobs, job)
+	return job, nil
+}
+
+func TestFactory(t *testing.T) {
+	c := dig.New()
+	err := c.Provide(func() cadb.CaDb {
+		return &mockDB{}
+	})
+	require.NoError(t, err)
+	err = c.Provide(func() *authority.Authority {
+		return nil
+	})
+	require.NoError(t, err)
+	err = c.Provide(func() *config.Configuration {
+		return &config.Configuration{
+			RegistrationAuthority: &config.RegistrationAuthority{},
+		}
+	})
+	require.NoError(t, err)
+
+	scheduler := &testutils.MockScheduler{}
+	err = c.Invoke(Factory(scheduler, "test_run", "Every 30 minutes", "-dry-run"))
+	require.NoError(t, err)
+	require.Len(t, scheduler.Tasks, 1)
+
+	err = c.Invoke(Factory(scheduler, "test_run", "Every 30 minutes", "-unknown"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse arguments")
+}
+
+func TestReconcile(t *testing.T) {
+	now := time.Now().UTC()
+	dir := t.TempDir()
+	certsDir := filepath.Join(dir, "certs")
+	crlsDir := filepath.Join(dir, "crls")
+	require.NoError(t, os.MkdirAll(certsDir, 0755))
+	require.NoError(t, os.MkdirAll(crlsDir, 0755))
+
+	newCert := func(id uint64, serial string) *model.Certificate {
+		return &model.Certificate{
+			ID:           id,
+			IKID:         "ikid",
+			SerialNumber: serial,
+			Profile:      "server",
+			Pem:          "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----",
+		}
+	}
+
+	published := newCert(1, "100000000000000001")
+	missing := newCert(2, "100000000000000002")
+	truncated := newCert(3, "100000000000000003")
+	issuer := newCert(4, "100000000000000004")
+	issuer.Profile = "ca"
+	external := newCert(5, "100000000000000005")
+	external.External = true
+
+	write := func(fn string, data []byte) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(fn), 0755))
+		require.NoError(t, os.WriteFile(fn, data, 0644))
+	}
+	write(filepath.Join(certsDir, published.FileName()), certpublisher.CertificateChain(published.ToPB()))
+	write(filepath.Join(certsDir, truncated.FileName()), []byte("pem"))
+	write(filepath.Join(certsDir, "orphaned.pem"), []byte("pem"))
+
+	crlPem := string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: []byte("crl")}))
+	current := &model.Crl{
+		ID:         10,
+		IKID:       "current",
+		ThisUpdate: xdb.Time(now.Add(-time.Hour)),
+		NextUpdate: xdb.Time(now.Add(time.Hour)),
+		Pem:        crlPem,
+	}
+	expired := &model.Crl{
+		ID:         11,
+		IKID:       "expired",
+		ThisUpdate: xdb.Time(now.Add(-2 * time.Hour)),
+		NextUpdate: xdb.Time(now.Add(-time.Hour)),
+		Pem:        crlPem,
+	}
+	absent := &model.Crl{
+		ID:         12,
+		IKID:       "absent",
+		ThisUpdate: xdb.Time(now.Add(-time.Hour)),
+		NextUpdate: xdb.Time(now.Add(time.Hour)),
+		Pem:        crlPem,
+	}
+	write(filepath.Join(crlsDir, "current.crl"), []byte("crl"))
+	write(filepath.Join(crlsDir, "expired.crl"), []byte("crl"))
+
+	shard := func(ikid string, shard uint32) *model.Crl {
+		c := *current
+		c.IKID = ikid
+		c.Shard = shard
+		return &c
+	}
+	write(filepath.Join(crlsDir, "current", "1.crl"), []byte("crl"))
+	write(filepath.Join(crlsDir, "absent", "1.crl"), []byte("crl"))
+
+	db := &mockDB{
+		certs:  model.Certificates{published, missing, truncated, issuer, external},
+		crls:   []*model.Crl{current, expired, absent},
+		shards: []*model.Crl{shard("current", 1), shard("current", 2), shard("absent", 1)},
+	}
+	cfg := &config.Publisher{
+		CertsBucket: certsDir,
+		CRLBucket:   crlsDir,
+	}
+
+	task, err := create(TaskName, db, cfg, nil, "every 1 hour", []string{"-dry-run"})
+	require.NoError(t, err)
+
+	report, err := task.reconcile(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Report{
+		model.PublishKindCert: {
+			DriftMissing:  1,
+			DriftStale:    1,
+			DriftOrphaned: 1,
+		},
+		model.PublishKindIssuer: {
+			DriftMissing: 1,
+		},
+		model.PublishKindCrl: {
+			DriftMissing: 2,
+			DriftStale:   1,
+		},
+	}, report)
+	assert.Empty(t, db.jobs, "dry run must not enqueue jobs")
+
+	task, err = create(TaskName, db, cfg, nil, "every 1 hour", []string{"-metadata"})
+	require.NoError(t, err)
+	task.run()
+
+	require.Len(t, db.jobs, 6)
+	assert.Equal(t, &model.PublishJob{Kind: model.PublishKindCert, IKID: "ikid", RefID: missing.ID}, db.jobs[0])
+	assert.Equal(t, &model.PublishJob{Kind: model.PublishKindCert, IKID: "ikid", RefID: truncated.ID}, db.jobs[1])
+	assert.Equal(t, &model.PublishJob{Kind: model.PublishKindIssuer, IKID: "ikid", RefID: issuer.ID}, db.jobs[2])
+	assert.Equal(t, &model.PublishJob{Kind: model.PublishKindCrl, IKID: "expired"}, db.jobs[3])
+	assert.Equal(t, &model.PublishJob{Kind: model.PublishKindCrl, IKID: "absent"}, db.jobs[4])
+	// the missing shard of the current CRL
+	assert.Equal(t, &model.PublishJob{Kind: model.PublishKindCrl, IKID: "current"}, db.jobs[5])
+
+	t.Run("shared bucket", func(t *testing.T) {
+		shared := t.TempDir()
+		write(filepath.Join(shared, published.FileName()), certpublisher.CertificateChain(published.ToPB()))
+		write(filepath.Join(shared, "current.crl"), []byte("crl"))
+
+		db := &mockDB{
+			certs: model.Certificates{published},
+			crls:  []*model.Crl{current},
+		}
+		task, err := create(TaskName, db, &config.Publisher{
+			CertsBucket: shared,
+			CRLBucket:   shared + "/",
+		}, nil, "every 1 hour", nil)
+		require.NoError(t, err)
+
+		report, err := task.reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, report)
+		assert.Empty(t, db.jobs)
+	})
+
+	t.Run("issuers and roots", func(t *testing.T) {
+		primary := t.TempDir()
+		mirror := t.TempDir()
+
+		issuer := newCert(20, "100000000000000020")
+		issuer.Profile = "ca"
+		issuer.SKID = "skid"
+		root := &model.RootCertificate{ID: 21, Pem: issuer.Pem}
+
+		pcfg := &config.Publisher{
+			IssuersBucket: primary,
+			RootsBucket:   primary,
+			Destinations: []*certpublisher.Destination{
+				{Name: "mirror", IssuersBucket: mirror, RootsBucket: mirror},
+			},
+		}
+		pub, err := certpublisher.NewPublisher(pcfg.PublisherConfig())
+		require.NoError(t, err)
+		_, err = pub.PublishIssuer(context.Background(), issuer.ToPB(), "skid.crt")
+		require.NoError(t, err)
+		_, err = pub.PublishRoots(context.Background(), model.RootCertificates{root}.ToDTO())
+		require.NoError(t, err)
+
+		db := &mockDB{
+			certs: model.Certificates{issuer},
+			roots: model.RootCertificates{root},
+		}
+		task, err := create(TaskName, db, pcfg, nil, "every 1 hour", nil)
+		require.NoError(t, err)
+		task.aia = map[string]string{"skid": "http://localhost/v1/cert/skid.crt"}
+
+		report, err := task.reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, report)
+		assert.Empty(t, db.jobs)
+
+		require.NoError(t, os.Remove(filepath.Join(mirror, "skid.p7c")))
+		write(filepath.Join(mirror, certpublisher.RootsFileName), []byte("stale"))
+
+		report, err = task.reconcile(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, Report{
+			model.PublishKindIssuer: {
+				DriftMissing: 1,
+			},
+			model.PublishKindRoots: {
+				DriftStale: 1,
+			},
+		}, report)
+		require.Len(t, db.jobs, 2)
+		assert.Equal(t, &model.PublishJob{Kind: model.PublishKindIssuer, IKID: "ikid", RefID: issuer.ID}, db.jobs[0])
+		assert.Equal(t, &model.PublishJob{Kind: model.PublishKindRoots}, db.jobs[1])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		db := &mockDB{err: errors.New("failed")}
+		task, err := create(TaskName, db, cfg, nil, "every 1 hour", nil)
+		require.NoError(t, err)
+
+		_, err = task.reconcile(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to list certificates")
+		task.run()
+	})
+}
@@ -0,0 +1,306 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/effective-security/porto/pkg/tasks"
+	"github.com/effective-security/porto/xhttp/correlation"
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/pkg/metricskey"
+	"github.com/effective-security/trusty/pkg/storage"
+	"github.com/effective-security/xdb"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/backend/tasks", "retention")
+
+// TaskName is the name of this task
+const TaskName = "retention"
+
+const day = 24 * time.Hour
+
+// Task defines the retention task
+type Task struct {
+	name     string
+	schedule string
+	db       cadb.CaDb
+	ctx      context.Context
+
+	// certsAge specifies the period after expiration to keep certificates,
+	// zero value disables the policy
+	certsAge time.Duration
+	// revokedAge specifies the period after expiration to keep revoked certificates,
+	// zero value disables the policy
+	revokedAge time.Duration
+	// nonces specifies to purge used or expired nonces
+	nonces bool
+	// jobsAge specifies the period to keep published jobs of the publish outbox,
+	// zero value disables the policy
+	jobsAge time.Duration
+	// signRequestsAge specifies the period to keep idempotency keys of sign requests,
+	// zero value disables the policy
+	signRequestsAge time.Duration
+	// archive specifies a storage location for purged rows
+	archive string
+	// batch specifies the number of rows to purge at once
+	batch int
+
+	// now is used in tests
+	now func() time.Time
+}
+
+func (t *Task) run() {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"reason", "recover",
+				"err", r,
+				"stack", debug.Stack())
+		}
+	}()
+
+	logger.ContextKV(t.ctx, xlog.TRACE, "task", TaskName)
+
+	now := t.now().UTC()
+	if t.certsAge > 0 {
+		err := t.purgeCertificates(t.ctx, now.Add(-t.certsAge))
+		if err != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"table", cadb.TableNameForCertificates,
+				"err", err.Error())
+		}
+	}
+	if t.revokedAge > 0 {
+		err := t.purgeRevoked(t.ctx, now.Add(-t.revokedAge))
+		if err != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"table", cadb.TableNameForRevoked,
+				"err", err.Error())
+		}
+	}
+	if t.nonces {
+		count, err := t.db.PurgeNonces(t.ctx, now)
+		if err != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"table", cadb.TableNameForNonces,
+				"err", err.Error())
+		} else {
+			metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForNonces)
+		}
+	}
+	if t.jobsAge > 0 {
+		count, err := t.db.PurgePublishJobs(t.ctx, now.Add(-t.jobsAge))
+		if err != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"table", cadb.TableNameForPublishJobs,
+				"err", err.Error())
+		} else {
+			metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForPublishJobs)
+		}
+	}
+	if t.signRequestsAge > 0 {
+		count, err := t.db.PurgeSignRequests(t.ctx, now.Add(-t.signRequestsAge))
+		if err != nil {
+			logger.ContextKV(t.ctx, xlog.ERROR,
+				"task", TaskName,
+				"table", cadb.TableNameForSignRequests,
+				"err", err.Error())
+		} else {
+			metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForSignRequests)
+		}
+	}
+}
+
+func (t *Task) purgeCertificates(ctx context.Context, before time.Time) error {
+	for {
+		var count int
+		err := t.inTx(ctx, func(db cadb.CaDb) error {
+			list, err := db.PurgeExpiredCertificates(ctx, before, t.batch)
+			if err != nil {
+				return err
+			}
+			count = len(list)
+			if count == 0 {
+				return nil
+			}
+			rows := make([]any, count)
+			for i, m := range list {
+				rows[i] = m
+			}
+			return t.archiveRows(ctx, cadb.TableNameForCertificates, list[0].ID, rows)
+		})
+		if err != nil {
+			return err
+		}
+
+		metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForCertificates)
+		if count < t.batch {
+			return nil
+		}
+	}
+}
+
+func (t *Task) purgeRevoked(ctx context.Context, before time.Time) error {
+	for {
+		var count int
+		err := t.inTx(ctx, func(db cadb.CaDb) error {
+			list, err := db.PurgeExpiredRevokedCertificates(ctx, before, t.batch)
+			if err != nil {
+				return err
+			}
+			count = len(list)
+			if count == 0 {
+				return nil
+			}
+			rows := make([]any, count)
+			for i, m := range list {
+				rows[i] = m
+			}
+			return t.archiveRows(ctx, cadb.TableNameForRevoked, list[0].Certificate.ID, rows)
+		})
+		if err != nil {
+			return err
+		}
+
+		metricskey.StatsDbRowsPurged.IncrCounter(float64(count), cadb.TableNameForRevoked)
+		if count < t.batch {
+			return nil
+		}
+	}
+}
+
+// inTx executes fn in a transaction, if supported by the DB provider,
+// so the rows are not deleted if the archive failed
+func (t *Task) inTx(ctx context.Context, fn func(db cadb.CaDb) error) error {
+	p, ok := t.db.(xdb.Provider)
+	if !ok {
+		return fn(t.db)
+	}
+
+	tx, err := p.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	txdb, ok := tx.(cadb.CaDb)
+	if !ok {
+		_ = tx.Rollback()
+		return errors.Errorf("unsupported transaction provider: %T", tx)
+	}
+
+	err = fn(txdb)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+// archiveRows writes the purged rows as JSON lines to the archive location
+func (t *Task) archiveRows(ctx context.Context, table string, firstID uint64, rows []any) error {
+	if t.archive == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range rows {
+		if err := enc.Encode(r); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	location := fmt.Sprintf("%s/%s/%s-%d.jsonl",
+		t.archive, table, t.now().UTC().Format("20060102T150405Z"), firstID)
+	_, err := storage.WriteFile(ctx, location, buf.Bytes())
+	if err != nil {
+		return errors.WithMessagef(err, "unable to archive to: %s", location)
+	}
+
+	logger.ContextKV(ctx, xlog.NOTICE, "table", table, "archive", location, "rows", len(rows))
+	return nil
+}
+
+func create(
+	name string,
+	db cadb.CaDb,
+	schedule string,
+	args []string,
+) (*Task, error) {
+	flagSet := flag.NewFlagSet("flags", flag.ContinueOnError)
+	certsPtr := flagSet.Int("certs-days", 0, "purge certificates expired more than the specified days ago")
+	revokedPtr := flagSet.Int("revoked-days", 0, "purge revoked certificates expired more than the specified days ago, and drop them from CRL; OCSP keeps the revoked status")
+	noncesPtr := flagSet.Bool("nonces", false, "purge used or expired nonces")
+	jobsPtr := flagSet.Int("jobs-days", 0, "purge published jobs of the publish outbox older than the specified days")
+	signRequestsPtr := flagSet.Int("sign-requests-days", 0, "purge idempotency keys of sign requests older than the specified days")
+	archivePtr := flagSet.String("archive", "", "storage location to archive purged rows")
+	batchPtr := flagSet.Int("batch", 1000, "number of rows to purge at once")
+
+	err := flagSet.Parse(args)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to parse arguments: %v", args)
+	}
+	if *certsPtr < 0 || *revokedPtr < 0 || *jobsPtr < 0 || *signRequestsPtr < 0 || *batchPtr <= 0 {
+		return nil, errors.Errorf("invalid arguments: %v", args)
+	}
+
+	task := &Task{
+		name:            name,
+		schedule:        schedule,
+		db:              db,
+		ctx:             correlation.WithID(context.Background()),
+		certsAge:        time.Duration(*certsPtr) * day,
+		revokedAge:      time.Duration(*revokedPtr) * day,
+		nonces:          *noncesPtr,
+		jobsAge:         time.Duration(*jobsPtr) * day,
+		archive:         *archivePtr,
+		batch:           *batchPtr,
+		signRequestsAge: time.Duration(*signRequestsPtr) * day,
+		now:             time.Now,
+	}
+
+	logger.KV(xlog.INFO,
+		"certs_days", *certsPtr,
+		"revoked_days", *revokedPtr,
+		"nonces", task.nonces,
+		"jobs_days", *jobsPtr,
+		"sign_requests_days", *signRequestsPtr,
+		"archive", task.archive)
+
+	return task, nil
+}
+
+// Factory returns a task factory
+func Factory(
+	s tasks.Scheduler,
+	name string,
+	schedule string,
+	args ...string,
+) any {
+	return func(db cadb.CaDb) error {
+		task, err := create(name, db, schedule, args)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		job, err := tasks.NewTask(task.schedule)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to schedule a job on schedule: %q", task.schedule)
+		}
+
+		t := job.Do(task.name, task.run)
+		s.Add(t)
+		return nil
+	}
+}
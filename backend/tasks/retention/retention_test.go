@@ -0,0 +1,175 @@
+package retention
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/model"
+	"github.com/effective-security/trusty/tests/testutils"
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+)
+
+type mockDB struct {
+	cadb.CaDb
+
+	certs   model.Certificates
+	revoked model.RevokedCertificates
+	nonces  int64
+
+	certsBefore   time.Time
+	revokedBefore time.Time
+	noncesBefore  time.Time
+	jobsBefore    time.Time
+	signBefore    time.Time
+	err           error
+}
+
+func (m *mockDB) PurgeExpiredCertificates(_ context.Context, before time.Time, limit int) (model.Certificates, error) {
+	m.certsBefore = before
+	if m.err != nil {
+		return nil, m.err
+	}
+	n := min(limit, len(m.certs))
+	list := m.certs[:n]
+	m.certs = m.certs[n:]
+	return list, nil
+}
+
+func (m *mockDB) PurgeExpiredRevokedCertificates(_ context.Context, before time.Time, limit int) (model.RevokedCertificates, error) {
+	m.revokedBefore = before
+	if m.err != nil {
+		return nil, m.err
+	}
+	n := min(limit, len(m.revoked))
+	list := m.revoked[:n]
+	m.revoked = m.revoked[n:]
+	return list, nil
+}
+
+func (m *mockDB) PurgeNonces(_ context.Context, before time.Time) (int64, error) {
+	m.noncesBefore = before
+	return m.nonces, m.err
+}
+
+func (m *mockDB) PurgePublishJobs(_ context.Context, before time.Time) (int64, error) {
+	m.jobsBefore = before
+	return 1, m.err
+}
+
+func (m *mockDB) PurgeSignRequests(_ context.Context, before time.Time) (int64, error) {
+	m.signBefore = before
+	return 1, m.err
+}
+
+func TestFactory(t *testing.T) {
+	c := dig.New()
+	err := c.Provide(func() cadb.CaDb {
+		return &mockDB{}
+	})
+	require.NoError(t, err)
+
+	scheduler := &testutils.MockScheduler{}
+	err = c.Invoke(Factory(scheduler, "test_run", "Every 30 minutes", "-certs-days", "30", "-nonces"))
+	require.NoError(t, err)
+	require.Len(t, scheduler.Tasks, 1)
+
+	err = c.Invoke(Factory(scheduler, "test_run", "Every 30 minutes", "-batch", "0"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid arguments")
+
+	err = c.Invoke(Factory(scheduler, "test_run", "Every 30 minutes", "-unknown"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse arguments")
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	archive := t.TempDir()
+
+	db := &mockDB{nonces: 3}
+	for i := 1; i <= 5; i++ {
+		db.certs = append(db.certs, &model.Certificate{ID: uint64(i), NotAfter: xdb.Time(now.Add(-100 * day))})
+	}
+	db.revoked = append(db.revoked, &model.RevokedCertificate{
+		Certificate: model.Certificate{ID: 100, NotAfter: xdb.Time(now.Add(-100 * day))},
+		Reason:      1,
+	})
+
+	task, err := create("retention", db, "every 1 hour", []string{
+		"-certs-days", "90",
+		"-revoked-days", "30",
+		"-nonces",
+		"-jobs-days", "7",
+		"-sign-requests-days", "3",
+		"-batch", "2",
+		"-archive", archive,
+	})
+	require.NoError(t, err)
+	task.now = func() time.Time { return now }
+
+	task.run()
+
+	assert.Empty(t, db.certs)
+	assert.Empty(t, db.revoked)
+	assert.Equal(t, now.Add(-90*day), db.certsBefore)
+	assert.Equal(t, now.Add(-30*day), db.revokedBefore)
+	assert.Equal(t, now, db.noncesBefore)
+	assert.Equal(t, now.Add(-7*day), db.jobsBefore)
+	assert.Equal(t, now.Add(-3*day), db.signBefore)
+
+	files, err := filepath.Glob(filepath.Join(archive, cadb.TableNameForCertificates, "*.jsonl"))
+	require.NoError(t, err)
+	// 3 batches: 2 + 2 + 1
+	assert.Len(t, files, 3)
+	assert.Equal(t, 5, countLines(t, files...))
+
+	files, err = filepath.Glob(filepath.Join(archive, cadb.TableNameForRevoked, "*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, 1, countLines(t, files...))
+
+	t.Run("disabled", func(t *testing.T) {
+		db := &mockDB{}
+		task, err := create("retention", db, "every 1 hour", nil)
+		require.NoError(t, err)
+		task.run()
+		assert.True(t, db.certsBefore.IsZero())
+		assert.True(t, db.revokedBefore.IsZero())
+		assert.True(t, db.noncesBefore.IsZero())
+		assert.True(t, db.jobsBefore.IsZero())
+		assert.True(t, db.signBefore.IsZero())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		db := &mockDB{err: errors.New("failed")}
+		task, err := create("retention", db, "every 1 hour", []string{"-certs-days", "1", "-revoked-days", "1", "-nonces"})
+		require.NoError(t, err)
+		task.run()
+		assert.False(t, db.certsBefore.IsZero())
+		assert.False(t, db.revokedBefore.IsZero())
+		assert.False(t, db.noncesBefore.IsZero())
+	})
+}
+
+func countLines(t *testing.T, files ...string) int {
+	count := 0
+	for _, fn := range files {
+		f, err := os.Open(fn)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			count++
+		}
+		_ = f.Close()
+	}
+	return count
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/effective-security/porto/pkg/tasks"
 	"github.com/effective-security/trusty/backend/tasks/certsmonitor"
 	"github.com/effective-security/trusty/backend/tasks/healthcheck"
+	"github.com/effective-security/trusty/backend/tasks/reconcile"
+	"github.com/effective-security/trusty/backend/tasks/retention"
 	"github.com/effective-security/trusty/backend/tasks/stats"
 )
 
@@ -31,4 +33,6 @@ var Factories = map[string]Factory{
 	certsmonitor.TaskName: certsmonitor.Factory,
 	stats.TaskName:        stats.Factory,
 	healthcheck.TaskName:  healthcheck.Factory,
+	retention.TaskName:    retention.Factory,
+	reconcile.TaskName:    reconcile.Factory,
 }
@@ -5,11 +5,15 @@ import (
 
 	"github.com/effective-security/trusty/backend/tasks"
 	"github.com/effective-security/trusty/backend/tasks/certsmonitor"
+	"github.com/effective-security/trusty/backend/tasks/reconcile"
+	"github.com/effective-security/trusty/backend/tasks/retention"
 	"github.com/stretchr/testify/require"
 )
 
 var factories = map[string]tasks.Factory{
 	certsmonitor.TaskName: certsmonitor.Factory,
+	retention.TaskName:    retention.Factory,
+	reconcile.TaskName:    reconcile.Factory,
 }
 
 func Test_invalidArgs(t *testing.T) {
@@ -50,6 +50,11 @@ type appFlags struct {
 	ClientKeyFile       string   `help:"Client key file"`
 	ClientTrustedCAFile string   `help:"Client trusted CA file"`
 	OnlyServer          string   `help:"Only start the specified server"`
+
+	Serve   struct{}   `cmd:"" default:"1" hidden:"" help:"start the service"`
+	Migrate migrateCmd `cmd:"" help:"migrate certificates from a legacy CA database"`
+	Backup  backupCmd  `cmd:"" help:"export encrypted backup of the CA database"`
+	Restore restoreCmd `cmd:"" help:"restore the CA database from encrypted backup"`
 }
 
 // App provides application container
@@ -63,6 +68,7 @@ type App struct {
 
 	args             []string
 	flags            appFlags
+	command          string
 	cfg              *config.Configuration
 	scheduler        tasks.Scheduler
 	containerFactory appcontainer.ContainerFactoryFn
@@ -192,6 +198,15 @@ func (a *App) Run(startedCh chan<- bool) error {
 	ver := version.Current().String()
 	logger.KV(xlog.INFO, "hostname", a.hostname, "ip", ipaddr, "version", ver)
 
+	switch a.command {
+	case "migrate":
+		return a.migrate()
+	case "backup":
+		return a.backup()
+	case "restore":
+		return a.restore()
+	}
+
 	if a.flags.CPUProfile != "" {
 		closer, err := appinit.CPUProfiler(a.flags.CPUProfile)
 		if err != nil {
@@ -345,10 +360,11 @@ func (a *App) loadConfig() error {
 	if err != nil {
 		return errors.WithMessagef(err, "failed to parse arguments: %v", a.args)
 	}
-	_, err = parser.Parse(a.args)
+	kctx, err := parser.Parse(a.args)
 	if err != nil {
 		return errors.WithMessagef(err, "failed to parse arguments: %v", a.args)
 	}
+	a.command = kctx.Command()
 
 	closer, err := appinit.Logs(&a.flags.LogConfig, "trusty")
 	if err != nil {
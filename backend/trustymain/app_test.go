@@ -114,6 +114,88 @@ func Test_AppInitWithCfg(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_AppMigrateFlags(t *testing.T) {
+	cfgFile, err := configloader.GetAbsFilename("etc/dev/"+config.ConfigFileName, projFolder)
+	require.NoError(t, err, "unable to determine config file")
+
+	app := NewApp([]string{
+		"migrate",
+		"--cfg", cfgFile,
+		"--issuer", "TrustyCA",
+	})
+	defer app.Close()
+
+	err = app.loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "migrate", app.command)
+	assert.Equal(t, "TrustyCA", app.flags.Migrate.Issuer)
+	assert.Equal(t, "legacy", app.flags.Migrate.Profile)
+
+	err = app.migrate()
+	require.Error(t, err)
+	assert.Equal(t, "either --openssl-index or --cfssl-db must be specified", err.Error())
+
+	app2 := NewApp([]string{
+		"migrate",
+		"--cfg", cfgFile,
+		"--issuer", "TrustyCA",
+		"--openssl-index", "index.txt",
+		"--cfssl-db", "certdb.db",
+	})
+	defer app2.Close()
+	err = app2.loadConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't be used together")
+}
+
+func Test_AppBackupFlags(t *testing.T) {
+	cfgFile, err := configloader.GetAbsFilename("etc/dev/"+config.ConfigFileName, projFolder)
+	require.NoError(t, err, "unable to determine config file")
+
+	app := NewApp([]string{
+		"backup",
+		"--cfg", cfgFile,
+		"--out", "trusty.backup",
+	})
+	defer app.Close()
+
+	err = app.loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "backup", app.command)
+	assert.Equal(t, "trusty.backup", app.flags.Backup.Out)
+
+	err = app.backup()
+	require.Error(t, err)
+	assert.Equal(t, "either --passphrase or --recipient must be specified", err.Error())
+
+	app2 := NewApp([]string{
+		"restore",
+		"--cfg", cfgFile,
+		"--in", "trusty.backup",
+	})
+	defer app2.Close()
+
+	err = app2.loadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "restore", app2.command)
+
+	err = app2.restore()
+	require.Error(t, err)
+	assert.Equal(t, "either --passphrase or --identity must be specified", err.Error())
+
+	app3 := NewApp([]string{
+		"restore",
+		"--cfg", cfgFile,
+		"--in", "trusty.backup",
+		"--passphrase", "secret",
+		"--identity", "key.txt",
+	})
+	defer app3.Close()
+	err = app3.loadConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't be used together")
+}
+
 func Test_AppInstance_StartFailOnPort(t *testing.T) {
 	cfgPath, err := filepath.Abs(projFolder + "etc/dev/" + config.ConfigFileName)
 	require.NoError(t, err)
@@ -0,0 +1,129 @@
+package trustymain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/backup"
+	"github.com/effective-security/xdb"
+	"github.com/pkg/errors"
+)
+
+// backupCmd specifies flags for the backup command
+type backupCmd struct {
+	Out        string   `required:"" help:"location of the backup archive"`
+	Passphrase string   `env:"TRUSTY_BACKUP_PASSPHRASE" help:"passphrase to encrypt the archive" xor:"key"`
+	Recipient  []string `help:"age public key to encrypt the archive for" xor:"key"`
+}
+
+// restoreCmd specifies flags for the restore command
+type restoreCmd struct {
+	In         string `required:"" help:"location of the backup archive"`
+	Passphrase string `env:"TRUSTY_BACKUP_PASSPHRASE" help:"passphrase to decrypt the archive" xor:"key"`
+	Identity   string `help:"location of age identity file to decrypt the archive" xor:"key"`
+}
+
+func (a *App) backup() error {
+	flags := &a.flags.Backup
+	if flags.Passphrase == "" && len(flags.Recipient) == 0 {
+		return errors.New("either --passphrase or --recipient must be specified")
+	}
+
+	db, err := a.backupDB()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(flags.Out, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	w, err := backup.NewEncryptor(f, flags.Passphrase, flags.Recipient)
+	if err != nil {
+		_ = os.Remove(flags.Out)
+		return err
+	}
+
+	m, err := backup.Backup(context.Background(), db, w)
+	if err == nil {
+		err = errors.WithStack(w.Close())
+	}
+	if err != nil {
+		_ = os.Remove(flags.Out)
+		return errors.WithMessage(err, "backup failed")
+	}
+
+	for _, t := range m.Tables {
+		fmt.Fprintf(os.Stdout, "%s: %d\n", t.Name, t.Rows)
+	}
+	return nil
+}
+
+func (a *App) restore() error {
+	flags := &a.flags.Restore
+	if flags.Passphrase == "" && flags.Identity == "" {
+		return errors.New("either --passphrase or --identity must be specified")
+	}
+
+	var ids io.Reader
+	if flags.Identity != "" {
+		idf, err := os.Open(flags.Identity)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer idf.Close()
+		ids = idf
+	}
+
+	f, err := os.Open(flags.In)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	r, err := backup.NewDecryptor(f, flags.Passphrase, ids)
+	if err != nil {
+		return err
+	}
+
+	db, err := a.backupDB()
+	if err != nil {
+		return err
+	}
+
+	m, err := backup.Restore(context.Background(), db, r)
+	if err != nil {
+		return errors.WithMessage(err, "restore failed")
+	}
+
+	for _, t := range m.Tables {
+		fmt.Fprintf(os.Stdout, "%s: %d\n", t.Name, t.Rows)
+	}
+	return nil
+}
+
+func (a *App) backupDB() (xdb.Provider, error) {
+	c, err := a.Container()
+	if err != nil {
+		return nil, err
+	}
+
+	var db cadb.CaDb
+	err = c.Invoke(func(d cadb.CaDb) {
+		db = d
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to open CA DB")
+	}
+
+	p, ok := db.(xdb.Provider)
+	if !ok {
+		return nil, errors.Errorf("unsupported CA DB provider: %T", db)
+	}
+	return p, nil
+}
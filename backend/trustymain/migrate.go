@@ -0,0 +1,88 @@
+package trustymain
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/effective-security/trusty/backend/db/cadb"
+	"github.com/effective-security/trusty/backend/db/cadb/legacy"
+	"github.com/effective-security/xpki/authority"
+	"github.com/pkg/errors"
+)
+
+// migrateCmd specifies flags for the migrate command
+type migrateCmd struct {
+	Issuer       string `required:"" help:"label of the configured issuer of the legacy certificates"`
+	OpensslIndex string `name:"openssl-index" help:"location of OpenSSL index.txt file" xor:"source"`
+	OpensslCerts string `name:"openssl-certs" help:"location of OpenSSL certificates folder, by default newcerts next to index.txt"`
+	CfsslDb      string `name:"cfssl-db" help:"CFSSL certdb data source: SQLite file or PostgreSQL URL" xor:"source"`
+	OrgID        uint64 `name:"org-id" help:"Organization ID for migrated certificates"`
+	Profile      string `default:"legacy" help:"profile name for migrated certificates"`
+	Label        string `help:"label for migrated certificates"`
+}
+
+func (a *App) migrate() error {
+	flags := &a.flags.Migrate
+	if flags.OpensslIndex == "" && flags.CfsslDb == "" {
+		return errors.New("either --openssl-index or --cfssl-db must be specified")
+	}
+
+	c, err := a.Container()
+	if err != nil {
+		return err
+	}
+
+	var ca *authority.Authority
+	var db cadb.CaDb
+	err = c.Invoke(func(a *authority.Authority, d cadb.CaDb) {
+		ca = a
+		db = d
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to create authority")
+	}
+
+	issuer, err := ca.GetIssuerByLabel(flags.Issuer)
+	if err != nil {
+		return errors.WithMessagef(err, "issuer not found: %s", flags.Issuer)
+	}
+
+	ctx := context.Background()
+	opts := legacy.Options{
+		OrgID:   flags.OrgID,
+		Profile: flags.Profile,
+		Label:   flags.Label,
+		DryRun:  a.flags.DryRun,
+	}
+
+	// the entries are migrated in batches, as loaded
+	res := new(legacy.Result)
+	migrate := func(entries []*legacy.Entry) error {
+		r, err := legacy.Migrate(ctx, db, issuer, entries, opts)
+		if r != nil {
+			res.Add(r)
+		}
+		return err
+	}
+
+	if flags.OpensslIndex != "" {
+		opts.Source = "openssl"
+		err = legacy.LoadOpenSSLIndex(flags.OpensslIndex, flags.OpensslCerts, legacy.BatchSize, migrate)
+	} else {
+		opts.Source = "cfssl"
+		certdb, dberr := legacy.OpenCFSSLCertDB(flags.CfsslDb)
+		if dberr != nil {
+			return dberr
+		}
+		defer certdb.Close()
+		err = legacy.LoadCFSSLCertDB(ctx, certdb, legacy.BatchSize, migrate)
+	}
+
+	// the batches migrated before the error are reported
+	fmt.Fprintf(os.Stdout, "issued: %d, revoked: %d, skipped: %d\n", res.Issued, res.Revoked, res.Skipped)
+	if err != nil {
+		return errors.WithMessagef(err, "unable to migrate %s database", opts.Source)
+	}
+	return nil
+}
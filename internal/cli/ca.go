@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/trusty/pkg/print"
 	"github.com/effective-security/xdb"
+	"github.com/effective-security/xpki/certutil"
 	"github.com/pkg/errors"
 )
 
@@ -19,10 +21,16 @@ type CaCmd struct {
 	Revoked        ListRevokedCertsCmd `cmd:"" help:"list revoked certificates"`
 	Profile        GetProfileCmd       `cmd:"" help:"show certificate profile"`
 	Sign           SignCmd             `cmd:"" help:"sign certificate"`
+	SignBatch      SignBatchCmd        `cmd:"" help:"sign list of certificates"`
+	Renew          RenewCmd            `cmd:"" help:"renew certificate with the same key"`
+	Rekey          RekeyCmd            `cmd:"" help:"renew certificate with a new key"`
+	Generate       GenerateCmd         `cmd:"" help:"generate key and sign certificate"`
 	PublishCrl     PublishCrlsCmd      `cmd:"" help:"publish CRL"`
 	Revoke         RevokeCmd           `cmd:"" help:"revoke certificate"`
 	SetCertLabel   UpdateCertLabelCmd  `cmd:"" help:"set certificate label"`
 	GetCertificate GetCertificateCmd   `cmd:"" help:"get certificate"`
+	Import         ImportCertCmd       `cmd:"" help:"import certificate issued by external CA"`
+	PublishStatus  PublishStatusCmd    `cmd:"" help:"show status of the publish outbox"`
 }
 
 // ListIssuersCmd shows issuers
@@ -157,14 +165,24 @@ func (a *GetProfileCmd) Run(cli *Cli) error {
 type SignCmd struct {
 	// Csr specifies CSR to sign
 	Csr         string `required:"" help:"request file"`
+	Format      string `help:"request format: pem, der, spki, spkac or crmf" enum:"pem,der,spki,spkac,crmf" default:"pem"`
 	Profile     string `required:"" help:"profile name"`
 	IssuerLabel string
 	Token       string
+	CN          string `help:"subject common name"`
 	SAN         []string
 	Label       string `help:"certificate label"`
 	Out         string
 }
 
+var requestFormats = map[string]pb.EncodingFormat{
+	"pem":   pb.EncodingFormat_PEM,
+	"der":   pb.EncodingFormat_DER,
+	"spki":  pb.EncodingFormat_SPKI,
+	"spkac": pb.EncodingFormat_SPKAC,
+	"crmf":  pb.EncodingFormat_CRMF,
+}
+
 // Run the command
 func (a *SignCmd) Run(cli *Cli) error {
 	client, err := cli.CAClient()
@@ -177,19 +195,226 @@ func (a *SignCmd) Run(cli *Cli) error {
 		return errors.WithMessagef(err, "failed to load request")
 	}
 
-	res, err := client.SignCertificate(context.Background(), &pb.SignCertificateRequest{
-		RequestFormat: pb.EncodingFormat_PEM,
+	req := &pb.SignCertificateRequest{
+		RequestFormat: requestFormats[a.Format],
 		Request:       csr,
 		Profile:       a.Profile,
 		IssuerLabel:   a.IssuerLabel,
 		Label:         a.Label,
 		Token:         a.Token,
 		SAN:           a.SAN,
+	}
+	if a.CN != "" {
+		req.Subject = &pb.X509Subject{CommonName: a.CN}
+	}
+
+	res, err := client.SignCertificate(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	return writeCertificate(cli, res, a.Out)
+}
+
+// SignBatchCmd signs the list of certificate requests
+type SignBatchCmd struct {
+	// Csr specifies the list of CSR to sign
+	Csr         []string `required:"" help:"request files"`
+	Profile     string   `required:"" help:"profile name"`
+	IssuerLabel string
+	Label       string `help:"certificate label"`
+	Out         string `help:"folder to write certificates to, as {index}.pem"`
+}
+
+// Run the command
+func (a *SignBatchCmd) Run(cli *Cli) error {
+	client, err := cli.CAClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.SignCertificatesRequest{}
+	for _, f := range a.Csr {
+		csr, err := cli.ReadFile(f)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to load request")
+		}
+		req.Requests = append(req.Requests, &pb.SignCertificateRequest{
+			RequestFormat: pb.EncodingFormat_PEM,
+			Request:       csr,
+			Profile:       a.Profile,
+			IssuerLabel:   a.IssuerLabel,
+			Label:         a.Label,
+		})
+	}
+
+	res, err := client.SignCertificates(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	if cli.IsJSON() && a.Out == "" {
+		_ = print.JSON(cli.Writer(), res)
+		return nil
+	}
+
+	failed := 0
+	for _, r := range res.Results {
+		if r.Certificate == nil {
+			failed++
+			fmt.Fprintf(cli.Writer(), "%s: %s\n", a.Csr[r.Index], r.Error)
+			continue
+		}
+		out := ""
+		if a.Out != "" {
+			out = filepath.Join(a.Out, fmt.Sprintf("%d.pem", r.Index))
+		}
+		err = writeCertificate(cli, &pb.CertificateResponse{Certificate: r.Certificate}, out)
+		if err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to sign %d of %d requests", failed, len(res.Results))
+	}
+	return nil
+}
+
+// RenewCmd renews certificate with the same key
+type RenewCmd struct {
+	ID                uint64 `kong:"arg" required:"" help:"certificate ID"`
+	NotBefore         string `help:"the time when the validity period starts"`
+	NotAfter          string `help:"the time when the validity period ends"`
+	RevokePredecessor bool   `help:"revoke the renewed certificate as superseded"`
+	GracePeriod       string `help:"the period to revoke the renewed certificate after, for example 24h"`
+	Out               string
+}
+
+// Run the command
+func (a *RenewCmd) Run(cli *Cli) error {
+	client, err := cli.CAClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.RenewCertificate(context.Background(), &pb.RenewCertificateRequest{
+		ID:                a.ID,
+		NotBefore:         a.NotBefore,
+		NotAfter:          a.NotAfter,
+		RevokePredecessor: a.RevokePredecessor,
+		GracePeriod:       a.GracePeriod,
 	})
 	if err != nil {
 		return err
 	}
 
+	return writeCertificate(cli, res, a.Out)
+}
+
+// RekeyCmd renews certificate with a new key
+type RekeyCmd struct {
+	ID uint64 `kong:"arg" required:"" help:"certificate ID"`
+	// Csr specifies CSR with a new key
+	Csr               string `required:"" help:"request file"`
+	NotBefore         string `help:"the time when the validity period starts"`
+	NotAfter          string `help:"the time when the validity period ends"`
+	RevokePredecessor bool   `help:"revoke the rekeyed certificate as superseded"`
+	GracePeriod       string `help:"the period to revoke the rekeyed certificate after, for example 24h"`
+	Out               string
+}
+
+// Run the command
+func (a *RekeyCmd) Run(cli *Cli) error {
+	client, err := cli.CAClient()
+	if err != nil {
+		return err
+	}
+
+	csr, err := cli.ReadFile(a.Csr)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load request")
+	}
+
+	res, err := client.RekeyCertificate(context.Background(), &pb.RekeyCertificateRequest{
+		ID:                a.ID,
+		RequestFormat:     pb.EncodingFormat_PEM,
+		Request:           csr,
+		NotBefore:         a.NotBefore,
+		NotAfter:          a.NotAfter,
+		RevokePredecessor: a.RevokePredecessor,
+		GracePeriod:       a.GracePeriod,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeCertificate(cli, res, a.Out)
+}
+
+// GenerateCmd generates key and signs certificate
+type GenerateCmd struct {
+	Profile     string   `required:"" help:"profile name"`
+	IssuerLabel string   `help:"issuer label"`
+	CN          string   `help:"subject common name"`
+	SAN         []string `help:"subject alternative names"`
+	Label       string   `help:"certificate label"`
+	Format      string   `help:"key bundle format: pkcs12 or pkcs8" enum:"pkcs12,pkcs8" default:"pkcs12"`
+	Password    string   `required:"" help:"password to protect the key bundle"`
+	Recipient   string   `help:"age X25519 public key to encrypt the key bundle to"`
+	Out         string   `help:"file to write the key bundle to"`
+}
+
+// Run the command
+func (a *GenerateCmd) Run(cli *Cli) error {
+	format := pb.KeyBundleFormat_PKCS12
+	if a.Format == "pkcs8" {
+		format = pb.KeyBundleFormat_PKCS8
+	}
+	if a.Out == "" && (format == pb.KeyBundleFormat_PKCS12 || a.Recipient != "") && !cli.IsJSON() {
+		return errors.New("--out must be specified for binary bundle")
+	}
+
+	client, err := cli.CAClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.GenerateCertificateRequest{
+		Profile:     a.Profile,
+		IssuerLabel: a.IssuerLabel,
+		SAN:         a.SAN,
+		Label:       a.Label,
+		Format:      format,
+		Password:    a.Password,
+		Recipient:   a.Recipient,
+	}
+	if a.CN != "" {
+		req.Subject = &pb.X509Subject{CommonName: a.CN}
+	}
+
+	res, err := client.GenerateAndSignCertificate(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	w := cli.Writer()
+	if a.Out != "" {
+		err = os.WriteFile(a.Out, res.Bundle, 0600)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Fprintf(w, "certificate ID: %d\n", res.Certificate.ID)
+	} else if cli.IsJSON() {
+		_ = print.JSON(w, res)
+	} else {
+		fmt.Fprint(w, string(res.Bundle))
+	}
+	return nil
+}
+
+// writeCertificate writes the issued certificate with its issuers
+// to the file, or prints it
+func writeCertificate(cli *Cli, res *pb.CertificateResponse, out string) error {
 	pem := res.Certificate.Pem
 	if !strings.HasSuffix(pem, "\n") {
 		pem += "\n"
@@ -197,8 +422,8 @@ func (a *SignCmd) Run(cli *Cli) error {
 	pem += res.Certificate.IssuersPem
 
 	w := cli.Writer()
-	if a.Out != "" {
-		err = os.WriteFile(a.Out, []byte(pem), 0664)
+	if out != "" {
+		err := os.WriteFile(out, []byte(pem), 0664)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -208,6 +433,11 @@ func (a *SignCmd) Run(cli *Cli) error {
 		fmt.Fprint(w, pem)
 		fmt.Fprint(w, "\n")
 	}
+	if !cli.IsJSON() {
+		for _, lint := range res.Warnings {
+			fmt.Fprintf(cli.ErrWriter(), "warning: %s: %s %s\n", lint.Severity, lint.Name, lint.Details)
+		}
+	}
 
 	return nil
 }
@@ -342,3 +572,95 @@ func (a *GetCertificateCmd) Run(cli *Cli) error {
 	_ = cli.Print(res)
 	return nil
 }
+
+// ImportCertCmd imports a certificate issued by external CA
+type ImportCertCmd struct {
+	Cert    string `kong:"arg" required:"" help:"certificate file, optionally with the issuers chain"`
+	Issuers string `help:"issuers bundle file"`
+	Profile string `help:"profile name, default: external"`
+	OrgID   uint64
+	Label   string            `help:"certificate label"`
+	Meta    map[string]string `help:"certificate metadata"`
+}
+
+// Run the command
+func (a *ImportCertCmd) Run(cli *Cli) error {
+	pemBytes, err := cli.ReadFile(a.Cert)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load certificate")
+	}
+
+	chain, err := certutil.ParseChainFromPEM(pemBytes)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to parse certificate")
+	}
+	if len(chain) == 0 {
+		return errors.New("certificate not found")
+	}
+
+	crt, err := certutil.EncodeToPEMString(false, chain[0])
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var issuers string
+	if a.Issuers != "" {
+		b, err := cli.ReadFile(a.Issuers)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to load issuers")
+		}
+		issuers = string(b)
+	} else if len(chain) > 1 {
+		issuers, err = certutil.EncodeToPEMString(false, chain[1:]...)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	client, err := cli.CAClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.ImportCertificate(context.Background(), &pb.ImportCertificateRequest{
+		Pem:        crt,
+		IssuersPem: issuers,
+		OrgID:      a.OrgID,
+		Profile:    a.Profile,
+		Label:      a.Label,
+		Metadata:   a.Meta,
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = cli.Print(res)
+	return nil
+}
+
+// PublishStatusCmd shows status of the publish outbox
+type PublishStatusCmd struct {
+	Status string `help:"list jobs with the status: pending, processing, failed, published"`
+	Limit  int64
+	After  uint64
+}
+
+// Run the command
+func (a *PublishStatusCmd) Run(cli *Cli) error {
+	client, err := cli.CAClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := client.GetPublishStatus(context.Background(), &pb.PublishStatusRequest{
+		Status: a.Status,
+		Limit:  a.Limit,
+		After:  a.After,
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = cli.Print(res)
+	return nil
+}
@@ -329,6 +329,9 @@ func (s *testSuite) TestSign() {
 			Pem:        "cert pem",
 			IssuersPem: "issuers pem",
 		},
+		Warnings: []*pb.LintResult{
+			{Name: "w_test", Severity: "warn", Details: "test details"},
+		},
 	}
 
 	s.MockAuthority.SetResponse(expectedResponse)
@@ -343,6 +346,147 @@ func (s *testSuite) TestSign() {
 	a.Csr = "testdata/request.csr"
 	err = a.Run(s.ctl)
 	s.Require().NoError(err)
+	s.Contains(s.Out.String(), "warning: warn: w_test test details\n")
+
+	a.Format = "spki"
+	a.CN = "device.test"
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+}
+
+func (s *testSuite) TestSignBatch() {
+	expectedResponse := &pb.SignCertificatesResponse{
+		Results: []*pb.SignCertificateResult{
+			{
+				Index: 0,
+				Certificate: &pb.Certificate{
+					ID:         1234,
+					Profile:    "server",
+					Pem:        "cert pem",
+					IssuersPem: "issuers pem",
+				},
+			},
+			{
+				Index: 1,
+				Code:  3,
+				Error: "invalid request",
+			},
+		},
+	}
+
+	s.MockAuthority.SetResponse(expectedResponse)
+
+	a := SignBatchCmd{
+		Profile: "server",
+		Csr:     []string{"notreal"},
+	}
+	err := a.Run(s.ctl)
+	s.EqualError(err, "failed to load request: open notreal: no such file or directory")
+
+	a.Csr = []string{"testdata/request.csr", "testdata/request.csr"}
+	err = a.Run(s.ctl)
+	s.EqualError(err, "failed to sign 1 of 2 requests")
+	s.Equal("cert pem\nissuers pem\ntestdata/request.csr: invalid request\n", s.Out.String())
+
+	s.ctl.O = "json"
+	s.Out.Reset()
+
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.HasText(`"Error": "invalid request"`)
+}
+
+func (s *testSuite) TestRenew() {
+	expectedResponse := &pb.CertificateResponse{
+		Certificate: &pb.Certificate{
+			ID:         1235,
+			OrgID:      1,
+			Profile:    "server",
+			Pem:        "cert pem",
+			IssuersPem: "issuers pem",
+			Metadata:   map[string]string{"renewed_from": "1234"},
+		},
+	}
+
+	s.MockAuthority.SetResponse(expectedResponse)
+
+	a := RenewCmd{
+		ID:                1234,
+		RevokePredecessor: true,
+		GracePeriod:       "24h",
+	}
+	err := a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.Equal("cert pem\nissuers pem\n", s.Out.String())
+
+	s.ctl.O = "json"
+	s.Out.Reset()
+
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.HasText(`"renewed_from": "1234"`)
+}
+
+func (s *testSuite) TestRekey() {
+	expectedResponse := &pb.CertificateResponse{
+		Certificate: &pb.Certificate{
+			ID:         1235,
+			OrgID:      1,
+			Profile:    "server",
+			Pem:        "cert pem",
+			IssuersPem: "issuers pem",
+		},
+	}
+
+	s.MockAuthority.SetResponse(expectedResponse)
+
+	a := RekeyCmd{
+		ID:  1234,
+		Csr: "notreal",
+	}
+	err := a.Run(s.ctl)
+	s.EqualError(err, "failed to load request: open notreal: no such file or directory")
+
+	a.Csr = "testdata/request.csr"
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.Equal("cert pem\nissuers pem\n", s.Out.String())
+}
+
+func (s *testSuite) TestGenerate() {
+	expectedResponse := &pb.GenerateCertificateResponse{
+		Certificate: &pb.Certificate{
+			ID:      1234,
+			Profile: "client",
+		},
+		Format: pb.KeyBundleFormat_PKCS8,
+		Bundle: []byte("bundle pem\n"),
+	}
+
+	s.MockAuthority.SetResponse(expectedResponse)
+
+	a := GenerateCmd{
+		Profile:  "client",
+		CN:       "client.test",
+		Format:   "pkcs12",
+		Password: "secret",
+	}
+	err := a.Run(s.ctl)
+	s.EqualError(err, "--out must be specified for binary bundle")
+
+	a.Format = "pkcs8"
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.Equal("bundle pem\n", s.Out.String())
+
+	s.Out.Reset()
+	a.Out = filepath.Join(s.T().TempDir(), "client.pem")
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.Equal("certificate ID: 1234\n", s.Out.String())
+	b, err := os.ReadFile(a.Out)
+	s.Require().NoError(err)
+	s.Equal("bundle pem\n", string(b))
 }
 
 func (s *testSuite) TestListCerts() {
@@ -506,3 +650,68 @@ func loadJSON(filename string, v any) error {
 	}
 	return nil
 }
+
+func (s *testSuite) TestImportCertificate() {
+	expectedResponse := new(pb.CertificateResponse)
+	err := loadJSON("testdata/cert.json", expectedResponse)
+	s.Require().NoError(err)
+	expectedResponse.Certificate.External = true
+
+	s.MockAuthority.SetResponse(expectedResponse)
+
+	a := ImportCertCmd{
+		Cert: "notreal",
+	}
+	err = a.Run(s.ctl)
+	s.EqualError(err, "failed to load certificate: open notreal: no such file or directory")
+
+	a.Cert = "testdata/request.csr"
+	err = a.Run(s.ctl)
+	s.EqualError(err, "certificate not found")
+
+	a.Cert = filepath.Join(projFolder, "etc/dev/roots/trusty_root_ca.pem")
+	a.Label = "external"
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.HasText("  External: true\n")
+
+	s.ctl.O = "json"
+	s.Out.Reset()
+
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.HasText(`"External": true`)
+}
+
+func (s *testSuite) TestPublishStatus() {
+	expectedResponse := &pb.PublishStatusResponse{
+		Counts: map[string]int64{"failed": 1, "published": 10},
+		Jobs: []*pb.PublishJob{
+			{
+				ID:        123,
+				Kind:      "cert",
+				IKID:      "9e0fd4a22cd5aa773de1fe00e5fefa13109849cb",
+				RefID:     97371720557570558,
+				Status:    "failed",
+				Attempts:  20,
+				LastError: "storage is down",
+			},
+		},
+	}
+	s.MockAuthority.SetResponse(expectedResponse)
+
+	a := PublishStatusCmd{
+		Status: "failed",
+	}
+	err := a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.HasText("failed: 1\npublished: 10\n")
+	s.HasText("storage is down")
+
+	s.ctl.O = "json"
+	s.Out.Reset()
+
+	err = a.Run(s.ctl)
+	s.Require().NoError(err)
+	s.HasText(`"Counts": {`)
+}
@@ -0,0 +1,179 @@
+// Package certlint provides pre-issuance linting of certificates.
+//
+// The certificate to be signed is checked by a Linter,
+// and the Policy decides which results block the issuance,
+// which are returned as warnings, and which are ignored.
+// The default Linter runs RFC 5280 and CA/Browser Forum Baseline Requirements
+// rules of zlint.
+package certlint
+
+import (
+	"crypto/x509"
+	"sort"
+	"strings"
+
+	"github.com/effective-security/x/slices"
+	"github.com/pkg/errors"
+	zx509 "github.com/zmap/zcrypto/x509"
+	"github.com/zmap/zlint/v3"
+	"github.com/zmap/zlint/v3/lint"
+)
+
+// Severity of the lint result
+type Severity int
+
+// Severity values, in the increasing order
+const (
+	Pass Severity = iota
+	Notice
+	Warn
+	Error
+	Fatal
+	// Never is the policy threshold that is never reached
+	Never
+)
+
+var severityNames = map[Severity]string{
+	Pass:   "pass",
+	Notice: "notice",
+	Warn:   "warn",
+	Error:  "error",
+	Fatal:  "fatal",
+	Never:  "never",
+}
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity by name:
+// pass, notice, warn, error, fatal, or never
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(name)
+	for s, n := range severityNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return Pass, errors.Errorf("invalid severity: %q", name)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (s *Severity) UnmarshalText(text []byte) error {
+	v, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// Result of the lint
+type Result struct {
+	// Name of the lint
+	Name string `json:"name"`
+	// Source of the lint, such as RFC5280 or CABF_BR
+	Source string `json:"source,omitempty"`
+	// Severity of the result
+	Severity Severity `json:"severity"`
+	// Details of the result
+	Details string `json:"details,omitempty"`
+}
+
+// Linter checks the certificate before it is signed.
+// The certificate is signed by an ephemeral key,
+// the linter must not check its signature.
+type Linter interface {
+	// Lint returns the results of the checks that did not pass
+	Lint(crt *x509.Certificate) ([]*Result, error)
+}
+
+// DefaultSources specifies the zlint sources of the default linter
+var DefaultSources = []string{string(lint.RFC5280), string(lint.CABFBaselineRequirements)}
+
+type zlinter struct {
+	registry lint.Registry
+}
+
+// NewZLinter returns the linter with zlint rules from the sources,
+// if not specified, then DefaultSources are used
+func NewZLinter(sources []string) (Linter, error) {
+	if len(sources) == 0 {
+		sources = DefaultSources
+	}
+	var list lint.SourceList
+	if err := list.FromString(strings.Join(sources, ",")); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	registry, err := lint.GlobalRegistry().Filter(lint.FilterOptions{
+		IncludeSources: list,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &zlinter{registry: registry}, nil
+}
+
+// Lint implements Linter
+func (l *zlinter) Lint(crt *x509.Certificate) ([]*Result, error) {
+	zcrt, err := zx509.ParseCertificate(crt.Raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse certificate")
+	}
+
+	var list []*Result
+	for name, r := range zlint.LintCertificateEx(zcrt, l.registry).Results {
+		var sev Severity
+		switch r.Status {
+		case lint.Notice:
+			sev = Notice
+		case lint.Warn:
+			sev = Warn
+		case lint.Error:
+			sev = Error
+		case lint.Fatal:
+			sev = Fatal
+		default:
+			continue
+		}
+		list = append(list, &Result{
+			Name:     name,
+			Source:   string(r.LintMetadata.Source),
+			Severity: sev,
+			Details:  r.Details,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Policy specifies how the results are handled
+type Policy struct {
+	// Block specifies the severity that blocks the issuance
+	Block Severity
+	// Warn specifies the severity that is returned as warning
+	Warn Severity
+	// Ignore specifies the names of lints to ignore
+	Ignore []string
+}
+
+// Apply returns the results that block the issuance,
+// and the results to return as warnings
+func (p *Policy) Apply(results []*Result) (blocked, warnings []*Result) {
+	for _, r := range results {
+		switch {
+		case slices.ContainsString(p.Ignore, r.Name):
+		case r.Severity >= p.Block:
+			blocked = append(blocked, r)
+		case r.Severity >= p.Warn:
+			warnings = append(warnings, r)
+		}
+	}
+	return
+}
@@ -0,0 +1,129 @@
+package certlint
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCertificate(t *testing.T, validity time.Duration, dnsNames []string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "[TEST] Lint CA", Organization: []string{"trusty"}, Country: []string{"US"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(5 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, ca, ca, key.Public(), key)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	notBefore := time.Now().Add(-time.Minute).UTC()
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(0).SetBytes([]byte{0x10, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}),
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              dnsNames,
+		OCSPServer:            []string{"http://localhost/ocsp"},
+		IssuingCertificateURL: []string{"http://localhost/ca.crt"},
+		CRLDistributionPoints: []string{"http://localhost/ca.crl"},
+		PolicyIdentifiers:     []asn1.ObjectIdentifier{{2, 23, 140, 1, 2, 1}},
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, ca, key.Public(), key)
+	require.NoError(t, err)
+	crt, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return crt
+}
+
+func TestZLinter(t *testing.T) {
+	_, err := NewZLinter([]string{"invalid"})
+	require.Error(t, err)
+
+	l, err := NewZLinter(nil)
+	require.NoError(t, err)
+
+	res, err := l.Lint(newCertificate(t, 90*24*time.Hour, []string{"trusty.com"}))
+	require.NoError(t, err)
+	for _, r := range res {
+		assert.Less(t, r.Severity, Error, "%s: %s", r.Name, r.Details)
+	}
+
+	res, err = l.Lint(newCertificate(t, 900*24*time.Hour, nil))
+	require.NoError(t, err)
+	names := map[string]*Result{}
+	for _, r := range res {
+		names[r.Name] = r
+	}
+	require.Contains(t, names, "e_sub_cert_valid_time_longer_than_825_days")
+	r := names["e_sub_cert_valid_time_longer_than_825_days"]
+	assert.Equal(t, Error, r.Severity)
+	assert.Equal(t, "CABF_BR", r.Source)
+	assert.Contains(t, names, "e_ext_san_missing")
+
+	_, err = l.Lint(&x509.Certificate{Raw: []byte("invalid")})
+	assert.Error(t, err)
+}
+
+func TestPolicy(t *testing.T) {
+	results := []*Result{
+		{Name: "n_notice", Severity: Notice},
+		{Name: "w_warn", Severity: Warn},
+		{Name: "e_error", Severity: Error},
+		{Name: "e_ignored", Severity: Fatal},
+	}
+
+	p := &Policy{Block: Error, Warn: Warn, Ignore: []string{"e_ignored"}}
+	blocked, warnings := p.Apply(results)
+	require.Len(t, blocked, 1)
+	assert.Equal(t, "e_error", blocked[0].Name)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "w_warn", warnings[0].Name)
+
+	p = &Policy{Block: Never, Warn: Notice}
+	blocked, warnings = p.Apply(results)
+	assert.Empty(t, blocked)
+	assert.Len(t, warnings, 4)
+}
+
+func TestSeverity(t *testing.T) {
+	for _, s := range []Severity{Pass, Notice, Warn, Error, Fatal, Never} {
+		v, err := ParseSeverity(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, v)
+	}
+	v, err := ParseSeverity("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, Warn, v)
+	_, err = ParseSeverity("invalid")
+	assert.Error(t, err)
+
+	js, err := json.Marshal(&Result{Name: "e_test", Severity: Error})
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"e_test","severity":"error"}`, string(js))
+
+	var r Result
+	require.NoError(t, json.Unmarshal(js, &r))
+	assert.Equal(t, Error, r.Severity)
+	assert.Error(t, json.Unmarshal([]byte(`{"severity":"invalid"}`), &r))
+}
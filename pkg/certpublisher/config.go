@@ -1,7 +1,83 @@
 package certpublisher
 
+import "github.com/effective-security/trusty/pkg/storage"
+
 // Config provides configuration for Certification Authority
 type Config struct {
 	CertsBucket string `json:"cert_bucket" yaml:"cert_bucket"`
 	CRLBucket   string `json:"crl_bucket" yaml:"crl_bucket"`
+	// IssuersBucket specifies location for issuer certificates and PKCS#7 bundles,
+	// it must match the AIA caIssuers URL.
+	// If empty, CertsBucket is used.
+	IssuersBucket string `json:"issuer_bucket,omitempty" yaml:"issuer_bucket,omitempty"`
+	// RootsBucket specifies location for the root store.
+	// If empty, the root store is not published.
+	RootsBucket string `json:"roots_bucket,omitempty" yaml:"roots_bucket,omitempty"`
+
+	// S3 specifies options for s3:// buckets
+	S3 storage.S3Options `json:"s3,omitempty" yaml:"s3,omitempty"`
+	// Filesystem specifies options for local folders
+	Filesystem storage.FilesystemOptions `json:"filesystem,omitempty" yaml:"filesystem,omitempty"`
+
+	// Policies specifies Content-Type and Cache-Control by object kind:
+	// cert, crl, issuer, p7c, roots
+	Policies map[string]*Policy `json:"policies,omitempty" yaml:"policies,omitempty"`
+	// Destinations specifies additional locations,
+	// the objects are published to all of them
+	Destinations []*Destination `json:"destinations,omitempty" yaml:"destinations,omitempty"`
+}
+
+// Destination provides configuration for additional publishing location
+type Destination struct {
+	// Name of the destination, used in logs
+	Name string `json:"name" yaml:"name"`
+
+	// Buckets for objects, empty value disables publishing of the kind
+	CertsBucket   string `json:"cert_bucket,omitempty" yaml:"cert_bucket,omitempty"`
+	CRLBucket     string `json:"crl_bucket,omitempty" yaml:"crl_bucket,omitempty"`
+	IssuersBucket string `json:"issuer_bucket,omitempty" yaml:"issuer_bucket,omitempty"`
+	RootsBucket   string `json:"roots_bucket,omitempty" yaml:"roots_bucket,omitempty"`
+
+	// S3 specifies options for s3:// buckets
+	S3 storage.S3Options `json:"s3,omitempty" yaml:"s3,omitempty"`
+	// Filesystem specifies options for local folders
+	Filesystem storage.FilesystemOptions `json:"filesystem,omitempty" yaml:"filesystem,omitempty"`
+
+	// Policies overrides Content-Type and Cache-Control by object kind
+	Policies map[string]*Policy `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// Policy specifies metadata of the published objects
+type Policy struct {
+	ContentType  string `json:"content_type,omitempty" yaml:"content_type,omitempty"`
+	CacheControl string `json:"cache_control,omitempty" yaml:"cache_control,omitempty"`
+}
+
+// Primary returns the destination defined by the top level settings
+func (c *Config) Primary() *Destination {
+	return &Destination{
+		Name:          "primary",
+		CertsBucket:   c.CertsBucket,
+		CRLBucket:     c.CRLBucket,
+		IssuersBucket: c.IssuersBucket,
+		RootsBucket:   c.RootsBucket,
+		S3:            c.S3,
+		Filesystem:    c.Filesystem,
+	}
+}
+
+// StorageOptions returns the storage options of the destination
+func (d *Destination) StorageOptions() *storage.Options {
+	return &storage.Options{
+		S3Options:         d.S3,
+		FilesystemOptions: d.Filesystem,
+	}
+}
+
+// IssuersLocation returns the bucket for issuer certificates
+func (d *Destination) IssuersLocation() string {
+	if d.IssuersBucket != "" {
+		return d.IssuersBucket
+	}
+	return d.CertsBucket
 }
@@ -0,0 +1,85 @@
+package certpublisher
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+)
+
+// encapsulatedContentInfo is ContentInfo without content
+type encapsulatedContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      encapsulatedContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      asn1.RawValue
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+// EncodePKCS7 returns degenerate certs-only PKCS#7 SignedData,
+// as defined in RFC 2315 and used by .p7c files
+func EncodePKCS7(certs [][]byte) ([]byte, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates to encode")
+	}
+
+	var raw []byte
+	for _, der := range certs {
+		raw = append(raw, der...)
+	}
+
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true}
+	sd, err := asn1.Marshal(signedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo:      encapsulatedContentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: raw},
+		SignerInfos:      emptySet,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	der, err := asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: sd},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return der, nil
+}
+
+// PEMCertificates returns DER of the certificates in PEM bundle
+func PEMCertificates(bundle string) ([][]byte, error) {
+	var certs [][]byte
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			certs = append(certs, block.Bytes)
+		}
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("unable to parse PEM certificates")
+	}
+	return certs, nil
+}
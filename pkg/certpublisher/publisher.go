@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/pem"
 	"fmt"
+	"net/url"
+	"path"
 	"strings"
 
 	"github.com/effective-security/trusty/api/pb"
@@ -14,72 +16,314 @@ import (
 
 var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/pkg", "certpublisher")
 
+// Content types of the published objects
+const (
+	ContentTypeCertChain = "application/pem-certificate-chain"
+	ContentTypeCRL       = "application/pkix-crl"
+	ContentTypeCert      = "application/pkix-cert"
+	ContentTypePKCS7     = "application/pkcs7-mime"
+)
+
+// Kinds of the published objects, used in policies
+const (
+	// KindCert specifies PEM chain of the issued certificate
+	KindCert = "cert"
+	// KindCRL specifies DER encoded CRL
+	KindCRL = "crl"
+	// KindIssuer specifies DER encoded issuer certificate
+	KindIssuer = "issuer"
+	// KindP7C specifies PKCS#7 certificates bundle
+	KindP7C = "p7c"
+	// KindRoots specifies PEM bundle of the root store
+	KindRoots = "roots"
+)
+
+// Root store file names
+const (
+	RootsFileName    = "roots.pem"
+	RootsP7CFileName = "roots.p7c"
+)
+
+// DefaultPolicies provides the policies used if not configured
+var DefaultPolicies = map[string]Policy{
+	KindCert:   {ContentType: ContentTypeCertChain, CacheControl: "public, max-age=31536000"},
+	KindCRL:    {ContentType: ContentTypeCRL, CacheControl: "public, max-age=900"}, // 15 mins
+	KindIssuer: {ContentType: ContentTypeCert, CacheControl: "public, max-age=86400"},
+	KindP7C:    {ContentType: ContentTypePKCS7, CacheControl: "public, max-age=86400"},
+	KindRoots:  {ContentType: ContentTypeCertChain, CacheControl: "public, max-age=3600"},
+}
+
 // Publisher interface
 type Publisher interface {
 	// PublishCertificate publishes issued cert
 	PublishCertificate(context.Context, *pb.Certificate, string) (string, error)
-	// PublishCRL publishes issued CRL
+	// PublishCRL publishes issued complete or delta CRL
 	PublishCRL(context.Context, *pb.Crl) (string, error)
+	// PublishIssuer publishes DER encoded issuer cert with the provided file name,
+	// and PKCS#7 bundle with its chain
+	PublishIssuer(context.Context, *pb.Certificate, string) (string, error)
+	// PublishRoots publishes the root store as PEM and PKCS#7 bundles
+	PublishRoots(context.Context, []*pb.RootCertificate) (string, error)
+}
+
+type destination struct {
+	*Destination
+	policies map[string]Policy
 }
 
 type publisher struct {
-	cfg *Config
+	destinations []*destination
 }
 
 // NewPublisher returns new Publisher
 func NewPublisher(cfg *Config) (Publisher, error) {
-	logger.KV(xlog.INFO, "cert_bucket", cfg.CertsBucket, "crl_bucket", cfg.CRLBucket)
-	return &publisher{cfg}, nil
+	p := &publisher{}
+	for _, d := range append([]*Destination{cfg.Primary()}, cfg.Destinations...) {
+		if d == nil {
+			return nil, errors.New("invalid destination")
+		}
+		p.destinations = append(p.destinations, &destination{
+			Destination: d,
+			policies:    MergePolicies(cfg.Policies, d.Policies),
+		})
+
+		logger.KV(xlog.INFO,
+			"destination", d.Name,
+			"cert_bucket", d.CertsBucket,
+			"crl_bucket", d.CRLBucket,
+			"issuer_bucket", d.IssuersLocation(),
+			"roots_bucket", d.RootsBucket)
+	}
+	return p, nil
 }
 
 // PublishCertificate publishes issued cert
 func (p *publisher) PublishCertificate(ctx context.Context, cert *pb.Certificate, filename string) (string, error) {
-	location := fmt.Sprintf("%s/%s", p.cfg.CertsBucket, filename)
+	return p.publish(ctx, KindCert, func(d *Destination) string { return d.CertsBucket }, filename, CertificateChain(cert))
+}
+
+// PublishCRL publishes issued complete or delta CRL
+func (p *publisher) PublishCRL(ctx context.Context, crl *pb.Crl) (string, error) {
+	der, err := CRLDer(crl)
+	if err != nil {
+		return "", err
+	}
+	filename := crl.IKID + ".crl"
+	if crl.BaseNumber > 0 {
+		filename = DeltaCRLName(filename)
+	} else if crl.Shard > 0 {
+		filename = ShardCRLName(filename, crl.Shard)
+	}
+	return p.publish(ctx, KindCRL, func(d *Destination) string { return d.CRLBucket }, filename, der)
+}
 
-	logger.KV(xlog.INFO, "location", location)
+// PublishIssuer publishes DER encoded issuer cert with the provided file name,
+// and PKCS#7 bundle with its chain
+func (p *publisher) PublishIssuer(ctx context.Context, cert *pb.Certificate, filename string) (string, error) {
+	certs, err := PEMCertificates(cert.Pem)
+	if err != nil {
+		return "", err
+	}
+	p7c, err := IssuerPKCS7(cert)
+	if err != nil {
+		return "", err
+	}
 
-	pem := strings.TrimSpace(cert.Pem)
-	if len(cert.IssuersPem) > 0 {
-		pem = pem + "\n" + strings.TrimSpace(cert.IssuersPem)
+	bucket := func(d *Destination) string { return d.IssuersLocation() }
+	location, err := p.publish(ctx, KindIssuer, bucket, filename, certs[0])
+	if err != nil {
+		return "", err
 	}
+	_, err = p.publish(ctx, KindP7C, bucket, P7CFileName(filename), p7c)
+	if err != nil {
+		return "", err
+	}
+	return location, nil
+}
 
-	_, err := storage.WriteFile(ctx, location, []byte(pem))
+// PublishRoots publishes the root store as PEM and PKCS#7 bundles
+func (p *publisher) PublishRoots(ctx context.Context, roots []*pb.RootCertificate) (string, error) {
+	bundle := RootsBundle(roots)
+	if len(bundle) == 0 {
+		return "", errors.New("no root certificates to publish")
+	}
+	p7c, err := RootsPKCS7(roots)
 	if err != nil {
-		return "", errors.WithMessagef(err, "unable to write file to: "+location)
+		return "", err
 	}
 
-	err = storage.SetMetadata(ctx, location, map[string]string{
-		"Content-Type":  "application/pem-certificate-chain",
-		"Cache-Control": "public, max-age=31536000",
-	})
+	bucket := func(d *Destination) string { return d.RootsBucket }
+	location, err := p.publish(ctx, KindRoots, bucket, RootsFileName, bundle)
 	if err != nil {
-		logger.ContextKV(ctx, xlog.WARNING, "reason", "SetMetadata", "err", err.Error())
+		return "", err
+	}
+	_, err = p.publish(ctx, KindP7C, bucket, RootsP7CFileName, p7c)
+	if err != nil {
+		return "", err
 	}
 	return location, nil
 }
 
-// PublishCRL publishes issued CRL
-func (p *publisher) PublishCRL(ctx context.Context, crl *pb.Crl) (string, error) {
-	fileName := fmt.Sprintf("%s/%s.crl", p.cfg.CRLBucket, crl.IKID)
+// publish writes the object to all destinations with configured bucket,
+// and returns the location in the first one.
+// The error is returned if any of destinations failed,
+// the caller is expected to retry as the writes are idempotent.
+func (p *publisher) publish(ctx context.Context, kind string, bucket func(*Destination) string, filename string, data []byte) (string, error) {
+	var location string
+	var failed []string
+	for _, d := range p.destinations {
+		b := bucket(d.Destination)
+		if b == "" {
+			continue
+		}
+		fileName := fmt.Sprintf("%s/%s", b, filename)
 
-	logger.KV(xlog.INFO, "location", fileName)
+		logger.KV(xlog.INFO, "destination", d.Name, "kind", kind, "location", fileName)
 
-	block, _ := pem.Decode([]byte(crl.Pem))
-	if block == nil || block.Type != "X509 CRL" || len(block.Headers) != 0 {
-		return "", errors.Errorf("unable to parse PEM CRL: block type %s", block.Type)
+		_, err := storage.WriteFile(ctx, fileName, data, d.StorageOptions())
+		if err != nil {
+			logger.ContextKV(ctx, xlog.ERROR,
+				"destination", d.Name,
+				"location", fileName,
+				"err", err.Error())
+			failed = append(failed, fileName)
+			continue
+		}
+
+		policy := d.policies[kind]
+		meta := map[string]string{}
+		if policy.ContentType != "" {
+			meta["Content-Type"] = policy.ContentType
+		}
+		if policy.CacheControl != "" {
+			meta["Cache-Control"] = policy.CacheControl
+		}
+		if len(meta) > 0 {
+			err = storage.SetMetadata(ctx, fileName, meta, d.StorageOptions())
+			if err != nil {
+				logger.ContextKV(ctx, xlog.WARNING, "reason", "SetMetadata", "err", err.Error())
+			}
+		}
+
+		if location == "" {
+			location = fileName
+		}
+	}
+
+	if len(failed) > 0 {
+		return "", errors.Errorf("unable to write file to: %s", strings.Join(failed, ", "))
+	}
+	return location, nil
+}
+
+// MergePolicies returns the default policies, overridden by the configured ones
+func MergePolicies(overrides ...map[string]*Policy) map[string]Policy {
+	policies := make(map[string]Policy, len(DefaultPolicies))
+	for kind, p := range DefaultPolicies {
+		policies[kind] = p
+	}
+	for _, m := range overrides {
+		for kind, o := range m {
+			if o == nil {
+				continue
+			}
+			p := policies[kind]
+			if o.ContentType != "" {
+				p.ContentType = o.ContentType
+			}
+			if o.CacheControl != "" {
+				p.CacheControl = o.CacheControl
+			}
+			policies[kind] = p
+		}
+	}
+	return policies
+}
+
+// IssuerFileName returns the file name of the issuer certificate
+// from AIA caIssuers URL, or SKID with .crt extension if the URL is not provided
+func IssuerFileName(aiaURL, skid string) string {
+	if aiaURL != "" {
+		if u, err := url.Parse(aiaURL); err == nil {
+			name := path.Base(u.Path)
+			if name != "" && name != "." && name != "/" {
+				return name
+			}
+		}
+	}
+	return skid + ".crt"
+}
+
+// DeltaCRLName returns the file name or URL of delta CRL
+// for the complete CRL file name or URL
+func DeltaCRLName(name string) string {
+	if strings.HasSuffix(name, ".crl") {
+		return strings.TrimSuffix(name, ".crl") + "-delta.crl"
+	}
+	return name + "-delta"
+}
+
+// ShardCRLName returns the file name or URL of CRL shard
+// for the complete CRL file name or URL
+func ShardCRLName(name string, shard uint32) string {
+	if base, ok := strings.CutSuffix(name, ".crl"); ok {
+		return fmt.Sprintf("%s/%d.crl", base, shard)
 	}
+	return fmt.Sprintf("%s/%d", name, shard)
+}
+
+// P7CFileName returns the file name of PKCS#7 bundle for the issuer file name
+func P7CFileName(filename string) string {
+	return strings.TrimSuffix(filename, path.Ext(filename)) + ".p7c"
+}
 
-	_, err := storage.WriteFile(ctx, fileName, block.Bytes)
+// IssuerPKCS7 returns PKCS#7 bundle of the issuer certificate and its chain
+func IssuerPKCS7(cert *pb.Certificate) ([]byte, error) {
+	certs, err := PEMCertificates(string(CertificateChain(cert)))
 	if err != nil {
-		return "", errors.WithMessagef(err, "unable to write file to: "+fileName)
+		return nil, err
 	}
+	return EncodePKCS7(certs)
+}
 
-	err = storage.SetMetadata(ctx, fileName, map[string]string{
-		"Content-Type":  "application/pkix-crl",
-		"Cache-Control": "public, max-age=900", // 15 mins
-	})
+// RootsBundle returns the published PEM bundle of the root store
+func RootsBundle(roots []*pb.RootCertificate) []byte {
+	var list []string
+	for _, r := range roots {
+		if pem := strings.TrimSpace(r.Pem); pem != "" {
+			list = append(list, pem)
+		}
+	}
+	return []byte(strings.Join(list, "\n"))
+}
+
+// RootsPKCS7 returns PKCS#7 bundle of the root store
+func RootsPKCS7(roots []*pb.RootCertificate) ([]byte, error) {
+	certs, err := PEMCertificates(string(RootsBundle(roots)))
 	if err != nil {
-		logger.ContextKV(ctx, xlog.WARNING, "reason", "SetMetadata", "err", err.Error())
+		return nil, err
+	}
+	return EncodePKCS7(certs)
+}
+
+// CertificateChain returns the published content of the certificate:
+// PEM encoded certificate followed by its issuers
+func CertificateChain(cert *pb.Certificate) []byte {
+	pem := strings.TrimSpace(cert.Pem)
+	if len(cert.IssuersPem) > 0 {
+		pem = pem + "\n" + strings.TrimSpace(cert.IssuersPem)
+	}
+	return []byte(pem)
+}
+
+// CRLDer returns the published content of the CRL in DER format
+func CRLDer(crl *pb.Crl) ([]byte, error) {
+	block, _ := pem.Decode([]byte(crl.Pem))
+	if block == nil {
+		return nil, errors.New("unable to parse PEM CRL")
+	}
+	if block.Type != "X509 CRL" || len(block.Headers) != 0 {
+		return nil, errors.Errorf("unable to parse PEM CRL: block type %s", block.Type)
 	}
-	return fileName, nil
+	return block.Bytes, nil
 }
@@ -2,10 +2,19 @@ package certpublisher_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/effective-security/trusty/api/pb"
 	"github.com/effective-security/trusty/backend/db/cadb/model"
@@ -62,6 +71,188 @@ func TestProvider(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.NoError(t, fileutil.FileExists(fn2))
+
+	fn3, err := p.PublishCRL(ctx, &pb.Crl{
+		Pem:        testCRL,
+		IKID:       "ikid",
+		CrlNumber:  2,
+		BaseNumber: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testDirPath+"/ikid-delta.crl", fn3)
+	assert.NoError(t, fileutil.FileExists(fn3))
+
+	fn4, err := p.PublishCRL(ctx, &pb.Crl{
+		Pem:       testCRL,
+		IKID:      "ikid",
+		CrlNumber: 3,
+		Shard:     2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testDirPath+"/ikid/2.crl", fn4)
+	assert.NoError(t, fileutil.FileExists(fn4))
+}
+
+func TestPublishIssuerAndRoots(t *testing.T) {
+	rootPem := newTestCert(t, "root")
+	issuerPem := newTestCert(t, "issuer")
+
+	primary := filepath.Join(testDirPath, guid.MustCreate())
+	mirror := filepath.Join(testDirPath, guid.MustCreate())
+
+	p, err := certpublisher.NewPublisher(&certpublisher.Config{
+		CertsBucket:   primary,
+		IssuersBucket: primary + "/aia",
+		RootsBucket:   primary + "/roots",
+		Policies: map[string]*certpublisher.Policy{
+			certpublisher.KindIssuer: {CacheControl: "no-cache"},
+		},
+		Destinations: []*certpublisher.Destination{
+			{
+				Name:        "mirror",
+				CertsBucket: mirror,
+				RootsBucket: mirror,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	cert := &pb.Certificate{
+		Pem:        issuerPem,
+		IssuersPem: rootPem,
+	}
+
+	location, err := p.PublishIssuer(ctx, cert, "issuer.crt")
+	require.NoError(t, err)
+	assert.Equal(t, primary+"/aia/issuer.crt", location)
+
+	for _, dir := range []string{primary + "/aia", mirror} {
+		der, err := os.ReadFile(filepath.Join(dir, "issuer.crt"))
+		require.NoError(t, err)
+		crt, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		assert.Equal(t, "issuer", crt.Subject.CommonName)
+
+		p7c, err := os.ReadFile(filepath.Join(dir, "issuer.p7c"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"issuer", "root"}, parseP7C(t, p7c))
+	}
+
+	location, err = p.PublishRoots(ctx, []*pb.RootCertificate{{Pem: rootPem}})
+	require.NoError(t, err)
+	assert.Equal(t, primary+"/roots/"+certpublisher.RootsFileName, location)
+
+	for _, dir := range []string{primary + "/roots", mirror} {
+		bundle, err := os.ReadFile(filepath.Join(dir, certpublisher.RootsFileName))
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(rootPem), string(bundle))
+
+		p7c, err := os.ReadFile(filepath.Join(dir, certpublisher.RootsP7CFileName))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"root"}, parseP7C(t, p7c))
+	}
+
+	_, err = p.PublishRoots(ctx, nil)
+	require.Error(t, err)
+	_, err = p.PublishIssuer(ctx, &pb.Certificate{Pem: "invalid"}, "issuer.crt")
+	require.Error(t, err)
+
+	t.Run("failed destination", func(t *testing.T) {
+		file := filepath.Join(testDirPath, guid.MustCreate())
+		require.NoError(t, os.WriteFile(file, []byte("file"), 0644))
+
+		p, err := certpublisher.NewPublisher(&certpublisher.Config{
+			CertsBucket: primary,
+			Destinations: []*certpublisher.Destination{
+				{Name: "invalid", CertsBucket: file},
+			},
+		})
+		require.NoError(t, err)
+		_, err = p.PublishIssuer(ctx, cert, "issuer.crt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to write file to: "+file)
+		// the healthy destination is still published
+		assert.NoError(t, fileutil.FileExists(filepath.Join(primary, "issuer.crt")))
+	})
+
+	_, err = certpublisher.NewPublisher(&certpublisher.Config{
+		Destinations: []*certpublisher.Destination{nil},
+	})
+	require.Error(t, err)
+}
+
+func TestIssuerFileName(t *testing.T) {
+	tcases := []struct {
+		aia, exp string
+	}{
+		{"", "skid.crt"},
+		{"http://localhost:7880/v1/cert/skid", "skid"},
+		{"http://pki.example.com/issuers/l2.crt", "l2.crt"},
+		{"http://pki.example.com/", "skid.crt"},
+		{"://invalid", "skid.crt"},
+	}
+	for _, tc := range tcases {
+		assert.Equal(t, tc.exp, certpublisher.IssuerFileName(tc.aia, "skid"), tc.aia)
+	}
+
+	assert.Equal(t, "l2.p7c", certpublisher.P7CFileName("l2.crt"))
+	assert.Equal(t, "ikid-delta.crl", certpublisher.DeltaCRLName("ikid.crl"))
+	assert.Equal(t, "http://localhost/v1/crl/ikid-delta", certpublisher.DeltaCRLName("http://localhost/v1/crl/ikid"))
+	assert.Equal(t, "skid.p7c", certpublisher.P7CFileName("skid"))
+	assert.Equal(t, "ikid/3.crl", certpublisher.ShardCRLName("ikid.crl", 3))
+	assert.Equal(t, "http://localhost/v1/crl/ikid/3", certpublisher.ShardCRLName("http://localhost/v1/crl/ikid", 3))
+}
+
+// newTestCert returns self-signed PEM certificate,
+// the chain is not verified by the publisher
+func newTestCert(t *testing.T, cn string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// parseP7C returns CNs of the certificates in PKCS#7 bundle
+func parseP7C(t *testing.T, der []byte) []string {
+	var ci struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}
+	_, err := asn1.Unmarshal(der, &ci)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.840.113549.1.7.2", ci.ContentType.String())
+
+	var sd struct {
+		Version          int
+		DigestAlgorithms asn1.RawValue
+		ContentInfo      asn1.RawValue
+		Certificates     asn1.RawValue `asn1:"tag:0"`
+		SignerInfos      asn1.RawValue
+	}
+	_, err = asn1.Unmarshal(ci.Content.Bytes, &sd)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sd.Version)
+
+	list, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	require.NoError(t, err)
+
+	var names []string
+	for _, c := range list {
+		names = append(names, c.Subject.CommonName)
+	}
+	return names
 }
 
 const testCRL = `-----BEGIN X509 CRL-----
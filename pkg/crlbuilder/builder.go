@@ -0,0 +1,530 @@
+// Package crlbuilder provides memory bounded CRL encoding for large revocation lists.
+//
+// The revoked certificates are added one by one, as they are read from the DB,
+// and their DER encoding is spooled to a temporary file,
+// instead of the list of parsed entries required by x509.CreateRevocationList.
+// The length of the revoked certificates is known only when all are added,
+// so the CRL is signed and written to io.Writer by Finish
+// with the entries read back from the file.
+//
+// The memory is bounded by the encoding buffers, except:
+// Ed25519 signs the message and not a digest, so TBS is read in memory to be signed;
+// and FinishPEM returns the whole CRL, use Finish to write it without buffering.
+//
+// The produced CRL is identical to the one created by x509.CreateRevocationList.
+package crlbuilder
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DER tags
+const (
+	tagInteger         = 0x02
+	tagBitString       = 0x03
+	tagOctetString     = 0x04
+	tagEnumerated      = 0x0a
+	tagUTCTime         = 0x17
+	tagGeneralizedTime = 0x18
+	tagSequence        = 0x30
+	tagExplicit0       = 0xa0
+)
+
+var (
+	oidExtensionReasonCode      = asn1.ObjectIdentifier{2, 5, 29, 21}
+	oidExtensionAuthorityKeyID  = asn1.ObjectIdentifier{2, 5, 29, 35}
+	oidExtensionCRLNumber       = asn1.ObjectIdentifier{2, 5, 29, 20}
+	oidSignatureSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSignatureSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSignatureSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidSignatureECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidSignatureECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidSignatureECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidSignatureEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// reasonCodeID is DER encoded OID of the reason code extension
+var reasonCodeID = appendOID(nil, oidExtensionReasonCode)
+
+// Builder encodes CRL with the revoked certificates added one by one,
+// Close must be called to remove the spooled entries
+type Builder struct {
+	template *x509.RevocationList
+	issuer   *x509.Certificate
+	signer   crypto.Signer
+
+	algorithm []byte
+	hash      crypto.Hash
+
+	// spool has the encoded revoked certificates
+	spool    *os.File
+	entries  *bufio.Writer
+	entryLen int
+	count    int
+	scratch  []byte
+	record   []byte
+}
+
+// New returns Builder for CRL with the template fields:
+// Number, ThisUpdate, NextUpdate, SignatureAlgorithm and ExtraExtensions.
+// The revoked certificates in the template are ignored, use Add instead.
+// The entries are spooled to a temporary file, which is removed by Close.
+func New(template *x509.RevocationList, issuer *x509.Certificate, signer crypto.Signer) (*Builder, error) {
+	if template == nil {
+		return nil, errors.New("template can not be nil")
+	}
+	if issuer == nil {
+		return nil, errors.New("issuer can not be nil")
+	}
+	if (issuer.KeyUsage & x509.KeyUsageCRLSign) == 0 {
+		return nil, errors.New("issuer must have the crlSign key usage bit set")
+	}
+	if len(issuer.SubjectKeyId) == 0 {
+		return nil, errors.New("issuer certificate doesn't contain a subject key identifier")
+	}
+	if template.NextUpdate.Before(template.ThisUpdate) {
+		return nil, errors.New("template.ThisUpdate is after template.NextUpdate")
+	}
+	if template.Number == nil {
+		return nil, errors.New("template contains nil Number field")
+	}
+	if numBytes := template.Number.Bytes(); len(numBytes) > 20 || (len(numBytes) == 20 && numBytes[0]&0x80 != 0) {
+		return nil, errors.New("CRL number exceeds 20 octets")
+	}
+
+	algorithm, hash, err := signingParams(signer.Public(), template.SignatureAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	spool, err := os.CreateTemp("", "crl-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CRL spool")
+	}
+
+	return &Builder{
+		template:  template,
+		issuer:    issuer,
+		signer:    signer,
+		algorithm: algorithm,
+		hash:      hash,
+		spool:     spool,
+		entries:   bufio.NewWriterSize(spool, spoolBufferSize),
+	}, nil
+}
+
+// spoolBufferSize specifies the size of the buffers to write and read the entries
+const spoolBufferSize = 64 * 1024
+
+// Close removes the spooled entries
+func (b *Builder) Close() error {
+	if b.spool == nil {
+		return nil
+	}
+	name := b.spool.Name()
+	err := b.spool.Close()
+	b.spool = nil
+	if rerr := os.Remove(name); err == nil {
+		err = rerr
+	}
+	return errors.WithStack(err)
+}
+
+// Len returns the number of added revoked certificates
+func (b *Builder) Len() int {
+	return b.count
+}
+
+// Add adds the revoked certificate,
+// the reason code extension is included if the reason is not 0
+func (b *Builder) Add(serial *big.Int, revokedAt time.Time, reason int) error {
+	if serial == nil || serial.Sign() < 0 {
+		return errors.New("invalid serial number")
+	}
+	if revokedAt.IsZero() {
+		return errors.New("invalid revocation time")
+	}
+
+	entry := appendInteger(b.scratch[:0], serial)
+	entry = appendTime(entry, revokedAt)
+	if reason != 0 {
+		if reason < 0 {
+			return errors.Errorf("invalid reason code: %d", reason)
+		}
+		// Extensions ::= SEQUENCE { Extension { extnID, extnValue OCTET STRING { ENUMERATED } } },
+		// all the lengths are short form
+		var buf [32]byte
+		value := appendEnumerated(buf[:0], reason)
+		extLen := len(reasonCodeID) + 2 + len(value)
+		entry = appendHeader(entry, tagSequence, extLen+2)
+		entry = appendHeader(entry, tagSequence, extLen)
+		entry = append(entry, reasonCodeID...)
+		entry = appendHeader(entry, tagOctetString, len(value))
+		entry = append(entry, value...)
+	}
+	b.scratch = entry
+
+	record := appendHeader(b.record[:0], tagSequence, len(entry))
+	record = append(record, entry...)
+	b.record = record
+
+	if _, err := b.entries.Write(record); err != nil {
+		return errors.WithStack(err)
+	}
+	b.entryLen += len(record)
+	b.count++
+	return nil
+}
+
+// Finish signs the CRL, and writes it in DER format
+func (b *Builder) Finish(w io.Writer) error {
+	if b.spool == nil {
+		return errors.New("builder is closed")
+	}
+	if err := b.entries.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	tbs, err := b.tbsCertList()
+	if err != nil {
+		return err
+	}
+
+	signature, err := b.sign(tbs)
+	if err != nil {
+		return err
+	}
+
+	sigValue := appendTLV(nil, tagBitString, append([]byte{0}, signature...))
+	hdr := appendHeader(nil, tagSequence, tbs.len()+len(b.algorithm)+len(sigValue))
+	if _, err = w.Write(hdr); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = b.writeTBS(w, tbs); err != nil {
+		return err
+	}
+	for _, part := range [][]byte{b.algorithm, sigValue} {
+		if _, err = w.Write(part); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// FinishPEM signs the CRL, and returns it in PEM format
+func (b *Builder) FinishPEM() (string, error) {
+	var sb strings.Builder
+	// base64 with line breaks, plus the headers and the signature
+	sb.Grow(b.entryLen*4/3 + b.entryLen/48 + 4096)
+	sb.WriteString("-----BEGIN X509 CRL-----\n")
+
+	lb := &lineBreaker{out: &sb}
+	enc := base64.NewEncoder(base64.StdEncoding, lb)
+	if err := b.Finish(enc); err != nil {
+		return "", err
+	}
+	_ = enc.Close()
+	lb.Close()
+
+	sb.WriteString("-----END X509 CRL-----\n")
+	return sb.String(), nil
+}
+
+// tbsParts is DER encoded TBSCertList,
+// the revoked certificates are in the spool between head and tail
+type tbsParts struct {
+	head     []byte
+	entryLen int
+	tail     []byte
+}
+
+func (t *tbsParts) len() int {
+	return len(t.head) + t.entryLen + len(t.tail)
+}
+
+// tbsCertList returns the parts of DER encoded TBSCertList,
+// the revoked certificates are not copied
+func (b *Builder) tbsCertList() (*tbsParts, error) {
+	aki, err := asn1.Marshal(struct {
+		ID []byte `asn1:"optional,tag:0"`
+	}{ID: b.issuer.SubjectKeyId})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	number, err := asn1.Marshal(b.template.Number)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	exts := append([]pkix.Extension{
+		{Id: oidExtensionAuthorityKeyID, Value: aki},
+		{Id: oidExtensionCRLNumber, Value: number},
+	}, b.template.ExtraExtensions...)
+	extsDer, err := asn1.Marshal(exts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	issuer := b.issuer.RawSubject
+	if len(issuer) == 0 {
+		if issuer, err = asn1.Marshal(b.issuer.Subject.ToRDNSequence()); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	// version v2, signature, issuer, thisUpdate, nextUpdate
+	fields := appendTLV(nil, tagInteger, []byte{1})
+	fields = append(fields, b.algorithm...)
+	fields = append(fields, issuer...)
+	fields = appendTime(fields, b.template.ThisUpdate)
+	fields = appendTime(fields, b.template.NextUpdate)
+
+	var revokedHdr []byte
+	if b.count > 0 {
+		revokedHdr = appendHeader(nil, tagSequence, b.entryLen)
+	}
+	tail := appendTLV(nil, tagExplicit0, extsDer)
+
+	size := len(fields) + len(revokedHdr) + b.entryLen + len(tail)
+	head := appendHeader(nil, tagSequence, size)
+	head = append(head, fields...)
+	head = append(head, revokedHdr...)
+
+	return &tbsParts{
+		head:     head,
+		entryLen: b.entryLen,
+		tail:     tail,
+	}, nil
+}
+
+// writeTBS writes TBSCertList with the entries read from the spool
+func (b *Builder) writeTBS(w io.Writer, tbs *tbsParts) error {
+	if _, err := w.Write(tbs.head); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := b.spool.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStack(err)
+	}
+	r := bufio.NewReaderSize(b.spool, spoolBufferSize)
+	n, err := io.CopyN(w, r, int64(tbs.entryLen))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read CRL entries: %d of %d", n, tbs.entryLen)
+	}
+	if _, err := w.Write(tbs.tail); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (b *Builder) sign(tbs *tbsParts) ([]byte, error) {
+	var (
+		digest []byte
+		opts   crypto.SignerOpts = b.hash
+	)
+	if b.hash == 0 {
+		// Ed25519 signs the message
+		var msg bytes.Buffer
+		msg.Grow(tbs.len())
+		if err := b.writeTBS(&msg, tbs); err != nil {
+			return nil, err
+		}
+		digest = msg.Bytes()
+		opts = crypto.Hash(0)
+	} else {
+		h := b.hash.New()
+		if err := b.writeTBS(h, tbs); err != nil {
+			return nil, err
+		}
+		digest = h.Sum(nil)
+	}
+
+	signature, err := b.signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign CRL")
+	}
+	return signature, nil
+}
+
+// signingParams returns DER encoded AlgorithmIdentifier and hash for the key,
+// as selected by x509.CreateRevocationList
+func signingParams(pub crypto.PublicKey, requested x509.SignatureAlgorithm) ([]byte, crypto.Hash, error) {
+	var (
+		oid        asn1.ObjectIdentifier
+		hash       crypto.Hash
+		nullParams bool
+	)
+
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		nullParams = true
+		switch requested {
+		case x509.UnknownSignatureAlgorithm, x509.SHA256WithRSA:
+			oid, hash = oidSignatureSHA256WithRSA, crypto.SHA256
+		case x509.SHA384WithRSA:
+			oid, hash = oidSignatureSHA384WithRSA, crypto.SHA384
+		case x509.SHA512WithRSA:
+			oid, hash = oidSignatureSHA512WithRSA, crypto.SHA512
+		}
+	case *ecdsa.PublicKey:
+		if requested == x509.UnknownSignatureAlgorithm {
+			switch pub.Curve {
+			case elliptic.P224(), elliptic.P256():
+				requested = x509.ECDSAWithSHA256
+			case elliptic.P384():
+				requested = x509.ECDSAWithSHA384
+			case elliptic.P521():
+				requested = x509.ECDSAWithSHA512
+			}
+		}
+		switch requested {
+		case x509.ECDSAWithSHA256:
+			oid, hash = oidSignatureECDSAWithSHA256, crypto.SHA256
+		case x509.ECDSAWithSHA384:
+			oid, hash = oidSignatureECDSAWithSHA384, crypto.SHA384
+		case x509.ECDSAWithSHA512:
+			oid, hash = oidSignatureECDSAWithSHA512, crypto.SHA512
+		}
+	case ed25519.PublicKey:
+		if requested == x509.UnknownSignatureAlgorithm || requested == x509.PureEd25519 {
+			oid = oidSignatureEd25519
+		}
+	default:
+		return nil, 0, errors.Errorf("unsupported key type: %T", pub)
+	}
+	if oid == nil {
+		return nil, 0, errors.Errorf("unsupported signature algorithm: %s", requested)
+	}
+
+	algorithm := appendOID(nil, oid)
+	if nullParams {
+		algorithm = append(algorithm, 0x05, 0x00)
+	}
+	return appendTLV(nil, tagSequence, algorithm), hash, nil
+}
+
+func appendHeader(dst []byte, tag byte, length int) []byte {
+	dst = append(dst, tag)
+	if length < 0x80 {
+		return append(dst, byte(length))
+	}
+	n := 0
+	for l := length; l > 0; l >>= 8 {
+		n++
+	}
+	dst = append(dst, 0x80|byte(n))
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(length>>(8*i)))
+	}
+	return dst
+}
+
+func appendTLV(dst []byte, tag byte, value []byte) []byte {
+	dst = appendHeader(dst, tag, len(value))
+	return append(dst, value...)
+}
+
+func appendOID(dst []byte, oid asn1.ObjectIdentifier) []byte {
+	der, _ := asn1.Marshal(oid)
+	return append(dst, der...)
+}
+
+// appendInteger appends non-negative INTEGER
+func appendInteger(dst []byte, n *big.Int) []byte {
+	var buf [32]byte
+	var b []byte
+	if size := (n.BitLen() + 7) / 8; size <= len(buf) {
+		b = n.FillBytes(buf[:size])
+	} else {
+		b = n.Bytes()
+	}
+
+	length := len(b)
+	pad := length == 0 || b[0]&0x80 != 0
+	if pad {
+		length++
+	}
+	dst = appendHeader(dst, tagInteger, length)
+	if pad {
+		dst = append(dst, 0)
+	}
+	return append(dst, b...)
+}
+
+// appendEnumerated appends non-negative ENUMERATED
+func appendEnumerated(dst []byte, v int) []byte {
+	var buf [9]byte
+	i := len(buf)
+	for {
+		i--
+		buf[i] = byte(v)
+		v >>= 8
+		if v == 0 {
+			break
+		}
+	}
+	if buf[i]&0x80 != 0 {
+		i--
+		buf[i] = 0
+	}
+	return appendTLV(dst, tagEnumerated, buf[i:])
+}
+
+// appendTime appends UTCTime, or GeneralizedTime outside of 1950-2049,
+// as encoded by encoding/asn1
+func appendTime(dst []byte, t time.Time) []byte {
+	t = t.UTC()
+	if year := t.Year(); year >= 1950 && year < 2050 {
+		dst = appendHeader(dst, tagUTCTime, 13)
+		return t.AppendFormat(dst, "060102150405Z")
+	}
+	dst = appendHeader(dst, tagGeneralizedTime, 15)
+	return t.AppendFormat(dst, "20060102150405Z")
+}
+
+// lineBreaker breaks base64 output into lines of 64 characters,
+// as encoded by encoding/pem
+type lineBreaker struct {
+	out  *strings.Builder
+	used int
+}
+
+const pemLineLength = 64
+
+func (l *lineBreaker) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		chunk := min(pemLineLength-l.used, len(b))
+		l.out.Write(b[:chunk])
+		l.used += chunk
+		b = b[chunk:]
+		if l.used == pemLineLength {
+			l.out.WriteByte('\n')
+			l.used = 0
+		}
+	}
+	return n, nil
+}
+
+func (l *lineBreaker) Close() {
+	if l.used > 0 {
+		l.out.WriteByte('\n')
+	}
+}
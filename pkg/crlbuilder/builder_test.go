@@ -0,0 +1,322 @@
+package crlbuilder
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIssuer(t testing.TB, key crypto.Signer) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "[TEST] CRL Builder", Organization: []string{"trusty"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	crt, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return crt
+}
+
+func entries(count int) []x509.RevocationListEntry {
+	at := time.Date(2024, 5, 1, 10, 20, 30, 0, time.UTC)
+	list := make([]x509.RevocationListEntry, count)
+	for i := range list {
+		sn := new(big.Int).Lsh(big.NewInt(int64(i+1)), 120)
+		sn.Add(sn, big.NewInt(int64(i)))
+		list[i] = x509.RevocationListEntry{
+			SerialNumber:   sn,
+			RevocationTime: at.Add(time.Duration(i) * time.Second),
+			ReasonCode:     i % 6,
+		}
+	}
+	// sign padding, and time out of UTCTime range
+	list = append(list, x509.RevocationListEntry{
+		SerialNumber:   big.NewInt(0x80),
+		RevocationTime: time.Date(2051, 1, 1, 0, 0, 0, 0, time.UTC),
+		ReasonCode:     10,
+	}, x509.RevocationListEntry{
+		SerialNumber:   big.NewInt(0x8001),
+		RevocationTime: time.Date(1949, 12, 31, 23, 59, 59, 0, time.UTC),
+		ReasonCode:     200,
+	})
+	return list
+}
+
+func template(number int64) *x509.RevocationList {
+	now := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	return &x509.RevocationList{
+		Number:     big.NewInt(number),
+		ThisUpdate: now,
+		NextUpdate: now.Add(12 * time.Hour),
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{2, 5, 29, 28}, Critical: true, Value: []byte{0x30, 0x00}},
+		},
+	}
+}
+
+func build(t testing.TB, tmpl *x509.RevocationList, issuer *x509.Certificate, signer crypto.Signer, list []x509.RevocationListEntry) []byte {
+	b, err := New(tmpl, issuer, signer)
+	require.NoError(t, err)
+	defer b.Close()
+	for _, e := range list {
+		require.NoError(t, b.Add(e.SerialNumber, e.RevocationTime, e.ReasonCode))
+	}
+	var buf bytes.Buffer
+	require.NoError(t, b.Finish(&buf))
+	return buf.Bytes()
+}
+
+func TestBuilderEquivalence(t *testing.T) {
+	// Ed25519 signatures are deterministic
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	issuer := newIssuer(t, key)
+
+	for _, count := range []int{0, 1, 5, 3000} {
+		t.Run(fmt.Sprintf("%d", count), func(t *testing.T) {
+			list := entries(count)
+			tmpl := template(int64(count + 1000))
+
+			der := build(t, tmpl, issuer, key, list)
+
+			tmpl.RevokedCertificateEntries = list
+			expected, err := x509.CreateRevocationList(rand.Reader, tmpl, issuer, key)
+			require.NoError(t, err)
+			assert.Equal(t, der, expected)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		tmpl := template(1)
+		der := build(t, tmpl, issuer, key, nil)
+		expected, err := x509.CreateRevocationList(rand.Reader, tmpl, issuer, key)
+		require.NoError(t, err)
+		assert.Equal(t, der, expected)
+	})
+}
+
+func TestBuilderKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	tcases := []struct {
+		name   string
+		key    crypto.Signer
+		alg    x509.SignatureAlgorithm
+		expAlg x509.SignatureAlgorithm
+	}{
+		{"rsa", rsaKey, 0, x509.SHA256WithRSA},
+		{"rsa384", rsaKey, x509.SHA384WithRSA, x509.SHA384WithRSA},
+		{"p256", p256, 0, x509.ECDSAWithSHA256},
+		{"p384", p384, 0, x509.ECDSAWithSHA384},
+		{"p521", p521, 0, x509.ECDSAWithSHA512},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := newIssuer(t, tc.key)
+			list := entries(10)
+			tmpl := template(2)
+			tmpl.SignatureAlgorithm = tc.alg
+
+			b, err := New(tmpl, issuer, tc.key)
+			require.NoError(t, err)
+			defer b.Close()
+			for _, e := range list {
+				require.NoError(t, b.Add(e.SerialNumber, e.RevocationTime, e.ReasonCode))
+			}
+			assert.Equal(t, len(list), b.Len())
+
+			crl, err := b.FinishPEM()
+			require.NoError(t, err)
+
+			block, rest := pem.Decode([]byte(crl))
+			require.NotNil(t, block)
+			assert.Empty(t, rest)
+			assert.Equal(t, "X509 CRL", block.Type)
+			assert.Equal(t, crl, string(pem.EncodeToMemory(block)))
+
+			rl, err := x509.ParseRevocationList(block.Bytes)
+			require.NoError(t, err)
+			require.NoError(t, rl.CheckSignatureFrom(issuer))
+			assert.Equal(t, tc.expAlg, rl.SignatureAlgorithm)
+			assert.Equal(t, int64(2), rl.Number.Int64())
+			assert.Equal(t, issuer.SubjectKeyId, rl.AuthorityKeyId)
+			require.Len(t, rl.RevokedCertificateEntries, len(list))
+			for i, e := range rl.RevokedCertificateEntries {
+				assert.Equal(t, list[i].SerialNumber, e.SerialNumber)
+				assert.Equal(t, list[i].RevocationTime, e.RevocationTime)
+				assert.Equal(t, list[i].ReasonCode, e.ReasonCode)
+			}
+		})
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := newIssuer(t, key)
+
+	_, err = New(nil, issuer, key)
+	assert.EqualError(t, err, "template can not be nil")
+	_, err = New(template(1), nil, key)
+	assert.EqualError(t, err, "issuer can not be nil")
+
+	noCrlSign := *issuer
+	noCrlSign.KeyUsage = x509.KeyUsageCertSign
+	_, err = New(template(1), &noCrlSign, key)
+	assert.EqualError(t, err, "issuer must have the crlSign key usage bit set")
+
+	noSKID := *issuer
+	noSKID.SubjectKeyId = nil
+	_, err = New(template(1), &noSKID, key)
+	assert.EqualError(t, err, "issuer certificate doesn't contain a subject key identifier")
+
+	tmpl := template(1)
+	tmpl.NextUpdate = tmpl.ThisUpdate.Add(-time.Second)
+	_, err = New(tmpl, issuer, key)
+	assert.EqualError(t, err, "template.ThisUpdate is after template.NextUpdate")
+
+	tmpl = template(1)
+	tmpl.Number = nil
+	_, err = New(tmpl, issuer, key)
+	assert.EqualError(t, err, "template contains nil Number field")
+
+	tmpl = template(1)
+	tmpl.Number = new(big.Int).Lsh(big.NewInt(1), 160)
+	_, err = New(tmpl, issuer, key)
+	assert.EqualError(t, err, "CRL number exceeds 20 octets")
+
+	tmpl = template(1)
+	tmpl.SignatureAlgorithm = x509.SHA256WithRSA
+	_, err = New(tmpl, issuer, key)
+	assert.EqualError(t, err, "unsupported signature algorithm: SHA256-RSA")
+
+	b, err := New(template(1), issuer, key)
+	require.NoError(t, err)
+	assert.EqualError(t, b.Add(nil, time.Now(), 0), "invalid serial number")
+	assert.EqualError(t, b.Add(big.NewInt(-1), time.Now(), 0), "invalid serial number")
+	assert.EqualError(t, b.Add(big.NewInt(1), time.Time{}, 0), "invalid revocation time")
+	assert.EqualError(t, b.Add(big.NewInt(1), time.Now(), -1), "invalid reason code: -1")
+	assert.Zero(t, b.Len())
+
+	// the spool is removed on close
+	name := b.spool.Name()
+	require.NoError(t, b.Close())
+	_, err = os.Stat(name)
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, b.Close())
+	assert.EqualError(t, b.Finish(io.Discard), "builder is closed")
+}
+
+func TestBuilderFinishTwice(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := newIssuer(t, key)
+
+	b, err := New(template(1), issuer, key)
+	require.NoError(t, err)
+	defer b.Close()
+
+	list := entries(100)
+	for _, e := range list[:50] {
+		require.NoError(t, b.Add(e.SerialNumber, e.RevocationTime, e.ReasonCode))
+	}
+	var buf bytes.Buffer
+	require.NoError(t, b.Finish(&buf))
+
+	// the entries can be added after Finish
+	for _, e := range list[50:] {
+		require.NoError(t, b.Add(e.SerialNumber, e.RevocationTime, e.ReasonCode))
+	}
+	buf.Reset()
+	require.NoError(t, b.Finish(&buf))
+
+	rl, err := x509.ParseRevocationList(buf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, rl.CheckSignatureFrom(issuer))
+	assert.Len(t, rl.RevokedCertificateEntries, len(list))
+}
+
+// The benchmarks compare the builder with x509.CreateRevocationList,
+// run with -benchmem to report the allocations:
+//
+//	go test ./pkg/crlbuilder -run=^$ -bench=. -benchmem
+func BenchmarkBuilder(b *testing.B) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(b, err)
+	issuer := newIssuer(b, key)
+	at := time.Now()
+
+	for _, count := range []int{10000, 100000, 1000000} {
+		b.Run(fmt.Sprintf("stream/%d", count), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				builder, err := New(template(1), issuer, key)
+				require.NoError(b, err)
+				sn := new(big.Int)
+				for n := 0; n < count; n++ {
+					// the entries are read from DB in batches, and not retained
+					sn.SetInt64(int64(n + 1))
+					if err = builder.Add(sn, at, n%6); err != nil {
+						b.Fatal(err)
+					}
+				}
+				// the CRL is written without buffering
+				if err = builder.Finish(io.Discard); err != nil {
+					b.Fatal(err)
+				}
+				_ = builder.Close()
+			}
+		})
+
+		b.Run(fmt.Sprintf("x509/%d", count), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tmpl := template(1)
+				tmpl.RevokedCertificateEntries = make([]x509.RevocationListEntry, 0, 1000)
+				for n := 0; n < count; n++ {
+					tmpl.RevokedCertificateEntries = append(tmpl.RevokedCertificateEntries, x509.RevocationListEntry{
+						SerialNumber:   big.NewInt(int64(n + 1)),
+						RevocationTime: at,
+						ReasonCode:     n % 6,
+					})
+				}
+				der, err := x509.CreateRevocationList(rand.Reader, tmpl, issuer, key)
+				if err != nil {
+					b.Fatal(err)
+				}
+				_ = pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+			}
+		})
+	}
+}
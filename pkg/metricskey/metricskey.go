@@ -29,6 +29,14 @@ var (
 		Help: "provides total number of Issuers",
 		//RequiredTags: []string{},
 	}
+
+	// StatsDbRowsPurged is counter metric for rows purged by retention policy
+	StatsDbRowsPurged = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "stats_table_rows_purged",
+		Help:         "provides the counter of rows purged by retention policy",
+		RequiredTags: []string{"table"},
+	}
 )
 
 // Health
@@ -124,6 +132,14 @@ var (
 		RequiredTags: []string{"ikid"},
 	}
 
+	// CACertImported is counter metric for imported external certs
+	CACertImported = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "ca_cert_imported",
+		Help:         "provides the counter of imported external certs",
+		RequiredTags: []string{"profile"},
+	}
+
 	// CACrlPublished is counter metric for published CRL
 	CACrlPublished = metrics.Describe{
 		Type: metrics.TypeCounter,
@@ -140,6 +156,38 @@ var (
 		RequiredTags: []string{"ikid", "status"},
 	}
 
+	// CAOcspPresigned is counter metric for pre-signed ocsp
+	CAOcspPresigned = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "ca_ocsp_presigned",
+		Help:         "provides the counter of pre-signed OCSP responses",
+		RequiredTags: []string{"ikid", "status"},
+	}
+
+	// CAOcspFromStore is counter metric for ocsp served from the store
+	CAOcspFromStore = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "ca_ocsp_from_store",
+		Help:         "provides the counter of OCSP responses served from the store",
+		RequiredTags: []string{"ikid", "status"},
+	}
+
+	// CAOcspResponderIssued is counter metric for issued delegated OCSP responders
+	CAOcspResponderIssued = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "ca_ocsp_responder_issued",
+		Help:         "provides the counter of issued delegated OCSP responders",
+		RequiredTags: []string{"ikid"},
+	}
+
+	// CAFailOcspResponder is counter metric for issuers without delegated OCSP responder
+	CAFailOcspResponder = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "ca_fail_ocsp_responder",
+		Help:         "provides the counter of failures to get delegated OCSP responder",
+		RequiredTags: []string{"ikid"},
+	}
+
 	// CAFailSignCert is counter metric
 	CAFailSignCert = metrics.Describe{
 		Type:         metrics.TypeCounter,
@@ -164,6 +212,30 @@ var (
 		RequiredTags: []string{"ca"},
 	}
 
+	// CAPublishJobs is counter metric for processed publish jobs
+	CAPublishJobs = metrics.Describe{
+		Type:         metrics.TypeCounter,
+		Name:         "ca_publish_jobs",
+		Help:         "provides the counter of processed publish jobs",
+		RequiredTags: []string{"kind", "status"},
+	}
+
+	// CAPublishQueue is gauge metric for publish jobs
+	CAPublishQueue = metrics.Describe{
+		Type:         metrics.TypeGauge,
+		Name:         "ca_publish_queue",
+		Help:         "provides number of publish jobs by status",
+		RequiredTags: []string{"status"},
+	}
+
+	// CAPublishDrift is gauge metric for drift of the published objects
+	CAPublishDrift = metrics.Describe{
+		Type:         metrics.TypeGauge,
+		Name:         "ca_publish_drift",
+		Help:         "provides number of missing, stale or orphaned published objects",
+		RequiredTags: []string{"kind", "type"},
+	}
+
 	// CAExpiryCertDays is gauge metric
 	CAExpiryCertDays = metrics.Describe{
 		Type:         metrics.TypeGauge,
@@ -243,6 +315,20 @@ var (
 		Name: "aia_download_fail_ocsp",
 		Help: "provides the counter of failed OCSP downloads",
 	}
+
+	// AIAOcspCacheHit is counter metric
+	AIAOcspCacheHit = metrics.Describe{
+		Type: metrics.TypeCounter,
+		Name: "aia_ocsp_cache_hit",
+		Help: "provides the counter of OCSP responses served from the cache",
+	}
+
+	// AIANotModified is counter metric
+	AIANotModified = metrics.Describe{
+		Type: metrics.TypeCounter,
+		Name: "aia_not_modified",
+		Help: "provides the counter of conditional requests for unchanged CRL or OCSP",
+	}
 )
 
 // Metrics provides the list of emitted metrics by this repo
@@ -250,6 +336,7 @@ var Metrics = []*metrics.Describe{
 	&StatsDbTableRowsTotal,
 	&StatsKmsKeysTotal,
 	&StatsCAIssuersTotal,
+	&StatsDbRowsPurged,
 	&HealthKmsKeysStatusFailCount,
 	&HealthCAStatusFailCount,
 	&HealthOCSPStatusFailCount,
@@ -260,11 +347,19 @@ var Metrics = []*metrics.Describe{
 	&HealthLogErrors,
 	&CACertIssued,
 	&CACertRevoked,
+	&CACertImported,
 	&CACrlPublished,
 	&CAOcspSigned,
+	&CAOcspPresigned,
+	&CAOcspFromStore,
+	&CAOcspResponderIssued,
+	&CAFailOcspResponder,
 	&CAFailSignCert,
 	&CAFailPublishCert,
 	&CAFailPublishCrl,
+	&CAPublishJobs,
+	&CAPublishQueue,
+	&CAPublishDrift,
 	&CAExpiryCertDays,
 	&CAExpiryCrlDays,
 	&CertExpiryDays,
@@ -275,4 +370,6 @@ var Metrics = []*metrics.Describe{
 	&AIADownloadFailCert,
 	&AIADownloadFailCrl,
 	&AIADownloadFailOCSP,
+	&AIAOcspCacheHit,
+	&AIANotModified,
 }
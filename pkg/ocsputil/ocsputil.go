@@ -0,0 +1,148 @@
+// Package ocsputil provides OCSP request and response encoding,
+// as described in RFC 6960.
+//
+// Unlike golang.org/x/crypto/ocsp, the request may contain multiple CertIDs
+// and extensions, and the response may contain multiple single responses
+// and response extensions, such as the nonce described in RFC 8954.
+package ocsputil
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// OIDNonce is id-pkix-ocsp-nonce extension
+	OIDNonce = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+
+	oidOCSPBasic = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+)
+
+// MaxNonceSize specifies the maximum size of the nonce value,
+// as described in RFC 8954
+const MaxNonceSize = 32
+
+var hashOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+	crypto.SHA1:   {1, 3, 14, 3, 2, 26},
+	crypto.SHA256: {2, 16, 840, 1, 101, 3, 4, 2, 1},
+	crypto.SHA384: {2, 16, 840, 1, 101, 3, 4, 2, 2},
+	crypto.SHA512: {2, 16, 840, 1, 101, 3, 4, 2, 3},
+}
+
+func hashFromOID(oid asn1.ObjectIdentifier) crypto.Hash {
+	for h, o := range hashOIDs {
+		if o.Equal(oid) {
+			return h
+		}
+	}
+	return 0
+}
+
+// CertID identifies the certificate in OCSP request and response
+type CertID struct {
+	HashAlgorithm  crypto.Hash
+	IssuerNameHash []byte
+	IssuerKeyHash  []byte
+	SerialNumber   *big.Int
+
+	// raw is DER encoded CertID as received,
+	// the response echoes it as is
+	raw []byte
+}
+
+// Raw returns DER encoded CertID
+func (c *CertID) Raw() ([]byte, error) {
+	if len(c.raw) > 0 {
+		return c.raw, nil
+	}
+	oid := hashOIDs[c.HashAlgorithm]
+	if oid == nil {
+		return nil, errors.Errorf("unsupported hash algorithm: %v", c.HashAlgorithm)
+	}
+	if c.SerialNumber == nil {
+		return nil, errors.New("missing serial number")
+	}
+	return asn1.Marshal(certID{
+		HashAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oid,
+			Parameters: asn1.NullRawValue,
+		},
+		NameHash:      c.IssuerNameHash,
+		IssuerKeyHash: c.IssuerKeyHash,
+		SerialNumber:  c.SerialNumber,
+	})
+}
+
+// ASN.1 structures of OCSP request and response, see RFC 6960, section 4
+
+type certID struct {
+	Raw           asn1.RawContent
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type ocspRequest struct {
+	TBSRequest tbsRequest
+	// the signature of the request is not verified
+	Signature asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type tbsRequest struct {
+	Version       int           `asn1:"explicit,tag:0,default:0,optional"`
+	RequestorName asn1.RawValue `asn1:"explicit,tag:1,optional"`
+	RequestList   []singleRequest
+	Extensions    []pkix.Extension `asn1:"explicit,tag:2,optional"`
+}
+
+type singleRequest struct {
+	Cert       certID
+	Extensions []pkix.Extension `asn1:"explicit,tag:0,optional"`
+}
+
+type responseASN1 struct {
+	Status   asn1.Enumerated
+	Response responseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type responseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type basicResponse struct {
+	TBSResponseData    responseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,default:0,explicit,tag:0"`
+	RawResponderID     asn1.RawValue
+	ProducedAt         time.Time `asn1:"generalized"`
+	Responses          []singleResponse
+	ResponseExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type singleResponse struct {
+	CertID           asn1.RawValue
+	Good             asn1.Flag        `asn1:"tag:0,optional"`
+	Revoked          revokedInfo      `asn1:"tag:1,optional"`
+	Unknown          asn1.Flag        `asn1:"tag:2,optional"`
+	ThisUpdate       time.Time        `asn1:"generalized"`
+	NextUpdate       time.Time        `asn1:"generalized,explicit,tag:0,optional"`
+	SingleExtensions []pkix.Extension `asn1:"explicit,tag:1,optional"`
+}
+
+type revokedInfo struct {
+	RevocationTime time.Time       `asn1:"generalized"`
+	Reason         asn1.Enumerated `asn1:"explicit,tag:0,optional"`
+}
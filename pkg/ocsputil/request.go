@@ -0,0 +1,122 @@
+package ocsputil
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+
+	"github.com/pkg/errors"
+)
+
+// Request is OCSP request
+type Request struct {
+	// CertIDs of the requested certificates
+	CertIDs []*CertID
+	// Nonce is the nonce extension of the request, if present
+	Nonce *pkix.Extension
+}
+
+// NonceValue returns the nonce value, or nil if the request has no nonce.
+// The value is expected to be OCTET STRING, as described in RFC 8954,
+// but some clients send the raw value, which is returned as is.
+func (r *Request) NonceValue() []byte {
+	if r.Nonce == nil {
+		return nil
+	}
+	var v []byte
+	if rest, err := asn1.Unmarshal(r.Nonce.Value, &v); err == nil && len(rest) == 0 {
+		return v
+	}
+	return r.Nonce.Value
+}
+
+// ParseRequest parses DER encoded OCSP request.
+// The request with unsupported critical extension,
+// or the nonce larger than MaxNonceSize, is rejected.
+func ParseRequest(der []byte) (*Request, error) {
+	var req ocspRequest
+	rest, err := asn1.Unmarshal(der, &req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid OCSP request")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data in OCSP request")
+	}
+	if len(req.TBSRequest.RequestList) == 0 {
+		return nil, errors.New("OCSP request contains no certificates")
+	}
+
+	res := &Request{
+		CertIDs: make([]*CertID, 0, len(req.TBSRequest.RequestList)),
+	}
+
+	for i := range req.TBSRequest.Extensions {
+		ext := &req.TBSRequest.Extensions[i]
+		switch {
+		case ext.Id.Equal(OIDNonce):
+			res.Nonce = ext
+		case ext.Critical:
+			return nil, errors.Errorf("unsupported critical extension: %s", ext.Id)
+		}
+	}
+	if res.Nonce != nil {
+		if n := len(res.NonceValue()); n == 0 || n > MaxNonceSize {
+			return nil, errors.Errorf("invalid nonce size: %d", n)
+		}
+	}
+
+	for _, r := range req.TBSRequest.RequestList {
+		for _, ext := range r.Extensions {
+			if ext.Critical {
+				return nil, errors.Errorf("unsupported critical extension: %s", ext.Id)
+			}
+		}
+
+		hash := hashFromOID(r.Cert.HashAlgorithm.Algorithm)
+		if hash == 0 {
+			return nil, errors.Errorf("unsupported hash algorithm: %s", r.Cert.HashAlgorithm.Algorithm)
+		}
+		if r.Cert.SerialNumber == nil {
+			return nil, errors.New("missing serial number")
+		}
+
+		res.CertIDs = append(res.CertIDs, &CertID{
+			HashAlgorithm:  hash,
+			IssuerNameHash: r.Cert.NameHash,
+			IssuerKeyHash:  r.Cert.IssuerKeyHash,
+			SerialNumber:   r.Cert.SerialNumber,
+			raw:            r.Cert.Raw,
+		})
+	}
+
+	return res, nil
+}
+
+// CreateRequest returns DER encoded OCSP request for the CertIDs,
+// with the nonce extension if the nonce is provided
+func CreateRequest(ids []*CertID, nonce []byte) ([]byte, error) {
+	req := ocspRequest{}
+	for _, id := range ids {
+		raw, err := id.Raw()
+		if err != nil {
+			return nil, err
+		}
+		var c certID
+		if _, err = asn1.Unmarshal(raw, &c); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		req.TBSRequest.RequestList = append(req.TBSRequest.RequestList, singleRequest{Cert: c})
+	}
+	if len(nonce) > 0 {
+		value, err := asn1.Marshal(nonce)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		req.TBSRequest.Extensions = []pkix.Extension{{Id: OIDNonce, Value: value}}
+	}
+
+	der, err := asn1.Marshal(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return der, nil
+}
@@ -0,0 +1,135 @@
+package ocsputil
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestParseRequest(t *testing.T) {
+	issuer, _ := newCert(t, nil, nil)
+
+	t.Run("x509", func(t *testing.T) {
+		for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+			der, err := ocsp.CreateRequest(
+				&x509.Certificate{SerialNumber: big.NewInt(1234)},
+				issuer,
+				&ocsp.RequestOptions{Hash: hash})
+			require.NoError(t, err)
+
+			req, err := ParseRequest(der)
+			require.NoError(t, err)
+			require.Len(t, req.CertIDs, 1)
+			assert.Nil(t, req.Nonce)
+			assert.Nil(t, req.NonceValue())
+
+			exp, err := ocsp.ParseRequest(der)
+			require.NoError(t, err)
+			id := req.CertIDs[0]
+			assert.Equal(t, exp.HashAlgorithm, id.HashAlgorithm)
+			assert.Equal(t, exp.IssuerNameHash, id.IssuerNameHash)
+			assert.Equal(t, exp.IssuerKeyHash, id.IssuerKeyHash)
+			assert.Equal(t, exp.SerialNumber, id.SerialNumber)
+		}
+	})
+
+	t.Run("multiple_with_nonce", func(t *testing.T) {
+		ids := []*CertID{
+			{HashAlgorithm: crypto.SHA1, IssuerNameHash: []byte{1}, IssuerKeyHash: []byte{2}, SerialNumber: big.NewInt(1)},
+			{HashAlgorithm: crypto.SHA256, IssuerNameHash: []byte{3}, IssuerKeyHash: []byte{4}, SerialNumber: big.NewInt(2)},
+		}
+		nonce := []byte("0123456789abcdef")
+		der, err := CreateRequest(ids, nonce)
+		require.NoError(t, err)
+
+		req, err := ParseRequest(der)
+		require.NoError(t, err)
+		require.Len(t, req.CertIDs, 2)
+		for i, id := range req.CertIDs {
+			assert.Equal(t, ids[i].HashAlgorithm, id.HashAlgorithm)
+			assert.Equal(t, ids[i].IssuerNameHash, id.IssuerNameHash)
+			assert.Equal(t, ids[i].IssuerKeyHash, id.IssuerKeyHash)
+			assert.Equal(t, ids[i].SerialNumber, id.SerialNumber)
+
+			exp, err := ids[i].Raw()
+			require.NoError(t, err)
+			raw, err := id.Raw()
+			require.NoError(t, err)
+			assert.Equal(t, exp, raw)
+		}
+		require.NotNil(t, req.Nonce)
+		assert.Equal(t, nonce, req.NonceValue())
+
+		// x/crypto parses the first CertID only
+		x, err := ocsp.ParseRequest(der)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1), x.SerialNumber)
+	})
+
+	t.Run("raw_nonce", func(t *testing.T) {
+		req := &Request{Nonce: &pkix.Extension{Id: OIDNonce, Value: []byte{1, 2, 3}}}
+		assert.Equal(t, []byte{1, 2, 3}, req.NonceValue())
+	})
+
+	mustMarshal := func(req ocspRequest) []byte {
+		der, err := asn1.Marshal(req)
+		require.NoError(t, err)
+		return der
+	}
+	id, err := (&CertID{HashAlgorithm: crypto.SHA1, IssuerKeyHash: []byte{1}, SerialNumber: big.NewInt(1)}).Raw()
+	require.NoError(t, err)
+	var cid certID
+	_, err = asn1.Unmarshal(id, &cid)
+	require.NoError(t, err)
+
+	longNonce, err := asn1.Marshal(make([]byte, MaxNonceSize+1))
+	require.NoError(t, err)
+	badHash := cid
+	badHash.Raw = nil
+	badHash.HashAlgorithm.Algorithm = asn1.ObjectIdentifier{1, 2, 3}
+
+	tcases := []struct {
+		name string
+		der  []byte
+		err  string
+	}{
+		{"invalid", []byte{1, 2, 3}, "invalid OCSP request"},
+		{"trailing", append(mustMarshal(ocspRequest{TBSRequest: tbsRequest{RequestList: []singleRequest{{Cert: cid}}}}), 0), "trailing data in OCSP request"},
+		{"empty", mustMarshal(ocspRequest{TBSRequest: tbsRequest{
+			Extensions: []pkix.Extension{{Id: OIDNonce, Value: []byte{4, 1, 1}}},
+		}}), "OCSP request contains no certificates"},
+		{"critical", mustMarshal(ocspRequest{TBSRequest: tbsRequest{
+			RequestList: []singleRequest{{Cert: cid}},
+			Extensions:  []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 2, 3}, Critical: true, Value: []byte{5, 0}}},
+		}}), "unsupported critical extension: 1.2.3"},
+		{"critical_single", mustMarshal(ocspRequest{TBSRequest: tbsRequest{
+			RequestList: []singleRequest{{Cert: cid, Extensions: []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 2, 4}, Critical: true, Value: []byte{5, 0}}}}},
+		}}), "unsupported critical extension: 1.2.4"},
+		{"long_nonce", mustMarshal(ocspRequest{TBSRequest: tbsRequest{
+			RequestList: []singleRequest{{Cert: cid}},
+			Extensions:  []pkix.Extension{{Id: OIDNonce, Value: longNonce}},
+		}}), "invalid nonce size: 33"},
+		{"hash", mustMarshal(ocspRequest{TBSRequest: tbsRequest{
+			RequestList: []singleRequest{{Cert: badHash}},
+		}}), "unsupported hash algorithm: 1.2.3"},
+	}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRequest(tc.der)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	_, err = CreateRequest([]*CertID{{HashAlgorithm: crypto.MD5, SerialNumber: big.NewInt(1)}}, nil)
+	assert.EqualError(t, err, "unsupported hash algorithm: MD5")
+	_, err = CreateRequest([]*CertID{{HashAlgorithm: crypto.SHA1}}, nil)
+	assert.EqualError(t, err, "missing serial number")
+}
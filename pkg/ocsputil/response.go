@@ -0,0 +1,296 @@
+package ocsputil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+// SingleResponse is the status of the certificate in OCSP response
+type SingleResponse struct {
+	CertID *CertID
+	// Status is one of ocsp.Good, ocsp.Revoked or ocsp.Unknown
+	Status           int
+	RevokedAt        time.Time
+	RevocationReason int
+	ThisUpdate       time.Time
+	NextUpdate       time.Time
+}
+
+// Response is OCSP response
+type Response struct {
+	Responses  []*SingleResponse
+	ProducedAt time.Time
+	// Extensions are the response extensions
+	Extensions []pkix.Extension
+	// Certificate of the delegated responder, if included in the response
+	Certificate *x509.Certificate
+
+	tbsResponseData    []byte
+	signature          []byte
+	signatureAlgorithm x509.SignatureAlgorithm
+}
+
+// Nonce returns the nonce extension of the response, if present
+func (r *Response) Nonce() *pkix.Extension {
+	for i := range r.Extensions {
+		if r.Extensions[i].Id.Equal(OIDNonce) {
+			return &r.Extensions[i]
+		}
+	}
+	return nil
+}
+
+// CheckSignatureFrom checks that the response is signed by the responder
+func (r *Response) CheckSignatureFrom(responder *x509.Certificate) error {
+	return responder.CheckSignature(r.signatureAlgorithm, r.tbsResponseData, r.signature)
+}
+
+// CreateResponse returns DER encoded OCSP response with the single responses
+// and extensions of the template, signed by the responder.
+// If the responder is not the issuer, then the responder certificate
+// is included in the response.
+func CreateResponse(template *Response, issuer, responder *x509.Certificate, signer crypto.Signer) ([]byte, error) {
+	if len(template.Responses) == 0 {
+		return nil, errors.New("no responses")
+	}
+
+	producedAt := template.ProducedAt
+	if producedAt.IsZero() {
+		producedAt = time.Now().Truncate(time.Minute)
+	}
+
+	tbs := responseData{
+		RawResponderID: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        1, // byName
+			IsCompound: true,
+			Bytes:      responder.RawSubject,
+		},
+		ProducedAt:         producedAt.UTC(),
+		ResponseExtensions: template.Extensions,
+	}
+
+	for _, r := range template.Responses {
+		raw, err := r.CertID.Raw()
+		if err != nil {
+			return nil, err
+		}
+		single := singleResponse{
+			CertID:     asn1.RawValue{FullBytes: raw},
+			ThisUpdate: r.ThisUpdate.UTC(),
+			NextUpdate: r.NextUpdate.UTC(),
+		}
+		switch r.Status {
+		case ocsp.Good:
+			single.Good = true
+		case ocsp.Unknown:
+			single.Unknown = true
+		case ocsp.Revoked:
+			if r.RevokedAt.IsZero() {
+				return nil, errors.New("missing revocation time")
+			}
+			single.Revoked = revokedInfo{
+				RevocationTime: r.RevokedAt.UTC(),
+				Reason:         asn1.Enumerated(r.RevocationReason),
+			}
+		default:
+			return nil, errors.Errorf("invalid status: %d", r.Status)
+		}
+		tbs.Responses = append(tbs.Responses, single)
+	}
+
+	tbsDER, err := asn1.Marshal(tbs)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	algorithm, hash, err := signingParams(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	digest := tbsDER
+	if hash != 0 {
+		h := hash.New()
+		_, _ = h.Write(tbsDER)
+		digest = h.Sum(nil)
+	}
+	signature, err := signer.Sign(rand.Reader, digest, hash)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to sign OCSP response")
+	}
+
+	basic := basicResponse{
+		TBSResponseData:    responseData{Raw: tbsDER},
+		SignatureAlgorithm: algorithm,
+		Signature: asn1.BitString{
+			Bytes:     signature,
+			BitLength: 8 * len(signature),
+		},
+	}
+	if !bytes.Equal(issuer.RawSubject, responder.RawSubject) {
+		basic.Certificates = []asn1.RawValue{{FullBytes: responder.Raw}}
+	}
+
+	basicDER, err := asn1.Marshal(basic)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	der, err := asn1.Marshal(responseASN1{
+		Status: asn1.Enumerated(ocsp.Success),
+		Response: responseBytes{
+			ResponseType: oidOCSPBasic,
+			Response:     basicDER,
+		},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return der, nil
+}
+
+// ParseResponse parses DER encoded OCSP response.
+// The signature is not verified, use CheckSignatureFrom.
+func ParseResponse(der []byte) (*Response, error) {
+	var resp responseASN1
+	rest, err := asn1.Unmarshal(der, &resp)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid OCSP response")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data in OCSP response")
+	}
+	if status := ocsp.ResponseStatus(resp.Status); status != ocsp.Success {
+		return nil, ocsp.ResponseError{Status: status}
+	}
+	if !resp.Response.ResponseType.Equal(oidOCSPBasic) {
+		return nil, errors.New("unsupported OCSP response type")
+	}
+
+	var basic basicResponse
+	rest, err = asn1.Unmarshal(resp.Response.Response, &basic)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid OCSP basic response")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data in OCSP basic response")
+	}
+
+	res := &Response{
+		ProducedAt:         basic.TBSResponseData.ProducedAt,
+		Extensions:         basic.TBSResponseData.ResponseExtensions,
+		tbsResponseData:    basic.TBSResponseData.Raw,
+		signature:          basic.Signature.RightAlign(),
+		signatureAlgorithm: signatureAlgorithmFromOID(basic.SignatureAlgorithm.Algorithm),
+	}
+	if len(basic.Certificates) > 0 {
+		res.Certificate, err = x509.ParseCertificate(basic.Certificates[0].FullBytes)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid responder certificate")
+		}
+	}
+
+	for _, single := range basic.TBSResponseData.Responses {
+		var id certID
+		if _, err = asn1.Unmarshal(single.CertID.FullBytes, &id); err != nil {
+			return nil, errors.WithMessage(err, "invalid CertID")
+		}
+		r := &SingleResponse{
+			CertID: &CertID{
+				HashAlgorithm:  hashFromOID(id.HashAlgorithm.Algorithm),
+				IssuerNameHash: id.NameHash,
+				IssuerKeyHash:  id.IssuerKeyHash,
+				SerialNumber:   id.SerialNumber,
+				raw:            id.Raw,
+			},
+			ThisUpdate: single.ThisUpdate,
+			NextUpdate: single.NextUpdate,
+		}
+		switch {
+		case bool(single.Good):
+			r.Status = ocsp.Good
+		case bool(single.Unknown):
+			r.Status = ocsp.Unknown
+		default:
+			r.Status = ocsp.Revoked
+			r.RevokedAt = single.Revoked.RevocationTime
+			r.RevocationReason = int(single.Revoked.Reason)
+		}
+		res.Responses = append(res.Responses, r)
+	}
+	if len(res.Responses) == 0 {
+		return nil, errors.New("OCSP response contains no responses")
+	}
+
+	return res, nil
+}
+
+var signatureAlgorithms = []struct {
+	algorithm x509.SignatureAlgorithm
+	oid       asn1.ObjectIdentifier
+	hash      crypto.Hash
+}{
+	{x509.SHA256WithRSA, asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}, crypto.SHA256},
+	{x509.SHA384WithRSA, asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}, crypto.SHA384},
+	{x509.SHA512WithRSA, asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}, crypto.SHA512},
+	{x509.ECDSAWithSHA256, asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}, crypto.SHA256},
+	{x509.ECDSAWithSHA384, asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}, crypto.SHA384},
+	{x509.ECDSAWithSHA512, asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}, crypto.SHA512},
+	{x509.PureEd25519, asn1.ObjectIdentifier{1, 3, 101, 112}, 0},
+}
+
+func signatureAlgorithmFromOID(oid asn1.ObjectIdentifier) x509.SignatureAlgorithm {
+	for _, a := range signatureAlgorithms {
+		if a.oid.Equal(oid) {
+			return a.algorithm
+		}
+	}
+	return x509.UnknownSignatureAlgorithm
+}
+
+// signingParams returns the signature algorithm and hash for the key,
+// the same as used by golang.org/x/crypto/ocsp by default
+func signingParams(pub crypto.PublicKey) (pkix.AlgorithmIdentifier, crypto.Hash, error) {
+	var algorithm x509.SignatureAlgorithm
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P384():
+			algorithm = x509.ECDSAWithSHA384
+		case elliptic.P521():
+			algorithm = x509.ECDSAWithSHA512
+		default:
+			algorithm = x509.ECDSAWithSHA256
+		}
+	case ed25519.PublicKey:
+		algorithm = x509.PureEd25519
+	default:
+		return pkix.AlgorithmIdentifier{}, 0, errors.Errorf("unsupported key type: %T", pub)
+	}
+
+	for _, a := range signatureAlgorithms {
+		if a.algorithm == algorithm {
+			ai := pkix.AlgorithmIdentifier{Algorithm: a.oid}
+			if _, ok := pub.(*rsa.PublicKey); ok {
+				ai.Parameters = asn1.NullRawValue
+			}
+			return ai, a.hash, nil
+		}
+	}
+	return pkix.AlgorithmIdentifier{}, 0, errors.Errorf("unsupported signature algorithm: %s", algorithm)
+}
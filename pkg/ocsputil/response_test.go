@@ -0,0 +1,203 @@
+package ocsputil
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// newCert returns a CA certificate, signed by the parent,
+// or self-signed if the parent is not provided
+func newCert(t *testing.T, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "test " + serial.String()},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	crt, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return crt, key
+}
+
+func certIDs(t *testing.T, issuer *x509.Certificate, hash crypto.Hash, serials ...int64) []*CertID {
+	var ids []*CertID
+	for _, serial := range serials {
+		der, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: big.NewInt(serial)}, issuer, &ocsp.RequestOptions{Hash: hash})
+		require.NoError(t, err)
+		req, err := ParseRequest(der)
+		require.NoError(t, err)
+		ids = append(ids, req.CertIDs...)
+	}
+	return ids
+}
+
+func TestCreateResponse(t *testing.T) {
+	issuer, issuerKey := newCert(t, nil, nil)
+	responder, responderKey := newCert(t, issuer, issuerKey)
+
+	thisUpdate := time.Now().UTC().Truncate(time.Minute)
+	nextUpdate := thisUpdate.Add(8 * time.Hour)
+	revokedAt := thisUpdate.Add(-time.Hour)
+
+	t.Run("single", func(t *testing.T) {
+		for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256} {
+			ids := certIDs(t, issuer, hash, 100)
+			der, err := CreateResponse(&Response{
+				Responses: []*SingleResponse{{
+					CertID:           ids[0],
+					Status:           ocsp.Revoked,
+					RevokedAt:        revokedAt,
+					RevocationReason: ocsp.KeyCompromise,
+					ThisUpdate:       thisUpdate,
+					NextUpdate:       nextUpdate,
+				}},
+			}, issuer, issuer, issuerKey)
+			require.NoError(t, err)
+
+			// compatible with x/crypto
+			res, err := ocsp.ParseResponse(der, issuer)
+			require.NoError(t, err)
+			assert.Equal(t, ocsp.Revoked, res.Status)
+			assert.Equal(t, ocsp.KeyCompromise, res.RevocationReason)
+			assert.Equal(t, revokedAt, res.RevokedAt)
+			assert.Equal(t, thisUpdate, res.ThisUpdate)
+			assert.Equal(t, nextUpdate, res.NextUpdate)
+			assert.Equal(t, hash, res.IssuerHash)
+			assert.Equal(t, int64(100), res.SerialNumber.Int64())
+			assert.Nil(t, res.Certificate)
+		}
+	})
+
+	t.Run("multiple_with_nonce", func(t *testing.T) {
+		ids := certIDs(t, issuer, crypto.SHA256, 1, 2, 3)
+		nonce := pkix.Extension{Id: OIDNonce, Value: []byte{4, 2, 1, 2}}
+		template := &Response{
+			Extensions: []pkix.Extension{nonce},
+		}
+		for i, status := range []int{ocsp.Good, ocsp.Revoked, ocsp.Unknown} {
+			template.Responses = append(template.Responses, &SingleResponse{
+				CertID:     ids[i],
+				Status:     status,
+				RevokedAt:  revokedAt,
+				ThisUpdate: thisUpdate,
+				NextUpdate: nextUpdate,
+			})
+		}
+		der, err := CreateResponse(template, issuer, responder, responderKey)
+		require.NoError(t, err)
+
+		res, err := ParseResponse(der)
+		require.NoError(t, err)
+		require.Len(t, res.Responses, 3)
+		require.NotNil(t, res.Nonce())
+		assert.Equal(t, nonce, *res.Nonce())
+		require.NotNil(t, res.Certificate)
+		assert.Equal(t, responder.Raw, res.Certificate.Raw)
+		assert.NoError(t, res.CheckSignatureFrom(responder))
+		assert.Error(t, res.CheckSignatureFrom(issuer))
+
+		for i, single := range res.Responses {
+			exp := template.Responses[i]
+			assert.Equal(t, exp.Status, single.Status)
+			assert.Equal(t, exp.CertID.SerialNumber, single.CertID.SerialNumber)
+			assert.Equal(t, crypto.SHA256, single.CertID.HashAlgorithm)
+			raw, _ := single.CertID.Raw()
+			expRaw, _ := exp.CertID.Raw()
+			assert.Equal(t, expRaw, raw)
+			assert.Equal(t, thisUpdate, single.ThisUpdate)
+			assert.Equal(t, nextUpdate, single.NextUpdate)
+		}
+		assert.Equal(t, revokedAt, res.Responses[1].RevokedAt)
+		assert.Equal(t, ocsp.Unspecified, res.Responses[1].RevocationReason)
+
+		// x/crypto does not support multiple responses without the certificate
+		_, err = ocsp.ParseResponse(der, issuer)
+		assert.Error(t, err)
+		x, err := ocsp.ParseResponseForCert(der, &x509.Certificate{SerialNumber: big.NewInt(2)}, issuer)
+		require.NoError(t, err)
+		assert.Equal(t, ocsp.Revoked, x.Status)
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		require.NoError(t, err)
+		_, ed, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		for _, key := range []crypto.Signer{rsaKey, p384, p521, ed} {
+			tmpl := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "responder"},
+				NotBefore:    time.Now().Add(-time.Hour),
+				NotAfter:     time.Now().Add(time.Hour),
+			}
+			crtDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+			require.NoError(t, err)
+			crt, err := x509.ParseCertificate(crtDER)
+			require.NoError(t, err)
+
+			der, err := CreateResponse(&Response{
+				Responses: []*SingleResponse{{
+					CertID:     certIDs(t, crt, crypto.SHA1, 1)[0],
+					Status:     ocsp.Good,
+					ThisUpdate: thisUpdate,
+					NextUpdate: nextUpdate,
+				}},
+			}, crt, crt, key)
+			require.NoError(t, err)
+
+			res, err := ParseResponse(der)
+			require.NoError(t, err)
+			assert.NoError(t, res.CheckSignatureFrom(crt), "%T", key)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := CreateResponse(&Response{}, issuer, issuer, issuerKey)
+		assert.EqualError(t, err, "no responses")
+
+		id := certIDs(t, issuer, crypto.SHA1, 1)[0]
+		_, err = CreateResponse(&Response{Responses: []*SingleResponse{{CertID: id, Status: 5}}}, issuer, issuer, issuerKey)
+		assert.EqualError(t, err, "invalid status: 5")
+
+		_, err = CreateResponse(&Response{Responses: []*SingleResponse{{CertID: id, Status: ocsp.Revoked}}}, issuer, issuer, issuerKey)
+		assert.EqualError(t, err, "missing revocation time")
+
+		_, err = ParseResponse([]byte{1, 2, 3})
+		assert.Error(t, err)
+
+		_, err = ParseResponse([]byte{0x30, 0x03, 0x0A, 0x01, 0x06})
+		assert.EqualError(t, err, "ocsp: error from server: unauthorized")
+	})
+}
@@ -74,6 +74,10 @@ func Print(w io.Writer, value any) {
 		CrlsTable(w, t.Crls)
 	case []*pb.Crl:
 		CrlsTable(w, t)
+	case *pb.PublishStatusResponse:
+		PublishStatus(w, t)
+	case []*pb.PublishJob:
+		PublishJobsTable(w, t)
 	case *pb.Certificate:
 		Certificate(w, t, true)
 	case *pb.CertificateResponse:
@@ -200,10 +200,33 @@ func TestPrint(t *testing.T) {
 				NextUpdate: "2012-12-01T22:08:41Z",
 			},
 		}
-		exp := "  ID  |  IKID  |     THIS UPDATE      |     NEXT UPDATE      | ISSUER  \n" +
-			"------+--------+----------------------+----------------------+---------\n" +
-			"  123 | 123456 | 2012-11-01T22:08:41Z | 2012-12-01T22:08:41Z | CN=ca   \n\n"
+		exp := "  ID  |  IKID  | NUMBER |     THIS UPDATE      |     NEXT UPDATE      | ISSUER  \n" +
+			"------+--------+--------+----------------------+----------------------+---------\n" +
+			"  123 | 123456 | 0      | 2012-11-01T22:08:41Z | 2012-12-01T22:08:41Z | CN=ca   \n\n"
 		checkFormat(list, exp)
 		checkFormat(&pb.CrlsResponse{Crls: list}, exp)
 	})
+
+	t.Run("PublishStatus", func(t *testing.T) {
+		list := []*pb.PublishJob{
+			{
+				ID:            123,
+				Kind:          "cert",
+				IKID:          "123456",
+				RefID:         1000,
+				Status:        "pending",
+				Attempts:      2,
+				NextAttemptAt: "2012-11-01T22:08:41Z",
+				LastError:     "failed",
+			},
+		}
+		exp := "  ID  | KIND |  IKID  | REF  | STATUS  | ATTEMPTS |     NEXT ATTEMPT     | LOCATION | ERROR   \n" +
+			"------+------+--------+------+---------+----------+----------------------+----------+---------\n" +
+			"  123 | cert | 123456 | 1000 | pending | 2        | 2012-11-01T22:08:41Z |          | failed  \n\n"
+		checkFormat(list, exp)
+		checkFormat(&pb.PublishStatusResponse{
+			Counts: map[string]int64{"published": 10, "pending": 1},
+			Jobs:   list,
+		}, "pending: 1\npublished: 10\n\n"+exp)
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -251,12 +252,19 @@ func CrlsTable(w io.Writer, list []*pb.Crl) {
 	table := tablewriter.NewWriter(w)
 	table.SetBorder(false)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetHeader([]string{"Id", "IKID", "This Update", "Next Update", "Issuer"})
+	table.SetHeader([]string{"Id", "IKID", "Number", "This Update", "Next Update", "Issuer"})
 
 	for _, c := range list {
+		number := strconv.FormatUint(c.CrlNumber, 10)
+		if c.BaseNumber > 0 {
+			number += " (delta of " + strconv.FormatUint(c.BaseNumber, 10) + ")"
+		} else if c.Shard > 0 {
+			number += " (shard " + strconv.FormatUint(uint64(c.Shard), 10) + ")"
+		}
 		table.Append([]string{
 			strconv.FormatUint(c.ID, 10),
 			c.IKID,
+			number,
 			c.ThisUpdate,
 			c.NextUpdate,
 			c.Issuer,
@@ -266,6 +274,47 @@ func CrlsTable(w io.Writer, list []*pb.Crl) {
 	fmt.Fprintln(w)
 }
 
+// PublishStatus prints the publish outbox status
+func PublishStatus(w io.Writer, r *pb.PublishStatusResponse) {
+	statuses := make([]string, 0, len(r.Counts))
+	for status := range r.Counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(w, "%s: %d\n", status, r.Counts[status])
+	}
+	fmt.Fprintln(w)
+
+	if len(r.Jobs) > 0 {
+		PublishJobsTable(w, r.Jobs)
+	}
+}
+
+// PublishJobsTable prints list of publish jobs
+func PublishJobsTable(w io.Writer, list []*pb.PublishJob) {
+	table := tablewriter.NewWriter(w)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Id", "Kind", "IKID", "Ref", "Status", "Attempts", "Next Attempt", "Location", "Error"})
+
+	for _, j := range list {
+		table.Append([]string{
+			strconv.FormatUint(j.ID, 10),
+			j.Kind,
+			j.IKID,
+			strconv.FormatUint(j.RefID, 10),
+			j.Status,
+			strconv.Itoa(int(j.Attempts)),
+			j.NextAttemptAt,
+			j.Location,
+			j.LastError,
+		})
+	}
+	table.Render()
+	fmt.Fprintln(w)
+}
+
 // RevokedCertificate prints RevokedCertificate
 func RevokedCertificate(w io.Writer, ci *pb.RevokedCertificate, withPem bool) {
 	fmt.Fprintf(w, "Revoked: %s\n", ci.RevokedAt)
@@ -284,6 +333,9 @@ func Certificate(w io.Writer, ci *pb.Certificate, withPem bool) {
 	fmt.Fprintf(w, "  Issued: %s\n", ci.NotAfter)
 	fmt.Fprintf(w, "  Expires: %s\n", ci.NotBefore)
 	fmt.Fprintf(w, "  Profile: %s\n", ci.Profile)
+	if ci.External {
+		fmt.Fprintf(w, "  External: true\n")
+	}
 	if len(ci.Locations) > 0 {
 		fmt.Fprintf(w, "  Locations:\n")
 		for _, v := range ci.Locations {
@@ -166,15 +166,36 @@ func TestCrlTable(t *testing.T) {
 			Issuer:     "CN=ca",
 			ThisUpdate: "2012-11-01T22:08:41+00:00",
 			NextUpdate: "2012-12-01T22:08:41+00:00",
+			CrlNumber:  2,
+		},
+		{
+			ID:         124,
+			IKID:       "123456",
+			Issuer:     "CN=ca",
+			ThisUpdate: "2012-11-01T22:08:41+00:00",
+			NextUpdate: "2012-11-02T22:08:41+00:00",
+			CrlNumber:  3,
+			BaseNumber: 2,
+		},
+		{
+			ID:         125,
+			IKID:       "123456",
+			Issuer:     "CN=ca",
+			ThisUpdate: "2012-11-01T22:08:41+00:00",
+			NextUpdate: "2012-12-01T22:08:41+00:00",
+			CrlNumber:  4,
+			Shard:      1,
 		},
 	}
 	w := bytes.NewBuffer([]byte{})
 	print.CrlsTable(w, list)
 	out := w.String()
 	assert.Equal(t, out,
-		"  ID  |  IKID  |        THIS UPDATE        |        NEXT UPDATE        | ISSUER  \n"+
-			"------+--------+---------------------------+---------------------------+---------\n"+
-			"  123 | 123456 | 2012-11-01T22:08:41+00:00 | 2012-12-01T22:08:41+00:00 | CN=ca   \n\n")
+		"  ID  |  IKID  |     NUMBER     |        THIS UPDATE        |        NEXT UPDATE        | ISSUER  \n"+
+			"------+--------+----------------+---------------------------+---------------------------+---------\n"+
+			"  123 | 123456 | 2              | 2012-11-01T22:08:41+00:00 | 2012-12-01T22:08:41+00:00 | CN=ca   \n"+
+			"  124 | 123456 | 3 (delta of 2) | 2012-11-01T22:08:41+00:00 | 2012-11-02T22:08:41+00:00 | CN=ca   \n"+
+			"  125 | 123456 | 4 (shard 1)    | 2012-11-01T22:08:41+00:00 | 2012-12-01T22:08:41+00:00 | CN=ca   \n\n")
 }
 
 func Test_Issuers(t *testing.T) {
@@ -3,16 +3,34 @@ package storage
 import (
 	"context"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
 )
 
+// VersionsDir is the name of the folder for previous versions of objects,
+// created next to the object when versioning is enabled
+const VersionsDir = ".versions"
+
+// tmpPrefix is the prefix of temporary files for atomic writes
+const tmpPrefix = ".tmp-"
+
+// FilesystemOptions are options for the local filesystem.
+// For cloud storage, the versioning is configured on the bucket.
+type FilesystemOptions struct {
+	// Versioning specifies to keep the previous version of an object,
+	// when it is overwritten
+	Versioning bool `json:"versioning,omitempty" yaml:"versioning,omitempty"`
+}
+
 // FilesystemConnection is an adapter for local filesystem access, basically for
 // testing purposes.
 type FilesystemConnection struct {
+	FilesystemOptions
 }
 
 func dirExists(path string) bool {
@@ -76,18 +94,170 @@ func (conn *FilesystemConnection) GetReader(ctx context.Context, path string) (r
 
 }
 
-// GetWriter opens the file at path for writing and returns the handle, creating
-// any intermediate directories.
+// GetWriter opens a temporary file next to path for writing and returns the handle,
+// creating any intermediate directories.
+// The temporary file is renamed to path on Close,
+// so readers never observe a partially written file.
 func (conn *FilesystemConnection) GetWriter(ctx context.Context, path string) (result io.WriteCloser, err error) {
 	file, err := conn.getPath(path, true)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	fp, err := os.Create(file)
+	fp, err := os.CreateTemp(filepath.Dir(file), tmpPrefix+filepath.Base(file)+"-*")
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	return fp, nil
+	return &atomicWriter{
+		File:       fp,
+		target:     file,
+		versioning: conn.Versioning,
+	}, nil
+}
+
+// ListObjects returns files with the path prefix, sorted by path.
+// If the prefix is an existing folder, then all files in the folder are listed.
+// Temporary files and previous versions are not listed.
+func (conn *FilesystemConnection) ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	abs, err := filepath.Abs(prefix)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	root := abs
+	if !strings.HasSuffix(prefix, "/") && !dirExists(abs) {
+		root = filepath.Dir(abs)
+	}
+	if strings.HasSuffix(prefix, "/") {
+		abs += "/"
+	}
+
+	var list []*ObjectInfo
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == VersionsDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), tmpPrefix) || !strings.HasPrefix(path, abs) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		list = append(list, fileInfo(path, info))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+	return list, nil
+}
+
+// Stat returns the file info
+func (conn *FilesystemConnection) Stat(ctx context.Context, path string) (*ObjectInfo, error) {
+	file, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if info.IsDir() {
+		return nil, errors.Errorf("not a file: %s", path)
+	}
+	return fileInfo(file, info), nil
+}
+
+func fileInfo(path string, info fs.FileInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UTC(),
+	}
+}
+
+// atomicWriter writes to a temporary file,
+// and renames it to the target file on Close
+type atomicWriter struct {
+	*os.File
+	target     string
+	versioning bool
+	closed     bool
+}
+
+func (w *atomicWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	tmp := w.File.Name()
+	// the temporary file is created with 0600 mode,
+	// the published file must be readable by the web server
+	err := w.File.Chmod(targetMode(w.target))
+	if err == nil {
+		err = w.File.Sync()
+	}
+	if cerr := w.File.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && w.versioning {
+		err = keepVersion(w.target)
+	}
+	if err == nil {
+		err = os.Rename(tmp, w.target)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// targetMode returns the mode of the existing file,
+// or 0644 for a new file
+func targetMode(file string) fs.FileMode {
+	if info, err := os.Stat(file); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
+
+// keepVersion moves the existing file to the versions folder,
+// with the modification time as suffix
+func keepVersion(file string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	dir := filepath.Join(filepath.Dir(file), VersionsDir)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	version := filepath.Join(dir,
+		filepath.Base(file)+"."+info.ModTime().UTC().Format("20060102T150405.000000000Z"))
+	// hard link keeps the current file readable until it is replaced
+	err = os.Link(file, version)
+	if err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+	return nil
 }
 
 // SetMetadata updates object with metadata, if supported
@@ -4,12 +4,14 @@ import (
 	"context"
 	"io"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/effective-security/xlog"
 	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -178,6 +180,71 @@ func (conn *GoogleCloudStorageConnection) Delete(ctx context.Context, path strin
 	return nil
 }
 
+// ListObjects returns objects with the path prefix, sorted by path
+func (conn *GoogleCloudStorageConnection) ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	uri, err := parseURI(prefix)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	it, err := conn.List(ctx, prefix, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*ObjectInfo
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		list = append(list, gcsObjectInfo(uri.Hostname(), attrs))
+	}
+	// GCS returns objects in lexicographical order
+	return list, nil
+}
+
+// Stat returns the object info
+func (conn *GoogleCloudStorageConnection) Stat(ctx context.Context, path string) (*ObjectInfo, error) {
+	uri, err := parseURI(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	obj, err := conn.getRemoteObject(ctx, path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, errors.WithMessagef(os.ErrNotExist, "object not found: %s", path)
+		}
+		return nil, errors.WithStack(err)
+	}
+	return gcsObjectInfo(uri.Hostname(), attrs), nil
+}
+
+func gcsObjectInfo(bucket string, attrs *storage.ObjectAttrs) *ObjectInfo {
+	meta := map[string]string{}
+	for k, v := range attrs.Metadata {
+		meta[k] = v
+	}
+	if attrs.ContentType != "" {
+		meta["Content-Type"] = attrs.ContentType
+	}
+	if attrs.CacheControl != "" {
+		meta["Cache-Control"] = attrs.CacheControl
+	}
+	return &ObjectInfo{
+		Path:     "gs://" + bucket + "/" + attrs.Name,
+		Size:     attrs.Size,
+		ModTime:  attrs.Updated.UTC(),
+		Metadata: meta,
+	}
+}
+
 // List objects in the bucket, based on the path prefix.
 // The returned iterator is not safe for concurrent operations without explicit synchronization.
 // This wraps the bucket.Objects call and getting the bucket, to list the file objects
@@ -0,0 +1,371 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/effective-security/xlog"
+	"github.com/pkg/errors"
+)
+
+// S3Options are options for the connection to S3 compatible storage.
+// If credentials are not specified, the default AWS credential chain is used,
+// including AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+type S3Options struct {
+	// Endpoint specifies custom endpoint URL, for MinIO or Ceph
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	// Region specifies the region
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	// UsePathStyle specifies to use path-style addressing: https://endpoint/bucket/key
+	UsePathStyle bool `json:"use_path_style,omitempty" yaml:"use_path_style,omitempty"`
+	// AccessKeyID specifies static credentials
+	AccessKeyID string `json:"access_key_id,omitempty" yaml:"access_key_id,omitempty"`
+	// SecretAccessKey specifies static credentials
+	SecretAccessKey string `json:"secret_access_key,omitempty" yaml:"secret_access_key,omitempty"`
+	// SessionToken specifies static credentials
+	SessionToken string `json:"session_token,omitempty" yaml:"session_token,omitempty"`
+	// ServerSideEncryption specifies SSE algorithm: AES256 or aws:kms
+	ServerSideEncryption string `json:"sse,omitempty" yaml:"sse,omitempty"`
+	// SSEKMSKeyID specifies KMS key ID for aws:kms encryption
+	SSEKMSKeyID string `json:"sse_kms_key_id,omitempty" yaml:"sse_kms_key_id,omitempty"`
+}
+
+// S3Connection manages a connection to S3 compatible storage.
+type S3Connection struct {
+	S3Options
+
+	client *s3.Client
+}
+
+// Open the connection to S3.
+// The context only needs to last until this function returns.
+func (conn *S3Connection) Open(ctx context.Context) (*s3.Client, error) {
+	if conn.client != nil {
+		return conn.client, nil
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if conn.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(conn.Region))
+	}
+	if conn.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(conn.AccessKeyID, conn.SecretAccessKey, conn.SessionToken)))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to load AWS config")
+	}
+	if cfg.Region == "" {
+		// S3 compatible services usually do not care about the region,
+		// but it is required for signing
+		cfg.Region = "us-east-1"
+	}
+
+	conn.client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if conn.Endpoint != "" {
+			o.BaseEndpoint = aws.String(conn.Endpoint)
+		}
+		o.UsePathStyle = conn.UsePathStyle
+	})
+	return conn.client, nil
+}
+
+// parseS3URI returns bucket and key
+func parseS3URI(path string) (string, string, error) {
+	bucket, key, err := parseS3Prefix(path)
+	if err != nil {
+		return "", "", err
+	}
+	if key == "" {
+		return "", "", errors.Errorf("invalid path: missing key: %s", path)
+	}
+	return bucket, key, nil
+}
+
+// parseS3Prefix returns bucket and key prefix, which can be empty
+func parseS3Prefix(path string) (string, string, error) {
+	uri, err := url.Parse(path)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	if uri.Scheme != "s3" {
+		return "", "", errors.Errorf("invalid scheme: %q", uri.Scheme)
+	}
+	if uri.Host == "" {
+		return "", "", errors.Errorf("invalid path: missing bucket: %s", path)
+	}
+	return uri.Host, strings.TrimPrefix(uri.Path, "/"), nil
+}
+
+// GetReader returns a reader tied to ctx for path. The caller is responsible
+// for calling Close on the reader when done.
+func (conn *S3Connection) GetReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	bucket, key, err := parseS3URI(path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := conn.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.KV(xlog.DEBUG, "bucket", bucket, "object", key)
+	res, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to open reader")
+	}
+	return res.Body, nil
+}
+
+// GetWriter returns a writer tied to ctx for path.
+// The content is uploaded when the writer is closed.
+func (conn *S3Connection) GetWriter(ctx context.Context, path string) (io.WriteCloser, error) {
+	bucket, key, err := parseS3URI(path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := conn.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Writer{
+		ctx:    ctx,
+		conn:   conn,
+		client: client,
+		bucket: bucket,
+		key:    key,
+	}, nil
+}
+
+// SetMetadata updates object with metadata.
+// S3 objects are immutable, so the object is copied onto itself with new metadata.
+func (conn *S3Connection) SetMetadata(ctx context.Context, path string, meta map[string]string) error {
+	bucket, key, err := parseS3URI(path)
+	if err != nil {
+		return err
+	}
+	client, err := conn.Open(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the metadata is replaced on copy,
+	// so the values not in meta are carried from the current object
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		CopySource:         aws.String((&url.URL{Path: bucket + "/" + key}).EscapedPath()),
+		MetadataDirective:  types.MetadataDirectiveReplace,
+		ContentType:        head.ContentType,
+		ContentEncoding:    head.ContentEncoding,
+		CacheControl:       head.CacheControl,
+		ContentLanguage:    head.ContentLanguage,
+		ContentDisposition: head.ContentDisposition,
+	}
+	if len(head.Metadata) > 0 {
+		input.Metadata = maps.Clone(head.Metadata)
+	}
+	for k, v := range meta {
+		switch strings.ToLower(k) {
+		case "contenttype", "content-type":
+			input.ContentType = aws.String(v)
+		case "contentencoding", "content-encoding":
+			input.ContentEncoding = aws.String(v)
+		case "cachecontrol", "cache-control":
+			input.CacheControl = aws.String(v)
+		case "contentlanguage", "content-language":
+			input.ContentLanguage = aws.String(v)
+		case "contentdisposition", "content-disposition":
+			input.ContentDisposition = aws.String(v)
+		default:
+			if input.Metadata == nil {
+				input.Metadata = map[string]string{k: v}
+			} else {
+				input.Metadata[k] = v
+			}
+		}
+	}
+	if conn.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(conn.ServerSideEncryption)
+		if conn.SSEKMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(conn.SSEKMSKeyID)
+		}
+	}
+
+	_, err = client.CopyObject(ctx, input)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Delete the file pointed to by path.
+func (conn *S3Connection) Delete(ctx context.Context, path string) error {
+	bucket, key, err := parseS3URI(path)
+	if err != nil {
+		return err
+	}
+	client, err := conn.Open(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ListObjects returns objects with the path prefix, sorted by path
+func (conn *S3Connection) ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	bucket, key, err := parseS3Prefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	client, err := conn.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*ObjectInfo
+	pages := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, obj := range page.Contents {
+			list = append(list, &ObjectInfo{
+				Path:    "s3://" + bucket + "/" + aws.ToString(obj.Key),
+				Size:    aws.ToInt64(obj.Size),
+				ModTime: aws.ToTime(obj.LastModified).UTC(),
+			})
+		}
+	}
+	// S3 returns objects in lexicographical order
+	return list, nil
+}
+
+// Stat returns the object info
+func (conn *S3Connection) Stat(ctx context.Context, path string) (*ObjectInfo, error) {
+	bucket, key, err := parseS3URI(path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := conn.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var nf *types.NotFound
+		if errors.As(err, &nf) {
+			return nil, errors.WithMessagef(os.ErrNotExist, "object not found: %s", path)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	meta := map[string]string{}
+	for k, v := range res.Metadata {
+		meta[k] = v
+	}
+	if res.ContentType != nil {
+		meta["Content-Type"] = aws.ToString(res.ContentType)
+	}
+	if res.CacheControl != nil {
+		meta["Cache-Control"] = aws.ToString(res.CacheControl)
+	}
+	return &ObjectInfo{
+		Path:     path,
+		Size:     aws.ToInt64(res.ContentLength),
+		ModTime:  aws.ToTime(res.LastModified).UTC(),
+		Metadata: meta,
+	}, nil
+}
+
+// Close does nothing for S3Connection.
+func (conn *S3Connection) Close() error {
+	return nil
+}
+
+// Wait can be used to block on the completion of a write operation.
+func (conn *S3Connection) Wait() error {
+	return nil
+}
+
+// s3Writer buffers the content, and uploads it on Close
+type s3Writer struct {
+	ctx    context.Context
+	conn   *S3Connection
+	client *s3.Client
+	bucket string
+	key    string
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("writer is closed")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(w.key),
+		Body:   bytes.NewReader(w.buf.Bytes()),
+	}
+	if w.conn.ServerSideEncryption != "" {
+		input.ServerSideEncryption = types.ServerSideEncryption(w.conn.ServerSideEncryption)
+		if w.conn.SSEKMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(w.conn.SSEKMSKeyID)
+		}
+	}
+
+	logger.KV(xlog.DEBUG, "bucket", w.bucket, "object", w.key, "size", w.buf.Len())
+	_, err := w.client.PutObject(w.ctx, input)
+	if err != nil {
+		return errors.WithMessagef(err, "unable to upload: s3://%s/%s", w.bucket, w.key)
+	}
+	return nil
+}
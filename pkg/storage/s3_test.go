@@ -0,0 +1,227 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/effective-security/trusty/pkg/storage"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type s3Object struct {
+	data   []byte
+	header http.Header
+}
+
+// s3Stub is in-process S3 stub with path-style addressing
+type s3Stub struct {
+	lock    sync.Mutex
+	objects map[string]*s3Object
+}
+
+func (s *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+		s.list(w, name, r.URL.Query().Get("prefix"))
+		return
+	}
+	switch r.Method {
+	case http.MethodHead:
+		obj := s.objects[name]
+		if obj == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		for k, v := range obj.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("Last-Modified", "Sun, 01 Jan 2023 00:00:00 GMT")
+	case http.MethodPut:
+		obj := &s3Object{header: http.Header{}}
+		if src := r.Header.Get("X-Amz-Copy-Source"); src != "" {
+			src, _ = url.PathUnescape(src)
+			from := s.objects[strings.TrimPrefix(src, "/")]
+			if from == nil {
+				writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+			obj.data = from.data
+		} else {
+			obj.data, _ = io.ReadAll(r.Body)
+		}
+		for k, v := range r.Header {
+			if strings.HasPrefix(k, "X-Amz-Meta-") ||
+				strings.HasPrefix(k, "X-Amz-Server-Side-Encryption") ||
+				k == "Content-Type" || k == "Cache-Control" {
+				obj.header[k] = v
+			}
+		}
+		s.objects[name] = obj
+
+		w.Header().Set("ETag", `"etag"`)
+		if r.Header.Get("X-Amz-Copy-Source") != "" {
+			_, _ = w.Write([]byte(`<CopyObjectResult><ETag>"etag"</ETag><LastModified>2023-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`))
+		}
+	case http.MethodGet:
+		obj := s.objects[name]
+		if obj == nil {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		_, _ = w.Write(obj.data)
+	case http.MethodDelete:
+		delete(s.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *s3Stub) list(w http.ResponseWriter, bucket, prefix string) {
+	var names []string
+	for name := range s.objects {
+		key := strings.TrimPrefix(name, bucket+"/")
+		if key != name && strings.HasPrefix(key, prefix) {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+
+	res := `<ListBucketResult><Name>` + bucket + `</Name><IsTruncated>false</IsTruncated>`
+	for _, key := range names {
+		res += `<Contents><Key>` + key + `</Key><Size>` + strconv.Itoa(len(s.objects[bucket+"/"+key].data)) +
+			`</Size><LastModified>2023-01-01T00:00:00.000Z</LastModified></Contents>`
+	}
+	res += `</ListBucketResult>`
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(res))
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(`<Error><Code>` + code + `</Code><Message>` + code + `</Message></Error>`))
+}
+
+func TestS3(t *testing.T) {
+	stub := &s3Stub{objects: map[string]*s3Object{}}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	opts := &storage.Options{
+		S3Options: storage.S3Options{
+			Endpoint:             server.URL,
+			Region:               "us-west-2",
+			UsePathStyle:         true,
+			AccessKeyID:          "key",
+			SecretAccessKey:      "secret",
+			ServerSideEncryption: "aws:kms",
+			SSEKMSKeyID:          "kms-key",
+		},
+	}
+
+	c, err := storage.ConnectionFromPath("s3://bucket/key", opts)
+	require.NoError(t, err)
+	require.IsType(t, &storage.S3Connection{}, c)
+	c.Close()
+
+	fn := "s3://trusty/certs/a b.pem"
+	data := []byte("certificate")
+	n, err := storage.WriteFile(ctx, fn, data, opts)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+
+	obj := stub.objects["trusty/certs/a b.pem"]
+	require.NotNil(t, obj)
+	assert.Equal(t, "aws:kms", obj.header.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "kms-key", obj.header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+
+	data2, err := storage.ReadFile(ctx, fn, opts)
+	require.NoError(t, err)
+	assert.Equal(t, data, data2)
+
+	err = storage.SetMetadata(ctx, fn, map[string]string{
+		"Content-Type":  "application/pem-certificate-chain",
+		"Cache-Control": "public, max-age=900",
+		"issuer":        "trusty",
+	}, opts)
+	require.NoError(t, err)
+
+	obj = stub.objects["trusty/certs/a b.pem"]
+	require.NotNil(t, obj)
+	assert.Equal(t, data, obj.data)
+	assert.Equal(t, "application/pem-certificate-chain", obj.header.Get("Content-Type"))
+	assert.Equal(t, "public, max-age=900", obj.header.Get("Cache-Control"))
+	assert.Equal(t, "trusty", obj.header.Get("X-Amz-Meta-Issuer"))
+
+	// the values not in the update are kept
+	err = storage.SetMetadata(ctx, fn, map[string]string{
+		"shard": "1",
+	}, opts)
+	require.NoError(t, err)
+	obj = stub.objects["trusty/certs/a b.pem"]
+	assert.Equal(t, "application/pem-certificate-chain", obj.header.Get("Content-Type"))
+	assert.Equal(t, "public, max-age=900", obj.header.Get("Cache-Control"))
+	assert.Equal(t, "trusty", obj.header.Get("X-Amz-Meta-Issuer"))
+	assert.Equal(t, "1", obj.header.Get("X-Amz-Meta-Shard"))
+
+	err = storage.SetMetadata(ctx, "s3://trusty/notfound", map[string]string{"shard": "1"}, opts)
+	assert.Error(t, err)
+
+	info, err := storage.Stat(ctx, fn, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), info.Size)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), info.ModTime)
+	assert.Equal(t, "application/pem-certificate-chain", info.Metadata["Content-Type"])
+	assert.Equal(t, "trusty", info.Metadata["issuer"])
+
+	_, err = storage.Stat(ctx, "s3://trusty/notfound", opts)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	_, err = storage.WriteFile(ctx, "s3://trusty/crls/1.crl", data, opts)
+	require.NoError(t, err)
+
+	list, err := storage.List(ctx, "s3://trusty/", opts)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "s3://trusty/certs/a b.pem", list[0].Path)
+	assert.Equal(t, "s3://trusty/crls/1.crl", list[1].Path)
+	assert.Equal(t, int64(len(data)), list[1].Size)
+
+	list, err = storage.List(ctx, "s3://trusty/crls/", opts)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	err = storage.DeletePath(ctx, "s3://trusty/crls/1.crl", opts)
+	require.NoError(t, err)
+
+	err = storage.DeletePath(ctx, fn, opts)
+	require.NoError(t, err)
+	assert.Empty(t, stub.objects)
+
+	_, err = storage.ReadFile(ctx, fn, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NoSuchKey")
+
+	_, err = storage.WriteFile(ctx, "s3://bucket", data, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing key")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/effective-security/xlog"
 	"github.com/pkg/errors"
@@ -14,6 +15,20 @@ var logger = xlog.NewPackageLogger("github.com/effective-security/trusty/pkg", "
 // Options collects the options for all supported storage types
 type Options struct {
 	GoogleOptions
+	S3Options
+	FilesystemOptions
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	// Path specifies the full path of the object, including the scheme
+	Path string
+	// Size specifies the size in bytes
+	Size int64
+	// ModTime specifies the last modification time
+	ModTime time.Time
+	// Metadata specifies the object metadata, if supported
+	Metadata map[string]string
 }
 
 // ReadConnection is a connection that can return file readers.
@@ -24,6 +39,13 @@ type ReadConnection interface {
 	// closing the reader when finished.
 	GetReader(ctx context.Context, path string) (io.ReadCloser, error)
 
+	// ListObjects returns objects with the path prefix, sorted by path
+	ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error)
+
+	// Stat returns the object info. The returned error satisfies
+	// errors.Is(err, os.ErrNotExist), if the object does not exist.
+	Stat(ctx context.Context, path string) (*ObjectInfo, error)
+
 	// Wait for something to complete after close
 	Wait() error
 }
@@ -59,6 +81,13 @@ type ReadWriteConnection interface {
 	// Delete the file pointed to by path.
 	Delete(ctx context.Context, path string) error
 
+	// ListObjects returns objects with the path prefix, sorted by path
+	ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error)
+
+	// Stat returns the object info. The returned error satisfies
+	// errors.Is(err, os.ErrNotExist), if the object does not exist.
+	Stat(ctx context.Context, path string) (*ObjectInfo, error)
+
 	// SetMetadata updates object with metadata, if supported
 	SetMetadata(ctx context.Context, path string, meta map[string]string) error
 
@@ -68,7 +97,8 @@ type ReadWriteConnection interface {
 
 // ConnectionFromPath attempts to deduce the right type of storage connection
 // from the given path. Currently this works because the only supported types
-// are Google Cloud Storage, whose paths require a gs:// prefix, and filesystem.
+// are Google Cloud Storage, whose paths require a gs:// prefix,
+// S3 compatible storage, whose paths require a s3:// prefix, and filesystem.
 func ConnectionFromPath(path string, options ...*Options) (ReadWriteConnection, error) {
 	if strings.HasPrefix(path, "gs://") {
 		gcs := GoogleOptions{}
@@ -79,7 +109,22 @@ func ConnectionFromPath(path string, options ...*Options) (ReadWriteConnection,
 			GoogleOptions: gcs,
 		}, nil
 	}
-	return &FilesystemConnection{}, nil
+	if strings.HasPrefix(path, "s3://") {
+		s3 := S3Options{}
+		if len(options) > 0 {
+			s3 = options[0].S3Options
+		}
+		return &S3Connection{
+			S3Options: s3,
+		}, nil
+	}
+	fs := FilesystemOptions{}
+	if len(options) > 0 {
+		fs = options[0].FilesystemOptions
+	}
+	return &FilesystemConnection{
+		FilesystemOptions: fs,
+	}, nil
 }
 
 // WrappedReader is an io.ReadCloser that carries its connection with it, and
@@ -217,15 +262,20 @@ func WriteFile(ctx context.Context, path string, data []byte, options ...*Option
 	if err != nil {
 		return 0, errors.WithStack(err)
 	}
-	defer writer.Close()
 	n, err := writer.Write(data)
 	if err != nil {
+		_ = writer.Close()
 		return n, errors.WithMessagef(err, "failed to write: %s", path)
 	}
 	if n != len(data) {
-		err = errors.WithStack(io.ErrShortWrite)
+		_ = writer.Close()
+		return n, errors.WithStack(io.ErrShortWrite)
 	}
-	return n, err
+	// some connections upload the content on Close
+	if err = writer.Close(); err != nil {
+		return n, errors.WithMessagef(err, "failed to close: %s", path)
+	}
+	return n, nil
 }
 
 // SetMetadata updates object with metadata, if supported
@@ -241,3 +291,23 @@ func SetMetadata(ctx context.Context, path string, meta map[string]string, optio
 	}
 	return closeError
 }
+
+// List returns objects with the path prefix using a singleton connection
+func List(ctx context.Context, prefix string, options ...*Options) ([]*ObjectInfo, error) {
+	conn, err := ConnectionFromPath(prefix, options...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	return conn.ListObjects(ctx, prefix)
+}
+
+// Stat returns the object info using a singleton connection
+func Stat(ctx context.Context, path string, options ...*Options) (*ObjectInfo, error) {
+	conn, err := ConnectionFromPath(path, options...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	return conn.Stat(ctx, path)
+}
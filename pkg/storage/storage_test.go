@@ -2,6 +2,7 @@ package storage_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path"
 	"path/filepath"
@@ -50,3 +51,117 @@ func TestConnection(t *testing.T) {
 	require.NoError(t, err)
 	c.Close()
 }
+
+func TestFilesystemListStat(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	for _, name := range []string{"b/2.crl", "b/1.crl", "a.pem", "bc.pem"} {
+		_, err := storage.WriteFile(ctx, filepath.Join(dir, name), []byte(name))
+		require.NoError(t, err)
+	}
+	// leftover of failed write
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b", ".tmp-3.crl-123"), []byte("partial"), 0644))
+
+	list, err := storage.List(ctx, dir)
+	require.NoError(t, err)
+	var names []string
+	for _, o := range list {
+		rel, _ := filepath.Rel(dir, o.Path)
+		names = append(names, rel)
+	}
+	assert.Equal(t, []string{"a.pem", "b/1.crl", "b/2.crl", "bc.pem"}, names)
+
+	list, err = storage.List(ctx, filepath.Join(dir, "b"))
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	list, err = storage.List(ctx, filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, filepath.Join(dir, "a.pem"), list[0].Path)
+
+	list, err = storage.List(ctx, filepath.Join(dir, "b")+"/")
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	list, err = storage.List(ctx, filepath.Join(dir, "notfound"))
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	info, err := storage.Stat(ctx, filepath.Join(dir, "b/1.crl"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("b/1.crl")), info.Size)
+	assert.False(t, info.ModTime.IsZero())
+
+	_, err = storage.Stat(ctx, filepath.Join(dir, "notfound"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestFilesystemAtomicWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "ca.crl")
+
+	_, err := storage.WriteFile(ctx, fn, []byte("v1"))
+	require.NoError(t, err)
+
+	w, err := storage.GetWriterFromPath(ctx, fn)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("v2"))
+	require.NoError(t, err)
+
+	// readers observe the previous content until the writer is closed
+	data, err := os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(data))
+
+	require.NoError(t, w.Close())
+	data, err = os.ReadFile(fn)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+
+	// the new file is readable by others
+	info, err := os.Stat(fn)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// the mode of the existing file is kept
+	require.NoError(t, os.Chmod(fn, 0640))
+	_, err = storage.WriteFile(ctx, fn, []byte("v2"))
+	require.NoError(t, err)
+	info, err = os.Stat(fn)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	_, err = os.Stat(filepath.Join(dir, storage.VersionsDir))
+	assert.True(t, os.IsNotExist(err))
+
+	t.Run("versioning", func(t *testing.T) {
+		opts := &storage.Options{
+			FilesystemOptions: storage.FilesystemOptions{Versioning: true},
+		}
+		_, err := storage.WriteFile(ctx, fn, []byte("v3"), opts)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(fn)
+		require.NoError(t, err)
+		assert.Equal(t, "v3", string(data))
+
+		versions, err := os.ReadDir(filepath.Join(dir, storage.VersionsDir))
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		data, err = os.ReadFile(filepath.Join(dir, storage.VersionsDir, versions[0].Name()))
+		require.NoError(t, err)
+		assert.Equal(t, "v2", string(data))
+
+		// versions are not listed
+		list, err := storage.List(ctx, dir)
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+}
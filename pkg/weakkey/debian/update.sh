@@ -0,0 +1,29 @@
+#!/bin/sh
+#
+# update.sh replaces the bundled lists of Debian weak keys (CVE-2008-0166)
+# by the lists of openssl-blacklist package, compressed with gzip.
+#
+# Usage: update.sh [package URL]
+#
+set -e
+
+DEB_URL=${1:-http://archive.debian.org/debian/pool/main/o/openssl-blacklist/openssl-blacklist_0.5-3_all.deb}
+DIR=$(cd "$(dirname "$0")" && pwd)
+TMP=$(mktemp -d)
+trap 'rm -rf "$TMP"' EXIT
+
+echo "*** downloading $DEB_URL"
+curl -fsSL -o "$TMP/openssl-blacklist.deb" "$DEB_URL"
+(cd "$TMP" && ar x openssl-blacklist.deb && mkdir data && tar -xf data.tar.* -C data)
+
+LISTS=$(ls "$TMP"/data/usr/share/openssl-blacklist/blacklist.RSA-* 2>/dev/null || true)
+if [ -z "$LISTS" ]; then
+	echo "*** the package does not have the lists"
+	exit 1
+fi
+
+rm -f "$DIR"/blacklist.*
+for f in $LISTS; do
+	gzip -9 -n -c "$f" > "$DIR/$(basename "$f").gz"
+	echo "*** updated $(basename "$f").gz"
+done
@@ -0,0 +1,198 @@
+// Package weakkey detects weak public keys before issuance.
+//
+// The keys are checked for the minimum size, for ROCA vulnerability
+// (CVE-2017-15361), and against the lists of Debian weak keys (CVE-2008-0166)
+// in the format of openssl-blacklist package.
+// The lists of openssl-blacklist package are bundled in the debian folder,
+// and updated by `go generate`.
+// The blocked keys are identified by SPKIHash.
+package weakkey
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/titanous/rocacheck"
+)
+
+// debianLists provides the bundled lists of Debian weak keys
+// from openssl-blacklist package, compressed with gzip
+//
+//go:generate sh ./debian/update.sh
+//go:embed debian
+var debianLists embed.FS
+
+// Default minimum sizes
+const (
+	DefaultMinRSASize   = 2048
+	DefaultMinECDSASize = 256
+)
+
+// SPKIHash returns hex encoded SHA-256 of the SubjectPublicKeyInfo
+func SPKIHash(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", errors.Wrap(err, "unsupported public key")
+	}
+	h := sha256.Sum256(der)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// CheckSize returns error if the key is smaller than the minimum size,
+// minECDSA specifies the minimum size of the curve.
+// If not specified, then the defaults are used.
+func CheckSize(pub crypto.PublicKey, minRSA, minECDSA int) error {
+	if minRSA == 0 {
+		minRSA = DefaultMinRSASize
+	}
+	if minECDSA == 0 {
+		minECDSA = DefaultMinECDSASize
+	}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if size := k.N.BitLen(); size < minRSA {
+			return errors.Errorf("RSA key size %d is less than %d", size, minRSA)
+		}
+	case *ecdsa.PublicKey:
+		if size := k.Curve.Params().BitSize; size < minECDSA {
+			return errors.Errorf("ECDSA curve %s is less than %d", k.Curve.Params().Name, minECDSA)
+		}
+	case ed25519.PublicKey:
+	default:
+		return errors.Errorf("unsupported public key: %T", pub)
+	}
+	return nil
+}
+
+// Checker checks the keys for known weaknesses,
+// the zero value checks only ROCA vulnerability
+type Checker struct {
+	debian map[string]struct{}
+}
+
+// New returns the checker with the bundled lists of Debian weak keys,
+// and the lists from the files, such as /usr/share/openssl-blacklist/blacklist.RSA-2048
+func New(debianFiles ...string) (*Checker, error) {
+	c := &Checker{}
+	err := c.loadDebianFS(debianLists, "debian")
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to load bundled lists")
+	}
+	for _, file := range debianFiles {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		err = c.LoadDebian(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "unable to load %s", file)
+		}
+	}
+	return c, nil
+}
+
+// loadDebianFS adds the lists of Debian weak keys named blacklist.*
+// from the folder, the files with .gz extension are decompressed
+func (c *Checker) loadDebianFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "blacklist.*"))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, name := range files {
+		err = c.loadDebianFile(fsys, name)
+		if err != nil {
+			return errors.WithMessagef(err, "unable to load %s", name)
+		}
+	}
+	return nil
+}
+
+func (c *Checker) loadDebianFile(fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	return c.LoadDebian(r)
+}
+
+// DebianKeys returns the number of loaded Debian weak keys
+func (c *Checker) DebianKeys() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.debian)
+}
+
+// LoadDebian adds the list of Debian weak keys in the format of openssl-blacklist:
+// one fingerprint per line, and the lines started with # are ignored
+func (c *Checker) LoadDebian(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fp := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if fp == "" || strings.HasPrefix(fp, "#") {
+			continue
+		}
+		if b, err := hex.DecodeString(fp); err != nil || len(b) != 10 {
+			return errors.Errorf("invalid fingerprint at line %d", line)
+		}
+		if c.debian == nil {
+			c.debian = map[string]struct{}{}
+		}
+		c.debian[fp] = struct{}{}
+	}
+	return errors.WithStack(scanner.Err())
+}
+
+// Check returns error if the key is known to be weak,
+// the nil checker checks only ROCA vulnerability
+func (c *Checker) Check(pub crypto.PublicKey) error {
+	k, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil
+	}
+	if c != nil {
+		if _, ok := c.debian[DebianFingerprint(k.N)]; ok {
+			return errors.New("Debian weak key (CVE-2008-0166)")
+		}
+	}
+	if rocacheck.IsWeak(k) {
+		return errors.New("key vulnerable to ROCA (CVE-2017-15361)")
+	}
+	return nil
+}
+
+// DebianFingerprint returns the fingerprint of the modulus in openssl-blacklist:
+// the last 20 hex characters of SHA-1 of "Modulus=<upper case hex>\n"
+func DebianFingerprint(n *big.Int) string {
+	h := sha1.Sum([]byte(fmt.Sprintf("Modulus=%X\n", n)))
+	return hex.EncodeToString(h[10:])
+}
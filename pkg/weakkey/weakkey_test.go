@@ -0,0 +1,153 @@
+package weakkey
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rocaKey is the public key vulnerable to ROCA
+const rocaKey = `-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAlze9c7qGdjDLVR/ntk+4
+ZkfMcYsAnmfTFHfe3Xv7jRQqPCXCULtr0y0jG3aRJmEenoXO9uDveqr43gFB9yvA
+dLEhu0aJqpB7lNZ+yXsvfVp/96dkSN8oWYL/dd9Z7GQOvVniHUY3Xsd7zdw2eYOy
+HSXhhA2Ttwnj3c1jEYfC0y9q1cU99aL0ogGDqolcOvlkJu+mGb+6+WyboFa1gwRu
+kYxBHZWKiHCt/eihvXsPTzTlXmTXWdGJtA1xZDnCBWuZ90b5R0agXVIESTl0cCyH
+aQM/tLZmktJIU+Eu7ALBXemPg9kh3SCnYd3/YvDGCtYSXOWthHwlP5CImRBcQaNn
+cQIDAQAB
+-----END PUBLIC KEY-----`
+
+func TestSPKIHash(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	h := sha256.Sum256(der)
+
+	hash, err := SPKIHash(key.Public())
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(h[:]), hash)
+
+	_, err = SPKIHash("invalid")
+	assert.Error(t, err)
+}
+
+func TestCheckSize(t *testing.T) {
+	rsa1024, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	rsa2048, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	ed, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	assert.EqualError(t, CheckSize(rsa1024.Public(), 0, 0), "RSA key size 1024 is less than 2048")
+	assert.NoError(t, CheckSize(rsa1024.Public(), 1024, 0))
+	assert.NoError(t, CheckSize(rsa2048.Public(), 0, 0))
+	assert.EqualError(t, CheckSize(rsa2048.Public(), 3072, 0), "RSA key size 2048 is less than 3072")
+	assert.EqualError(t, CheckSize(p224.Public(), 0, 0), "ECDSA curve P-224 is less than 256")
+	assert.NoError(t, CheckSize(p384.Public(), 0, 384))
+	assert.NoError(t, CheckSize(ed, 0, 0))
+	assert.EqualError(t, CheckSize("invalid", 0, 0), "unsupported public key: string")
+}
+
+func TestChecker(t *testing.T) {
+	b, _ := pem.Decode([]byte(rocaKey))
+	require.NotNil(t, b)
+	roca, err := x509.ParsePKIXPublicKey(b.Bytes)
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var nilChecker *Checker
+	assert.EqualError(t, nilChecker.Check(roca), "key vulnerable to ROCA (CVE-2017-15361)")
+	assert.NoError(t, nilChecker.Check(key.Public()))
+
+	file := filepath.Join(t.TempDir(), "blacklist.RSA-2048")
+	list := "# comment\n\n" + DebianFingerprint(key.N) + "\n"
+	require.NoError(t, os.WriteFile(file, []byte(list), 0o644))
+
+	c, err := New(file)
+	require.NoError(t, err)
+	assert.EqualError(t, c.Check(key.Public()), "Debian weak key (CVE-2008-0166)")
+	assert.Error(t, c.Check(roca))
+
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	assert.NoError(t, c.Check(ec.Public()))
+
+	_, err = New(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	err = c.LoadDebian(strings.NewReader("# comment\ninvalid\n"))
+	assert.EqualError(t, err, "invalid fingerprint at line 2")
+}
+
+func TestLoadDebianFS(t *testing.T) {
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	key2, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, err = w.Write([]byte("# comment\n" + DebianFingerprint(key1.N) + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	fsys := fstest.MapFS{
+		"debian/blacklist.RSA-2048.gz": {Data: gz.Bytes()},
+		"debian/blacklist.RSA-4096":    {Data: []byte(DebianFingerprint(key2.N) + "\n")},
+		"debian/update.sh":             {Data: []byte("invalid")},
+	}
+	c := &Checker{}
+	require.NoError(t, c.loadDebianFS(fsys, "debian"))
+	assert.Equal(t, 2, c.DebianKeys())
+	assert.Error(t, c.Check(key1.Public()))
+	assert.Error(t, c.Check(key2.Public()))
+
+	fsys["debian/blacklist.RSA-1024.gz"] = &fstest.MapFile{Data: []byte("invalid")}
+	err = c.loadDebianFS(fsys, "debian")
+	assert.ErrorContains(t, err, "unable to load debian/blacklist.RSA-1024.gz")
+
+	// the bundled lists
+	c, err = New()
+	require.NoError(t, err)
+	files, err := debianLists.ReadDir("debian")
+	require.NoError(t, err)
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), "blacklist.") {
+			assert.NotZero(t, c.DebianKeys(), f.Name())
+		}
+	}
+
+	var nilChecker *Checker
+	assert.Zero(t, nilChecker.DebianKeys())
+}
+
+func TestDebianFingerprint(t *testing.T) {
+	// printf 'Modulus=FF\n' | sha1sum | cut -c21-40
+	assert.Equal(t, "2abfa6aa260e50654b72", DebianFingerprint(big.NewInt(0xff)))
+}
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE public.revoked DROP COLUMN IF EXISTS external;
+ALTER TABLE public.certificates DROP COLUMN IF EXISTS external;
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+--
+-- External certificates, imported for inventory
+--
+ALTER TABLE public.certificates
+    ADD COLUMN IF NOT EXISTS external boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.revoked
+    ADD COLUMN IF NOT EXISTS external boolean NOT NULL DEFAULT false;
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_publish_jobs_status;
+DROP INDEX IF EXISTS idx_publish_jobs_pending;
+DROP TABLE IF EXISTS public.publish_jobs;
+
+--
+--
+--
+COMMIT;
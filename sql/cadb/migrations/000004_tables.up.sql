@@ -0,0 +1,39 @@
+BEGIN;
+
+--
+-- Publish outbox
+--
+CREATE TABLE IF NOT EXISTS public.publish_jobs
+(
+    id bigint NOT NULL,
+    kind character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    ref_id bigint NOT NULL DEFAULT 0,
+    status character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL,
+    last_error text COLLATE pg_catalog."default" NOT NULL DEFAULT '',
+    location text COLLATE pg_catalog."default" NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    CONSTRAINT publish_jobs_pkey PRIMARY KEY (id)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+-- only one pending job per object
+CREATE UNIQUE INDEX IF NOT EXISTS idx_publish_jobs_pending
+    ON public.publish_jobs USING btree
+    (kind, ikid, ref_id)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_publish_jobs_status
+    ON public.publish_jobs USING btree
+    (status, next_attempt_at);
+
+--
+--
+--
+COMMIT;
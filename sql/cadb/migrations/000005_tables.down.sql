@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.delta_crls;
+ALTER TABLE public.crls DROP COLUMN IF EXISTS crl_number;
+DROP TABLE IF EXISTS public.crl_numbers;
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,44 @@
+BEGIN;
+
+--
+-- CRL numbers, shared by complete and delta CRLs of the issuer
+--
+CREATE TABLE IF NOT EXISTS public.crl_numbers
+(
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    "number" bigint NOT NULL,
+    CONSTRAINT crl_numbers_pkey PRIMARY KEY (ikid)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+ALTER TABLE public.crls
+    ADD COLUMN IF NOT EXISTS crl_number bigint NOT NULL DEFAULT 0;
+
+--
+-- Delta CRLs
+--
+CREATE TABLE IF NOT EXISTS public.delta_crls
+(
+    id bigint NOT NULL,
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    crl_number bigint NOT NULL,
+    base_number bigint NOT NULL,
+    this_update timestamp with time zone,
+    next_update timestamp with time zone,
+    issuer character varying(260) COLLATE pg_catalog."default" NOT NULL,
+    pem text COLLATE pg_catalog."default" NOT NULL,
+    CONSTRAINT delta_crls_pkey PRIMARY KEY (id),
+    CONSTRAINT delta_crls_ikid UNIQUE (ikid)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+--
+--
+--
+COMMIT;
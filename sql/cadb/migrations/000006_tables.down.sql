@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.shard_crl_numbers;
+DROP TABLE IF EXISTS public.shard_crls;
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,46 @@
+BEGIN;
+
+--
+-- Sharded CRLs
+--
+CREATE TABLE IF NOT EXISTS public.shard_crls
+(
+    id bigint NOT NULL,
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    shard int NOT NULL,
+    crl_number bigint NOT NULL,
+    this_update timestamp with time zone,
+    next_update timestamp with time zone,
+    issuer character varying(260) COLLATE pg_catalog."default" NOT NULL,
+    pem text COLLATE pg_catalog."default" NOT NULL,
+    CONSTRAINT shard_crls_pkey PRIMARY KEY (id),
+    CONSTRAINT shard_crls_ikid_shard UNIQUE (ikid, shard)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_shard_crls_next_update
+    ON public.shard_crls USING btree
+    (next_update);
+
+--
+-- CRL numbers of shards, each shard has own sequence
+--
+CREATE TABLE IF NOT EXISTS public.shard_crl_numbers
+(
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    shard int NOT NULL,
+    "number" bigint NOT NULL,
+    CONSTRAINT shard_crl_numbers_pkey PRIMARY KEY (ikid, shard)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+--
+--
+--
+COMMIT;
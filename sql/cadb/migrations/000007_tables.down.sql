@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ocsp_responses;
+
+--
+--
+--
+COMMIT;
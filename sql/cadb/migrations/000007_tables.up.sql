@@ -0,0 +1,31 @@
+BEGIN;
+
+--
+-- Pre-signed OCSP responses,
+-- the id is the ID of the certificate
+--
+CREATE TABLE IF NOT EXISTS public.ocsp_responses
+(
+    id bigint NOT NULL,
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    serial_number character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    status character varying(16) COLLATE pg_catalog."default" NOT NULL,
+    this_update timestamp with time zone NOT NULL,
+    next_update timestamp with time zone NOT NULL,
+    der bytea NOT NULL,
+    CONSTRAINT ocsp_responses_pkey PRIMARY KEY (id),
+    CONSTRAINT ocsp_responses_ikid_serial UNIQUE (ikid, serial_number)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_ocsp_responses_next_update
+    ON public.ocsp_responses USING btree
+    (next_update);
+
+--
+--
+--
+COMMIT;
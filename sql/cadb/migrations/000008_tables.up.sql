@@ -0,0 +1,33 @@
+BEGIN;
+
+--
+-- Delegated OCSP responders of issuers,
+-- the key is the URI of the key in the crypto provider,
+-- the keys are never exported
+--
+CREATE TABLE IF NOT EXISTS public.ocsp_responders
+(
+    id bigint NOT NULL,
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    serial_number character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    not_before timestamp with time zone NOT NULL,
+    not_after timestamp with time zone NOT NULL,
+    pem text COLLATE pg_catalog."default" NOT NULL,
+    key text COLLATE pg_catalog."default" NOT NULL,
+    created_at timestamp with time zone DEFAULT Now(),
+    CONSTRAINT ocsp_responders_pkey PRIMARY KEY (id),
+    CONSTRAINT ocsp_responders_ikid_serial UNIQUE (ikid, serial_number)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_ocsp_responders_ikid_not_after
+    ON public.ocsp_responders USING btree
+    (ikid, not_after);
+
+--
+--
+--
+COMMIT;
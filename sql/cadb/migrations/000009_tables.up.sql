@@ -0,0 +1,30 @@
+BEGIN;
+
+--
+-- Idempotency keys of sign requests, unique within the org,
+-- certificate_id is 0 while the request is in progress
+--
+CREATE TABLE IF NOT EXISTS public.sign_requests
+(
+    id bigint NOT NULL,
+    org_id bigint NOT NULL DEFAULT 0,
+    idempotency_key character varying(256) COLLATE pg_catalog."default" NOT NULL,
+    digest character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    certificate_id bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone DEFAULT Now(),
+    CONSTRAINT sign_requests_pkey PRIMARY KEY (id),
+    CONSTRAINT sign_requests_org_idempotency_key UNIQUE (org_id, idempotency_key)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_sign_requests_created_at
+    ON public.sign_requests USING btree
+    (created_at);
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.blocked_keys;
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+--
+-- Blocked public keys, identified by hex encoded SHA256 of SPKI,
+-- certificate_id is the revoked certificate, or 0 if added by operator
+--
+CREATE TABLE IF NOT EXISTS public.blocked_keys
+(
+    id bigint NOT NULL,
+    spki_hash character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    source character varying(32) COLLATE pg_catalog."default" NOT NULL,
+    certificate_id bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone DEFAULT Now(),
+    CONSTRAINT blocked_keys_pkey PRIMARY KEY (id),
+    CONSTRAINT blocked_keys_spki_hash UNIQUE (spki_hash)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+--
+--
+--
+COMMIT;
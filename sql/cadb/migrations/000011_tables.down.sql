@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.pending_revocations;
+
+--
+--
+--
+COMMIT;
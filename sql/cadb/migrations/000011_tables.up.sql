@@ -0,0 +1,29 @@
+BEGIN;
+
+--
+-- Certificates to revoke at revoke_at,
+-- such as superseded certificates after the grace period
+--
+CREATE TABLE IF NOT EXISTS public.pending_revocations
+(
+    id bigint NOT NULL,
+    certificate_id bigint NOT NULL,
+    reason integer NOT NULL,
+    revoke_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT Now(),
+    CONSTRAINT pending_revocations_pkey PRIMARY KEY (id),
+    CONSTRAINT pending_revocations_certificate_id UNIQUE (certificate_id)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+CREATE INDEX IF NOT EXISTS idx_pending_revocations_revoke_at
+    ON public.pending_revocations USING btree
+    (revoke_at);
+
+--
+--
+--
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+--
+-- Tombstones of revoked certificates purged by the retention task,
+-- to keep answering OCSP requests with revoked status
+--
+CREATE TABLE IF NOT EXISTS public.revoked_tombstones
+(
+    ikid character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    serial_number character varying(64) COLLATE pg_catalog."default" NOT NULL,
+    no_tafter timestamp with time zone,
+    revoked_at timestamp with time zone,
+    reason int NOT NULL,
+    created_at timestamp with time zone DEFAULT Now(),
+    CONSTRAINT revoked_tombstones_pkey PRIMARY KEY (ikid, serial_number)
+)
+WITH (
+    OIDS = FALSE
+)
+TABLESPACE pg_default;
+
+--
+--
+--
+COMMIT;